@@ -0,0 +1,1102 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// newFlagSet returns a flag set for a subcommand that exits on bad usage
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.SetOutput(os.Stderr)
+	return flags
+}
+
+// cmdRealpath prints one "TYPE\tREALPATH\tPATH" line per path, where TYPE is d for directories,
+// f for other files and b for paths that lead nowhere, like broken symlinks
+func cmdRealpath() {
+	for _, path := range os.Args[2:] {
+		kind, real := resolvePath(path)
+		fmt.Printf("%s\t%s\t%s\n", kind, real, path)
+	}
+}
+
+// resolvePath follows the symlinks of path and returns its type and real path, which is "" when broken
+func resolvePath(path string) (string, string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "b", ""
+	}
+	real, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "b", ""
+	}
+	if abs, err := filepath.Abs(real); err == nil {
+		real = abs
+	}
+	if info.IsDir() {
+		return "d", real
+	}
+	return "f", real
+}
+
+// cmdStat prints detailed information about files, like stat(1)
+func cmdStat() {
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, "stat: missing operand")
+		os.Exit(1)
+	}
+
+	users := readIDNames("/etc/passwd")
+	groups := readIDNames("/etc/group")
+	exitCode := 0
+	for _, path := range os.Args[2:] {
+		if err := printStat(path, users, groups); err != nil {
+			fmt.Fprintf(os.Stderr, "stat: %s: %v\n", path, err)
+			exitCode = 1
+		}
+	}
+	os.Exit(exitCode)
+}
+
+func printStat(path string, users, groups map[uint32]string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	mode := info.Mode()
+
+	name := path
+	if mode&os.ModeSymlink != 0 {
+		if target, err := os.Readlink(path); err == nil {
+			name = fmt.Sprintf("%s -> %s", path, target)
+		}
+	}
+
+	fmt.Printf("  File: %s\n", name)
+	sys, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		fmt.Printf("  Size: %-10d %s\n", info.Size(), fileTypeName(mode))
+		fmt.Printf("Access: (%04o/%s)\n", statPerm(mode), formatModeString(mode))
+		fmt.Printf("Modify: %s\n", info.ModTime().Format(time.RFC3339Nano))
+		return nil
+	}
+
+	fmt.Printf("  Size: %-10d Blocks: %-10d IO Block: %-6d %s\n", info.Size(), sys.Blocks, sys.Blksize, fileTypeName(mode))
+	fmt.Printf("Device: %xh/%dd  Inode: %-10d Links: %d\n", uint64(sys.Dev), uint64(sys.Dev), sys.Ino, uint64(sys.Nlink))
+	fmt.Printf("Access: (%04o/%s)  Uid: (%5d/%8s)   Gid: (%5d/%8s)\n",
+		statPerm(mode), formatModeString(mode),
+		sys.Uid, idName(users, sys.Uid), sys.Gid, idName(groups, sys.Gid))
+	fmt.Printf("Access: %s\n", timespecString(sys.Atim))
+	fmt.Printf("Modify: %s\n", timespecString(sys.Mtim))
+	fmt.Printf("Change: %s\n", timespecString(sys.Ctim))
+	return nil
+}
+
+// statPerm returns the permission bits including setuid, setgid and sticky
+func statPerm(mode os.FileMode) uint32 {
+	perm := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		perm |= 0o4000
+	}
+	if mode&os.ModeSetgid != 0 {
+		perm |= 0o2000
+	}
+	if mode&os.ModeSticky != 0 {
+		perm |= 0o1000
+	}
+	return perm
+}
+
+func fileTypeName(mode os.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return "directory"
+	case mode&os.ModeSymlink != 0:
+		return "symbolic link"
+	case mode&os.ModeCharDevice != 0:
+		return "character special file"
+	case mode&os.ModeDevice != 0:
+		return "block special file"
+	case mode&os.ModeNamedPipe != 0:
+		return "fifo"
+	case mode&os.ModeSocket != 0:
+		return "socket"
+	default:
+		return "regular file"
+	}
+}
+
+func idName(names map[uint32]string, id uint32) string {
+	if name, ok := names[id]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+func timespecString(ts syscall.Timespec) string {
+	return time.Unix(int64(ts.Sec), int64(ts.Nsec)).Format("2006-01-02 15:04:05.000000000 -0700")
+}
+
+// cmdFind lists files below a directory whose name, path or regular expression matches
+func cmdFind() {
+	flags := newFlagSet("find")
+	name := flags.String("name", "", "glob the file name must match (a pattern with / matches the relative path)")
+	pathGlob := flags.String("path", "", "glob the whole path must match, where * also matches /, like find(1)")
+	regex := flags.String("regex", "", "regular expression (Go syntax) that must match somewhere in the path")
+	maxDepth := flags.Int("maxdepth", -1, "descend at most this many levels (-1 for no limit)")
+	fileType := flags.String("type", "", "f for files, d for directories")
+
+	// Accept both `find DIR -name X` like find(1) and `find -name X DIR`
+	args := os.Args[2:]
+	root := "."
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		root = args[0]
+		args = args[1:]
+	}
+	_ = flags.Parse(args)
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+
+	filter, err := newFindFilter(*name, *pathGlob, *regex, *fileType)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "find: %v\n", err)
+		os.Exit(1)
+	}
+
+	exitCode := 0
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "find: %s: %v\n", path, err)
+			exitCode = 1
+			return nil
+		}
+
+		depth := pathDepth(root, path)
+		if *maxDepth >= 0 && depth > *maxDepth {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// Do not descend into other filesystems like /proc and /sys
+		if d.IsDir() && depth > 0 && isPseudoFS(path) {
+			return filepath.SkipDir
+		}
+
+		if filter.matches(root, path, d) {
+			fmt.Println(path)
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "find: %v\n", err)
+		exitCode = 1
+	}
+	os.Exit(exitCode)
+}
+
+// findFilter holds the -name, -path, -regex and -type filters of find
+type findFilter struct {
+	name     string
+	path     *regexp.Regexp
+	regex    *regexp.Regexp
+	fileType string
+}
+
+func newFindFilter(name, pathGlob, regex, fileType string) (*findFilter, error) {
+	f := &findFilter{name: name, fileType: fileType}
+	if name != "" {
+		if _, err := filepath.Match(name, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", name, err)
+		}
+	}
+	if pathGlob != "" {
+		re, err := pathGlobRegexp(pathGlob)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", pathGlob, err)
+		}
+		f.path = re
+	}
+	if regex != "" {
+		re, err := regexp.Compile(regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %v", regex, err)
+		}
+		f.regex = re
+	}
+	return f, nil
+}
+
+// matches reports whether path satisfies all filters
+func (f *findFilter) matches(root, path string, d fs.DirEntry) bool {
+	switch f.fileType {
+	case "f":
+		if !d.Type().IsRegular() {
+			return false
+		}
+	case "d":
+		if !d.IsDir() {
+			return false
+		}
+	}
+	if f.path != nil && !f.path.MatchString(path) {
+		return false
+	}
+	if f.regex != nil && !f.regex.MatchString(path) {
+		return false
+	}
+	if f.name == "" {
+		return true
+	}
+
+	subject := d.Name()
+	if strings.Contains(f.name, "/") {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return false
+		}
+		subject = rel
+	}
+	matched, _ := filepath.Match(f.name, subject)
+	return matched
+}
+
+// pathGlobRegexp translates a glob of find -path into a regular expression. Unlike filepath.Match,
+// * and ? also match /, as fnmatch(3) does without FNM_PATHNAME.
+func pathGlobRegexp(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	// File names may contain newlines
+	b.WriteString("(?s)^")
+	for i := 0; i < len(glob); i++ {
+		switch glob[i] {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+			}
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case '[':
+			j := i + 1
+			if j < len(glob) && glob[j] == '!' {
+				j++
+			}
+			// A ] right after [ or [! belongs to the class
+			if j < len(glob) && glob[j] == ']' {
+				j++
+			}
+			end := strings.IndexByte(glob[j:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class")
+			}
+			class := glob[i+1 : j+end]
+			negate := strings.HasPrefix(class, "!")
+			class = strings.TrimPrefix(class, "!")
+			class = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`).Replace(class)
+			if negate {
+				class = "^" + class
+			}
+			b.WriteString("[" + class + "]")
+			i = j + end
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// pathDepth returns how many levels path is below root
+func pathDepth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
+
+// isPseudoFS reports whether path is the mount point of a kernel filesystem
+func isPseudoFS(path string) bool {
+	switch filepath.Clean(path) {
+	case "/proc", "/sys", "/dev":
+		return true
+	}
+	return false
+}
+
+// cmdDu prints the disk usage of files and directories
+func cmdDu() {
+	flags := newFlagSet("du")
+	human := flags.Bool("h", false, "print sizes in human readable format")
+	maxDepth := flags.Int("d", -1, "print the total for a directory only if it is at most this many levels deep")
+	summarize := flags.Bool("s", false, "display only a total for each argument")
+	_ = flags.Parse(os.Args[2:])
+
+	if *summarize {
+		*maxDepth = 0
+	}
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	exitCode := 0
+	for _, root := range paths {
+		sizes := make(map[string]int64)
+		var order []string
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "du: %s: %v\n", path, err)
+				exitCode = 1
+				return nil
+			}
+			if d.IsDir() {
+				if path != root && isPseudoFS(path) {
+					return filepath.SkipDir
+				}
+				order = append(order, path)
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			usage := diskUsage(info)
+			// Charge the size to the entry and all of its parents up to root
+			for p := path; ; p = filepath.Dir(p) {
+				sizes[p] += usage
+				if p == root || p == filepath.Dir(p) {
+					break
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "du: %v\n", err)
+			exitCode = 1
+			continue
+		}
+
+		if len(order) == 0 {
+			// A single file
+			fmt.Printf("%s\t%s\n", formatDuSize(sizes[root], *human), root)
+			continue
+		}
+		// Children are printed before their parents, like du
+		for i := len(order) - 1; i >= 0; i-- {
+			path := order[i]
+			if *maxDepth >= 0 && pathDepth(root, path) > *maxDepth {
+				continue
+			}
+			fmt.Printf("%s\t%s\n", formatDuSize(sizes[path], *human), path)
+		}
+	}
+	os.Exit(exitCode)
+}
+
+// diskUsage returns the space allocated for a file
+func diskUsage(info os.FileInfo) int64 {
+	if sys, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int64(sys.Blocks) * 512
+	}
+	return info.Size()
+}
+
+// formatDuSize formats a size in bytes as KiB like du, or human readable with -h
+func formatDuSize(size int64, human bool) string {
+	if !human {
+		return fmt.Sprintf("%d", (size+1023)/1024)
+	}
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d", size)
+	}
+	value := float64(size)
+	for _, suffix := range []string{"K", "M", "G", "T", "P"} {
+		value /= unit
+		if value < unit {
+			if value < 10 {
+				return fmt.Sprintf("%.1f%s", value, suffix)
+			}
+			return fmt.Sprintf("%.0f%s", value, suffix)
+		}
+	}
+	return fmt.Sprintf("%.0fE", value/unit)
+}
+
+// cmdRm removes files, and directories with their contents when -r is given
+func cmdRm() {
+	flags := newFlagSet("rm")
+	recursive := flags.Bool("r", false, "remove directories and their contents")
+	force := flags.Bool("f", false, "ignore nonexistent files")
+	_ = flags.Parse(os.Args[2:])
+
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "rm: missing operand")
+		os.Exit(1)
+	}
+
+	exitCode := 0
+	for _, path := range flags.Args() {
+		if err := removePath(path, *recursive, *force); err != nil {
+			fmt.Fprintf(os.Stderr, "rm: %v\n", err)
+			exitCode = 1
+		}
+	}
+	os.Exit(exitCode)
+}
+
+func removePath(path string, recursive, force bool) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if force && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if info.IsDir() {
+		if !recursive {
+			return fmt.Errorf("%s is a directory", path)
+		}
+		return os.RemoveAll(path)
+	}
+	return os.Remove(path)
+}
+
+// cmdMv renames a path, or moves paths into a directory, like mv(1)
+func cmdMv() {
+	args := os.Args[2:]
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, "mv: missing destination operand")
+		os.Exit(1)
+	}
+
+	sources, dest := args[:len(args)-1], args[len(args)-1]
+	info, err := os.Stat(dest)
+	intoDir := err == nil && info.IsDir()
+	if len(sources) > 1 && !intoDir {
+		fmt.Fprintf(os.Stderr, "mv: %s is not a directory\n", dest)
+		os.Exit(1)
+	}
+
+	exitCode := 0
+	for _, source := range sources {
+		target := dest
+		if intoDir {
+			target = filepath.Join(dest, filepath.Base(source))
+		}
+		if err := movePath(source, target); err != nil {
+			fmt.Fprintf(os.Stderr, "mv: %v\n", err)
+			exitCode = 1
+		}
+	}
+	os.Exit(exitCode)
+}
+
+// movePath renames source to target. Across filesystems, e.g. into a mounted volume,
+// the tree is copied and the source removed afterwards.
+func movePath(source, target string) error {
+	err := os.Rename(source, target)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := copyTree(source, target); err != nil {
+		return err
+	}
+	return os.RemoveAll(source)
+}
+
+// copyTree copies files, directories and symlinks keeping their permissions
+func copyTree(source, target string) error {
+	return filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(target, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.Mkdir(dst, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, dst)
+		case info.Mode().IsRegular():
+			return copyFile(path, dst, info.Mode().Perm())
+		default:
+			return fmt.Errorf("cannot move special file %s across filesystems", path)
+		}
+	})
+}
+
+func copyFile(source, target string, perm os.FileMode) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// cmdChmod changes file permissions with an octal or symbolic mode, like chmod(1)
+func cmdChmod() {
+	if len(os.Args) < 4 {
+		fmt.Fprintln(os.Stderr, "chmod: missing operand")
+		os.Exit(1)
+	}
+
+	mode := os.Args[2]
+	exitCode := 0
+	for _, path := range os.Args[3:] {
+		info, err := os.Stat(path)
+		if err == nil {
+			var perm os.FileMode
+			perm, err = parseFileMode(mode, info.Mode(), info.IsDir())
+			if err == nil {
+				err = os.Chmod(path, perm)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "chmod: %s: %v\n", path, err)
+			exitCode = 1
+		}
+	}
+	os.Exit(exitCode)
+}
+
+// parseFileMode applies an octal mode such as 0755 or a symbolic one such as u+x,go-w
+// to the current mode and returns the permission and special bits to set
+func parseFileMode(mode string, current os.FileMode, isDir bool) (os.FileMode, error) {
+	if n, err := strconv.ParseUint(mode, 8, 32); err == nil {
+		if n > 07777 {
+			return 0, fmt.Errorf("invalid mode %q", mode)
+		}
+		perm := os.FileMode(n & 0777)
+		if n&04000 != 0 {
+			perm |= os.ModeSetuid
+		}
+		if n&02000 != 0 {
+			perm |= os.ModeSetgid
+		}
+		if n&01000 != 0 {
+			perm |= os.ModeSticky
+		}
+		return perm, nil
+	}
+
+	result := current & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+	for _, clause := range strings.Split(mode, ",") {
+		who := strings.IndexAny(clause, "+-=")
+		if who < 0 || strings.Trim(clause[:who], "ugoa") != "" {
+			return 0, fmt.Errorf("invalid mode %q", mode)
+		}
+		var mask os.FileMode
+		for _, c := range clause[:who] {
+			switch c {
+			case 'u':
+				mask |= 0700 | os.ModeSetuid
+			case 'g':
+				mask |= 0070 | os.ModeSetgid
+			case 'o':
+				mask |= 0007 | os.ModeSticky
+			case 'a':
+				mask |= 0777 | os.ModeSetuid | os.ModeSetgid | os.ModeSticky
+			}
+		}
+		if mask == 0 {
+			mask = 0777 | os.ModeSetuid | os.ModeSetgid | os.ModeSticky
+		}
+
+		rest := clause[who:]
+		for rest != "" {
+			op := rest[0]
+			end := strings.IndexAny(rest[1:], "+-=")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			var bits os.FileMode
+			for _, c := range rest[1 : end+1] {
+				switch c {
+				case 'r':
+					bits |= 0444
+				case 'w':
+					bits |= 0222
+				case 'x':
+					bits |= 0111
+				case 'X':
+					if isDir || result&0111 != 0 {
+						bits |= 0111
+					}
+				case 's':
+					bits |= os.ModeSetuid | os.ModeSetgid
+				case 't':
+					bits |= os.ModeSticky
+				default:
+					return 0, fmt.Errorf("invalid mode %q", mode)
+				}
+			}
+			bits &= mask
+			switch op {
+			case '+':
+				result |= bits
+			case '-':
+				result &^= bits
+			case '=':
+				result = result&^mask | bits
+			}
+			rest = rest[end+1:]
+		}
+	}
+	return result, nil
+}
+
+// cmdChown changes the owner and group of files, like chown(1).
+// Names are looked up in /etc/passwd and /etc/group of the container.
+func cmdChown() {
+	if len(os.Args) < 4 {
+		fmt.Fprintln(os.Stderr, "chown: missing operand")
+		os.Exit(1)
+	}
+
+	uid, gid, err := parseOwner(os.Args[2], readIDNames("/etc/passwd"), readIDNames("/etc/group"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "chown: %v\n", err)
+		os.Exit(1)
+	}
+
+	exitCode := 0
+	for _, path := range os.Args[3:] {
+		if err := os.Lchown(path, uid, gid); err != nil {
+			fmt.Fprintf(os.Stderr, "chown: %v\n", err)
+			exitCode = 1
+		}
+	}
+	os.Exit(exitCode)
+}
+
+// parseOwner parses OWNER[:GROUP], where both may be names or numeric IDs.
+// An omitted part is returned as -1, which leaves it unchanged.
+func parseOwner(spec string, users, groups map[uint32]string) (uid, gid int, err error) {
+	owner, group, _ := strings.Cut(spec, ":")
+	if owner == "" && group == "" {
+		return 0, 0, fmt.Errorf("invalid owner %q", spec)
+	}
+	if uid, err = lookupID(owner, users); err != nil {
+		return 0, 0, fmt.Errorf("invalid user: %w", err)
+	}
+	if gid, err = lookupID(group, groups); err != nil {
+		return 0, 0, fmt.Errorf("invalid group: %w", err)
+	}
+	return uid, gid, nil
+}
+
+func lookupID(name string, names map[uint32]string) (int, error) {
+	if name == "" {
+		return -1, nil
+	}
+	if id, err := strconv.ParseUint(name, 10, 32); err == nil {
+		return int(id), nil
+	}
+	for id, n := range names {
+		if n == name {
+			return int(id), nil
+		}
+	}
+	return 0, fmt.Errorf("%q not found", name)
+}
+
+// cmdMkdir creates directories, like mkdir(1)
+func cmdMkdir() {
+	flags := newFlagSet("mkdir")
+	parents := flags.Bool("p", false, "create parent directories, no error if existing")
+	_ = flags.Parse(os.Args[2:])
+
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "mkdir: missing operand")
+		os.Exit(1)
+	}
+
+	exitCode := 0
+	for _, path := range flags.Args() {
+		var err error
+		if *parents {
+			err = os.MkdirAll(path, 0755)
+		} else {
+			err = os.Mkdir(path, 0755)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "mkdir: %v\n", err)
+			exitCode = 1
+		}
+	}
+	os.Exit(exitCode)
+}
+
+// cmdTouch creates empty files or updates the times of existing ones, like touch(1)
+func cmdTouch() {
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, "touch: missing file operand")
+		os.Exit(1)
+	}
+
+	exitCode := 0
+	for _, path := range os.Args[2:] {
+		if err := touchFile(path, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "touch: %v\n", err)
+			exitCode = 1
+		}
+	}
+	os.Exit(exitCode)
+}
+
+func touchFile(path string, now time.Time) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(path, now, now)
+}
+
+// cmdSha256 prints the SHA-256 checksum of files, like sha256sum
+func cmdSha256() {
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, "sha256: missing file operand")
+		os.Exit(1)
+	}
+
+	exitCode := 0
+	for _, path := range os.Args[2:] {
+		sum, err := sha256File(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "sha256: %s: %v\n", path, err)
+			exitCode = 1
+			continue
+		}
+		fmt.Printf("%s  %s\n", sum, path)
+	}
+	os.Exit(exitCode)
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cmdGrep searches files for lines matching a regular expression
+func cmdGrep() {
+	flags := newFlagSet("grep")
+	recursive := flags.Bool("r", false, "search directories recursively")
+	ignoreCase := flags.Bool("i", false, "ignore case")
+	lineNumbers := flags.Bool("n", false, "print line numbers")
+	filesOnly := flags.Bool("l", false, "print only the names of matching files")
+	maxCount := flags.Int("m", 0, "stop after this many matches in total (0 for no limit)")
+	_ = flags.Parse(os.Args[2:])
+
+	if flags.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "grep: missing pattern")
+		os.Exit(2)
+	}
+	expr := flags.Arg(0)
+	if *ignoreCase {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "grep: invalid pattern: %v\n", err)
+		os.Exit(2)
+	}
+
+	paths := flags.Args()[1:]
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	g := &grepper{re: re, lineNumbers: *lineNumbers, filesOnly: *filesOnly, maxCount: *maxCount}
+	exitCode := 1
+	for _, root := range paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "grep: %s: %v\n", root, err)
+			continue
+		}
+		if info.IsDir() && !*recursive {
+			fmt.Fprintf(os.Stderr, "grep: %s: Is a directory\n", root)
+			continue
+		}
+
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "grep: %s: %v\n", path, err)
+				return nil
+			}
+			if d.IsDir() {
+				if path != root && isPseudoFS(path) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			if err := g.grepFile(path); err != nil {
+				if errors.Is(err, errMaxCount) {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "grep: %s: %v\n", path, err)
+			}
+			return nil
+		})
+		if g.matches > 0 {
+			exitCode = 0
+		}
+		if errors.Is(err, errMaxCount) {
+			break
+		}
+	}
+	os.Exit(exitCode)
+}
+
+// errMaxCount stops the search once -m matches were printed
+var errMaxCount = errors.New("maximum number of matches reached")
+
+type grepper struct {
+	re          *regexp.Regexp
+	lineNumbers bool
+	filesOnly   bool
+	maxCount    int
+	matches     int
+}
+
+// grepFile prints the matching lines of a single file. Binary files are skipped.
+func (g *grepper) grepFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	reader := bufio.NewReader(f)
+	head, _ := reader.Peek(8000)
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if !g.re.MatchString(line) {
+			continue
+		}
+
+		g.matches++
+		switch {
+		case g.filesOnly:
+			fmt.Println(path)
+		case g.lineNumbers:
+			fmt.Printf("%s:%d:%s\n", path, lineNo, line)
+		default:
+			fmt.Printf("%s:%s\n", path, line)
+		}
+		if g.maxCount > 0 && g.matches >= g.maxCount {
+			return errMaxCount
+		}
+		if g.filesOnly {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// cmdTail prints the last lines of a file and optionally follows it
+func cmdTail() {
+	flags := newFlagSet("tail")
+	lines := flags.Int("n", 10, "number of lines to print")
+	follow := flags.Bool("f", false, "output appended data as the file grows")
+	followName := flags.Bool("F", false, "like -f, but follow a new file at the path after log rotation")
+	_ = flags.Parse(os.Args[2:])
+
+	if flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "tail: exactly one file is required")
+		os.Exit(1)
+	}
+	path := flags.Arg(0)
+
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tail: %s: %v\n", path, err)
+		os.Exit(1)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	offset, err := printLastLines(f, *lines)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tail: %s: %v\n", path, err)
+		os.Exit(1)
+	}
+	if !*follow && !*followName {
+		return
+	}
+
+	if err := followFile(os.Stdout, f, path, offset, *followName, 500*time.Millisecond); err != nil {
+		fmt.Fprintf(os.Stderr, "tail: %s: %v\n", path, err)
+		os.Exit(1)
+	}
+}
+
+// printLastLines writes the last n lines of f and returns the offset of the end of the file
+func printLastLines(f *os.File, n int) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+
+	// Read backwards in chunks until enough newlines were seen
+	const chunkSize = 64 * 1024
+	start := size
+	newlines := 0
+	buf := make([]byte, chunkSize)
+	for start > 0 && newlines <= n {
+		readSize := int64(chunkSize)
+		if start < readSize {
+			readSize = start
+		}
+		start -= readSize
+		if _, err := f.ReadAt(buf[:readSize], start); err != nil && err != io.EOF {
+			return 0, err
+		}
+		for i := readSize - 1; i >= 0; i-- {
+			if buf[i] != '\n' {
+				continue
+			}
+			// A trailing newline terminates the last line, it does not start a new one
+			if start+i == size-1 {
+				continue
+			}
+			newlines++
+			if newlines == n {
+				start += i + 1
+				break
+			}
+		}
+		if newlines == n {
+			break
+		}
+	}
+	if n == 0 {
+		start = size
+	}
+
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if _, err := io.CopyN(os.Stdout, f, size-start); err != nil && err != io.EOF {
+		return 0, err
+	}
+	return size, nil
+}
+
+// followFile polls f for appended data. A file that shrinks was truncated and is read from the start.
+// With reopen, a different file appearing at path, e.g. after log rotation, is followed from its start
+// once the rest of the old file was read.
+// It returns when writing to w fails, i.e. dcv stopped listening.
+func followFile(w io.Writer, f *os.File, path string, offset int64, reopen bool, interval time.Duration) error {
+	buf := make([]byte, 32*1024)
+	for {
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		if info.Size() < offset {
+			fmt.Fprintln(os.Stderr, "tail: file truncated")
+			offset = 0
+		}
+
+		for info.Size() > offset {
+			n, err := f.ReadAt(buf, offset)
+			if n > 0 {
+				if _, werr := w.Write(buf[:n]); werr != nil {
+					return nil
+				}
+				offset += int64(n)
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if reopen {
+			if pathInfo, err := os.Stat(path); err == nil && !os.SameFile(info, pathInfo) {
+				if newFile, err := os.Open(path); err == nil {
+					fmt.Fprintf(os.Stderr, "tail: %s has been replaced; following the new file\n", path)
+					_ = f.Close()
+					f = newFile
+					offset = 0
+					continue
+				}
+			}
+		}
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// followOutput collects what followFile writes and makes it return once closed
+type followOutput struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	closed bool
+}
+
+func (o *followOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return 0, errors.New("closed")
+	}
+	return o.buf.Write(p)
+}
+
+func (o *followOutput) String() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.buf.String()
+}
+
+func TestFollowFileRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(path, []byte("a\n"), 0600))
+	f, err := os.Open(path)
+	require.NoError(t, err)
+
+	output := &followOutput{}
+	done := make(chan error, 1)
+	go func() {
+		done <- followFile(output, f, path, 2, true, 10*time.Millisecond)
+	}()
+
+	appendFile := func(path, data string) {
+		t.Helper()
+		w, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+		require.NoError(t, err)
+		_, err = w.WriteString(data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	}
+
+	appendFile(path, "b\n")
+	assert.Eventually(t, func() bool { return output.String() == "b\n" }, time.Second, 5*time.Millisecond)
+
+	// Rotate: the rest of the old file is read before the new file is followed
+	require.NoError(t, os.Rename(path, path+".1"))
+	appendFile(path+".1", "c\n")
+	appendFile(path, "d\n")
+	assert.Eventually(t, func() bool { return output.String() == "b\nc\nd\n" }, time.Second, 5*time.Millisecond)
+
+	output.mu.Lock()
+	output.closed = true
+	output.mu.Unlock()
+	appendFile(path, "e\n")
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("followFile did not return after the output was closed")
+	}
+}
+
+func TestRemovePath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	sub := filepath.Join(dir, "sub")
+	require.NoError(t, os.WriteFile(file, []byte("x"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(sub, "nested"), 0700))
+
+	assert.ErrorContains(t, removePath(sub, false, false), "is a directory")
+	assert.DirExists(t, sub)
+	assert.NoError(t, removePath(sub, true, false))
+	assert.NoDirExists(t, sub)
+
+	assert.NoError(t, removePath(file, false, false))
+	assert.NoFileExists(t, file)
+	assert.Error(t, removePath(file, false, false))
+	assert.NoError(t, removePath(file, false, true), "-f ignores missing files")
+}
+
+func TestParseFileMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		current os.FileMode
+		isDir   bool
+		want    os.FileMode
+	}{
+		{"755", 0644, false, 0755},
+		{"4750", 0644, false, 0750 | os.ModeSetuid},
+		{"u+x", 0644, false, 0744},
+		{"go-w", 0666, false, 0644},
+		{"a=r", 0755, false, 0444},
+		{"+x", 0600, false, 0711},
+		{"u=rwx,g=rx,o=", 0600, false, 0750},
+		{"g+s", 0755, true, 0755 | os.ModeSetgid},
+		{"+t", 0777, true, 0777 | os.ModeSticky},
+		{"a+X", 0644, false, 0644},
+		{"a+X", 0644, true, 0755},
+		{"u-w+x", 0644, false, 0544},
+	}
+	for _, tt := range tests {
+		got, err := parseFileMode(tt.mode, tt.current, tt.isDir)
+		require.NoError(t, err, tt.mode)
+		assert.Equal(t, tt.want, got, tt.mode)
+	}
+
+	for _, mode := range []string{"u+q", "z+x", "rwx", "17777"} {
+		_, err := parseFileMode(mode, 0644, false)
+		assert.Error(t, err, mode)
+	}
+}
+
+func TestParseOwner(t *testing.T) {
+	users := map[uint32]string{0: "root", 1000: "app"}
+	groups := map[uint32]string{0: "root", 50: "staff"}
+
+	uid, gid, err := parseOwner("app:staff", users, groups)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1000, 50}, []int{uid, gid})
+
+	uid, gid, err = parseOwner("app", users, groups)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1000, -1}, []int{uid, gid}, "the group is kept")
+
+	uid, gid, err = parseOwner(":50", users, groups)
+	require.NoError(t, err)
+	assert.Equal(t, []int{-1, 50}, []int{uid, gid}, "the owner is kept")
+
+	uid, gid, err = parseOwner("33:33", users, groups)
+	require.NoError(t, err)
+	assert.Equal(t, []int{33, 33}, []int{uid, gid}, "numeric IDs need no entry")
+
+	_, _, err = parseOwner("nobody", users, groups)
+	assert.ErrorContains(t, err, "invalid user")
+	_, _, err = parseOwner("", users, groups)
+	assert.Error(t, err)
+}
+
+func TestCopyTree(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "src")
+	require.NoError(t, os.MkdirAll(filepath.Join(source, "sub"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "sub", "run.sh"), []byte("echo"), 0755))
+	require.NoError(t, os.Symlink("sub/run.sh", filepath.Join(source, "run")))
+
+	target := filepath.Join(dir, "dst")
+	require.NoError(t, copyTree(source, target))
+	data, err := os.ReadFile(filepath.Join(target, "sub", "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "echo", string(data))
+	info, err := os.Stat(filepath.Join(target, "sub", "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	link, err := os.Readlink(filepath.Join(target, "run"))
+	require.NoError(t, err)
+	assert.Equal(t, "sub/run.sh", link)
+
+	assert.Error(t, copyTree(source, target), "existing files are not overwritten")
+}
+
+func TestTouchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new.txt")
+	past := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, touchFile(path, past))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), info.Size())
+	assert.True(t, info.ModTime().Equal(past))
+
+	require.NoError(t, os.WriteFile(path, []byte("keep"), 0644))
+	require.NoError(t, touchFile(path, past))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "keep", string(data), "existing files are not truncated")
+}
+
+func TestResolvePath(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "release"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "release", "app.conf"), nil, 0o644))
+	require.NoError(t, os.Symlink("release", filepath.Join(dir, "current")))
+	require.NoError(t, os.Symlink("current/app.conf", filepath.Join(dir, "conf")))
+	require.NoError(t, os.Symlink("missing", filepath.Join(dir, "broken")))
+
+	kind, real := resolvePath(filepath.Join(dir, "current"))
+	assert.Equal(t, "d", kind)
+	assert.Equal(t, filepath.Join(dir, "release"), real)
+
+	kind, real = resolvePath(filepath.Join(dir, "conf"))
+	assert.Equal(t, "f", kind)
+	assert.Equal(t, filepath.Join(dir, "release", "app.conf"), real)
+
+	kind, real = resolvePath(filepath.Join(dir, "broken"))
+	assert.Equal(t, "b", kind)
+	assert.Equal(t, "", real)
+}
+
+func TestPathGlobRegexp(t *testing.T) {
+	tests := []struct {
+		glob    string
+		path    string
+		matched bool
+	}{
+		{"/etc/*.conf", "/etc/nginx.conf", true},
+		{"/etc/*.conf", "/etc/nginx/nginx.conf", true},
+		{"/etc/*.conf", "/etc/nginx.conf.bak", false},
+		{"/var/log/app?.log", "/var/log/app1.log", true},
+		{"/data/[ab]*", "/data/b.txt", true},
+		{"/data/[!ab]*", "/data/b.txt", false},
+		{"/data/[]x]", "/data/]", true},
+		{`/data/\*`, "/data/*", true},
+		{`/data/\*`, "/data/a", false},
+		{"/data/日本*", "/data/日本語.txt", true},
+		{"/data/a.b", "/data/axb", false},
+	}
+	for _, tt := range tests {
+		re, err := pathGlobRegexp(tt.glob)
+		require.NoError(t, err, tt.glob)
+		assert.Equal(t, tt.matched, re.MatchString(tt.path), "%s %s", tt.glob, tt.path)
+	}
+
+	_, err := pathGlobRegexp("/data/[ab")
+	assert.Error(t, err)
+}
+
+func TestFindFilter(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "conf", "nginx"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "conf", "nginx", "site.conf"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "conf", "app.yaml"), nil, 0644))
+
+	find := func(filter *findFilter) []string {
+		var found []string
+		require.NoError(t, filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			require.NoError(t, err)
+			if filter.matches(dir, path, d) {
+				rel, _ := filepath.Rel(dir, path)
+				found = append(found, rel)
+			}
+			return nil
+		}))
+		return found
+	}
+
+	filter, err := newFindFilter("", filepath.Join(dir, "conf", "*.conf"), "", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"conf/nginx/site.conf"}, find(filter))
+
+	filter, err = newFindFilter("", "", `(?i)\.YA?ML$`, "f")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"conf/app.yaml"}, find(filter))
+
+	filter, err = newFindFilter("", "", "", "d")
+	require.NoError(t, err)
+	assert.Equal(t, []string{".", "conf", "conf/nginx"}, find(filter))
+
+	_, err = newFindFilter("", "", "(", "")
+	assert.Error(t, err)
+}
@@ -1,17 +1,38 @@
 // dcv-helper: Minimal static binary for file operations in containers
-// Build with: CGO_ENABLED=0 go build -ldflags="-s -w" -o dcv-helper main.go
+// Build with: CGO_ENABLED=0 go build -ldflags="-s -w" -o dcv-helper .
 package main
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 )
 
-const version = "1.0.0"
+const version = "1.9.0"
+
+// protocolVersion is the version of the --json output format and the command set.
+// dcv re-injects the helper when the injected one speaks an older protocol.
+// Bump it whenever the JSON output changes or a command is added.
+//
+// 2: the debugging, file management, search and follow commands, find -path and -regex, and -print-pid
+const protocolVersion = 2
 
 func main() {
+	// -print-pid prints the PID before running the command, so that dcv can stop a command
+	// that keeps running in the container when its docker exec is killed
+	if len(os.Args) > 1 && os.Args[1] == "-print-pid" {
+		fmt.Println(os.Getpid())
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+
 	if len(os.Args) < 2 {
 		printUsage()
 		os.Exit(1)
@@ -22,8 +43,46 @@ func main() {
 		cmdLs()
 	case "cat":
 		cmdCat()
+	case "size":
+		cmdSize()
+	case "kill":
+		cmdKill()
+	case "sleep":
+		cmdSleep()
+	case "rm":
+		cmdRm()
+	case "mv":
+		cmdMv()
+	case "chmod":
+		cmdChmod()
+	case "chown":
+		cmdChown()
+	case "mkdir":
+		cmdMkdir()
+	case "touch":
+		cmdTouch()
+	case "realpath":
+		cmdRealpath()
+	case "stat":
+		cmdStat()
+	case "find":
+		cmdFind()
+	case "du":
+		cmdDu()
+	case "tail":
+		cmdTail()
+	case "grep":
+		cmdGrep()
+	case "sha256":
+		cmdSha256()
+	case "env":
+		cmdEnv()
+	case "ps":
+		cmdPs()
+	case "netstat":
+		cmdNetstat()
 	case "version":
-		fmt.Println("dcv-helper", version)
+		cmdVersion()
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", os.Args[1])
 		printUsage()
@@ -32,18 +91,89 @@ func main() {
 }
 
 func printUsage() {
-	fmt.Fprintln(os.Stderr, "Usage: dcv-helper <command> [args...]")
+	fmt.Fprintln(os.Stderr, "Usage: dcv-helper [-print-pid] <command> [args...]")
 	fmt.Fprintln(os.Stderr, "Commands:")
-	fmt.Fprintln(os.Stderr, "  ls [path]    - List directory contents")
-	fmt.Fprintln(os.Stderr, "  cat <file>   - Display file contents")
-	fmt.Fprintln(os.Stderr, "  version      - Show version")
+	fmt.Fprintln(os.Stderr, "  ls [--json] [path] - List directory contents")
+	fmt.Fprintln(os.Stderr, "  cat [-offset N] [-length N] <file>... - Display file contents")
+	fmt.Fprintln(os.Stderr, "  size <file>...     - Print file sizes in bytes, following symlinks")
+	fmt.Fprintln(os.Stderr, "  kill -SIG <pid>... - Send a signal to processes")
+	fmt.Fprintln(os.Stderr, "  sleep <seconds>    - Wait, e.g. as the process of a container that only runs the helper")
+	fmt.Fprintln(os.Stderr, "  rm [-r] [-f] <path>... - Remove files and directories")
+	fmt.Fprintln(os.Stderr, "  mv <source>... <dest> - Rename or move files and directories")
+	fmt.Fprintln(os.Stderr, "  chmod <mode> <path>... - Change permissions (octal or symbolic)")
+	fmt.Fprintln(os.Stderr, "  chown <owner>[:<group>] <path>... - Change owner and group")
+	fmt.Fprintln(os.Stderr, "  mkdir [-p] <dir>... - Create directories")
+	fmt.Fprintln(os.Stderr, "  touch <file>...    - Create empty files or update their times")
+	fmt.Fprintln(os.Stderr, "  realpath <path>... - Print the type and real path of paths, following symlinks")
+	fmt.Fprintln(os.Stderr, "  stat <file>...     - Display file status")
+	fmt.Fprintln(os.Stderr, "  find [-name GLOB] [-path GLOB] [-regex RE] [-maxdepth N] [-type f|d] [dir] - Search for files")
+	fmt.Fprintln(os.Stderr, "  du [-h] [-s] [-d N] [path]... - Estimate disk usage")
+	fmt.Fprintln(os.Stderr, "  tail [-n N] [-f|-F] <file> - Print the last lines of a file")
+	fmt.Fprintln(os.Stderr, "  grep [-r] [-i] [-n] [-l] [-m N] PATTERN [path]... - Search file contents")
+	fmt.Fprintln(os.Stderr, "  sha256 <file>...   - Print SHA-256 checksums")
+	fmt.Fprintln(os.Stderr, "  env [pid]          - Print the environment of a process (default: 1)")
+	fmt.Fprintln(os.Stderr, "  ps                 - List processes")
+	fmt.Fprintln(os.Stderr, "  netstat            - List sockets with their processes")
+	fmt.Fprintln(os.Stderr, "  version [--json] - Show version")
+}
+
+// versionInfo is the output of `version --json`
+type versionInfo struct {
+	Version  string `json:"version"`
+	Protocol int    `json:"protocol"`
+}
+
+// cmdVersion prints the helper version, as JSON when --json is given
+func cmdVersion() {
+	if len(os.Args) > 2 && os.Args[2] == "--json" {
+		writeJSON(versionInfo{Version: version, Protocol: protocolVersion})
+		return
+	}
+	fmt.Println("dcv-helper", version)
+}
+
+// lsOutput is the output of `ls --json`
+type lsOutput struct {
+	Protocol int         `json:"protocol"`
+	Path     string      `json:"path"`
+	Entries  []fileEntry `json:"entries"`
+}
+
+// fileEntry describes a single file in `ls --json` output
+type fileEntry struct {
+	Name string `json:"name"`
+	// Mode is the ls -l style mode string, e.g. "drwxr-xr-x"
+	Mode       string    `json:"mode"`
+	Perm       uint32    `json:"perm"`
+	Size       int64     `json:"size"`
+	MTime      time.Time `json:"mtime"`
+	UID        uint32    `json:"uid"`
+	GID        uint32    `json:"gid"`
+	User       string    `json:"user,omitempty"`
+	Group      string    `json:"group,omitempty"`
+	Nlink      uint64    `json:"nlink"`
+	Inode      uint64    `json:"inode"`
+	LinkTarget string    `json:"link_target,omitempty"`
+	IsDir      bool      `json:"is_dir"`
 }
 
 // cmdLs implements a simple ls command
 func cmdLs() {
+	args := os.Args[2:]
+	jsonOutput := false
+	if len(args) > 0 && args[0] == "--json" {
+		jsonOutput = true
+		args = args[1:]
+	}
+
 	path := "."
-	if len(os.Args) > 2 {
-		path = os.Args[2]
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	if jsonOutput {
+		cmdLsJSON(path)
+		return
 	}
 
 	info, err := os.Stat(path)
@@ -74,24 +204,125 @@ func cmdLs() {
 	}
 }
 
-// printFileInfo prints file information in ls -la compatible format
-func printFileInfo(info os.FileInfo) {
+// cmdLsJSON lists a directory (or a single file) as JSON
+func cmdLsJSON(path string) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ls: %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	users := readIDNames("/etc/passwd")
+	groups := readIDNames("/etc/group")
+	output := lsOutput{Protocol: protocolVersion, Path: path, Entries: []fileEntry{}}
+
+	// Follow a symlink given as the path itself, like ls does
+	if info.Mode()&os.ModeSymlink != 0 {
+		if target, err := os.Stat(path); err == nil {
+			info = target
+		}
+	}
+
+	if !info.IsDir() {
+		output.Entries = append(output.Entries, newFileEntry(filepath.Dir(path), info, users, groups))
+		writeJSON(output)
+		return
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ls: %s: %v\n", path, err)
+		os.Exit(1)
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		output.Entries = append(output.Entries, newFileEntry(path, info, users, groups))
+	}
+	writeJSON(output)
+}
+
+// newFileEntry builds the JSON description of a file in dir
+func newFileEntry(dir string, info os.FileInfo, users, groups map[uint32]string) fileEntry {
 	mode := info.Mode()
-	typeChar := '-'
+	entry := fileEntry{
+		Name:  info.Name(),
+		Mode:  formatModeString(mode),
+		Perm:  uint32(mode.Perm()),
+		Size:  info.Size(),
+		MTime: info.ModTime(),
+		IsDir: mode.IsDir(),
+		Nlink: 1,
+	}
+	if mode&os.ModeSetuid != 0 {
+		entry.Perm |= 0o4000
+	}
+	if mode&os.ModeSetgid != 0 {
+		entry.Perm |= 0o2000
+	}
+	if mode&os.ModeSticky != 0 {
+		entry.Perm |= 0o1000
+	}
 
-	if mode.IsDir() {
-		typeChar = 'd'
-	} else if mode&os.ModeSymlink != 0 {
-		typeChar = 'l'
-	} else if mode&os.ModeDevice != 0 {
-		typeChar = 'b'
-	} else if mode&os.ModeCharDevice != 0 {
-		typeChar = 'c'
-	} else if mode&os.ModeNamedPipe != 0 {
-		typeChar = 'p'
-	} else if mode&os.ModeSocket != 0 {
-		typeChar = 's'
+	if sys, ok := info.Sys().(*syscall.Stat_t); ok {
+		entry.UID = sys.Uid
+		entry.GID = sys.Gid
+		entry.Nlink = uint64(sys.Nlink)
+		entry.Inode = sys.Ino
+	}
+	entry.User = users[entry.UID]
+	entry.Group = groups[entry.GID]
+
+	if mode&os.ModeSymlink != 0 {
+		if target, err := os.Readlink(filepath.Join(dir, info.Name())); err == nil {
+			entry.LinkTarget = target
+		}
+	}
+	return entry
+}
+
+// readIDNames reads name:x:id lines from /etc/passwd or /etc/group.
+// Missing files are fine; the numeric IDs are used then.
+func readIDNames(path string) map[uint32]string {
+	names := make(map[uint32]string)
+	f, err := os.Open(path)
+	if err != nil {
+		return names
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) < 3 {
+			continue
+		}
+		id, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			continue
+		}
+		if _, exists := names[uint32(id)]; !exists {
+			names[uint32(id)] = fields[0]
+		}
+	}
+	return names
+}
+
+// writeJSON writes v to stdout as a single JSON document
+func writeJSON(v any) {
+	if err := json.NewEncoder(os.Stdout).Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write JSON: %v\n", err)
+		os.Exit(1)
 	}
+}
+
+// printFileInfo prints file information in ls -la compatible format
+func printFileInfo(info os.FileInfo) {
+	mode := info.Mode()
 
 	// Get uid/gid if available
 	uid := "0"
@@ -107,20 +338,38 @@ func printFileInfo(info os.FileInfo) {
 	// Format similar to ls -la:
 	// drwxr-xr-x  2 uid gid 4096 Dec 15 10:30 dirname
 	// Using numeric uid/gid since we can't resolve to names without /etc/passwd
-	fmt.Printf("%c%s %3s %5s %5s %10d %s %s %s %s\n",
-		typeChar,
-		formatMode(mode),
+	fmt.Printf("%s %3s %5s %5s %10d %s %s %s %s\n",
+		formatModeString(mode),
 		nlinks,
 		uid,
 		gid,
 		info.Size(),
-		"Jan",   // Placeholder month
-		"1",     // Placeholder day
-		"00:00", // Placeholder time
+		info.ModTime().Format("Jan"),
+		info.ModTime().Format("2"),
+		info.ModTime().Format("15:04"),
 		info.Name(),
 	)
 }
 
+// formatModeString formats file mode like ls -l, e.g. drwxr-xr-x
+func formatModeString(mode os.FileMode) string {
+	typeChar := '-'
+	if mode.IsDir() {
+		typeChar = 'd'
+	} else if mode&os.ModeSymlink != 0 {
+		typeChar = 'l'
+	} else if mode&os.ModeCharDevice != 0 {
+		typeChar = 'c'
+	} else if mode&os.ModeDevice != 0 {
+		typeChar = 'b'
+	} else if mode&os.ModeNamedPipe != 0 {
+		typeChar = 'p'
+	} else if mode&os.ModeSocket != 0 {
+		typeChar = 's'
+	}
+	return string(typeChar) + formatMode(mode)
+}
+
 // formatMode formats file mode as rwxrwxrwx
 func formatMode(mode os.FileMode) string {
 	const str = "rwxrwxrwx"
@@ -139,23 +388,28 @@ func formatMode(mode os.FileMode) string {
 
 // cmdCat implements a simple cat command
 func cmdCat() {
-	if len(os.Args) < 3 {
+	flags := newFlagSet("cat")
+	offset := flags.Int64("offset", 0, "byte offset to start reading at")
+	length := flags.Int64("length", -1, "maximum number of bytes to print, -1 for all")
+	_ = flags.Parse(os.Args[2:])
+
+	if flags.NArg() == 0 {
 		fmt.Fprintln(os.Stderr, "cat: missing file operand")
 		os.Exit(1)
 	}
 
 	exitCode := 0
-	for i := 2; i < len(os.Args); i++ {
-		if err := catFile(os.Args[i]); err != nil {
-			fmt.Fprintf(os.Stderr, "cat: %s: %v\n", os.Args[i], err)
+	for _, path := range flags.Args() {
+		if err := catFile(path, *offset, *length); err != nil {
+			fmt.Fprintf(os.Stderr, "cat: %s: %v\n", path, err)
 			exitCode = 1
 		}
 	}
 	os.Exit(exitCode)
 }
 
-// catFile displays contents of a single file
-func catFile(path string) error {
+// catFile writes length bytes of a file from offset, or the rest of the file when length is negative
+func catFile(path string, offset, length int64) error {
 	file, err := os.Open(path)
 	if err != nil {
 		return err
@@ -164,6 +418,115 @@ func catFile(path string) error {
 		_ = file.Close()
 	}()
 
-	_, err = io.Copy(os.Stdout, file)
+	var r io.Reader = file
+	if offset > 0 {
+		r = io.NewSectionReader(file, offset, 1<<62)
+	}
+	if length >= 0 {
+		r = io.LimitReader(r, length)
+	}
+	_, err = io.Copy(os.Stdout, r)
 	return err
 }
+
+// cmdSize prints the size of each file, following symlinks
+func cmdSize() {
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, "size: missing file operand")
+		os.Exit(1)
+	}
+
+	exitCode := 0
+	for _, path := range os.Args[2:] {
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "size: %s: %v\n", path, err)
+			exitCode = 1
+			continue
+		}
+		fmt.Println(info.Size())
+	}
+	os.Exit(exitCode)
+}
+
+// signals maps the names accepted by cmdKill to signal numbers
+var signals = map[string]syscall.Signal{
+	"HUP":  syscall.SIGHUP,
+	"INT":  syscall.SIGINT,
+	"QUIT": syscall.SIGQUIT,
+	"KILL": syscall.SIGKILL,
+	"USR1": syscall.SIGUSR1,
+	"USR2": syscall.SIGUSR2,
+	"TERM": syscall.SIGTERM,
+	"CONT": syscall.SIGCONT,
+	"STOP": syscall.SIGSTOP,
+}
+
+// cmdKill implements a minimal kill command: kill [-SIGNAL] pid...
+func cmdKill() {
+	args := os.Args[2:]
+	sig := syscall.SIGTERM
+
+	if len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		parsed, err := parseSignal(args[0][1:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "kill: %v\n", err)
+			os.Exit(1)
+		}
+		sig = parsed
+		args = args[1:]
+	}
+
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "kill: missing pid operand")
+		os.Exit(1)
+	}
+
+	exitCode := 0
+	for _, arg := range args {
+		pid, err := strconv.Atoi(arg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "kill: invalid pid: %s\n", arg)
+			exitCode = 1
+			continue
+		}
+		if err := syscall.Kill(pid, sig); err != nil {
+			fmt.Fprintf(os.Stderr, "kill: %d: %v\n", pid, err)
+			exitCode = 1
+		}
+	}
+	os.Exit(exitCode)
+}
+
+// cmdSleep waits for the given number of seconds or until it is terminated.
+// As PID 1 of a container it must handle SIGTERM itself, the kernel ignores it otherwise.
+func cmdSleep() {
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "sleep: exactly one duration in seconds is required")
+		os.Exit(1)
+	}
+	seconds, err := strconv.Atoi(os.Args[2])
+	if err != nil || seconds < 0 {
+		fmt.Fprintf(os.Stderr, "sleep: invalid duration: %s\n", os.Args[2])
+		os.Exit(1)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	select {
+	case <-time.After(time.Duration(seconds) * time.Second):
+	case <-signals:
+	}
+}
+
+// parseSignal accepts a signal number or name, with or without the SIG prefix
+func parseSignal(s string) (syscall.Signal, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return syscall.Signal(n), nil
+	}
+	name := strings.TrimPrefix(strings.ToUpper(s), "SIG")
+	if sig, ok := signals[name]; ok {
+		return sig, nil
+	}
+	return 0, fmt.Errorf("unknown signal: %s", s)
+}
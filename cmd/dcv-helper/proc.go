@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// cmdEnv prints the environment of a process, PID 1 by default
+func cmdEnv() {
+	pid := "1"
+	if len(os.Args) > 2 {
+		pid = os.Args[2]
+	}
+	if _, err := strconv.Atoi(pid); err != nil {
+		fmt.Fprintf(os.Stderr, "env: invalid pid: %s\n", pid)
+		os.Exit(1)
+	}
+
+	data, err := os.ReadFile(filepath.Join("/proc", pid, "environ"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "env: %v\n", err)
+		os.Exit(1)
+	}
+	for _, entry := range bytes.Split(data, []byte{0}) {
+		if len(entry) > 0 {
+			fmt.Println(string(entry))
+		}
+	}
+}
+
+// procInfo is a process read from /proc
+type procInfo struct {
+	PID     int
+	PPID    int
+	UID     uint32
+	State   string
+	RSSKB   int64
+	Command string
+}
+
+// cmdPs lists the processes of the container, like ps
+func cmdPs() {
+	procs, err := readProcesses("/proc")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ps: %v\n", err)
+		os.Exit(1)
+	}
+	users := readIDNames("/etc/passwd")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PID\tPPID\tUSER\tSTAT\tRSS\tCOMMAND")
+	for _, p := range procs {
+		user, ok := users[p.UID]
+		if !ok {
+			user = strconv.FormatUint(uint64(p.UID), 10)
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%d\t%s\n", p.PID, p.PPID, user, p.State, p.RSSKB, p.Command)
+	}
+	_ = w.Flush()
+}
+
+// readProcesses reads all processes below procRoot sorted by PID
+func readProcesses(procRoot string) ([]procInfo, error) {
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var procs []procInfo
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		// The process may exit while we read it
+		p, err := readProcess(filepath.Join(procRoot, entry.Name()), pid)
+		if err != nil {
+			continue
+		}
+		procs = append(procs, p)
+	}
+	sort.Slice(procs, func(i, j int) bool { return procs[i].PID < procs[j].PID })
+	return procs, nil
+}
+
+func readProcess(dir string, pid int) (procInfo, error) {
+	p := procInfo{PID: pid}
+
+	status, err := os.ReadFile(filepath.Join(dir, "status"))
+	if err != nil {
+		return p, err
+	}
+	name := ""
+	for _, line := range strings.Split(string(status), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		switch key {
+		case "Name":
+			name = fields[0]
+		case "State":
+			p.State = fields[0]
+		case "PPid":
+			p.PPID, _ = strconv.Atoi(fields[0])
+		case "Uid":
+			// Real, effective, saved and filesystem UID; ps shows the effective one
+			idx := 0
+			if len(fields) > 1 {
+				idx = 1
+			}
+			uid, _ := strconv.ParseUint(fields[idx], 10, 32)
+			p.UID = uint32(uid)
+		case "VmRSS":
+			p.RSSKB, _ = strconv.ParseInt(fields[0], 10, 64)
+		}
+	}
+
+	cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline"))
+	if err == nil {
+		p.Command = strings.TrimSpace(string(bytes.ReplaceAll(cmdline, []byte{0}, []byte{' '})))
+	}
+	if p.Command == "" {
+		// Kernel threads and zombies have no command line
+		p.Command = "[" + name + "]"
+	}
+	return p, nil
+}
+
+// socketEntry is a socket read from /proc/net/{tcp,tcp6,udp,udp6}
+type socketEntry struct {
+	Proto      string
+	LocalAddr  string
+	RemoteAddr string
+	State      string
+	Inode      uint64
+}
+
+// tcpStates maps the hex state of /proc/net/tcp to the netstat name
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED",
+	"02": "SYN_SENT",
+	"03": "SYN_RECV",
+	"04": "FIN_WAIT1",
+	"05": "FIN_WAIT2",
+	"06": "TIME_WAIT",
+	"07": "CLOSE",
+	"08": "CLOSE_WAIT",
+	"09": "LAST_ACK",
+	"0A": "LISTEN",
+	"0B": "CLOSING",
+}
+
+// cmdNetstat lists the sockets of the container with the process owning them, like netstat -tunap
+func cmdNetstat() {
+	var sockets []socketEntry
+	for _, proto := range []string{"tcp", "tcp6", "udp", "udp6"} {
+		f, err := os.Open(filepath.Join("/proc/net", proto))
+		if err != nil {
+			// IPv6 may be disabled
+			continue
+		}
+		entries, err := parseProcNet(f, proto)
+		_ = f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "netstat: %s: %v\n", proto, err)
+			continue
+		}
+		sockets = append(sockets, entries...)
+	}
+
+	owners := socketOwners("/proc")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PROTO\tLOCAL ADDRESS\tFOREIGN ADDRESS\tSTATE\tPID/PROGRAM")
+	for _, s := range sockets {
+		owner := owners[s.Inode]
+		if owner == "" {
+			owner = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Proto, s.LocalAddr, s.RemoteAddr, s.State, owner)
+	}
+	_ = w.Flush()
+}
+
+// parseProcNet parses the content of /proc/net/tcp and its siblings
+func parseProcNet(r io.Reader, proto string) ([]socketEntry, error) {
+	var entries []socketEntry
+	scanner := bufio.NewScanner(r)
+	first := true
+	for scanner.Scan() {
+		if first {
+			// Header line
+			first = false
+			continue
+		}
+		entry, ok := parseProcNetLine(scanner.Text(), proto)
+		if ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// parseProcNetLine parses a line like
+// "0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000 0 0 12345 ..."
+func parseProcNetLine(line, proto string) (socketEntry, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 10 {
+		return socketEntry{}, false
+	}
+
+	local, err := decodeProcNetAddr(fields[1])
+	if err != nil {
+		return socketEntry{}, false
+	}
+	remote, err := decodeProcNetAddr(fields[2])
+	if err != nil {
+		return socketEntry{}, false
+	}
+	inode, _ := strconv.ParseUint(fields[9], 10, 64)
+
+	// UDP sockets reuse the TCP codes but only ESTABLISHED (connected) is meaningful
+	state := tcpStates[strings.ToUpper(fields[3])]
+	if strings.HasPrefix(proto, "udp") && state != "ESTABLISHED" {
+		state = ""
+	}
+
+	return socketEntry{
+		Proto:      proto,
+		LocalAddr:  local,
+		RemoteAddr: remote,
+		State:      state,
+		Inode:      inode,
+	}, true
+}
+
+// decodeProcNetAddr decodes "0100007F:1F90" into "127.0.0.1:8080".
+// The address is stored as 32-bit words in host byte order (little endian on all supported architectures).
+func decodeProcNetAddr(s string) (string, error) {
+	addrHex, portHex, ok := strings.Cut(s, ":")
+	if !ok {
+		return "", fmt.Errorf("invalid address: %s", s)
+	}
+	raw, err := hex.DecodeString(addrHex)
+	if err != nil || (len(raw) != 4 && len(raw) != 16) {
+		return "", fmt.Errorf("invalid address: %s", s)
+	}
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return "", fmt.Errorf("invalid port: %s", s)
+	}
+
+	ip := make(net.IP, len(raw))
+	for i := 0; i < len(raw); i += 4 {
+		ip[i], ip[i+1], ip[i+2], ip[i+3] = raw[i+3], raw[i+2], raw[i+1], raw[i]
+	}
+	return net.JoinHostPort(ip.String(), strconv.FormatUint(port, 10)), nil
+}
+
+// socketOwners maps socket inodes to "pid/name" by scanning the file descriptors of all processes
+func socketOwners(procRoot string) map[uint64]string {
+	owners := make(map[uint64]string)
+	procs, err := os.ReadDir(procRoot)
+	if err != nil {
+		return owners
+	}
+
+	for _, proc := range procs {
+		if _, err := strconv.Atoi(proc.Name()); err != nil {
+			continue
+		}
+		fdDir := filepath.Join(procRoot, proc.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+
+		name := ""
+		if comm, err := os.ReadFile(filepath.Join(procRoot, proc.Name(), "comm")); err == nil {
+			name = strings.TrimSpace(string(comm))
+		}
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(target, "socket:[") {
+				continue
+			}
+			inode, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(target, "socket:["), "]"), 10, 64)
+			if err != nil {
+				continue
+			}
+			owners[inode] = proc.Name() + "/" + name
+		}
+	}
+	return owners
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeProcNetAddr(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"0100007F:1F90", "127.0.0.1:8080"},
+		{"00000000:0050", "0.0.0.0:80"},
+		{"00000000000000000000000001000000:01BB", "[::1]:443"},
+	}
+	for _, tt := range tests {
+		got, err := decodeProcNetAddr(tt.input)
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, got)
+	}
+
+	_, err := decodeProcNetAddr("zz:0050")
+	assert.Error(t, err)
+}
+
+func TestParseProcNet(t *testing.T) {
+	input := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 12345 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F90 0100007F:C350 01 00000000:00000000 00:00000000 00000000     0        0 12346 1 0000000000000000 20 4 30 10 -1
+`
+	entries, err := parseProcNet(strings.NewReader(input), "tcp")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "0.0.0.0:8080", entries[0].LocalAddr)
+	assert.Equal(t, "LISTEN", entries[0].State)
+	assert.Equal(t, uint64(12345), entries[0].Inode)
+	assert.Equal(t, "127.0.0.1:50000", entries[1].RemoteAddr)
+	assert.Equal(t, "ESTABLISHED", entries[1].State)
+
+	// Unconnected UDP sockets have no state
+	udp, err := parseProcNet(strings.NewReader(input), "udp")
+	require.NoError(t, err)
+	assert.Equal(t, "", udp[0].State)
+	assert.Equal(t, "ESTABLISHED", udp[1].State)
+}
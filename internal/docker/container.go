@@ -73,6 +73,15 @@ func (c *Container) OperationArgs(cmd string, extraArgs ...string) []string {
 	return append(args, extraArgs...)
 }
 
+// DaemonArgs returns the arguments that run a docker command against the daemon the container belongs to.
+// Unlike OperationArgs it does not add the container ID.
+func (c *Container) DaemonArgs(args ...string) []string {
+	if c.isDind {
+		return append([]string{"exec", c.hostContainerID, "docker"}, args...)
+	}
+	return args
+}
+
 func (c *Container) IsDind() bool {
 	return c.isDind
 }
@@ -81,6 +90,10 @@ func (c *Container) HostContainerID() string {
 	return c.hostContainerID
 }
 
+func (c *Container) HostContainerName() string {
+	return c.hostContainerName
+}
+
 func (c *Container) InteractiveExecArgs(extraArgs ...string) []string {
 	if c.isDind {
 		// For DinD containers, we need to exec into the host container first,
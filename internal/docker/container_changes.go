@@ -0,0 +1,58 @@
+package docker
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+// GetContainerChanges lists the paths the container added, changed or deleted relative to its image
+func GetContainerChanges(container *Container) ([]models.ContainerChange, error) {
+	output, err := ExecuteCaptured(container.OperationArgs("diff")...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get filesystem changes: %w", err)
+	}
+	return ParseContainerDiff(output), nil
+}
+
+// GetImageFileContent reads a file as it is in the image of the container.
+// The image is read through a container that is created for it but never started.
+func GetImageFileContent(container *Container, filePath string, limit int64) (*FileContent, error) {
+	imageOutput, err := ExecuteCaptured(container.OperationArgs("inspect", "--format", "{{.Image}}")...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the image of the container: %w", err)
+	}
+	imageID := strings.TrimSpace(string(imageOutput))
+
+	return readFileFromImage(container, imageID, filePath, limit)
+}
+
+// readFileFromImage copies a file out of an image through a temporary, never started container.
+// container only selects the daemon, so that images inside DinD containers work too.
+func readFileFromImage(container *Container, imageID, filePath string, limit int64) (*FileContent, error) {
+	createOutput, err := ExecuteCaptured(container.DaemonArgs(imageContainerCreateArgs(imageID)...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a container from image %s: %w", imageID, err)
+	}
+	tempID := lastLine(createOutput)
+	defer func() {
+		_, _ = ExecuteCaptured(container.DaemonArgs("rm", "-f", tempID)...)
+	}()
+
+	content, err := readCommandOutput(container.DaemonArgs("cp", "-L", fmt.Sprintf("%s:%s", tempID, filePath), "-"), func(r io.Reader) (*FileContent, error) {
+		return extractFileFromTar(r, limit)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in the image: %w", filePath, err)
+	}
+	return content, nil
+}
+
+// lastLine returns the last non-empty line of a command output.
+// docker create prints pull progress before the ID when the image is pulled.
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -79,3 +79,11 @@ func TestContainer_OperationArgs(t *testing.T) {
 		assert.Equal(t, []string{"exec", "host123", "docker", "logs", "dind456", "--tail", "100"}, args)
 	})
 }
+
+func TestContainer_DaemonArgs(t *testing.T) {
+	container := NewContainer("abc123", "test-container", "Test Container", "running")
+	assert.Equal(t, []string{"create", "img", "true"}, container.DaemonArgs("create", "img", "true"))
+
+	dind := NewDindContainer("host123", "host-container", "dind456", "dind-container", "running")
+	assert.Equal(t, []string{"exec", "host123", "docker", "rm", "tmp"}, dind.DaemonArgs("rm", "tmp"))
+}
@@ -0,0 +1,89 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CopyToContainer copies a local file or directory into a directory of the container.
+// DinD containers cannot be reached by `docker cp` directly, so the source is staged
+// in a temporary directory of the host container and copied from there by the inner daemon.
+func CopyToContainer(container *Container, localPath string, containerDir string) error {
+	if err := checkLocalReadable(localPath); err != nil {
+		return err
+	}
+
+	dest := fmt.Sprintf("%s:%s", container.ContainerID(), containerDir)
+	if !container.IsDind() {
+		if _, err := ExecuteCaptured("cp", localPath, dest); err != nil {
+			return explainCopyError(localPath, containerDir, err)
+		}
+		return nil
+	}
+
+	hostID := container.HostContainerID()
+	stageDir := fmt.Sprintf("/tmp/dcv-upload-%d", time.Now().UnixNano())
+	if _, err := ExecuteCaptured("exec", hostID, "mkdir", "-p", stageDir); err != nil {
+		return fmt.Errorf("failed to create staging directory in host container %s: %w", hostID, err)
+	}
+	defer func() {
+		_, _ = ExecuteCaptured("exec", hostID, "rm", "-rf", stageDir)
+	}()
+
+	if _, err := ExecuteCaptured("cp", localPath, fmt.Sprintf("%s:%s", hostID, stageDir)); err != nil {
+		return fmt.Errorf("failed to stage %s in host container %s: %w", localPath, hostID, err)
+	}
+
+	staged := path.Join(stageDir, filepath.Base(localPath))
+	if _, err := ExecuteCaptured("exec", hostID, "docker", "cp", staged, dest); err != nil {
+		return explainCopyError(localPath, containerDir, err)
+	}
+	return nil
+}
+
+// checkLocalReadable fails early with a clear message when the local source cannot be read
+func checkLocalReadable(localPath string) error {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return fmt.Errorf("permission denied: cannot read local path %s", localPath)
+		}
+		return fmt.Errorf("cannot access local path %s: %w", localPath, err)
+	}
+	if info.IsDir() {
+		_, err = os.ReadDir(localPath)
+	} else {
+		var f *os.File
+		f, err = os.Open(localPath)
+		if err == nil {
+			_ = f.Close()
+		}
+	}
+	if err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return fmt.Errorf("permission denied: cannot read local path %s", localPath)
+		}
+		return fmt.Errorf("cannot read local path %s: %w", localPath, err)
+	}
+	return nil
+}
+
+// explainCopyError turns the common `docker cp` failures into messages that say what went wrong
+func explainCopyError(localPath, containerDir string, err error) error {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "read-only"):
+		return fmt.Errorf("cannot write to %s: the destination is on a read-only filesystem or volume: %w", containerDir, err)
+	case strings.Contains(msg, "permission denied"):
+		return fmt.Errorf("permission denied while copying %s to %s: %w", localPath, containerDir, err)
+	case strings.Contains(msg, "no such file or directory") || strings.Contains(msg, "could not find the file"):
+		return fmt.Errorf("destination directory %s does not exist in the container: %w", containerDir, err)
+	default:
+		return fmt.Errorf("failed to copy %s to %s: %w", localPath, containerDir, err)
+	}
+}
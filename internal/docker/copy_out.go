@@ -0,0 +1,352 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ConflictPolicy decides what happens when a copied file already exists locally
+type ConflictPolicy int
+
+const (
+	ConflictOverwrite ConflictPolicy = iota
+	ConflictSkip
+	ConflictRename
+)
+
+// CopyProgress is the progress of CopyOut and ArchiveOut
+type CopyProgress struct {
+	Files   int    // regular files written
+	Bytes   int64  // bytes of the files written
+	Skipped int    // files that existed and were skipped
+	Path    string // the path in the container being copied
+}
+
+// CopyOut copies files and directories of a container into the local directory dest.
+// A single source may also be copied to a new name, like `docker cp` does when dest does not exist.
+// The sources are streamed as archives and extracted here, so that progress can be reported.
+func CopyOut(ctx context.Context, container *Container, sources []string, dest string, policy ConflictPolicy, progress func(CopyProgress)) error {
+	targets, intoDir := copyOutTargets(sources, dest)
+	if intoDir {
+		if err := os.MkdirAll(dest, 0755); err != nil {
+			return fmt.Errorf("failed to create destination directory: %w", err)
+		}
+	} else if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	var current CopyProgress
+	for i, source := range sources {
+		target := targets[i]
+		if policy == ConflictRename {
+			target = UniqueLocalPath(target)
+		}
+		current.Path = source
+		err := streamFromContainer(ctx, container, source, func(r io.Reader) error {
+			return extractArchive(r, path.Base(source), target, policy, func(files, skipped int, bytes int64) {
+				current.Files += files
+				current.Skipped += skipped
+				current.Bytes += bytes
+				progress(current)
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ArchiveOut writes files and directories of a container into one gzip-compressed tar archive
+func ArchiveOut(ctx context.Context, container *Container, sources []string, archivePath string, progress func(CopyProgress)) (err error) {
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(archivePath)
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	var current CopyProgress
+	for _, source := range sources {
+		current.Path = source
+		err := streamFromContainer(ctx, container, source, func(r io.Reader) error {
+			return copyArchive(r, tw, func(bytes int64) {
+				current.Files++
+				current.Bytes += bytes
+				progress(current)
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// streamFromContainer runs `docker cp CONTAINER:PATH -` and passes the archive it writes to read
+func streamFromContainer(ctx context.Context, container *Container, source string, read func(io.Reader) error) error {
+	args := container.DaemonArgs("cp", fmt.Sprintf("%s:%s", container.ContainerID(), source), "-")
+	slog.Info("Executing docker command", slog.String("args", strings.Join(args, " ")))
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to get stdout pipe: %w", err)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to copy %s: %w", source, err)
+	}
+
+	readErr := read(stdout)
+	if readErr != nil {
+		_ = cmd.Process.Kill()
+	}
+	waitErr := cmd.Wait()
+	switch {
+	case ctx.Err() != nil:
+		return fmt.Errorf("copy of %s canceled: %w", source, ctx.Err())
+	case readErr != nil:
+		return fmt.Errorf("failed to copy %s: %w", source, readErr)
+	case waitErr != nil:
+		return fmt.Errorf("failed to copy %s: %w: %s", source, waitErr, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// extractArchive extracts an archive of `docker cp`, whose entries start with root, to target.
+// Files that exist are replaced, or kept with ConflictSkip. The entries below root are created through an
+// os.Root opened on target, so that a symlink extracted earlier cannot redirect later entries outside of it.
+func extractArchive(r io.Reader, root, target string, policy ConflictPolicy, report func(files, skipped int, bytes int64)) error {
+	var tree, parent *os.Root
+	defer func() {
+		for _, r := range []*os.Root{tree, parent} {
+			if r != nil {
+				_ = r.Close()
+			}
+		}
+	}()
+	openTree := func(perm os.FileMode) (*os.Root, error) {
+		if tree != nil {
+			return tree, nil
+		}
+		if err := os.MkdirAll(target, perm); err != nil {
+			return nil, err
+		}
+		var err error
+		tree, err = os.OpenRoot(target)
+		return tree, err
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rel, ok := strings.CutPrefix(path.Clean(header.Name), root)
+		if !ok || (rel != "" && !strings.HasPrefix(rel, "/")) || !filepath.IsLocal("."+rel) {
+			return fmt.Errorf("unexpected path %q in the archive", header.Name)
+		}
+		perm := header.FileInfo().Mode().Perm()
+
+		// dir and name locate the entry: target itself is created in its parent, the rest below target
+		var dir *os.Root
+		var name string
+		switch {
+		case rel == "" && header.Typeflag == tar.TypeDir:
+			if _, err := openTree(perm | 0700); err != nil {
+				return err
+			}
+			continue
+		case rel == "":
+			if parent == nil {
+				if parent, err = os.OpenRoot(filepath.Dir(target)); err != nil {
+					return err
+				}
+			}
+			dir, name = parent, filepath.Base(target)
+		default:
+			if dir, err = openTree(0755); err != nil {
+				return err
+			}
+			name = filepath.FromSlash(rel[1:])
+		}
+
+		if header.Typeflag == tar.TypeDir {
+			if err := dir.MkdirAll(name, perm|0700); err != nil {
+				return err
+			}
+			continue
+		}
+		_, err = dir.Lstat(name)
+		exists := err == nil
+		if exists && policy == ConflictSkip {
+			report(0, 1, 0)
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeReg:
+			// An existing file is only replaced once the new one is complete
+			n, err := writeLocalFile(dir, name, tr, perm)
+			if err != nil {
+				return err
+			}
+			report(1, 0, n)
+		case tar.TypeSymlink:
+			if err := removeExisting(dir, name, exists); err != nil {
+				return err
+			}
+			if err := dir.Symlink(header.Linkname, name); err != nil {
+				return err
+			}
+			report(1, 0, 0)
+		case tar.TypeLink:
+			linkRel, ok := strings.CutPrefix(path.Clean(header.Linkname), root+"/")
+			if !ok || !filepath.IsLocal(linkRel) || tree == nil {
+				return fmt.Errorf("unexpected link target %q in the archive", header.Linkname)
+			}
+			if err := removeExisting(dir, name, exists); err != nil {
+				return err
+			}
+			if err := tree.Link(filepath.FromSlash(linkRel), name); err != nil {
+				return err
+			}
+			report(1, 0, 0)
+		default:
+			// Devices, pipes and sockets cannot be copied out
+			slog.Debug("Skipping special file", slog.String("path", header.Name))
+		}
+	}
+}
+
+func removeExisting(dir *os.Root, name string, exists bool) error {
+	if !exists {
+		return nil
+	}
+	if err := dir.Remove(name); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeLocalFile writes the file under a temporary name next to it and renames it into place once it is complete,
+// so that a failed or canceled copy never leaves a partial file that a retry with ConflictSkip would keep
+func writeLocalFile(dir *os.Root, name string, r io.Reader, perm os.FileMode) (int64, error) {
+	tmp := filepath.Join(filepath.Dir(name), fmt.Sprintf(".%s.dcv-%x", filepath.Base(name), rand.Uint64()))
+	f, err := dir.OpenFile(tmp, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = dir.Rename(tmp, name)
+	}
+	if err != nil {
+		_ = dir.Remove(tmp)
+	}
+	return n, err
+}
+
+// copyArchive appends the entries of an archive to tw
+func copyArchive(r io.Reader, tw *tar.Writer, report func(bytes int64)) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		n, err := io.Copy(tw, tr)
+		if err != nil {
+			return err
+		}
+		report(n)
+	}
+}
+
+// UniqueLocalPath returns p, or "name (N).ext" next to it when p exists
+func UniqueLocalPath(p string) string {
+	if _, err := os.Lstat(p); errors.Is(err, os.ErrNotExist) {
+		return p
+	}
+	ext := filepath.Ext(p)
+	base := strings.TrimSuffix(p, ext)
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, i, ext)
+		if _, err := os.Lstat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate
+		}
+	}
+}
+
+// LocalConflicts returns the local paths that copying sources into dest with CopyOut would overwrite
+func LocalConflicts(sources []string, dest string) []string {
+	targets, _ := copyOutTargets(sources, dest)
+	var conflicts []string
+	for _, target := range targets {
+		if _, err := os.Lstat(target); err == nil {
+			conflicts = append(conflicts, target)
+		}
+	}
+	return conflicts
+}
+
+// copyOutTargets returns the local path of each source. Sources go into dest when it is a directory,
+// ends with a separator or several sources are copied; a single source is copied to dest otherwise.
+func copyOutTargets(sources []string, dest string) (targets []string, intoDir bool) {
+	intoDir = len(sources) > 1 || strings.HasSuffix(dest, string(filepath.Separator))
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		intoDir = true
+	}
+	for _, source := range sources {
+		if intoDir {
+			targets = append(targets, filepath.Join(dest, path.Base(source)))
+		} else {
+			targets = append(targets, dest)
+		}
+	}
+	return targets, intoDir
+}
@@ -0,0 +1,130 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractArchive(t *testing.T) {
+	archive := buildTar(t,
+		dirEntry("conf/"),
+		fileEntry("conf/app.yaml", "port: 80\n"),
+		dirEntry("conf/extra/"),
+		fileEntry("conf/extra/a.txt", "new"),
+		tarEntry{header: &tar.Header{Typeflag: tar.TypeSymlink, Name: "conf/current", Linkname: "app.yaml"}},
+		tarEntry{header: &tar.Header{Typeflag: tar.TypeLink, Name: "conf/app2.yaml", Linkname: "conf/app.yaml"}},
+	)
+
+	target := filepath.Join(t.TempDir(), "conf")
+	require.NoError(t, os.MkdirAll(filepath.Join(target, "extra"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(target, "extra", "a.txt"), []byte("old"), 0644))
+
+	var files, skipped int
+	var size int64
+	report := func(f, s int, n int64) {
+		files += f
+		skipped += s
+		size += n
+	}
+	require.NoError(t, extractArchive(bytes.NewReader(archive), "conf", target, ConflictSkip, report))
+	assert.Equal(t, 3, files)
+	assert.Equal(t, 1, skipped)
+	assert.Equal(t, int64(9), size)
+	data, err := os.ReadFile(filepath.Join(target, "extra", "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(data), "existing files are kept when skipping")
+	link, err := os.Readlink(filepath.Join(target, "current"))
+	require.NoError(t, err)
+	assert.Equal(t, "app.yaml", link)
+	data, err = os.ReadFile(filepath.Join(target, "app2.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "port: 80\n", string(data))
+
+	require.NoError(t, extractArchive(bytes.NewReader(archive), "conf", target, ConflictOverwrite, report))
+	data, err = os.ReadFile(filepath.Join(target, "extra", "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+
+	escaping := buildTar(t, fileEntry("conf/../../evil", "x"))
+	assert.Error(t, extractArchive(bytes.NewReader(escaping), "conf", target, ConflictOverwrite, report))
+
+	// A symlink from the archive cannot redirect the entries after it
+	outside := t.TempDir()
+	redirecting := buildTar(t,
+		dirEntry("conf/"),
+		tarEntry{header: &tar.Header{Typeflag: tar.TypeSymlink, Name: "conf/out", Linkname: outside}},
+		fileEntry("conf/out/evil", "x"),
+	)
+	assert.Error(t, extractArchive(bytes.NewReader(redirecting), "conf", filepath.Join(t.TempDir(), "conf"), ConflictOverwrite, report))
+	assert.NoFileExists(t, filepath.Join(outside, "evil"))
+}
+
+func TestExtractArchive_Interrupted(t *testing.T) {
+	archive := buildTar(t, dirEntry("logs/"), fileEntry("logs/a.log", strings.Repeat("x", 4096)))
+	target := filepath.Join(t.TempDir(), "logs")
+	report := func(int, int, int64) {}
+
+	// A copy that stops in the middle of a file leaves nothing a retry would skip
+	assert.Error(t, extractArchive(bytes.NewReader(archive[:2048]), "logs", target, ConflictSkip, report))
+	entries, err := os.ReadDir(target)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	require.NoError(t, extractArchive(bytes.NewReader(archive), "logs", target, ConflictSkip, report))
+	data, err := os.ReadFile(filepath.Join(target, "a.log"))
+	require.NoError(t, err)
+	assert.Len(t, data, 4096)
+}
+
+func TestCopyArchive(t *testing.T) {
+	archive := buildTar(t, dirEntry("logs/"), fileEntry("logs/a.log", "abc"), fileEntry("logs/b.log", "de"))
+	var out bytes.Buffer
+	tw := tar.NewWriter(&out)
+	var files int
+	var size int64
+	require.NoError(t, copyArchive(bytes.NewReader(archive), tw, func(n int64) {
+		files++
+		size += n
+	}))
+	require.NoError(t, tw.Close())
+	assert.Equal(t, 2, files)
+	assert.Equal(t, int64(5), size)
+
+	tr := tar.NewReader(&out)
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, header.Name)
+	}
+	assert.Equal(t, []string{"logs/", "logs/a.log", "logs/b.log"}, names)
+}
+
+func TestCopyOutTargets(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.log"), nil, 0644))
+
+	targets, intoDir := copyOutTargets([]string{"/var/log/app.log"}, dir)
+	assert.True(t, intoDir)
+	assert.Equal(t, []string{filepath.Join(dir, "app.log")}, targets)
+
+	renamed := filepath.Join(dir, "renamed.log")
+	targets, intoDir = copyOutTargets([]string{"/var/log/app.log"}, renamed)
+	assert.False(t, intoDir)
+	assert.Equal(t, []string{renamed}, targets)
+
+	assert.Equal(t, []string{filepath.Join(dir, "app.log")},
+		LocalConflicts([]string{"/var/log/app.log", "/var/log/other.log"}, dir))
+
+	assert.Equal(t, filepath.Join(dir, "app (1).log"), UniqueLocalPath(filepath.Join(dir, "app.log")))
+	assert.Equal(t, renamed, UniqueLocalPath(renamed))
+}
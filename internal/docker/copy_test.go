@@ -0,0 +1,69 @@
+package docker
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainCopyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "read-only rootfs",
+			err:      errors.New("command failed with exit code 1: exit status 1\nError response from daemon: container rootfs is marked read-only"),
+			expected: "cannot write to /app: the destination is on a read-only filesystem or volume",
+		},
+		{
+			name:     "permission denied",
+			err:      errors.New("command failed with exit code 1: exit status 1\nopen /src/secret: permission denied"),
+			expected: "permission denied while copying /src/secret to /app",
+		},
+		{
+			name:     "missing destination",
+			err:      errors.New("command failed with exit code 1: exit status 1\nError response from daemon: Could not find the file /app in container abc"),
+			expected: "destination directory /app does not exist in the container",
+		},
+		{
+			name:     "other failure",
+			err:      errors.New("command failed with exit code 1: exit status 1\nboom"),
+			expected: "failed to copy /src/secret to /app",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := explainCopyError("/src/secret", "/app", tt.err)
+			assert.Contains(t, err.Error(), tt.expected)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestCheckLocalReadable(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	require.NoError(t, os.WriteFile(file, []byte("hello"), 0644))
+
+	assert.NoError(t, checkLocalReadable(file))
+	assert.NoError(t, checkLocalReadable(dir))
+
+	err := checkLocalReadable(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot access local path")
+
+	if os.Geteuid() != 0 {
+		unreadable := filepath.Join(dir, "unreadable.txt")
+		require.NoError(t, os.WriteFile(unreadable, []byte("secret"), 0000))
+		err = checkLocalReadable(unreadable)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "permission denied: cannot read local path")
+	}
+}
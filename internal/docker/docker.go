@@ -28,8 +28,9 @@ func (c *Client) ListContainerFiles(containerID, path string) ([]models.Containe
 }
 
 // GetFileContent retrieves file content from a container
-func (c *Client) GetFileContent(containerID, filePath string) (string, error) {
-	return c.fileOps.GetFileContent(context.TODO(), containerID, filePath)
+func (c *Client) GetFileContent(containerID, filePath string) (*FileContent, error) {
+	container := NewContainer(containerID, "", "", "")
+	return c.fileOps.GetFileContent(context.TODO(), container, filePath, MaxFileContentSize)
 }
 
 // ListComposeContainers lists containers for a Docker Compose project
@@ -126,6 +127,16 @@ func (c *Client) ListImages(showAll bool) ([]models.DockerImage, error) {
 	return ParseImagesJSON(output)
 }
 
+// GetImageHistory returns the build steps of an image, newest first
+func (c *Client) GetImageHistory(image string) ([]models.ImageHistoryEntry, error) {
+	output, err := ExecuteCaptured("history", "--no-trunc", "--format", "json", image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute docker history: %w", err)
+	}
+
+	return ParseImageHistoryJSON(output)
+}
+
 func (c *Client) ListNetworks() ([]models.DockerNetwork, error) {
 	output, err := ExecuteCaptured("network", "ls", "--format", "json")
 	if err != nil {
@@ -191,3 +202,17 @@ func (c *Client) GetStats(all bool) ([]models.ContainerStats, error) {
 
 	return ParseStatsJSON(output)
 }
+
+// GetDindStats returns container stats for the containers inside a Docker-in-Docker container
+func (c *Client) GetDindStats(hostContainerID string, all bool) ([]models.ContainerStats, error) {
+	args := []string{"exec", hostContainerID, "docker", "stats", "--no-stream", "--format", "json"}
+	if all {
+		args = append(args, "--all")
+	}
+	output, err := c.ExecuteCaptured(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats in dind container %s: %w", hostContainerID, err)
+	}
+
+	return ParseStatsJSON(output)
+}
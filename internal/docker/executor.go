@@ -1,6 +1,7 @@
 package docker
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -21,7 +22,15 @@ func Execute(args ...string) *exec.Cmd {
 }
 
 func ExecuteCaptured(args ...string) ([]byte, error) {
+	return ExecuteCapturedWithInput(nil, args...)
+}
+
+// ExecuteCapturedWithInput is ExecuteCaptured with data fed to the standard input of the command
+func ExecuteCapturedWithInput(input []byte, args ...string) ([]byte, error) {
 	cmd := Execute(args...)
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
 
 	startTime := time.Now()
 	cmdStr := strings.Join(cmd.Args, " ")
@@ -0,0 +1,69 @@
+package docker
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+// PeerContainer is a running container a file can be compared with
+type PeerContainer struct {
+	Container *Container
+	Image     string
+	// SameImage is set for the containers created from the image of the compared one, like replicas
+	SameImage bool
+}
+
+// PeerContainers lists the running containers of the daemon container runs on, other than container itself.
+// The containers of the same image come first, since replicas are what is usually compared.
+func PeerContainers(container *Container) ([]PeerContainer, error) {
+	output, err := ExecuteCaptured(container.DaemonArgs("ps", "--format", "json", "--no-trunc")...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	listed, err := ParsePSJSON(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse docker ps JSON output: %w", err)
+	}
+	return peerContainers(container, listed), nil
+}
+
+func peerContainers(container *Container, listed []models.DockerContainer) []PeerContainer {
+	// IDs may be short on either side
+	isSelf := func(c models.DockerContainer) bool {
+		id := container.ContainerID()
+		return id != "" && (strings.HasPrefix(c.ID, id) || strings.HasPrefix(id, c.ID))
+	}
+
+	image := ""
+	if i := slices.IndexFunc(listed, isSelf); i >= 0 {
+		image = listed[i].Image
+	}
+
+	peers := make([]PeerContainer, 0, len(listed))
+	for _, c := range listed {
+		if isSelf(c) {
+			continue
+		}
+		peer := PeerContainer{Image: c.Image, SameImage: image != "" && c.Image == image}
+		if container.IsDind() {
+			peer.Container = NewDindContainer(container.HostContainerID(), container.HostContainerName(), c.ID, c.Names, c.State)
+		} else {
+			peer.Container = NewContainer(c.ID, c.Names, c.Names, c.State)
+		}
+		peers = append(peers, peer)
+	}
+	slices.SortStableFunc(peers, func(a, b PeerContainer) int {
+		if a.SameImage != b.SameImage {
+			if a.SameImage {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.Container.GetName(), b.Container.GetName())
+	})
+	return peers
+}
@@ -0,0 +1,34 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+func TestPeerContainers(t *testing.T) {
+	listed := []models.DockerContainer{
+		{ID: "db0000000000", Names: "db", Image: "postgres:16", State: "running"},
+		{ID: "web200000000", Names: "web-2", Image: "app:1.2", State: "running"},
+		{ID: "web100000000", Names: "web-1", Image: "app:1.2", State: "running"},
+		{ID: "cache0000000", Names: "cache", Image: "redis:7", State: "running"},
+	}
+
+	peers := peerContainers(NewContainer("web1", "web-1", "web-1", "running"), listed)
+	var names []string
+	for _, p := range peers {
+		names = append(names, p.Container.GetName())
+	}
+	assert.Equal(t, []string{"web-2", "cache", "db"}, names)
+	assert.True(t, peers[0].SameImage)
+	assert.False(t, peers[1].SameImage)
+	assert.Equal(t, "redis:7", peers[1].Image)
+
+	// Containers inside dind are reached through the same host
+	peers = peerContainers(NewDindContainer("host1", "dind", "cache0000000", "cache", "running"), listed)
+	assert.Len(t, peers, 3)
+	assert.True(t, peers[0].Container.IsDind())
+	assert.Equal(t, "host1", peers[0].Container.HostContainerID())
+}
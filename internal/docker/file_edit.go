@@ -0,0 +1,187 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// maxSymlinkHops bounds how many symlinks are followed to find the file to edit
+const maxSymlinkHops = 8
+
+// EditableFile is a file copied out of a container to be edited locally.
+// It remembers the mode and ownership of the original so that writing it back keeps them.
+type EditableFile struct {
+	// ContainerPath is the path of the file in the container, with symlinks resolved
+	ContainerPath string
+	// LocalPath is the copy in a temporary directory
+	LocalPath string
+	// Original is the content when the file was copied out
+	Original []byte
+
+	Mode  int64
+	UID   int
+	GID   int
+	Uname string
+	Gname string
+}
+
+// FetchFileForEdit copies a regular file out of the container into a new temporary directory.
+// The local copy keeps the base name so editors can detect the file type. Call Cleanup when done.
+func FetchFileForEdit(container *Container, filePath string) (*EditableFile, error) {
+	header, data, resolved, err := readFileForEdit(container, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "dcv-edit-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	localPath := filepath.Join(dir, path.Base(resolved))
+	if err := os.WriteFile(localPath, data, 0600); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	return &EditableFile{
+		ContainerPath: resolved,
+		LocalPath:     localPath,
+		Original:      data,
+		Mode:          header.Mode,
+		UID:           header.Uid,
+		GID:           header.Gid,
+		Uname:         header.Uname,
+		Gname:         header.Gname,
+	}, nil
+}
+
+// readFileForEdit reads a file through `docker cp`, following symlinks itself
+// so that writing back replaces the target instead of the link. It returns the resolved path.
+// The archive is streamed, and copying stops at the header of a file too large to edit.
+func readFileForEdit(container *Container, filePath string) (*tar.Header, []byte, string, error) {
+	for range maxSymlinkHops {
+		var header *tar.Header
+		content, err := readCommandOutput(copyFromContainerArgs(container, filePath), func(r io.Reader) (*FileContent, error) {
+			var content *FileContent
+			var err error
+			header, content, err = readEditEntry(r, filePath)
+			return content, err
+		})
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("failed to copy %s out of the container: %w", filePath, err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeReg:
+			if content.Truncated {
+				return nil, nil, "", fmt.Errorf("%s is too large to edit (%d bytes)", filePath, header.Size)
+			}
+			return header, content.Data, filePath, nil
+		case tar.TypeSymlink:
+			target := header.Linkname
+			if !path.IsAbs(target) {
+				target = path.Join(path.Dir(filePath), target)
+			}
+			filePath = target
+		case tar.TypeDir:
+			return nil, nil, "", fmt.Errorf("%s is a directory", filePath)
+		default:
+			return nil, nil, "", fmt.Errorf("%s is not a regular file", filePath)
+		}
+	}
+	return nil, nil, "", fmt.Errorf("too many levels of symbolic links: %s", filePath)
+}
+
+// readEditEntry reads the first entry of the archive of filePath. The content of a regular file larger than
+// MaxFileContentSize is not read and is marked truncated, as is every other entry, of which only the header is needed.
+func readEditEntry(r io.Reader, filePath string) (*tar.Header, *FileContent, error) {
+	tr := tar.NewReader(r)
+	header, err := tr.Next()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read archive of %s: %w", filePath, err)
+	}
+	if header.Typeflag != tar.TypeReg {
+		// The archive of a directory may be large
+		return header, &FileContent{Truncated: true}, nil
+	}
+	if header.Size > MaxFileContentSize {
+		return header, &FileContent{Size: header.Size, Truncated: true}, nil
+	}
+	data, err := io.ReadAll(tr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s from archive: %w", filePath, err)
+	}
+	return header, &FileContent{Data: data, Size: header.Size}, nil
+}
+
+// Edited returns the current content of the local copy and whether it differs from the original
+func (f *EditableFile) Edited() ([]byte, bool, error) {
+	data, err := os.ReadFile(f.LocalPath)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read edited file: %w", err)
+	}
+	return data, !bytes.Equal(data, f.Original), nil
+}
+
+// WriteBack copies data into the container in place of the original file,
+// keeping its mode and ownership. `docker cp -a` keeps the uid and gid of the archive.
+func (f *EditableFile) WriteBack(container *Container, data []byte) error {
+	archive, err := f.archive(data)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	containerDir := path.Dir(f.ContainerPath)
+	if _, err := ExecuteCapturedWithInput(archive, copyToContainerArgs(container, containerDir)...); err != nil {
+		return explainCopyError(f.LocalPath, containerDir, err)
+	}
+	return nil
+}
+
+// archive returns a tar archive holding data under the name, mode and owner of the original file
+func (f *EditableFile) archive(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     path.Base(f.ContainerPath),
+		Size:     int64(len(data)),
+		Mode:     f.Mode,
+		Uid:      f.UID,
+		Gid:      f.GID,
+		Uname:    f.Uname,
+		Gname:    f.Gname,
+		ModTime:  time.Now(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Cleanup removes the temporary directory of the local copy
+func (f *EditableFile) Cleanup() {
+	_ = os.RemoveAll(filepath.Dir(f.LocalPath))
+}
+
+// copyToContainerArgs returns the arguments of `docker cp -a - CONTAINER:DIR`, which extracts a tar archive from stdin
+func copyToContainerArgs(container *Container, containerDir string) []string {
+	dest := fmt.Sprintf("%s:%s", container.ContainerID(), containerDir)
+	if container.IsDind() {
+		// -i forwards the archive to the docker CLI of the host container
+		return []string{"exec", "-i", container.HostContainerID(), "docker", "cp", "-a", "-", dest}
+	}
+	return []string{"cp", "-a", "-", dest}
+}
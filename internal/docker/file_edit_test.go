@@ -0,0 +1,102 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyArgs(t *testing.T) {
+	container := NewContainer("abc123", "web", "web", "running")
+	dind := NewDindContainer("host123", "dind", "inner456", "app", "running")
+
+	assert.Equal(t, []string{"cp", "abc123:/etc/hosts", "-"}, copyFromContainerArgs(container, "/etc/hosts"))
+	assert.Equal(t, []string{"exec", "host123", "docker", "cp", "-L", "inner456:/etc/hosts", "-"},
+		copyFromContainerArgs(dind, "/etc/hosts", "-L"))
+
+	assert.Equal(t, []string{"cp", "-a", "-", "abc123:/etc"}, copyToContainerArgs(container, "/etc"))
+	assert.Equal(t, []string{"exec", "-i", "host123", "docker", "cp", "-a", "-", "inner456:/etc"},
+		copyToContainerArgs(dind, "/etc"))
+}
+
+func TestEditableFile(t *testing.T) {
+	dir := t.TempDir()
+	file := &EditableFile{
+		ContainerPath: "/etc/nginx/nginx.conf",
+		LocalPath:     filepath.Join(dir, "nginx.conf"),
+		Original:      []byte("worker_processes 1;\n"),
+		Mode:          0o640,
+		UID:           101,
+		GID:           102,
+		Uname:         "nginx",
+		Gname:         "nginx",
+	}
+	require.NoError(t, os.WriteFile(file.LocalPath, file.Original, 0600))
+
+	t.Run("unchanged copy", func(t *testing.T) {
+		_, changed, err := file.Edited()
+		require.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("changed copy", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(file.LocalPath, []byte("worker_processes 4;\n"), 0600))
+		data, changed, err := file.Edited()
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, "worker_processes 4;\n", string(data))
+	})
+
+	t.Run("archive keeps mode and owner", func(t *testing.T) {
+		archive, err := file.archive([]byte("new content"))
+		require.NoError(t, err)
+
+		tr := tar.NewReader(bytes.NewReader(archive))
+		header, err := tr.Next()
+		require.NoError(t, err)
+		assert.Equal(t, "nginx.conf", header.Name)
+		assert.Equal(t, int64(0o640), header.Mode)
+		assert.Equal(t, 101, header.Uid)
+		assert.Equal(t, 102, header.Gid)
+		assert.Equal(t, "nginx", header.Uname)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		assert.Equal(t, "new content", string(data))
+	})
+
+	t.Run("cleanup removes the temporary directory", func(t *testing.T) {
+		file.Cleanup()
+		_, err := os.Stat(dir)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestReadEditEntry(t *testing.T) {
+	header, content, err := readEditEntry(bytes.NewReader(buildTar(t, fileEntry("app.yaml", "port: 80\n"))), "/etc/app.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "app.yaml", header.Name)
+	assert.False(t, content.Truncated)
+	assert.Equal(t, "port: 80\n", string(content.Data))
+
+	// The content of a large file is not read, so the archive only holds its header
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "big.bin", Size: MaxFileContentSize + 1}))
+	header, content, err = readEditEntry(&buf, "/big.bin")
+	require.NoError(t, err)
+	assert.Equal(t, int64(MaxFileContentSize+1), header.Size)
+	assert.True(t, content.Truncated)
+	assert.Empty(t, content.Data)
+
+	header, content, err = readEditEntry(bytes.NewReader(buildTar(t,
+		tarEntry{header: &tar.Header{Typeflag: tar.TypeSymlink, Name: "current", Linkname: "app.yaml"}})), "/etc/current")
+	require.NoError(t, err)
+	assert.Equal(t, "app.yaml", header.Linkname)
+	assert.True(t, content.Truncated)
+}
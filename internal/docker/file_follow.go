@@ -0,0 +1,33 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// FollowFileCommand returns the command that prints the last lines of a file and then
+// follows it by name, so that log rotation and truncation are handled.
+// The container's tail is used when it has one; otherwise the helper is injected.
+func (fo *FileOperations) FollowFileCommand(ctx context.Context, container *Container, filePath string, lines int) (*RemoteCommand, error) {
+	// tail -F is available in both coreutils and busybox; sh is needed to stop it later
+	_, errNative := ExecuteCaptured(container.OperationArgs("exec", "sh", "-c", `exec tail -n 0 "$1"`, "sh", filePath)...)
+	if errNative == nil {
+		return followFileNativeCommand(container, filePath, lines), nil
+	}
+	slog.Debug("tail failed, following the file with the helper", slog.Any("error", errNative))
+
+	if err := fo.PrepareHelper(ctx, container); err != nil {
+		return nil, fmt.Errorf("unable to follow %s:\nnative: %s\nhelper: %w", filePath, errNative, err)
+	}
+	return followFileHelperCommand(container, filePath, lines), nil
+}
+
+func followFileNativeCommand(container *Container, filePath string, lines int) *RemoteCommand {
+	return remoteShellCommand(container, "tail", "-n", strconv.Itoa(lines), "-F", filePath)
+}
+
+func followFileHelperCommand(container *Container, filePath string, lines int) *RemoteCommand {
+	return remoteHelperCommand(container, "tail", "-n", strconv.Itoa(lines), "-F", filePath)
+}
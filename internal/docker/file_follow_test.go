@@ -0,0 +1,21 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFollowFileCommand(t *testing.T) {
+	container := NewContainer("abc123", "web", "web", "running")
+
+	native := followFileNativeCommand(container, "/var/log/app.log", 1000)
+	assert.Equal(t, []string{"exec", "abc123", "sh", "-c", `echo $$; exec "$@"`, "sh", "tail", "-n", "1000", "-F", "/var/log/app.log"},
+		native.Args)
+	assert.Equal(t, []string{"exec", "abc123", "sh", "-c", `kill "$1"`, "sh", "42"}, native.KillArgs(42))
+
+	helper := followFileHelperCommand(container, "/var/log/app.log", 1000)
+	assert.Equal(t, []string{"exec", "abc123", "/.dcv-helper", "-print-pid", "tail", "-n", "1000", "-F", "/var/log/app.log"},
+		helper.Args)
+	assert.Equal(t, []string{"exec", "abc123", "/.dcv-helper", "kill", "42"}, helper.KillArgs(42))
+}
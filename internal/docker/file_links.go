@@ -0,0 +1,123 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+)
+
+// ResolvedPath is where a path leads after following its symbolic links
+type ResolvedPath struct {
+	Path string
+	// RealPath is the path without symbolic links, "" when Broken
+	RealPath string
+	IsDir    bool
+	Broken   bool
+}
+
+// resolveScript prints what `dcv-helper realpath` prints, with the container's shell and readlink
+const resolveScript = `for f; do
+if [ -d "$f" ]; then t=d; elif [ -e "$f" ]; then t=f; else t=b; fi
+printf '%s\t%s\t%s\n' "$t" "$(readlink -f "$f")" "$f"
+done`
+
+// ResolvePaths follows the symbolic links of paths in the container, to tell which links point to
+// directories and which are broken. The container's readlink is used when it has one, the helper otherwise.
+// Links are resolved for every listing, so a missing helper is not injected for it.
+func (fo *FileOperations) ResolvePaths(ctx context.Context, container *Container, paths ...string) ([]ResolvedPath, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	var args []string
+	if _, errNative := ExecuteCaptured(fileToolProbeArgs(container, "readlink")...); errNative == nil {
+		args = container.OperationArgs("exec", append([]string{"sh", "-c", resolveScript, "sh"}, paths...)...)
+	} else {
+		slog.Debug("readlink not found in the container, using the helper", slog.Any("error", errNative))
+		if err := fo.ensureHelper(ctx, container, false); err != nil {
+			return nil, fmt.Errorf("unable to resolve links:\nnative: %s\nhelper: %w", errNative, err)
+		}
+		args = HelperArgs(container, "realpath", paths...)
+	}
+
+	output, err := ExecuteCaptured(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve links: %w", err)
+	}
+	return parseResolvedPaths(output), nil
+}
+
+// parseResolvedPaths parses the "TYPE\tREALPATH\tPATH" lines of `dcv-helper realpath` and resolveScript
+func parseResolvedPaths(output []byte) []ResolvedPath {
+	var resolved []ResolvedPath
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		r := ResolvedPath{Path: fields[2]}
+		switch fields[0] {
+		case "d":
+			r.IsDir = true
+			r.RealPath = fields[1]
+		case "f":
+			r.RealPath = fields[1]
+		default:
+			r.Broken = true
+		}
+		resolved = append(resolved, r)
+	}
+	return resolved
+}
+
+// Resolve follows the symbolic links of paths in the image
+func (f *ImageFilesystem) Resolve(paths ...string) []ResolvedPath {
+	resolved := make([]ResolvedPath, 0, len(paths))
+	for _, p := range paths {
+		real, ok := f.index.resolve(p)
+		if !ok {
+			resolved = append(resolved, ResolvedPath{Path: p, Broken: true})
+			continue
+		}
+		resolved = append(resolved, ResolvedPath{Path: p, RealPath: real, IsDir: f.index.entries[real].file.IsDir})
+	}
+	return resolved
+}
+
+// maxLinkHops is how many symbolic links are followed before a path is considered a loop, like Linux's ELOOP
+const maxLinkHops = 40
+
+// resolve follows the symbolic links of every element of p, like the kernel does inside the container.
+// It returns the real path, and false when p leads nowhere.
+func (x *fsIndex) resolve(p string) (string, bool) {
+	resolved := "/"
+	rest := strings.Split(cleanArchivePath(p), "/")
+	hops := 0
+	for len(rest) > 0 {
+		name := rest[0]
+		rest = rest[1:]
+		if name == "" || name == "." {
+			continue
+		}
+		next := path.Join(resolved, name)
+		entry, ok := x.entries[next]
+		if !ok {
+			return "", false
+		}
+		if entry.file.LinkTarget == "" {
+			resolved = next
+			continue
+		}
+		hops++
+		if hops > maxLinkHops {
+			return "", false
+		}
+		if path.IsAbs(entry.file.LinkTarget) {
+			resolved = "/"
+		}
+		rest = append(strings.Split(entry.file.LinkTarget, "/"), rest...)
+	}
+	return resolved, true
+}
@@ -0,0 +1,52 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResolvedPaths(t *testing.T) {
+	output := []byte("d\t/usr/bin\t/bin\nf\t/etc/alternatives/vi\t/usr/bin/vi\nb\t/opt/missing\t/opt/current\n\n")
+	assert.Equal(t, []ResolvedPath{
+		{Path: "/bin", RealPath: "/usr/bin", IsDir: true},
+		{Path: "/usr/bin/vi", RealPath: "/etc/alternatives/vi"},
+		{Path: "/opt/current", Broken: true},
+	}, parseResolvedPaths(output))
+}
+
+func TestListDirArg(t *testing.T) {
+	assert.Equal(t, "/", listDirArg("/"))
+	assert.Equal(t, "/bin/", listDirArg("/bin"))
+	assert.Equal(t, "/bin/", listDirArg("/bin/"))
+}
+
+func TestImageFilesystemResolve(t *testing.T) {
+	archive := buildTar(t,
+		dirEntry("./"),
+		fileEntry("usr/bin/app", "binary"),
+		tarEntry{header: &tar.Header{Typeflag: tar.TypeSymlink, Name: "bin", Linkname: "usr/bin", Mode: 0777}},
+		tarEntry{header: &tar.Header{Typeflag: tar.TypeSymlink, Name: "usr/local/app", Linkname: "../../bin/app", Mode: 0777}},
+		tarEntry{header: &tar.Header{Typeflag: tar.TypeSymlink, Name: "broken", Linkname: "/nowhere", Mode: 0777}},
+		tarEntry{header: &tar.Header{Typeflag: tar.TypeSymlink, Name: "loop", Linkname: "loop", Mode: 0777}},
+	)
+	index, err := indexFilesystem(bytes.NewReader(archive))
+	require.NoError(t, err)
+	image := &ImageFilesystem{index: index}
+
+	assert.Equal(t, []ResolvedPath{
+		{Path: "/bin", RealPath: "/usr/bin", IsDir: true},
+		{Path: "/usr/local/app", RealPath: "/usr/bin/app"},
+		{Path: "/broken", Broken: true},
+		{Path: "/loop", Broken: true},
+	}, image.Resolve("/bin", "/usr/local/app", "/broken", "/loop"))
+
+	// A directory reached through a link lists the entries of its target
+	files, err := image.List("/bin")
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "app", files[0].Name)
+}
@@ -0,0 +1,29 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// FileCommandArgs returns the docker arguments that run a file management tool such as
+// rm, mv, chmod, chown, mkdir or touch in a container. The container's own tool is used
+// when it has one; otherwise the helper, which accepts the same arguments, is injected.
+func (fo *FileOperations) FileCommandArgs(ctx context.Context, container *Container, tool string, args ...string) ([]string, error) {
+	_, errNative := ExecuteCaptured(fileToolProbeArgs(container, tool)...)
+	if errNative == nil {
+		return container.OperationArgs("exec", append([]string{tool}, args...)...), nil
+	}
+	slog.Debug("Tool not found in the container, using the helper",
+		slog.String("tool", tool), slog.Any("error", errNative))
+
+	if err := fo.PrepareHelper(ctx, container); err != nil {
+		return nil, fmt.Errorf("unable to run %s:\nnative: %s\nhelper: %w", tool, errNative, err)
+	}
+	return HelperArgs(container, tool, args...), nil
+}
+
+// fileToolProbeArgs checks whether the container has a shell and the tool, without running the tool
+func fileToolProbeArgs(container *Container, tool string) []string {
+	return container.OperationArgs("exec", "sh", "-c", `command -v "$1"`, "sh", tool)
+}
@@ -0,0 +1,17 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileToolProbeArgs(t *testing.T) {
+	container := NewContainer("abc123", "web", "web", "running")
+	assert.Equal(t, []string{"exec", "abc123", "sh", "-c", `command -v "$1"`, "sh", "chmod"},
+		fileToolProbeArgs(container, "chmod"))
+
+	dind := NewDindContainer("host1", "dind", "inner1", "app", "running")
+	assert.Equal(t, []string{"exec", "host1", "docker", "exec", "inner1", "sh", "-c", `command -v "$1"`, "sh", "mv"},
+		fileToolProbeArgs(dind, "mv"))
+}
@@ -1,9 +1,16 @@
 package docker
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"strings"
+	"sync"
 
 	"github.com/docker/docker/client"
 
@@ -13,12 +20,23 @@ import (
 // FileOperations provides multi-strategy file operations for containers
 type FileOperations struct {
 	client *client.Client
+
+	mu sync.Mutex
+	// verifiedHelpers records containers whose injected helper speaks the current protocol
+	verifiedHelpers map[string]bool
+	// injectedHelpers records the containers this dcv injected a helper into
+	injectedHelpers map[string]injectedHelper
+	// plainLs records containers whose ls does not know --time-style, like the one of BusyBox
+	plainLs map[string]bool
 }
 
 // NewFileOperations creates a new file operations handler
 func NewFileOperations(dockerClient *client.Client) *FileOperations {
 	return &FileOperations{
-		client: dockerClient,
+		client:          dockerClient,
+		verifiedHelpers: make(map[string]bool),
+		injectedHelpers: make(map[string]injectedHelper),
+		plainLs:         make(map[string]bool),
 	}
 }
 
@@ -45,11 +63,24 @@ func (fo *FileOperations) ListFiles(ctx context.Context, container *Container, p
 		container.containerID, path, errNative, errHelper)
 }
 
-// listFilesNative tries to list files using the native ls command
+// listFilesNative tries to list files using the native ls command.
+// It asks ls for epoch modification times, so they do not depend on the time zone of the container,
+// and lists without them when ls does not know --time-style.
 func (fo *FileOperations) listFilesNative(container *Container, path string) ([]models.ContainerFile, error) {
-	// Execute ls -la command
-	args := container.OperationArgs("exec", "ls", "-la", path)
-	captured, err := ExecuteCaptured(args...)
+	key := helperKey(container)
+	fo.mu.Lock()
+	plain := fo.plainLs[key]
+	fo.mu.Unlock()
+
+	captured, err := ExecuteCaptured(nativeLsArgs(container, path, !plain)...)
+	if err != nil && !plain {
+		captured, err = ExecuteCaptured(nativeLsArgs(container, path, false)...)
+		if err == nil {
+			fo.mu.Lock()
+			fo.plainLs[key] = true
+			fo.mu.Unlock()
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("native ls failed: %w", err)
 	}
@@ -64,79 +95,347 @@ func (fo *FileOperations) listFilesNative(container *Container, path string) ([]
 	return files, nil
 }
 
+// nativeLsArgs returns the docker arguments that run ls -la on path, printing epoch times when epoch is set.
+// The trailing slash lists the directory a symbolic link points to, where `ls -la link` would show the link itself.
+func nativeLsArgs(container *Container, path string, epoch bool) []string {
+	cmd := []string{"ls", "-la"}
+	if epoch {
+		cmd = append(cmd, "--time-style=+%s")
+	}
+	return container.OperationArgs("exec", append(cmd, listDirArg(path))...)
+}
+
+// listDirArg returns dir with a trailing slash, so that ls follows it when it is a symbolic link
+func listDirArg(dir string) string {
+	return strings.TrimSuffix(dir, "/") + "/"
+}
+
 // listFilesWithHelper lists files using the injected helper binary
 func (fo *FileOperations) listFilesWithHelper(ctx context.Context, container *Container, path string) ([]models.ContainerFile, error) {
-	// Use the helper path directly
-	helperPath := GetHelperPath()
+	if err := fo.ensureHelper(ctx, container, false); err != nil {
+		return nil, err
+	}
 
 	// Execute helper ls command
-	cmd := []string{helperPath, "ls", path}
+	cmd := []string{HelperPathFor(container), "ls", "--json", path}
 	args := container.OperationArgs("exec", cmd...)
 	outputBytes, err := ExecuteCaptured(args...)
 	if err != nil {
 		return nil, fmt.Errorf("helper ls failed: %w(%v)", err, cmd)
 	}
-	output := string(outputBytes)
 
-	// Parse helper output (similar format to ls -la)
-	files := parseHelperLsOutput(output)
-	return files, nil
+	return parseHelperLsJSON(outputBytes)
 }
 
-// GetFileContent retrieves file content from a container using multiple strategies
-func (fo *FileOperations) GetFileContent(ctx context.Context, containerID, filePath string) (string, error) {
-	// Strategy 1: Try native cat command first
-	content, err := fo.getFileContentNative(ctx, containerID, filePath)
+// PrepareHelper makes sure the container has a helper that speaks the current protocol,
+// injecting it when it is missing or outdated. It is used before running helper tools.
+func (fo *FileOperations) PrepareHelper(ctx context.Context, container *Container) error {
+	return fo.ensureHelper(ctx, container, true)
+}
+
+// HelperArgs returns the docker arguments that run a helper command in the container
+func HelperArgs(container *Container, command string, args ...string) []string {
+	return container.OperationArgs("exec", append([]string{HelperPathFor(container), command}, args...)...)
+}
+
+// ensureHelper checks that the injected helper speaks the current protocol and is the embedded binary.
+// An outdated or different helper is replaced. A missing helper is injected only when injectMissing is set,
+// because listing falls back to the helper silently and should not write into containers on its own.
+func (fo *FileOperations) ensureHelper(ctx context.Context, container *Container, injectMissing bool) error {
+	key := helperKey(container)
+	fo.mu.Lock()
+	verified := fo.verifiedHelpers[key]
+	fo.mu.Unlock()
+	if verified {
+		return nil
+	}
+
+	status, err := fo.InspectHelper(ctx, container)
 	if err == nil {
-		return content, nil
+		err = status.Err
+	}
+	if errors.Is(err, errHelperOutdated) || errors.Is(err, errHelperChecksum) || (injectMissing && errors.Is(err, errHelperMissing)) {
+		slog.Info("Injecting helper",
+			slog.String("container", container.Title()),
+			slog.Any("reason", err))
+		injectErr := fo.InjectHelper(ctx, container)
+		switch {
+		case injectErr == nil:
+			_, err = fo.helperVersion(container)
+		case errors.Is(err, errHelperChecksum):
+			// The helper speaks the current protocol, so it keeps working where it cannot be replaced
+			slog.Warn("Keeping a helper that differs from the embedded one",
+				slog.String("container", container.Title()),
+				slog.Any("error", injectErr))
+			err = nil
+		default:
+			return fmt.Errorf("failed to inject helper: %w", injectErr)
+		}
+	}
+	if err != nil {
+		return err
 	}
 
-	slog.Debug("Native cat failed, trying helper injection", "error", err)
+	fo.mu.Lock()
+	fo.verifiedHelpers[key] = true
+	fo.mu.Unlock()
+	return nil
+}
 
-	// Strategy 2: Try helper injection
-	content, err = fo.getFileContentWithHelper(ctx, containerID, filePath)
+// helperVersion asks the injected helper for its version
+func (fo *FileOperations) helperVersion(container *Container) (helperVersionInfo, error) {
+	helperPath := HelperPathFor(container)
+	output, err := ExecuteCaptured(container.OperationArgs("exec", helperPath, "version", "--json")...)
+	if err != nil && isExecutableNotFound(err) && !hasHelperPath(container) {
+		// A read-only container may carry a helper injected into another location by an earlier dcv
+		if located, ok := locateHelper(container); ok {
+			output, err = located, nil
+		}
+	}
+	if err != nil {
+		if isExecutableNotFound(err) {
+			return helperVersionInfo{}, fmt.Errorf("%w at %s: %w", errHelperMissing, helperPath, err)
+		}
+		if strings.Contains(err.Error(), "exec format error") {
+			return helperVersionInfo{}, fmt.Errorf("%w: built for another architecture: %w", errHelperOutdated, err)
+		}
+		return helperVersionInfo{}, fmt.Errorf("helper version failed: %w", err)
+	}
+	return parseHelperVersion(output)
+}
+
+// helperKey identifies a container for the helper verification cache
+func helperKey(container *Container) string {
+	if container.IsDind() {
+		return container.HostContainerID() + "/" + container.ContainerID()
+	}
+	return container.ContainerID()
+}
+
+// MaxFileContentSize is the default limit of how much of a file is read into memory
+const MaxFileContentSize int64 = 10 * 1024 * 1024
+
+// FileContent is the content of a file read from a container
+type FileContent struct {
+	Data []byte
+	// Size is the size of the file in the container. It is larger than len(Data) when Truncated,
+	// or -1 when the file was read with a command that was stopped at the limit.
+	Size      int64
+	Truncated bool
+}
+
+// GetFileContent retrieves up to limit bytes of a file from a container using multiple strategies
+func (fo *FileOperations) GetFileContent(ctx context.Context, container *Container, filePath string, limit int64) (*FileContent, error) {
+	var errs []string
+	// An empty file from an archive is only used when reading it directly fails too
+	var empty *FileContent
+
+	// Strategy 1: Read the archive through the Docker API (not available for DinD containers)
+	if fo.client != nil && !container.IsDind() {
+		content, err := fo.getFileContentFromArchive(ctx, container, filePath, limit)
+		if err == nil && !isPseudoFile(content) {
+			return content, nil
+		}
+		if err == nil {
+			empty = content
+		} else {
+			slog.Debug("Reading file through the Docker API failed, trying docker cp", slog.Any("error", err))
+			errs = append(errs, fmt.Sprintf("api: %s", err))
+		}
+	}
+
+	// Strategy 2: docker cp to stdout, which also works through the DinD host container
+	if fo.client == nil || container.IsDind() {
+		content, err := fo.getFileContentWithCp(container, filePath, limit)
+		if err == nil && !isPseudoFile(content) {
+			return content, nil
+		}
+		if err == nil {
+			empty = content
+		} else {
+			slog.Debug("docker cp failed, trying cat", slog.Any("error", err))
+			errs = append(errs, fmt.Sprintf("cp: %s", err))
+		}
+	}
+
+	// Strategy 3: cat inside the container. Needed for files like /proc/* that
+	// appear empty in archives, and the last resort when copying is not possible.
+	content, err := fo.getFileContentNative(container, filePath, limit)
+	if err == nil {
+		return content, nil
+	}
+	slog.Debug("Native cat failed, trying helper injection", slog.Any("error", err))
+	errs = append(errs, fmt.Sprintf("native: %s", err))
+
+	// Strategy 4: cat with the injected helper binary
+	content, err = fo.getFileContentWithHelper(container, filePath, limit)
 	if err == nil {
 		return content, nil
 	}
+	slog.Debug("Helper injection failed", slog.Any("error", err))
+	errs = append(errs, fmt.Sprintf("helper: %s", err))
 
-	slog.Debug("Helper injection failed", "error", err)
+	if empty != nil {
+		return empty, nil
+	}
 
 	// All strategies failed
-	return "", fmt.Errorf("unable to read file: native cat and helper injection both failed")
+	return nil, fmt.Errorf("unable to read file\nContainer: %s, Path: %s\n%s",
+		container.containerID, filePath, strings.Join(errs, "\n"))
+}
+
+// getFileContentFromArchive reads a file through the Docker API archive endpoint
+func (fo *FileOperations) getFileContentFromArchive(ctx context.Context, container *Container, filePath string, limit int64) (*FileContent, error) {
+	reader, stat, err := fo.client.CopyFromContainer(ctx, container.ContainerID(), filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// The archive of a symlink contains the link itself, read the target instead
+	if stat.Mode&os.ModeSymlink != 0 && stat.LinkTarget != "" {
+		_ = reader.Close()
+		reader, stat, err = fo.client.CopyFromContainer(ctx, container.ContainerID(), stat.LinkTarget)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	if stat.Mode.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", filePath)
+	}
+	return extractFileFromTar(reader, limit)
+}
+
+// getFileContentWithCp reads a file with `docker cp CONTAINER:PATH -`, which writes a tar archive to stdout
+func (fo *FileOperations) getFileContentWithCp(container *Container, filePath string, limit int64) (*FileContent, error) {
+	// -L follows a symlink so the archive contains the target file
+	content, err := readCommandOutput(copyFromContainerArgs(container, filePath, "-L"), func(r io.Reader) (*FileContent, error) {
+		return extractFileFromTar(r, limit)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("docker cp failed: %w", err)
+	}
+	return content, nil
+}
+
+// copyFromContainerArgs returns the arguments of `docker cp CONTAINER:PATH -`, which writes a tar archive to stdout
+func copyFromContainerArgs(container *Container, filePath string, flags ...string) []string {
+	var args []string
+	if container.IsDind() {
+		args = []string{"exec", container.HostContainerID(), "docker", "cp"}
+	} else {
+		args = []string{"cp"}
+	}
+	args = append(args, flags...)
+	return append(args, fmt.Sprintf("%s:%s", container.ContainerID(), filePath), "-")
 }
 
 // getFileContentNative tries to get file content using the native cat command
-func (fo *FileOperations) getFileContentNative(ctx context.Context, containerID, filePath string) (string, error) {
-	// Create a temporary container for executing the cat command
-	container := NewContainer(containerID, "", "", "")
-	args := container.OperationArgs("exec", "cat", filePath)
-	outputBytes, err := ExecuteCaptured(args...)
+func (fo *FileOperations) getFileContentNative(container *Container, filePath string, limit int64) (*FileContent, error) {
+	content, err := readCommandOutput(container.OperationArgs("exec", "cat", filePath), limitedReader(limit))
 	if err != nil {
-		return "", fmt.Errorf("native cat failed: %w", err)
+		return nil, fmt.Errorf("native cat failed: %w", err)
 	}
-	return string(outputBytes), nil
+	return content, nil
 }
 
-// getFileContentWithHelper gets file content using docker cp command
-func (fo *FileOperations) getFileContentWithHelper(ctx context.Context, containerID, filePath string) (string, error) {
-	// Use docker cp to extract the file content
-	// docker cp CONTAINER:PATH - outputs to stdout
-	args := []string{"docker", "cp", fmt.Sprintf("%s:%s", containerID, filePath), "-"}
-	captured, err := ExecuteCaptured(args...)
+// getFileContentWithHelper gets file content using the cat command of the injected helper binary
+func (fo *FileOperations) getFileContentWithHelper(container *Container, filePath string, limit int64) (*FileContent, error) {
+	content, err := readCommandOutput(container.OperationArgs("exec", HelperPathFor(container), "cat", filePath), limitedReader(limit))
 	if err != nil {
-		return "", fmt.Errorf("docker cp failed: %w", err)
+		return nil, fmt.Errorf("helper cat failed: %w", err)
+	}
+	return content, nil
+}
+
+// readCommandOutput runs a docker command and passes its output to read. The command is killed when read
+// cuts the content or fails, so that a large file is never transferred in full.
+func readCommandOutput(args []string, read func(io.Reader) (*FileContent, error)) (*FileContent, error) {
+	cmd := Execute(args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stdout pipe: %w", err)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	content, readErr := read(stdout)
+	if readErr != nil || content.Truncated {
+		_ = cmd.Process.Kill()
+		waitErr := cmd.Wait()
+		if readErr == nil {
+			return content, nil
+		}
+		// A command that failed explains why on stderr, while the read only saw the output end
+		if message := strings.TrimSpace(stderr.String()); waitErr != nil && message != "" {
+			return nil, fmt.Errorf("%w: %s", waitErr, message)
+		}
+		return nil, readErr
+	}
+	// Only the end of an archive is left
+	_, _ = io.Copy(io.Discard, stdout)
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
 	}
+	return content, nil
+}
+
+// extractFileFromTar returns the first regular file of a tar archive, reading at most limit bytes
+func extractFileFromTar(r io.Reader, limit int64) (*FileContent, error) {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("archive does not contain a regular file")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeReg:
+			// handled below
+		case tar.TypeDir:
+			return nil, fmt.Errorf("%s is a directory", header.Name)
+		default:
+			continue
+		}
 
-	// The output is in tar format, so we need to extract the actual file content
-	// For simplicity, we'll just return the raw output for now
-	// TODO: Properly extract content from tar format
-	return string(captured), nil
+		data, err := io.ReadAll(io.LimitReader(tr, limit))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from archive: %w", header.Name, err)
+		}
+		return &FileContent{
+			Data:      data,
+			Size:      header.Size,
+			Truncated: header.Size > int64(len(data)),
+		}, nil
+	}
+}
+
+// limitedReader reads the output of a command up to limit bytes. The size of a longer output is unknown,
+// since it is not read any further.
+func limitedReader(limit int64) func(io.Reader) (*FileContent, error) {
+	return func(r io.Reader) (*FileContent, error) {
+		data, err := io.ReadAll(io.LimitReader(r, limit+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) > limit {
+			return &FileContent{Data: data[:limit], Size: -1, Truncated: true}, nil
+		}
+		return &FileContent{Data: data, Size: int64(len(data))}, nil
+	}
 }
 
-// parseHelperLsOutput parses the output from our helper's ls command
-// The format is now similar to ls -la:
-// drwxr-xr-x  2 uid gid 4096 Jan 1 00:00 dirname
-func parseHelperLsOutput(output string) []models.ContainerFile {
-	// The helper now outputs in ls -la format, so we can reuse the standard parser
-	return models.ParseLsOutput(output)
+// isPseudoFile reports whether an archived file looks like a procfs/sysfs entry.
+// Those report a size of 0 and come out empty, although reading them yields content.
+func isPseudoFile(content *FileContent) bool {
+	return content.Size == 0
 }
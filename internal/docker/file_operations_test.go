@@ -0,0 +1,127 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tarEntry is an entry of a test archive; the size of regular files is that of body
+type tarEntry struct {
+	header *tar.Header
+	body   string
+}
+
+func buildTar(t *testing.T, entries ...tarEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		if e.header.Typeflag == tar.TypeReg {
+			e.header.Size = int64(len(e.body))
+		}
+		require.NoError(t, tw.WriteHeader(e.header))
+		if e.body != "" {
+			_, err := tw.Write([]byte(e.body))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func dirEntry(name string) tarEntry {
+	return tarEntry{header: &tar.Header{Typeflag: tar.TypeDir, Name: name, Mode: 0755}}
+}
+
+func fileEntry(name, body string) tarEntry {
+	return tarEntry{header: &tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0644}, body: body}
+}
+
+func TestExtractFileFromTar(t *testing.T) {
+	t.Run("regular file", func(t *testing.T) {
+		archive := buildTar(t, fileEntry("app.bin", strings.Repeat("\xffa", 5)))
+
+		content, err := extractFileFromTar(bytes.NewReader(archive), MaxFileContentSize)
+		require.NoError(t, err)
+		assert.Equal(t, bytes.Repeat([]byte{0xff, 'a'}, 5), content.Data)
+		assert.Equal(t, int64(10), content.Size)
+		assert.False(t, content.Truncated)
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		archive := buildTar(t, fileEntry("big.log", strings.Repeat("\xffa", 50)))
+
+		content, err := extractFileFromTar(bytes.NewReader(archive), 8)
+		require.NoError(t, err)
+		assert.Len(t, content.Data, 8)
+		assert.Equal(t, int64(100), content.Size)
+		assert.True(t, content.Truncated)
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		archive := buildTar(t, dirEntry("etc/"))
+
+		_, err := extractFileFromTar(bytes.NewReader(archive), MaxFileContentSize)
+		assert.ErrorContains(t, err, "is a directory")
+	})
+
+	t.Run("skips non-regular entries", func(t *testing.T) {
+		archive := buildTar(t,
+			tarEntry{header: &tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "target"}},
+			fileEntry("target", "data"),
+		)
+
+		content, err := extractFileFromTar(bytes.NewReader(archive), MaxFileContentSize)
+		require.NoError(t, err)
+		assert.Len(t, content.Data, 4)
+	})
+
+	t.Run("no regular file", func(t *testing.T) {
+		archive := buildTar(t, tarEntry{header: &tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "target"}})
+
+		_, err := extractFileFromTar(bytes.NewReader(archive), MaxFileContentSize)
+		assert.ErrorContains(t, err, "does not contain a regular file")
+	})
+
+	t.Run("not a tar archive", func(t *testing.T) {
+		_, err := extractFileFromTar(bytes.NewReader([]byte("plain text output")), MaxFileContentSize)
+		assert.ErrorContains(t, err, "failed to read archive")
+	})
+}
+
+func TestLimitedReader(t *testing.T) {
+	content, err := limitedReader(10)(strings.NewReader("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), content.Data)
+	assert.Equal(t, int64(5), content.Size)
+	assert.False(t, content.Truncated)
+
+	content, err = limitedReader(5)(strings.NewReader("hello"))
+	require.NoError(t, err)
+	assert.False(t, content.Truncated, "an output of exactly the limit is complete")
+
+	// Only one byte more than the limit is read
+	rest := strings.NewReader("hello world")
+	content, err = limitedReader(5)(rest)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), content.Data)
+	assert.Equal(t, int64(-1), content.Size)
+	assert.True(t, content.Truncated)
+	assert.Equal(t, 5, rest.Len())
+}
+
+func TestIsPseudoFile(t *testing.T) {
+	assert.True(t, isPseudoFile(&FileContent{}))
+	assert.False(t, isPseudoFile(&FileContent{Data: []byte("x"), Size: 1}))
+}
+
+func TestNativeLsArgs(t *testing.T) {
+	container := NewContainer("abc123", "web", "web", "running")
+	assert.Equal(t, []string{"exec", "abc123", "ls", "-la", "--time-style=+%s", "/etc/"}, nativeLsArgs(container, "/etc", true))
+	assert.Equal(t, []string{"exec", "abc123", "ls", "-la", "/etc/"}, nativeLsArgs(container, "/etc/", false))
+}
@@ -0,0 +1,91 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// GetFileSize returns the size of a file in a container, following symlinks
+func (fo *FileOperations) GetFileSize(ctx context.Context, container *Container, filePath string) (int64, error) {
+	var errs []string
+
+	// Strategy 1: Stat through the Docker API (not available for DinD containers)
+	if fo.client != nil && !container.IsDind() {
+		stat, err := fo.client.ContainerStatPath(ctx, container.ContainerID(), filePath)
+		if err == nil && stat.LinkTarget != "" && stat.LinkTarget != filePath {
+			stat, err = fo.client.ContainerStatPath(ctx, container.ContainerID(), stat.LinkTarget)
+		}
+		if err == nil {
+			if stat.Mode.IsDir() {
+				return 0, fmt.Errorf("%s is a directory", filePath)
+			}
+			return stat.Size, nil
+		}
+		slog.Debug("Stat through the Docker API failed, trying stat", slog.Any("error", err))
+		errs = append(errs, fmt.Sprintf("api: %s", err))
+	}
+
+	// Strategy 2: stat inside the container
+	output, err := ExecuteCaptured(container.OperationArgs("exec", "stat", "-L", "-c", "%s", filePath)...)
+	if err == nil {
+		return parseFileSize(output)
+	}
+	slog.Debug("Native stat failed, trying the helper", slog.Any("error", err))
+	errs = append(errs, fmt.Sprintf("native: %s", err))
+
+	// Strategy 3: the injected helper
+	if err := fo.ensureHelper(ctx, container, false); err != nil {
+		errs = append(errs, fmt.Sprintf("helper: %s", err))
+	} else {
+		output, err := ExecuteCaptured(HelperArgs(container, "size", filePath)...)
+		if err == nil {
+			return parseFileSize(output)
+		}
+		errs = append(errs, fmt.Sprintf("helper: %s", err))
+	}
+
+	return 0, fmt.Errorf("unable to get the size of %s:\n%s", filePath, strings.Join(errs, "\n"))
+}
+
+func parseFileSize(output []byte) (int64, error) {
+	size, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected size %q: %w", strings.TrimSpace(string(output)), err)
+	}
+	return size, nil
+}
+
+// ReadFileRange reads up to length bytes of a file in a container, starting at offset.
+// Only the requested range is transferred, so that parts of very large files can be shown.
+func (fo *FileOperations) ReadFileRange(ctx context.Context, container *Container, filePath string, offset, length int64) ([]byte, error) {
+	// Strategy 1: tail and head inside the container
+	data, errNative := ExecuteCaptured(readRangeNativeArgs(container, filePath, offset, length)...)
+	if errNative == nil {
+		return data, nil
+	}
+	slog.Debug("Reading a range with tail and head failed, trying the helper", slog.Any("error", errNative))
+
+	// Strategy 2: the injected helper
+	errHelper := fo.ensureHelper(ctx, container, false)
+	if errHelper == nil {
+		data, errHelper = ExecuteCaptured(readRangeHelperArgs(container, filePath, offset, length)...)
+		if errHelper == nil {
+			return data, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unable to read %s at offset %d:\nnative: %s\nhelper: %s", filePath, offset, errNative, errHelper)
+}
+
+// readRangeNativeArgs reads a range with `tail -c +N | head -c M`. tail counts from 1.
+func readRangeNativeArgs(container *Container, filePath string, offset, length int64) []string {
+	return container.OperationArgs("exec", "sh", "-c", `tail -c +"$1" "$3" | head -c "$2"`, "sh",
+		strconv.FormatInt(offset+1, 10), strconv.FormatInt(length, 10), filePath)
+}
+
+func readRangeHelperArgs(container *Container, filePath string, offset, length int64) []string {
+	return HelperArgs(container, "cat", "-offset", strconv.FormatInt(offset, 10), "-length", strconv.FormatInt(length, 10), filePath)
+}
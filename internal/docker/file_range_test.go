@@ -0,0 +1,49 @@
+package docker
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRangeArgs(t *testing.T) {
+	container := NewContainer("abc123", "web", "web", "running")
+	assert.Equal(t, []string{"exec", "abc123", "/.dcv-helper", "cat", "-offset", "100", "-length", "50", "/var/log/app.log"},
+		readRangeHelperArgs(container, "/var/log/app.log", 100, 50))
+
+	args := readRangeNativeArgs(container, "/var/log/app.log", 100, 50)
+	assert.Equal(t, []string{"exec", "abc123", "sh", "-c"}, args[:4])
+	assert.Equal(t, []string{"sh", "101", "50", "/var/log/app.log"}, args[5:])
+}
+
+func TestReadRangeNativeCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	path := filepath.Join(t.TempDir(), "data.txt")
+	require.NoError(t, os.WriteFile(path, []byte("0123456789"), 0600))
+
+	// Run the script the way it runs in a container, without docker exec
+	args := readRangeNativeArgs(NewContainer("abc123", "web", "web", "running"), path, 3, 4)
+	output, err := exec.Command(args[2], args[3:]...).Output()
+	require.NoError(t, err)
+	assert.Equal(t, "3456", string(output))
+
+	args = readRangeNativeArgs(NewContainer("abc123", "web", "web", "running"), path, 8, 100)
+	output, err = exec.Command(args[2], args[3:]...).Output()
+	require.NoError(t, err)
+	assert.Equal(t, "89", string(output))
+}
+
+func TestParseFileSize(t *testing.T) {
+	size, err := parseFileSize([]byte("3221225472\n"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3221225472), size)
+
+	_, err = parseFileSize([]byte("stat: not found"))
+	assert.Error(t, err)
+}
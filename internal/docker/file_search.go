@@ -0,0 +1,226 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FileSearchKind selects between searching file names and file contents
+type FileSearchKind int
+
+const (
+	// FileSearchFind searches file names like find(1)
+	FileSearchFind FileSearchKind = iota
+	// FileSearchGrep searches file contents like grep -rn
+	FileSearchGrep
+)
+
+// FileSearchQuery describes a recursive search below a directory of a container
+type FileSearchQuery struct {
+	Kind FileSearchKind
+	Dir  string
+	// Pattern is a glob for find, or a regular expression for grep and for find with Regex set
+	Pattern string
+	// Regex matches the find pattern as a regular expression against the full path.
+	// The dialects of find -regex differ, so it always runs in the helper.
+	Regex bool
+	// MaxDepth limits how deep find descends; -1 means no limit
+	MaxDepth int
+	// Type restricts find to regular files ("f") or directories ("d")
+	Type string
+	// IgnoreCase makes grep and regex find case insensitive
+	IgnoreCase bool
+}
+
+// FileSearchHit is a single search result
+type FileSearchHit struct {
+	Path string
+	// Line is the 1-based line number of a grep match, 0 for find results
+	Line int
+	Text string
+}
+
+// FileSearch is a validated search that can be run in a container
+type FileSearch struct {
+	Query FileSearchQuery
+}
+
+// pseudoFilesystems are skipped by searches; walking them is slow and never what the user wants
+var pseudoFilesystems = []string{"/proc", "/sys", "/dev"}
+
+// NewFileSearch validates the query. Regular expressions use Go syntax, which matches
+// the extended regular expressions of grep -E for everyday patterns.
+func NewFileSearch(q FileSearchQuery) (*FileSearch, error) {
+	if q.Pattern == "" {
+		return nil, fmt.Errorf("search pattern is empty")
+	}
+	if q.Dir == "" {
+		q.Dir = "/"
+	}
+	if q.Type != "" && q.Type != "f" && q.Type != "d" {
+		return nil, fmt.Errorf("invalid file type %q: use f or d", q.Type)
+	}
+
+	s := &FileSearch{Query: q}
+	if q.Kind == FileSearchGrep || q.Regex {
+		if _, err := regexp.Compile(s.expr()); err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+	} else if _, err := path.Match(q.Pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid glob pattern: %w", err)
+	}
+	return s, nil
+}
+
+// Command returns the command that streams the search results.
+// The container's own find and grep are used when present; otherwise the helper runs the search
+// and is injected first when needed. A regular expression find always runs in the helper.
+func (s *FileSearch) Command(ctx context.Context, fo *FileOperations, container *Container) (*RemoteCommand, error) {
+	reason := "the helper runs regular expression searches"
+	if !s.Query.Regex || s.Query.Kind == FileSearchGrep {
+		// grep is run through find so that pseudo filesystems can be pruned; both have to exist,
+		// and sh to stop the search in the container
+		probes := [][]string{{"sh", "-c", ":"}, {"find", s.Query.Dir, "-maxdepth", "0"}}
+		if s.Query.Kind == FileSearchGrep {
+			probes = append(probes, []string{"grep", "-q", "dcv", "/dev/null"})
+		}
+
+		missing := ""
+		for _, probe := range probes {
+			// Other failures, like a missing directory, are reported by the search itself
+			if _, err := ExecuteCaptured(container.OperationArgs("exec", probe...)...); err != nil && isExecutableNotFound(err) {
+				missing = probe[0]
+				break
+			}
+		}
+		if missing == "" {
+			return remoteShellCommand(container, s.nativeArgs()...), nil
+		}
+		reason = "the container has no " + missing
+	}
+
+	if err := fo.PrepareHelper(ctx, container); err != nil {
+		return nil, fmt.Errorf("%s and the helper cannot be used: %w", reason, err)
+	}
+	return remoteHelperCommand(container, s.helperCommand(), s.helperArgs()...), nil
+}
+
+// expr returns the regular expression of a grep or regex find in Go syntax
+func (s *FileSearch) expr() string {
+	if s.Query.IgnoreCase {
+		return "(?i)" + s.Query.Pattern
+	}
+	return s.Query.Pattern
+}
+
+// pathGlob returns the find -path glob of a pattern with /, which is relative to the search directory
+func (s *FileSearch) pathGlob() (string, bool) {
+	if !strings.Contains(s.Query.Pattern, "/") {
+		return "", false
+	}
+	return path.Join(s.Query.Dir, s.Query.Pattern), true
+}
+
+// nativeArgs returns the command line for find(1) and grep(1) of the container.
+// Both GNU and busybox understand it.
+func (s *FileSearch) nativeArgs() []string {
+	q := s.Query
+	args := []string{"find", q.Dir}
+	if q.MaxDepth >= 0 {
+		args = append(args, "-maxdepth", strconv.Itoa(q.MaxDepth))
+	}
+
+	// ( -path /proc -o -path /sys -o -path /dev ) -prune -o
+	args = append(args, "(")
+	for i, p := range pseudoFilesystems {
+		if i > 0 {
+			args = append(args, "-o")
+		}
+		args = append(args, "-path", p)
+	}
+	args = append(args, ")", "-prune", "-o")
+
+	if q.Kind == FileSearchGrep {
+		grep := []string{"grep", "-n", "-H", "-E"}
+		if q.IgnoreCase {
+			grep = append(grep, "-i")
+		}
+		args = append(args, "-type", "f", "-exec")
+		args = append(args, grep...)
+		return append(args, "--", q.Pattern, "{}", "+")
+	}
+
+	if q.Type != "" {
+		args = append(args, "-type", q.Type)
+	}
+	if glob, ok := s.pathGlob(); ok {
+		args = append(args, "-path", glob)
+	} else {
+		args = append(args, "-name", q.Pattern)
+	}
+	return append(args, "-print")
+}
+
+func (s *FileSearch) helperCommand() string {
+	if s.Query.Kind == FileSearchGrep {
+		return "grep"
+	}
+	return "find"
+}
+
+// helperArgs returns the arguments of the helper's find or grep command
+func (s *FileSearch) helperArgs() []string {
+	q := s.Query
+	if q.Kind == FileSearchGrep {
+		args := []string{"-r", "-n"}
+		if q.IgnoreCase {
+			args = append(args, "-i")
+		}
+		return append(args, "--", q.Pattern, q.Dir)
+	}
+
+	args := []string{q.Dir}
+	if q.MaxDepth >= 0 {
+		args = append(args, "-maxdepth", strconv.Itoa(q.MaxDepth))
+	}
+	if q.Type != "" {
+		args = append(args, "-type", q.Type)
+	}
+	if q.Regex {
+		return append(args, "-regex", s.expr())
+	}
+	// The helper matches -path like find(1), so that both give the same results
+	if glob, ok := s.pathGlob(); ok {
+		return append(args, "-path", glob)
+	}
+	return append(args, "-name", q.Pattern)
+}
+
+// grepLineRe matches "path:line:text". The path is matched lazily, so only paths
+// that themselves contain ":<digits>:" are split at the wrong place.
+var grepLineRe = regexp.MustCompile(`^(.*?):(\d+):(.*)$`)
+
+// ParseLine turns a line of search output into a hit.
+// It returns false for lines that are not results, like "Binary file x matches".
+func (s *FileSearch) ParseLine(line string) (FileSearchHit, bool) {
+	if s.Query.Kind == FileSearchGrep {
+		m := grepLineRe.FindStringSubmatch(line)
+		if m == nil {
+			return FileSearchHit{}, false
+		}
+		lineNo, err := strconv.Atoi(m[2])
+		if err != nil {
+			return FileSearchHit{}, false
+		}
+		return FileSearchHit{Path: m[1], Line: lineNo, Text: m[3]}, true
+	}
+
+	if line == "" {
+		return FileSearchHit{}, false
+	}
+	return FileSearchHit{Path: line}, true
+}
@@ -0,0 +1,124 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFileSearch(t *testing.T) {
+	t.Run("empty pattern", func(t *testing.T) {
+		_, err := NewFileSearch(FileSearchQuery{Kind: FileSearchFind, Dir: "/"})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid glob", func(t *testing.T) {
+		_, err := NewFileSearch(FileSearchQuery{Kind: FileSearchFind, Pattern: "[a"})
+		assert.ErrorContains(t, err, "invalid glob pattern")
+	})
+
+	t.Run("invalid regexp", func(t *testing.T) {
+		_, err := NewFileSearch(FileSearchQuery{Kind: FileSearchGrep, Pattern: "(foo"})
+		assert.ErrorContains(t, err, "invalid regular expression")
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		_, err := NewFileSearch(FileSearchQuery{Kind: FileSearchFind, Pattern: "*", Type: "l"})
+		assert.ErrorContains(t, err, "invalid file type")
+	})
+
+	t.Run("directory defaults to root", func(t *testing.T) {
+		s, err := NewFileSearch(FileSearchQuery{Kind: FileSearchFind, Pattern: "*.conf"})
+		require.NoError(t, err)
+		assert.Equal(t, "/", s.Query.Dir)
+	})
+}
+
+func TestFileSearch_NativeArgs(t *testing.T) {
+	prune := []string{"(", "-path", "/proc", "-o", "-path", "/sys", "-o", "-path", "/dev", ")", "-prune", "-o"}
+
+	t.Run("find by name", func(t *testing.T) {
+		s, err := NewFileSearch(FileSearchQuery{Kind: FileSearchFind, Dir: "/etc", Pattern: "*.conf", MaxDepth: 2, Type: "f"})
+		require.NoError(t, err)
+		expected := append([]string{"find", "/etc", "-maxdepth", "2"}, prune...)
+		expected = append(expected, "-type", "f", "-name", "*.conf", "-print")
+		assert.Equal(t, expected, s.nativeArgs())
+	})
+
+	t.Run("find by path", func(t *testing.T) {
+		s, err := NewFileSearch(FileSearchQuery{Kind: FileSearchFind, Dir: "/etc", Pattern: "nginx/*.conf", MaxDepth: -1})
+		require.NoError(t, err)
+		expected := append([]string{"find", "/etc"}, prune...)
+		expected = append(expected, "-path", "/etc/nginx/*.conf", "-print")
+		assert.Equal(t, expected, s.nativeArgs())
+	})
+
+	t.Run("grep", func(t *testing.T) {
+		s, err := NewFileSearch(FileSearchQuery{Kind: FileSearchGrep, Dir: "/app", Pattern: "TODO|FIXME", MaxDepth: -1, IgnoreCase: true})
+		require.NoError(t, err)
+		expected := append([]string{"find", "/app"}, prune...)
+		expected = append(expected, "-type", "f", "-exec", "grep", "-n", "-H", "-E", "-i", "--", "TODO|FIXME", "{}", "+")
+		assert.Equal(t, expected, s.nativeArgs())
+	})
+}
+
+func TestFileSearch_HelperArgs(t *testing.T) {
+	t.Run("find", func(t *testing.T) {
+		s, err := NewFileSearch(FileSearchQuery{Kind: FileSearchFind, Dir: "/etc", Pattern: "*.conf", MaxDepth: 1, Type: "d"})
+		require.NoError(t, err)
+		assert.Equal(t, "find", s.helperCommand())
+		assert.Equal(t, []string{"/etc", "-maxdepth", "1", "-type", "d", "-name", "*.conf"}, s.helperArgs())
+	})
+
+	t.Run("find by path matches like find -path", func(t *testing.T) {
+		s, err := NewFileSearch(FileSearchQuery{Kind: FileSearchFind, Dir: "/etc", Pattern: "nginx/*.conf", MaxDepth: -1})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/etc", "-path", "/etc/nginx/*.conf"}, s.helperArgs())
+	})
+
+	t.Run("regex find filters in the helper", func(t *testing.T) {
+		s, err := NewFileSearch(FileSearchQuery{Kind: FileSearchFind, Dir: "/", Pattern: `\.ya?ml$`, Regex: true, MaxDepth: -1, IgnoreCase: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/", "-regex", `(?i)\.ya?ml$`}, s.helperArgs())
+	})
+
+	t.Run("grep", func(t *testing.T) {
+		s, err := NewFileSearch(FileSearchQuery{Kind: FileSearchGrep, Dir: "/app", Pattern: "-v", MaxDepth: -1})
+		require.NoError(t, err)
+		assert.Equal(t, "grep", s.helperCommand())
+		assert.Equal(t, []string{"-r", "-n", "--", "-v", "/app"}, s.helperArgs())
+	})
+}
+
+func TestFileSearch_ParseLine(t *testing.T) {
+	t.Run("grep output", func(t *testing.T) {
+		s, err := NewFileSearch(FileSearchQuery{Kind: FileSearchGrep, Pattern: "listen"})
+		require.NoError(t, err)
+
+		hit, ok := s.ParseLine("/etc/nginx/nginx.conf:12:    listen 80;")
+		require.True(t, ok)
+		assert.Equal(t, FileSearchHit{Path: "/etc/nginx/nginx.conf", Line: 12, Text: "    listen 80;"}, hit)
+
+		hit, ok = s.ParseLine("/app/a.txt:3:time: 10:20:30")
+		require.True(t, ok)
+		assert.Equal(t, "/app/a.txt", hit.Path)
+		assert.Equal(t, 3, hit.Line)
+		assert.Equal(t, "time: 10:20:30", hit.Text)
+
+		_, ok = s.ParseLine("Binary file /bin/sh matches")
+		assert.False(t, ok)
+	})
+
+	t.Run("find output", func(t *testing.T) {
+		s, err := NewFileSearch(FileSearchQuery{Kind: FileSearchFind, Pattern: "*.conf"})
+		require.NoError(t, err)
+
+		hit, ok := s.ParseLine("/etc/resolv.conf")
+		require.True(t, ok)
+		assert.Equal(t, FileSearchHit{Path: "/etc/resolv.conf"}, hit)
+
+		_, ok = s.ParseLine("")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,80 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// DirectorySizes returns the disk usage in bytes of paths in the container, by path.
+// It runs `du -s`, which both du and the helper print in KiB; the container's du is used when it has one.
+// Directories that cannot be read in full, like /proc for a non-root user, still get the size du found.
+func (fo *FileOperations) DirectorySizes(ctx context.Context, container *Container, paths ...string) (map[string]int64, error) {
+	if len(paths) == 0 {
+		return map[string]int64{}, nil
+	}
+	args, err := fo.FileCommandArgs(ctx, container, "du", append([]string{"-s"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := Execute(args...).Output()
+	sizes := parseDuOutput(output)
+	if err != nil && len(sizes) == 0 {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("du failed: %w\n%s", err, bytes.TrimSpace(exitErr.Stderr))
+		}
+		return nil, fmt.Errorf("du failed: %w", err)
+	}
+	return sizes, nil
+}
+
+// parseDuOutput parses the "KIB\tPATH" lines of du into sizes in bytes by path
+func parseDuOutput(output []byte) map[string]int64 {
+	sizes := make(map[string]int64)
+	for _, line := range strings.Split(string(output), "\n") {
+		size, p, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		kib, err := strconv.ParseInt(strings.TrimSpace(size), 10, 64)
+		if err != nil {
+			continue
+		}
+		sizes[p] = kib * 1024
+	}
+	return sizes
+}
+
+// DirectorySizes returns the total size of the files below paths in the image, by path
+func (f *ImageFilesystem) DirectorySizes(paths ...string) map[string]int64 {
+	sizes := make(map[string]int64, len(paths))
+	for _, p := range paths {
+		sizes[p] = f.index.usage(cleanArchivePath(p))
+	}
+	return sizes
+}
+
+// usage sums the sizes of the files below name; hard links are counted once
+func (x *fsIndex) usage(name string) int64 {
+	entry, ok := x.entries[name]
+	if !ok {
+		return 0
+	}
+	if !entry.file.IsDir {
+		if entry.linkTo != "" || entry.file.LinkTarget != "" {
+			return 0
+		}
+		return entry.file.Size
+	}
+	var total int64
+	for _, child := range x.children[name] {
+		total += x.usage(child)
+	}
+	return total
+}
@@ -0,0 +1,37 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDuOutput(t *testing.T) {
+	output := []byte("4\t/app/config\n1536\t/app/node_modules\ndu: cannot read directory '/app/secret': Permission denied\n\n")
+	assert.Equal(t, map[string]int64{
+		"/app/config":       4 * 1024,
+		"/app/node_modules": 1536 * 1024,
+	}, parseDuOutput(output))
+}
+
+func TestImageFilesystemDirectorySizes(t *testing.T) {
+	archive := buildTar(t,
+		dirEntry("./"),
+		fileEntry("app/main.js", "console.log(1)"),
+		fileEntry("app/lib/util.js", "exports"),
+		tarEntry{header: &tar.Header{Typeflag: tar.TypeLink, Name: "app/lib/copy.js", Linkname: "app/lib/util.js"}},
+		tarEntry{header: &tar.Header{Typeflag: tar.TypeSymlink, Name: "app/current", Linkname: "lib", Mode: 0777}},
+	)
+	index, err := indexFilesystem(bytes.NewReader(archive))
+	require.NoError(t, err)
+	image := &ImageFilesystem{index: index}
+
+	assert.Equal(t, map[string]int64{
+		"/app":     int64(len("console.log(1)") + len("exports")),
+		"/app/lib": int64(len("exports")),
+		"/nowhere": 0,
+	}, image.DirectorySizes("/app", "/app/lib", "/nowhere"))
+}
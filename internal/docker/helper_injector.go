@@ -1,10 +1,144 @@
 package docker
 
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/docker/docker/client"
+)
+
 // HelperInjector is kept for backward compatibility
-// The actual logic has been moved to view_helper_injector.go
+// The interactive injection lives in view_helper_injector.go
 type HelperInjector struct{}
 
 // GetHelperPath returns the path where the helper binary will be injected
 func GetHelperPath() string {
 	return "/.dcv-helper"
 }
+
+// DetectContainerArch tries to detect the container's architecture.
+// It returns "" when the architecture cannot be determined.
+func DetectContainerArch(ctx context.Context, dockerClient *client.Client, container *Container) string {
+	// Inspect container to get architecture
+	inspect, err := dockerClient.ContainerInspect(ctx, container.ContainerID())
+	if err != nil {
+		slog.Debug("Failed to inspect container for architecture", "error", err)
+		return ""
+	}
+
+	// Architecture is in format like "amd64", "arm64", etc.
+	if inspect.Platform != "" {
+		// Platform might be like "linux/amd64"
+		parts := strings.Split(inspect.Platform, "/")
+		if len(parts) > 1 {
+			return parts[1]
+		}
+	}
+
+	// Try to get from image config
+	if inspect.Config != nil && inspect.Config.Labels != nil {
+		if arch, ok := inspect.Config.Labels["architecture"]; ok {
+			return arch
+		}
+	}
+
+	// Default detection failed
+	return ""
+}
+
+// WriteHelperTempFile writes the embedded helper binary for arch into an executable temporary file.
+// An empty arch means the architecture dcv runs on. The caller removes the file.
+func WriteHelperTempFile(arch string) (string, error) {
+	if arch == "" {
+		arch = runtime.GOARCH
+	}
+
+	binaryData, err := GetHelperBinary(arch)
+	if err != nil {
+		return "", fmt.Errorf("failed to get helper binary: %w", err)
+	}
+
+	tempFile, err := os.CreateTemp("", "dcv-helper-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer func() {
+		_ = tempFile.Close()
+	}()
+
+	if _, err := tempFile.Write(binaryData); err != nil {
+		_ = os.Remove(tempFile.Name())
+		return "", fmt.Errorf("failed to write helper binary to temp file: %w", err)
+	}
+
+	if err := tempFile.Chmod(0755); err != nil {
+		_ = os.Remove(tempFile.Name())
+		return "", fmt.Errorf("failed to make temp file executable: %w", err)
+	}
+
+	return tempFile.Name(), nil
+}
+
+// HelperInjectCommands returns the docker arguments that copy the helper from tempFile into the container.
+// DinD containers get the helper through their host container.
+func HelperInjectCommands(container *Container, tempFile string, helperPath string) [][]string {
+	if container.IsDind() {
+		return [][]string{
+			{"cp", tempFile, fmt.Sprintf("%s:%s", container.HostContainerID(), helperPath)},
+			{"exec", container.HostContainerID(), "docker", "cp", helperPath, fmt.Sprintf("%s:%s", container.ContainerID(), helperPath)},
+		}
+	}
+	return [][]string{
+		{"cp", tempFile, fmt.Sprintf("%s:%s", container.ContainerID(), helperPath)},
+	}
+}
+
+// InjectHelper copies the embedded helper binary into the container without user interaction.
+// When the root filesystem is read-only or not writable by the container user, the helper is put
+// into the first other location that is writable and executable, and its path is recorded for
+// HelperPathFor. A *HelperInjectError explains why no location could be used.
+func (fo *FileOperations) InjectHelper(ctx context.Context, container *Container) error {
+	arch := fo.helperArch(ctx, container)
+
+	binary, err := GetHelperBinary(arch)
+	if err != nil {
+		return fmt.Errorf("failed to get helper binary: %w", err)
+	}
+	tempFile, err := WriteHelperTempFile(arch)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tempFile)
+	}()
+
+	var previous string
+	if hasHelperPath(container) {
+		previous = HelperPathFor(container)
+	}
+	// Mounts and $HOME are only extra candidates, so the fixed ones are tried when inspect fails
+	inspectOutput, err := ExecuteCaptured(container.DaemonArgs("inspect", container.ContainerID())...)
+	if err != nil {
+		slog.Debug("Failed to inspect container for helper locations", slog.Any("error", err))
+	}
+
+	injectErr := &HelperInjectError{Container: container}
+	for _, helperPath := range helperCandidates(previous, inspectOutput) {
+		err := injectHelperAt(container, tempFile, binary, helperPath)
+		if err != nil {
+			slog.Debug("Cannot inject helper",
+				slog.String("path", helperPath),
+				slog.Any("error", err))
+			injectErr.Attempts = append(injectErr.Attempts, HelperInjectAttempt{Path: helperPath, Err: err})
+			continue
+		}
+		setHelperPath(container, helperPath)
+		fo.recordInjection(container, arch)
+		return nil
+	}
+	return injectErr
+}
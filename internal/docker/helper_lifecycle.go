@@ -0,0 +1,228 @@
+package docker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// errHelperChecksum means the injected helper speaks the current protocol but is not the embedded binary
+var errHelperChecksum = errors.New("injected helper does not match the embedded one")
+
+// HelperStatus describes the helper found in a container
+type HelperStatus struct {
+	Container *Container
+	Version   string
+	Protocol  int
+	// Arch is the architecture of the embedded binary the helper was compared with
+	Arch string
+	// Checksum is the SHA-256 of the injected binary, "" when the helper cannot compute it
+	Checksum string
+	// Expected is the SHA-256 of the embedded binary, "" when dcv was built without it
+	Expected string
+	// InjectedAt is when this dcv injected the helper; zero for helpers found in the container
+	InjectedAt time.Time
+	// Err tells why the helper cannot be used as it is, e.g. because it is outdated
+	Err error
+}
+
+// State summarizes the status in a word for lists
+func (s HelperStatus) State() string {
+	switch {
+	case errors.Is(s.Err, errHelperOutdated):
+		return "outdated"
+	case errors.Is(s.Err, errHelperChecksum):
+		return "mismatch"
+	case s.Err != nil:
+		return "broken"
+	case s.Expected == "" || s.Checksum == "":
+		return "unverified"
+	default:
+		return "ok"
+	}
+}
+
+// injectedHelper records a helper injected by this dcv
+type injectedHelper struct {
+	container *Container
+	arch      string
+	at        time.Time
+}
+
+// HelperChecksum returns the SHA-256 of the embedded helper binary for arch
+func HelperChecksum(arch string) (string, error) {
+	binary, err := GetHelperBinary(arch)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(binary)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// helperArch returns the architecture of the helper binary that fits the container
+func (fo *FileOperations) helperArch(ctx context.Context, container *Container) string {
+	if fo.client != nil {
+		if arch := DetectContainerArch(ctx, fo.client, container); arch != "" {
+			return arch
+		}
+	}
+	return runtime.GOARCH
+}
+
+// InspectHelper checks the helper injected into the container against the embedded binary.
+// It returns errHelperMissing when the container has no helper. A helper that is outdated or
+// differs from the embedded binary is reported in HelperStatus.Err.
+func (fo *FileOperations) InspectHelper(ctx context.Context, container *Container) (HelperStatus, error) {
+	status := HelperStatus{Container: container}
+	fo.mu.Lock()
+	if injected, ok := fo.injectedHelpers[helperKey(container)]; ok {
+		status.InjectedAt = injected.at
+		status.Arch = injected.arch
+	}
+	fo.mu.Unlock()
+
+	info, err := fo.helperVersion(container)
+	if errors.Is(err, errHelperMissing) {
+		return status, err
+	}
+	status.Version = info.Version
+	status.Protocol = info.Protocol
+	if err != nil {
+		status.Err = err
+		return status, nil
+	}
+
+	if status.Arch == "" {
+		status.Arch = fo.helperArch(ctx, container)
+	}
+	if expected, err := HelperChecksum(status.Arch); err == nil {
+		status.Expected = expected
+	} else {
+		slog.Debug("Embedded helper is not available for verification", slog.Any("error", err))
+	}
+	output, err := ExecuteCaptured(HelperArgs(container, "sha256", HelperPathFor(container))...)
+	if err != nil {
+		status.Err = fmt.Errorf("failed to compute the checksum of the helper: %w", err)
+		return status, nil
+	}
+	status.Checksum = parseSha256Output(output)
+	if status.Expected != "" && status.Checksum != status.Expected {
+		status.Err = fmt.Errorf("%w: sha256 %s, expected %s for %s", errHelperChecksum,
+			shortChecksum(status.Checksum), shortChecksum(status.Expected), status.Arch)
+	}
+	return status, nil
+}
+
+// parseSha256Output returns the checksum of the first line of sha256sum-style output
+func parseSha256Output(output []byte) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	sum, _, _ := strings.Cut(line, " ")
+	return sum
+}
+
+func shortChecksum(sum string) string {
+	if len(sum) > 12 {
+		return sum[:12]
+	}
+	return sum
+}
+
+// recordInjection remembers that this dcv injected the helper into the container
+func (fo *FileOperations) recordInjection(container *Container, arch string) {
+	fo.mu.Lock()
+	defer fo.mu.Unlock()
+	fo.injectedHelpers[helperKey(container)] = injectedHelper{container: container, arch: arch, at: time.Now()}
+	// A DinD container gets the helper through its host, which keeps a copy
+	if container.IsDind() {
+		host := NewContainer(container.HostContainerID(), container.HostContainerName(), container.HostContainerName(), "running")
+		if _, ok := fo.injectedHelpers[helperKey(host)]; !ok {
+			fo.injectedHelpers[helperKey(host)] = injectedHelper{container: host, arch: arch, at: time.Now()}
+		}
+	}
+}
+
+// RecordInjection remembers a helper injected outside FileOperations, e.g. interactively,
+// so that it is verified again and listed with the injected helpers.
+// An empty arch means the architecture dcv runs on.
+func (fo *FileOperations) RecordInjection(container *Container, arch string) {
+	if arch == "" {
+		arch = runtime.GOARCH
+	}
+	fo.forgetVerification(container)
+	fo.recordInjection(container, arch)
+}
+
+func (fo *FileOperations) forgetVerification(container *Container) {
+	fo.mu.Lock()
+	defer fo.mu.Unlock()
+	delete(fo.verifiedHelpers, helperKey(container))
+}
+
+// ReinjectHelper replaces the helper of the container with the embedded binary
+func (fo *FileOperations) ReinjectHelper(ctx context.Context, container *Container) error {
+	fo.forgetVerification(container)
+	if err := fo.InjectHelper(ctx, container); err != nil {
+		return err
+	}
+	return fo.ensureHelper(ctx, container, false)
+}
+
+// EjectHelperArgs returns the docker arguments that make the helper remove itself from the container
+func EjectHelperArgs(container *Container) []string {
+	return HelperArgs(container, "rm", "-f", HelperPathFor(container))
+}
+
+// ForgetHelper drops what is known about the helper of the container, including where it was injected.
+// Build the arguments that eject the helper before calling it.
+func (fo *FileOperations) ForgetHelper(container *Container) {
+	fo.mu.Lock()
+	defer fo.mu.Unlock()
+	delete(fo.verifiedHelpers, helperKey(container))
+	delete(fo.injectedHelpers, helperKey(container))
+	forgetHelperPath(container)
+}
+
+// FindHelpers inspects the helpers of the given containers and of the containers this dcv
+// injected one into, e.g. in DinD, and returns those that carry a helper
+func (fo *FileOperations) FindHelpers(ctx context.Context, containers []*Container) []HelperStatus {
+	candidates := make(map[string]*Container)
+	for _, container := range containers {
+		if container.GetState() == "running" {
+			candidates[helperKey(container)] = container
+		}
+	}
+	fo.mu.Lock()
+	for key, injected := range fo.injectedHelpers {
+		if _, ok := candidates[key]; !ok {
+			candidates[key] = injected.container
+		}
+	}
+	fo.mu.Unlock()
+
+	var found []HelperStatus
+	for key, container := range candidates {
+		status, err := fo.InspectHelper(ctx, container)
+		if err == nil && status.Err != nil && strings.Contains(status.Err.Error(), "No such container") {
+			err = status.Err
+		}
+		if err != nil {
+			// Ejected, or removed outside dcv together with its container
+			fo.mu.Lock()
+			delete(fo.injectedHelpers, key)
+			fo.mu.Unlock()
+			continue
+		}
+		found = append(found, status)
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Container.Title() < found[j].Container.Title()
+	})
+	return found
+}
@@ -0,0 +1,60 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSha256Output(t *testing.T) {
+	assert.Equal(t, "9f86d081884c7d65", parseSha256Output([]byte("9f86d081884c7d65  /.dcv-helper\n")))
+	assert.Equal(t, "", parseSha256Output(nil))
+}
+
+func TestHelperStatusState(t *testing.T) {
+	assert.Equal(t, "ok", HelperStatus{Checksum: "abc", Expected: "abc"}.State())
+	assert.Equal(t, "unverified", HelperStatus{Checksum: "abc"}.State())
+	assert.Equal(t, "outdated", HelperStatus{Err: errHelperOutdated}.State())
+	assert.Equal(t, "mismatch", HelperStatus{Err: errHelperChecksum}.State())
+	assert.Equal(t, "broken", HelperStatus{Err: errors.New("permission denied")}.State())
+}
+
+func TestHelperChecksum(t *testing.T) {
+	binary, err := GetHelperBinary("amd64")
+	if err != nil {
+		t.Skip("helper binaries are not embedded")
+	}
+	sum, err := HelperChecksum("amd64")
+	require.NoError(t, err)
+	expected := sha256.Sum256(binary)
+	assert.Equal(t, hex.EncodeToString(expected[:]), sum)
+
+	_, err = HelperChecksum("s390x")
+	assert.Error(t, err)
+}
+
+func TestRecordInjection(t *testing.T) {
+	fo := NewFileOperations(nil)
+	dind := NewDindContainer("host1", "dind", "inner1", "app", "running")
+	fo.verifiedHelpers[helperKey(dind)] = true
+	fo.RecordInjection(dind, "arm64")
+
+	assert.False(t, fo.verifiedHelpers[helperKey(dind)], "a new helper is verified again")
+	require.Contains(t, fo.injectedHelpers, "host1/inner1")
+	require.Contains(t, fo.injectedHelpers, "host1", "the host keeps the copy it passed on")
+	assert.Equal(t, "dind", fo.injectedHelpers["host1"].container.Title())
+	assert.Equal(t, "arm64", fo.injectedHelpers["host1/inner1"].arch)
+
+	fo.ForgetHelper(dind)
+	assert.NotContains(t, fo.injectedHelpers, "host1/inner1")
+	assert.Contains(t, fo.injectedHelpers, "host1")
+}
+
+func TestEjectHelperArgs(t *testing.T) {
+	container := NewContainer("abc123", "web", "web", "running")
+	assert.Equal(t, []string{"exec", "abc123", "/.dcv-helper", "rm", "-f", "/.dcv-helper"}, EjectHelperArgs(container))
+}
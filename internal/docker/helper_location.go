@@ -0,0 +1,233 @@
+package docker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"sync"
+)
+
+// helperFileName is the name of the helper binary in whichever directory it is injected into
+const helperFileName = ".dcv-helper"
+
+// helperFallbackDirs are tried when the root filesystem is not writable.
+// Both are usually tmpfs mounts that even read-only containers can write to.
+var helperFallbackDirs = []string{"/tmp", "/dev/shm"}
+
+// errHelperNotExecutable means the helper was written but cannot run there, e.g. on a noexec mount
+var errHelperNotExecutable = errors.New("helper cannot be executed")
+
+// helperLocations records where the helper was injected into each container.
+// It is shared by all FileOperations because helper commands are also built without one (see HelperArgs).
+var helperLocations = struct {
+	sync.Mutex
+	paths map[string]string
+}{paths: make(map[string]string)}
+
+// HelperPathFor returns the path of the helper injected into the container,
+// GetHelperPath() unless it had to be injected somewhere else
+func HelperPathFor(container *Container) string {
+	helperLocations.Lock()
+	defer helperLocations.Unlock()
+	if p, ok := helperLocations.paths[helperKey(container)]; ok {
+		return p
+	}
+	return GetHelperPath()
+}
+
+func setHelperPath(container *Container, helperPath string) {
+	helperLocations.Lock()
+	defer helperLocations.Unlock()
+	if helperPath == GetHelperPath() {
+		delete(helperLocations.paths, helperKey(container))
+		return
+	}
+	helperLocations.paths[helperKey(container)] = helperPath
+}
+
+func forgetHelperPath(container *Container) {
+	helperLocations.Lock()
+	defer helperLocations.Unlock()
+	delete(helperLocations.paths, helperKey(container))
+}
+
+func hasHelperPath(container *Container) bool {
+	helperLocations.Lock()
+	defer helperLocations.Unlock()
+	_, ok := helperLocations.paths[helperKey(container)]
+	return ok
+}
+
+// helperInspect is the part of `docker inspect` that tells where a container can write
+type helperInspect struct {
+	Config struct {
+		User string
+		Env  []string
+	}
+	Mounts []struct {
+		Destination string
+		RW          bool
+	}
+}
+
+// helperCandidates returns the paths the helper may be injected at, in order of preference:
+// the path used before, the root directory, /tmp, /dev/shm, writable mounts and $HOME.
+// inspectOutput is the output of `docker inspect`; when it cannot be parsed only the fixed paths are returned.
+func helperCandidates(previous string, inspectOutput []byte) []string {
+	var candidates []string
+	seen := make(map[string]bool)
+	add := func(p string) {
+		if p == "" || seen[p] {
+			return
+		}
+		seen[p] = true
+		candidates = append(candidates, p)
+	}
+
+	add(previous)
+	add(GetHelperPath())
+	for _, dir := range helperFallbackDirs {
+		add(path.Join(dir, helperFileName))
+	}
+
+	var inspects []helperInspect
+	if err := json.Unmarshal(inspectOutput, &inspects); err != nil || len(inspects) == 0 {
+		return candidates
+	}
+	inspect := inspects[0]
+	for _, mount := range inspect.Mounts {
+		// Sockets and other files are bind mounted too, but only directories can hold the helper
+		if !mount.RW || mount.Destination == "/" || strings.HasSuffix(mount.Destination, ".sock") {
+			continue
+		}
+		add(path.Join(mount.Destination, helperFileName))
+	}
+	if home := containerHome(inspect); home != "" {
+		add(path.Join(home, helperFileName))
+	}
+	return candidates
+}
+
+// containerHome returns the home directory of the container's user, "" when it is not known
+func containerHome(inspect helperInspect) string {
+	for _, env := range inspect.Config.Env {
+		if home, ok := strings.CutPrefix(env, "HOME="); ok {
+			return home
+		}
+	}
+	user, _, _ := strings.Cut(inspect.Config.User, ":")
+	if user == "" || user == "root" || user == "0" {
+		return "/root"
+	}
+	return ""
+}
+
+// HelperInjectAttempt is a location the helper could not be injected at
+type HelperInjectAttempt struct {
+	Path string
+	Err  error
+}
+
+// Reason tells in a few words why the helper could not be injected at the path
+func (a HelperInjectAttempt) Reason() string {
+	if strings.Contains(a.Err.Error(), "exec format error") {
+		return "the helper is built for another architecture"
+	}
+	if errors.Is(a.Err, errHelperNotExecutable) {
+		return "mounted noexec, the helper cannot run from there"
+	}
+	msg := strings.ToLower(a.Err.Error())
+	switch {
+	case strings.Contains(msg, "no such container") || strings.Contains(msg, "is not running"):
+		return "the container is not running"
+	case strings.Contains(msg, "executable file not found"):
+		return "docker cp cannot write there and the container has no shell to write it with"
+	case strings.Contains(msg, "read-only"):
+		return "read-only file system"
+	case strings.Contains(msg, "permission denied") || strings.Contains(msg, "operation not permitted"):
+		return "not writable by the container user"
+	case strings.Contains(msg, "no such file or directory") || strings.Contains(msg, "could not find the file") || strings.Contains(msg, "not a directory"):
+		return "the directory does not exist"
+	default:
+		line, _, _ := strings.Cut(strings.TrimSpace(a.Err.Error()), "\n")
+		return line
+	}
+}
+
+// HelperInjectError explains why the helper could not be injected anywhere in the container
+type HelperInjectError struct {
+	Container *Container
+	Attempts  []HelperInjectAttempt
+}
+
+func (e *HelperInjectError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "cannot inject the helper into %s, no location is both writable and executable:", e.Container.Title())
+	for _, attempt := range e.Attempts {
+		fmt.Fprintf(&b, "\n  %s: %s", attempt.Path, attempt.Reason())
+	}
+	return b.String()
+}
+
+func (e *HelperInjectError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Attempts))
+	for _, attempt := range e.Attempts {
+		errs = append(errs, attempt.Err)
+	}
+	return errs
+}
+
+// helperWriteArgs returns the docker arguments that write the helper from stdin to helperPath with the container's shell.
+// It is the fallback for tmpfs mounts, which `docker cp` cannot write to in containers with a read-only root filesystem.
+func helperWriteArgs(container *Container, helperPath string) []string {
+	script := []string{"sh", "-c", `cat > "$1" && chmod 755 "$1"`, "sh", helperPath}
+	if container.IsDind() {
+		// -i forwards the binary through the docker CLI of the host container
+		return append([]string{"exec", "-i", container.HostContainerID(), "docker", "exec", "-i", container.ContainerID()}, script...)
+	}
+	return append([]string{"exec", "-i", container.ContainerID()}, script...)
+}
+
+// injectHelperAt copies the helper to helperPath and checks that it runs there
+func injectHelperAt(container *Container, tempFile string, binary []byte, helperPath string) error {
+	var copyErr error
+	for _, args := range HelperInjectCommands(container, tempFile, helperPath) {
+		if _, copyErr = ExecuteCaptured(args...); copyErr != nil {
+			break
+		}
+	}
+	if copyErr != nil {
+		if _, err := ExecuteCapturedWithInput(binary, helperWriteArgs(container, helperPath)...); err != nil {
+			return errors.Join(copyErr, err)
+		}
+	}
+
+	if _, err := ExecuteCaptured(container.OperationArgs("exec", helperPath, "version", "--json")...); err != nil {
+		// Do not leave a binary nobody can run behind
+		if _, rmErr := ExecuteCaptured(container.OperationArgs("exec", "rm", "-f", helperPath)...); rmErr != nil {
+			slog.Debug("Failed to remove the helper that cannot run",
+				slog.String("path", helperPath),
+				slog.Any("error", rmErr))
+		}
+		return fmt.Errorf("%w: %w", errHelperNotExecutable, err)
+	}
+	return nil
+}
+
+// locateHelper looks for a helper an earlier dcv injected into a fallback directory, and records its path.
+// It returns the output of `version --json` of the helper it found.
+func locateHelper(container *Container) ([]byte, bool) {
+	for _, dir := range helperFallbackDirs {
+		helperPath := path.Join(dir, helperFileName)
+		output, err := ExecuteCaptured(container.OperationArgs("exec", helperPath, "version", "--json")...)
+		if err != nil {
+			continue
+		}
+		setHelperPath(container, helperPath)
+		return output, true
+	}
+	return nil, false
+}
@@ -0,0 +1,103 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelperCandidates(t *testing.T) {
+	inspect := []byte(`[{
+		"Config": {"User": "1000", "Env": ["PATH=/usr/bin", "HOME=/home/app"]},
+		"Mounts": [
+			{"Destination": "/data", "RW": true},
+			{"Destination": "/config", "RW": false},
+			{"Destination": "/var/run/docker.sock", "RW": true},
+			{"Destination": "/tmp", "RW": true}
+		]
+	}]`)
+	assert.Equal(t, []string{
+		"/.dcv-helper",
+		"/tmp/.dcv-helper",
+		"/dev/shm/.dcv-helper",
+		"/data/.dcv-helper",
+		"/home/app/.dcv-helper",
+	}, helperCandidates("", inspect))
+
+	// The location used before comes first, and the fixed ones are tried without inspect
+	assert.Equal(t, []string{
+		"/dev/shm/.dcv-helper",
+		"/.dcv-helper",
+		"/tmp/.dcv-helper",
+	}, helperCandidates("/dev/shm/.dcv-helper", nil))
+}
+
+func TestContainerHome(t *testing.T) {
+	var inspect helperInspect
+	assert.Equal(t, "/root", containerHome(inspect))
+
+	inspect.Config.User = "app:app"
+	assert.Equal(t, "", containerHome(inspect))
+
+	inspect.Config.Env = []string{"HOME=/srv"}
+	assert.Equal(t, "/srv", containerHome(inspect))
+}
+
+func TestHelperInjectAttemptReason(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{errors.New("Error response from daemon: container rootfs is marked read-only"), "read-only file system"},
+		{errors.New("sh: can't create /.dcv-helper: Read-only file system"), "read-only file system"},
+		{errors.New("sh: can't create /root/.dcv-helper: Permission denied"), "not writable by the container user"},
+		{fmt.Errorf("%w: permission denied", errHelperNotExecutable), "mounted noexec, the helper cannot run from there"},
+		{fmt.Errorf("%w: exec format error", errHelperNotExecutable), "the helper is built for another architecture"},
+		{errors.Join(errors.New("read-only"), errors.New(`exec: "sh": executable file not found in $PATH`)),
+			"docker cp cannot write there and the container has no shell to write it with"},
+		{errors.New("Error response from daemon: Could not find the file /data in container"), "the directory does not exist"},
+		{errors.New("something else\ndetails"), "something else"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, HelperInjectAttempt{Err: tt.err}.Reason(), tt.err.Error())
+	}
+}
+
+func TestHelperInjectError(t *testing.T) {
+	err := &HelperInjectError{
+		Container: NewContainer("abc123", "web", "web", "running"),
+		Attempts: []HelperInjectAttempt{
+			{Path: "/.dcv-helper", Err: errors.New("read-only file system")},
+			{Path: "/tmp/.dcv-helper", Err: fmt.Errorf("%w: permission denied", errHelperNotExecutable)},
+		},
+	}
+	assert.Equal(t, "cannot inject the helper into web, no location is both writable and executable:\n"+
+		"  /.dcv-helper: read-only file system\n"+
+		"  /tmp/.dcv-helper: mounted noexec, the helper cannot run from there", err.Error())
+	assert.ErrorIs(t, err, errHelperNotExecutable)
+}
+
+func TestHelperPathFor(t *testing.T) {
+	container := NewContainer("readonly1", "readonly", "readonly", "running")
+	assert.Equal(t, GetHelperPath(), HelperPathFor(container))
+
+	setHelperPath(container, "/tmp/.dcv-helper")
+	assert.Equal(t, "/tmp/.dcv-helper", HelperPathFor(container))
+	assert.Equal(t, []string{"exec", "readonly1", "/tmp/.dcv-helper", "ls", "/"}, HelperArgs(container, "ls", "/"))
+	assert.Equal(t, []string{"exec", "readonly1", "/tmp/.dcv-helper", "rm", "-f", "/tmp/.dcv-helper"}, EjectHelperArgs(container))
+
+	NewFileOperations(nil).ForgetHelper(container)
+	assert.Equal(t, GetHelperPath(), HelperPathFor(container))
+}
+
+func TestHelperWriteArgs(t *testing.T) {
+	container := NewContainer("abc123", "web", "web", "running")
+	assert.Equal(t, []string{"exec", "-i", "abc123", "sh", "-c", `cat > "$1" && chmod 755 "$1"`, "sh", "/tmp/.dcv-helper"},
+		helperWriteArgs(container, "/tmp/.dcv-helper"))
+
+	dind := NewDindContainer("host1", "dind", "inner1", "app", "running")
+	assert.Equal(t, []string{"exec", "-i", "host1", "docker", "exec", "-i", "inner1", "sh", "-c", `cat > "$1" && chmod 755 "$1"`, "sh", "/tmp/.dcv-helper"},
+		helperWriteArgs(dind, "/tmp/.dcv-helper"))
+}
@@ -0,0 +1,102 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+// HelperProtocolVersion is the version of the helper's JSON output and command set that dcv understands.
+// It must match protocolVersion in cmd/dcv-helper.
+const HelperProtocolVersion = 2
+
+// errHelperOutdated means the injected helper is older than the embedded one
+var errHelperOutdated = errors.New("injected helper is outdated")
+
+// errHelperMissing means the helper has not been injected into the container
+var errHelperMissing = errors.New("helper is not injected")
+
+// helperVersionInfo is the output of `dcv-helper version --json`
+type helperVersionInfo struct {
+	Version  string `json:"version"`
+	Protocol int    `json:"protocol"`
+}
+
+// helperLsOutput is the output of `dcv-helper ls --json`
+type helperLsOutput struct {
+	Protocol int               `json:"protocol"`
+	Path     string            `json:"path"`
+	Entries  []helperFileEntry `json:"entries"`
+}
+
+// helperFileEntry describes a single file in `dcv-helper ls --json` output
+type helperFileEntry struct {
+	Name       string    `json:"name"`
+	Mode       string    `json:"mode"`
+	Perm       uint32    `json:"perm"`
+	Size       int64     `json:"size"`
+	MTime      time.Time `json:"mtime"`
+	UID        uint32    `json:"uid"`
+	GID        uint32    `json:"gid"`
+	User       string    `json:"user"`
+	Group      string    `json:"group"`
+	Nlink      uint64    `json:"nlink"`
+	Inode      uint64    `json:"inode"`
+	LinkTarget string    `json:"link_target"`
+	IsDir      bool      `json:"is_dir"`
+}
+
+// parseHelperVersion parses `dcv-helper version --json`.
+// Helpers from before the JSON protocol print plain text, which is reported as errHelperOutdated.
+func parseHelperVersion(output []byte) (helperVersionInfo, error) {
+	var info helperVersionInfo
+	if err := json.Unmarshal(bytes.TrimSpace(output), &info); err != nil {
+		return info, fmt.Errorf("%w: %q", errHelperOutdated, bytes.TrimSpace(output))
+	}
+	if info.Protocol < HelperProtocolVersion {
+		return info, fmt.Errorf("%w: version %s speaks protocol %d, need %d", errHelperOutdated, info.Version, info.Protocol, HelperProtocolVersion)
+	}
+	return info, nil
+}
+
+// parseHelperLsJSON converts `dcv-helper ls --json` output into container files
+func parseHelperLsJSON(output []byte) ([]models.ContainerFile, error) {
+	var ls helperLsOutput
+	if err := json.Unmarshal(output, &ls); err != nil {
+		return nil, fmt.Errorf("failed to parse helper ls output: %w", err)
+	}
+	if ls.Protocol < HelperProtocolVersion {
+		return nil, fmt.Errorf("%w: ls output uses protocol %d, need %d", errHelperOutdated, ls.Protocol, HelperProtocolVersion)
+	}
+
+	files := make([]models.ContainerFile, 0, len(ls.Entries))
+	for _, e := range ls.Entries {
+		owner := e.User
+		if owner == "" {
+			owner = strconv.FormatUint(uint64(e.UID), 10)
+		}
+		group := e.Group
+		if group == "" {
+			group = strconv.FormatUint(uint64(e.GID), 10)
+		}
+		files = append(files, models.ContainerFile{
+			Name:        e.Name,
+			Size:        e.Size,
+			Mode:        e.Mode,
+			ModTime:     e.MTime,
+			IsDir:       e.IsDir,
+			LinkTarget:  e.LinkTarget,
+			Permissions: e.Mode,
+			Owner:       owner,
+			Group:       group,
+			Links:       strconv.FormatUint(e.Nlink, 10),
+			Inode:       e.Inode,
+		})
+	}
+	return files, nil
+}
@@ -0,0 +1,99 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHelperVersion(t *testing.T) {
+	t.Run("current helper", func(t *testing.T) {
+		info, err := parseHelperVersion([]byte(`{"version":"1.9.0","protocol":2}` + "\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "1.9.0", info.Version)
+		assert.Equal(t, 2, info.Protocol)
+	})
+
+	t.Run("helper without JSON support", func(t *testing.T) {
+		_, err := parseHelperVersion([]byte("dcv-helper 1.0.0\n"))
+		assert.ErrorIs(t, err, errHelperOutdated)
+	})
+
+	t.Run("older protocol", func(t *testing.T) {
+		_, err := parseHelperVersion([]byte(`{"version":"1.2.0","protocol":1}`))
+		assert.ErrorIs(t, err, errHelperOutdated)
+	})
+}
+
+func TestParseHelperLsJSON(t *testing.T) {
+	output := []byte(`{"protocol":2,"path":"/data","entries":[
+		{"name":"my file.txt","mode":"-rw-r--r--","perm":420,"size":12,"mtime":"2025-03-04T05:06:07Z","uid":1000,"gid":1000,"user":"app","group":"app","nlink":1,"inode":42,"is_dir":false},
+		{"name":"current","mode":"lrwxrwxrwx","perm":511,"size":7,"mtime":"2025-03-04T05:06:07Z","uid":0,"gid":0,"nlink":1,"inode":43,"link_target":"release","is_dir":false},
+		{"name":"logs","mode":"drwxr-xr-x","perm":493,"size":4096,"mtime":"2025-03-04T05:06:07Z","uid":0,"gid":0,"user":"root","group":"root","nlink":2,"inode":44,"is_dir":true}
+	]}`)
+
+	files, err := parseHelperLsJSON(output)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+
+	assert.Equal(t, "my file.txt", files[0].Name)
+	assert.Equal(t, int64(12), files[0].Size)
+	assert.Equal(t, "-rw-r--r--", files[0].Permissions)
+	assert.Equal(t, time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC), files[0].ModTime)
+	assert.Equal(t, "app", files[0].Owner)
+	assert.Equal(t, "app", files[0].Group)
+	assert.Equal(t, "1", files[0].Links)
+	assert.Equal(t, uint64(42), files[0].Inode)
+
+	assert.Equal(t, "release", files[1].LinkTarget)
+	// Unknown users fall back to the numeric ID
+	assert.Equal(t, "0", files[1].Owner)
+	assert.Equal(t, "0", files[1].Group)
+
+	assert.True(t, files[2].IsDir)
+	assert.Equal(t, "2", files[2].Links)
+}
+
+func TestParseHelperLsJSON_Errors(t *testing.T) {
+	_, err := parseHelperLsJSON([]byte("-rw-r--r-- 1 0 0 12 Jan 1 00:00 file"))
+	assert.ErrorContains(t, err, "failed to parse helper ls output")
+
+	_, err = parseHelperLsJSON([]byte(`{"protocol":1,"entries":[]}`))
+	assert.ErrorIs(t, err, errHelperOutdated)
+}
+
+func TestHelperInjectCommands(t *testing.T) {
+	container := NewContainer("abc123", "web", "web", "running")
+	assert.Equal(t, [][]string{
+		{"cp", "/tmp/dcv-helper-1", "abc123:/.dcv-helper"},
+	}, HelperInjectCommands(container, "/tmp/dcv-helper-1", "/.dcv-helper"))
+
+	dind := NewDindContainer("host1", "dind", "inner1", "app", "running")
+	assert.Equal(t, [][]string{
+		{"cp", "/tmp/dcv-helper-1", "host1:/.dcv-helper"},
+		{"exec", "host1", "docker", "cp", "/.dcv-helper", "inner1:/.dcv-helper"},
+	}, HelperInjectCommands(dind, "/tmp/dcv-helper-1", "/.dcv-helper"))
+}
+
+func TestHelperKey(t *testing.T) {
+	assert.Equal(t, "abc123", helperKey(NewContainer("abc123", "web", "web", "running")))
+	assert.Equal(t, "host1/inner1", helperKey(NewDindContainer("host1", "dind", "inner1", "app", "running")))
+}
+
+func TestHelperArgs(t *testing.T) {
+	t.Run("regular container", func(t *testing.T) {
+		container := NewContainer("abc123", "web", "web", "running")
+		assert.Equal(t,
+			[]string{"exec", "abc123", "/.dcv-helper", "du", "-h", "/var"},
+			HelperArgs(container, "du", "-h", "/var"))
+	})
+
+	t.Run("dind container", func(t *testing.T) {
+		container := NewDindContainer("host123", "host-container", "dind456", "dind-container", "running")
+		assert.Equal(t,
+			[]string{"exec", "host123", "docker", "exec", "dind456", "/.dcv-helper", "ps"},
+			HelperArgs(container, "ps"))
+	})
+}
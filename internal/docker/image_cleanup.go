@@ -0,0 +1,154 @@
+package docker
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+)
+
+// ImageClass tells whether an image can be removed
+type ImageClass int
+
+const (
+	// ImageDangling images have no tag, even when they are known by digest;
+	// `docker image prune` removes them when no container uses them
+	ImageDangling ImageClass = iota
+	// ImageUnused images have a tag but no container, running or not, uses them
+	ImageUnused
+	// ImageInUse images are used by a container and cannot be removed without it
+	ImageInUse
+)
+
+func (c ImageClass) String() string {
+	switch c {
+	case ImageDangling:
+		return "dangling"
+	case ImageUnused:
+		return "unused"
+	default:
+		return "in use"
+	}
+}
+
+// ImageUsage is an image with the containers that use it
+type ImageUsage struct {
+	ID          string
+	RepoTags    []string
+	RepoDigests []string
+	Created     time.Time
+	Size        int64
+	// UniqueSize is the part of Size not shared with other images, what removing the image alone frees
+	UniqueSize int64
+	Class      ImageClass
+	// Containers are the names of the containers created from the image, in any state
+	Containers []string
+}
+
+// Name is the first tag of the image, or its repository for images only known by digest
+func (u ImageUsage) Name() string {
+	if len(u.RepoTags) > 0 {
+		return u.RepoTags[0]
+	}
+	if len(u.RepoDigests) > 0 {
+		repository, _, _ := strings.Cut(u.RepoDigests[0], "@")
+		return repository + ":<none>"
+	}
+	return "<none>:<none>"
+}
+
+// RemoveArgs are the arguments of `docker rmi` that remove the image: its tags, so that an image
+// tagged in several repositories is removed without forcing, or its ID when it has none
+func (u ImageUsage) RemoveArgs() []string {
+	if len(u.RepoTags) > 0 {
+		return u.RepoTags
+	}
+	return []string{u.ID}
+}
+
+// ImageUsages lists the images with the containers that use them, dangling images first, then unused and in-use ones,
+// largest first within each class
+func ImageUsages(ctx context.Context, cli *client.Client) ([]ImageUsage, error) {
+	images, err := cli.ImageList(ctx, image.ListOptions{SharedSize: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	return classifyImages(images, containers), nil
+}
+
+func classifyImages(images []image.Summary, containers []container.Summary) []ImageUsage {
+	users := make(map[string][]string)
+	for _, c := range containers {
+		name := c.ID
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		users[c.ImageID] = append(users[c.ImageID], name)
+	}
+
+	usages := make([]ImageUsage, 0, len(images))
+	for _, img := range images {
+		usage := ImageUsage{
+			ID:          img.ID,
+			RepoTags:    withoutNone(img.RepoTags),
+			RepoDigests: withoutNone(img.RepoDigests),
+			Created:     time.Unix(img.Created, 0),
+			Size:        img.Size,
+			UniqueSize:  img.Size,
+			Containers:  users[img.ID],
+		}
+		// SharedSize is -1 when the daemon did not compute it
+		if img.SharedSize > 0 {
+			usage.UniqueSize = img.Size - img.SharedSize
+		}
+		slices.Sort(usage.Containers)
+		switch {
+		case len(usage.Containers) > 0:
+			usage.Class = ImageInUse
+		case len(usage.RepoTags) == 0:
+			usage.Class = ImageDangling
+		default:
+			usage.Class = ImageUnused
+		}
+		usages = append(usages, usage)
+	}
+
+	slices.SortStableFunc(usages, func(a, b ImageUsage) int {
+		return cmp.Or(cmp.Compare(a.Class, b.Class), cmp.Compare(b.Size, a.Size), cmp.Compare(a.Name(), b.Name()))
+	})
+	return usages
+}
+
+// withoutNone drops the "<none>:<none>" and "<none>@<none>" placeholders older daemons list for untagged images
+func withoutNone(refs []string) []string {
+	var result []string
+	for _, ref := range refs {
+		if !strings.HasPrefix(ref, "<none>") {
+			result = append(result, ref)
+		}
+	}
+	return result
+}
+
+// PruneCandidates returns the images `docker image prune` removes: the dangling ones, or with all every image no
+// container uses, like `docker image prune --all`. Removing exactly these with `docker rmi` prunes what was previewed,
+// even when images are pulled or containers removed in the meantime.
+func PruneCandidates(usages []ImageUsage, all bool) []ImageUsage {
+	var candidates []ImageUsage
+	for _, usage := range usages {
+		if usage.Class == ImageDangling || (all && usage.Class == ImageUnused) {
+			candidates = append(candidates, usage)
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,51 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyImages(t *testing.T) {
+	images := []image.Summary{
+		{ID: "sha256:app", RepoTags: []string{"app:latest", "registry.example.com/app:1.0"}, Size: 300, SharedSize: 100},
+		{ID: "sha256:old", RepoTags: []string{"<none>:<none>"}, RepoDigests: []string{"<none>@<none>"}, Size: 200, SharedSize: -1},
+		{ID: "sha256:digest", RepoDigests: []string{"alpine@sha256:0123"}, Size: 50},
+		{ID: "sha256:redis", RepoTags: []string{"redis:7"}, Size: 120},
+		{ID: "sha256:tool", RepoTags: []string{"tool:dev"}, Size: 500},
+	}
+	containers := []container.Summary{
+		{ID: "c1", Names: []string{"/web-2"}, ImageID: "sha256:app"},
+		{ID: "c2", Names: []string{"/web-1"}, ImageID: "sha256:app"},
+		{ID: "c3", Names: []string{"/cache"}, ImageID: "sha256:redis"},
+	}
+
+	usages := classifyImages(images, containers)
+	require.Len(t, usages, 5)
+
+	var order []string
+	for _, u := range usages {
+		order = append(order, u.Class.String()+" "+u.Name())
+	}
+	assert.Equal(t, []string{
+		"dangling <none>:<none>",
+		"dangling alpine:<none>",
+		"unused tool:dev",
+		"in use app:latest",
+		"in use redis:7",
+	}, order)
+
+	assert.Equal(t, []string{"sha256:old"}, usages[0].RemoveArgs())
+	assert.Equal(t, int64(200), usages[0].UniqueSize)
+	assert.Equal(t, []string{"web-1", "web-2"}, usages[3].Containers)
+	assert.Equal(t, int64(200), usages[3].UniqueSize)
+	assert.Equal(t, []string{"app:latest", "registry.example.com/app:1.0"}, usages[3].RemoveArgs())
+
+	assert.Len(t, PruneCandidates(usages, false), 2)
+	all := PruneCandidates(usages, true)
+	require.Len(t, all, 3)
+	assert.Equal(t, "tool:dev", all[2].Name())
+}
@@ -0,0 +1,300 @@
+package docker
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+// imageBrowserLabel marks the containers created to read the files of an image
+const imageBrowserLabel = "dcv.image-browser"
+
+// maxIDNamesSize bounds how much of /etc/passwd and /etc/group is read to name owners
+const maxIDNamesSize = 1 << 20
+
+// ImageFilesystem is the file tree of an image, read from a container that is created for it but never
+// started, so that nothing from the image runs. Files can be read from Container with FileOperations.
+type ImageFilesystem struct {
+	Image     string
+	Container *Container
+	index     *fsIndex
+}
+
+// OpenImageFilesystem creates a container from the image and indexes the files of its export.
+// Remove the container with Close.
+func OpenImageFilesystem(image string) (*ImageFilesystem, error) {
+	output, err := ExecuteCaptured(imageContainerCreateArgs(image)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a container from image %s: %w", image, err)
+	}
+	id := lastLine(output)
+	name := "image " + image
+	container := NewContainer(id, name, name, "created")
+
+	index, err := exportFilesystem(id)
+	if err != nil {
+		_, _ = ExecuteCaptured("rm", "-f", id)
+		return nil, err
+	}
+	return &ImageFilesystem{Image: image, Container: container, index: index}, nil
+}
+
+// imageContainerCreateArgs creates a container to read the files of an image. It is never started, and is
+// labeled so that RemoveTemporaryContainers finds it when dcv exits without removing it.
+func imageContainerCreateArgs(image string) []string {
+	// The command is never run; it only keeps create from failing for images without one
+	args := append([]string{"create", "--label", imageBrowserLabel + "=" + image}, ownerArgs()...)
+	return append(args, "--network", "none", image, "true")
+}
+
+// exportFilesystem indexes the headers of `docker export`, without keeping the file contents
+func exportFilesystem(containerID string) (*fsIndex, error) {
+	cmd := Execute("export", containerID)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stdout pipe: %w", err)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to export the image filesystem: %w", err)
+	}
+
+	index, err := indexFilesystem(stdout)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil, fmt.Errorf("failed to read the image filesystem: %w", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to export the image filesystem: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return index, nil
+}
+
+// Close removes the container the filesystem was read from
+func (f *ImageFilesystem) Close() error {
+	if _, err := ExecuteCaptured("rm", "-f", f.Container.ContainerID()); err != nil {
+		return fmt.Errorf("failed to remove the image browser container: %w", err)
+	}
+	return nil
+}
+
+// List returns the entries of a directory, sorted by name
+func (f *ImageFilesystem) List(dir string) ([]models.ContainerFile, error) {
+	return f.index.list(dir)
+}
+
+// Stat returns the entry of a path
+func (f *ImageFilesystem) Stat(filePath string) (models.ContainerFile, bool) {
+	entry, ok := f.index.entries[cleanArchivePath(filePath)]
+	if !ok {
+		return models.ContainerFile{}, false
+	}
+	return entry.file, true
+}
+
+type fsEntry struct {
+	file     models.ContainerFile
+	uid, gid int
+	linkTo   string // target of a hard link
+}
+
+// fsIndex is the file tree of a filesystem archive
+type fsIndex struct {
+	entries  map[string]*fsEntry
+	children map[string][]string
+}
+
+func (x *fsIndex) list(dir string) ([]models.ContainerFile, error) {
+	dir = cleanArchivePath(dir)
+	// A directory reached through a symbolic link lists the entries of its target
+	if real, ok := x.resolve(dir); ok {
+		dir = real
+	}
+	entry, ok := x.entries[dir]
+	if !ok {
+		return nil, fmt.Errorf("%s: no such file or directory", dir)
+	}
+	if !entry.file.IsDir {
+		return nil, fmt.Errorf("%s: not a directory", dir)
+	}
+	files := make([]models.ContainerFile, 0, len(x.children[dir]))
+	for _, child := range x.children[dir] {
+		files = append(files, x.entries[child].file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+	return files, nil
+}
+
+// indexFilesystem reads the headers of a filesystem archive. Only /etc/passwd and
+// /etc/group are read, to name the owners like ls does inside the container.
+func indexFilesystem(r io.Reader) (*fsIndex, error) {
+	index := &fsIndex{
+		entries:  map[string]*fsEntry{},
+		children: map[string][]string{},
+	}
+	var users, groups map[int]string
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := cleanArchivePath(header.Name)
+		mode := header.FileInfo().Mode()
+		entry := &fsEntry{
+			file: models.ContainerFile{
+				Name:        path.Base(name),
+				Size:        header.Size,
+				Mode:        lsModeString(mode),
+				ModTime:     header.ModTime,
+				IsDir:       mode.IsDir(),
+				Permissions: lsModeString(mode),
+				Owner:       header.Uname,
+				Group:       header.Gname,
+				Links:       "1",
+			},
+			uid: header.Uid,
+			gid: header.Gid,
+		}
+		switch header.Typeflag {
+		case tar.TypeSymlink:
+			entry.file.LinkTarget = header.Linkname
+		case tar.TypeLink:
+			entry.linkTo = cleanArchivePath(header.Linkname)
+		case tar.TypeReg:
+			switch name {
+			case "/etc/passwd":
+				users = readIDNames(tr)
+			case "/etc/group":
+				groups = readIDNames(tr)
+			}
+		}
+		index.add(name, entry)
+	}
+
+	for _, entry := range index.entries {
+		if target, ok := index.entries[entry.linkTo]; ok && entry.linkTo != "" {
+			entry.file.Size = target.file.Size
+			entry.file.Mode = target.file.Mode
+			entry.file.Permissions = target.file.Permissions
+		}
+		if entry.file.Owner == "" {
+			entry.file.Owner = idName(users, entry.uid)
+		}
+		if entry.file.Group == "" {
+			entry.file.Group = idName(groups, entry.gid)
+		}
+	}
+	return index, nil
+}
+
+// add records an entry, creating the parent directories that the archive does not list
+func (x *fsIndex) add(name string, entry *fsEntry) {
+	if existing, ok := x.entries[name]; ok {
+		*existing = *entry
+		return
+	}
+	x.entries[name] = entry
+	if name == "/" {
+		return
+	}
+	parent := path.Dir(name)
+	if _, ok := x.entries[parent]; !ok {
+		x.add(parent, &fsEntry{file: models.ContainerFile{
+			Name:        path.Base(parent),
+			Mode:        "drwxr-xr-x",
+			IsDir:       true,
+			Permissions: "drwxr-xr-x",
+			Links:       "1",
+		}})
+	}
+	x.children[parent] = append(x.children[parent], name)
+}
+
+// cleanArchivePath turns an archive entry name like "./usr/bin/" into "/usr/bin"
+func cleanArchivePath(name string) string {
+	return path.Clean("/" + name)
+}
+
+func readIDNames(r io.Reader) map[int]string {
+	names := map[int]string{}
+	scanner := bufio.NewScanner(io.LimitReader(r, maxIDNamesSize))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) < 3 {
+			continue
+		}
+		id, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+		if _, exists := names[id]; !exists {
+			names[id] = fields[0]
+		}
+	}
+	return names
+}
+
+func idName(names map[int]string, id int) string {
+	if name, ok := names[id]; ok {
+		return name
+	}
+	return strconv.Itoa(id)
+}
+
+// lsModeString formats a mode like ls -l does, e.g. "drwxr-xr-x" or "-rwsr-xr-x"
+func lsModeString(mode os.FileMode) string {
+	b := []byte("----------")
+	switch {
+	case mode.IsDir():
+		b[0] = 'd'
+	case mode&os.ModeSymlink != 0:
+		b[0] = 'l'
+	case mode&os.ModeCharDevice != 0:
+		b[0] = 'c'
+	case mode&os.ModeDevice != 0:
+		b[0] = 'b'
+	case mode&os.ModeNamedPipe != 0:
+		b[0] = 'p'
+	case mode&os.ModeSocket != 0:
+		b[0] = 's'
+	}
+	const rwx = "rwxrwxrwx"
+	for i := 0; i < 9; i++ {
+		if mode&(1<<uint(8-i)) != 0 {
+			b[i+1] = rwx[i]
+		}
+	}
+	special := func(index int, set bool, lower, upper byte) {
+		if !set {
+			return
+		}
+		if b[index] == '-' {
+			b[index] = upper
+		} else {
+			b[index] = lower
+		}
+	}
+	special(3, mode&os.ModeSetuid != 0, 's', 'S')
+	special(6, mode&os.ModeSetgid != 0, 's', 'S')
+	special(9, mode&os.ModeSticky != 0, 't', 'T')
+	return string(b)
+}
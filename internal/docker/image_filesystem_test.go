@@ -0,0 +1,172 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+func TestImageContainerCreateArgs(t *testing.T) {
+	assert.Equal(t, []string{
+		"create",
+		"--label", "dcv.image-browser=nginx:latest",
+		"--label", "dcv.owner=" + currentOwner.String(),
+		"--network", "none",
+		"nginx:latest", "true",
+	}, imageContainerCreateArgs("nginx:latest"))
+}
+
+func TestIndexFilesystem(t *testing.T) {
+	archive := buildTar(t,
+		dirEntry("./"),
+		dirEntry("etc/"),
+		fileEntry("etc/passwd", "root:x:0:0:root:/root:/bin/sh\napp:x:1000:1000::/home/app:/bin/sh\n"),
+		fileEntry("etc/group", "root:x:0:\napp:x:1000:\n"),
+		tarEntry{header: &tar.Header{Typeflag: tar.TypeReg, Name: "usr/bin/app", Mode: 0o4755, Uid: 1000, Gid: 1000}, body: "binary"},
+		tarEntry{header: &tar.Header{Typeflag: tar.TypeSymlink, Name: "bin", Linkname: "usr/bin", Mode: 0777}},
+		tarEntry{header: &tar.Header{Typeflag: tar.TypeLink, Name: "usr/bin/app2", Linkname: "usr/bin/app"}},
+		tarEntry{header: &tar.Header{Typeflag: tar.TypeReg, Name: "data", Mode: 0600, Uid: 42, Gid: 42}},
+	)
+	index, err := indexFilesystem(bytes.NewReader(archive))
+	require.NoError(t, err)
+
+	root, err := index.list("/")
+	require.NoError(t, err)
+	var names []string
+	for _, f := range root {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"bin", "data", "etc", "usr"}, names)
+	assert.Equal(t, "lrwxrwxrwx", root[0].Permissions)
+	assert.Equal(t, "usr/bin", root[0].LinkTarget)
+	assert.Equal(t, "42", root[1].Owner, "unknown ids are shown as numbers")
+	assert.True(t, root[3].IsDir, "parent directories missing from the archive are created")
+
+	bin, err := index.list("/usr/bin/")
+	require.NoError(t, err)
+	require.Len(t, bin, 2)
+	assert.Equal(t, models.ContainerFile{
+		Name: "app", Size: 6, Mode: "-rwsr-xr-x", Permissions: "-rwsr-xr-x",
+		Owner: "app", Group: "app", Links: "1", ModTime: bin[0].ModTime,
+	}, bin[0])
+	assert.Equal(t, int64(6), bin[1].Size, "hard links take the size of their target")
+
+	_, err = index.list("/missing")
+	assert.Error(t, err)
+	_, err = index.list("/data")
+	assert.Error(t, err)
+}
+
+func TestLsModeString(t *testing.T) {
+	assert.Equal(t, "drwxrwxrwt", lsModeString(os.ModeDir|os.ModeSticky|0777))
+	assert.Equal(t, "-rw-r-Sr--", lsModeString(os.ModeSetgid|0644))
+	assert.Equal(t, "crw-rw-rw-", lsModeString(os.ModeDevice|os.ModeCharDevice|0666))
+}
+
+func TestParseImageArchive(t *testing.T) {
+	base := buildTar(t,
+		dirEntry("etc/"),
+		fileEntry("etc/os-release", "base"),
+		dirEntry("app/"),
+		fileEntry("app/old.txt", "old"),
+		fileEntry("app/cache/a", "a"),
+	)
+	update := buildTar(t,
+		dirEntry("etc/"),
+		fileEntry("etc/os-release", "updated"),
+		dirEntry("app/"),
+		fileEntry("app/.wh.old.txt", ""),
+		fileEntry("app/new.txt", "new"),
+	)
+	opaque := buildTar(t,
+		dirEntry("app/cache/"),
+		fileEntry("app/cache/b", "b"),
+		fileEntry("app/cache/.wh..wh..opq", ""),
+	)
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err := gz.Write(opaque)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	manifest, err := json.Marshal([]map[string]any{{
+		"Config": "blobs/sha256/config",
+		"Layers": []string{"blobs/sha256/base", "blobs/sha256/update", "blobs/sha256/opaque", "blobs/sha256/empty"},
+	}})
+	require.NoError(t, err)
+	archive := buildTar(t,
+		fileEntry("blobs/sha256/config", `{"architecture":"amd64"}`),
+		fileEntry("blobs/sha256/base", string(base)),
+		fileEntry("blobs/sha256/update", string(update)),
+		fileEntry("blobs/sha256/opaque", compressed.String()),
+		fileEntry("manifest.json", string(manifest)),
+	)
+
+	layers, err := parseImageArchive(bytes.NewReader(archive))
+	require.NoError(t, err)
+	require.Len(t, layers.Layers, 4)
+	assert.Equal(t, "base", layers.Layers[0].ID)
+	assert.Equal(t, int64(8), layers.Layers[0].Size)
+	assert.Equal(t, []models.ContainerChange{
+		{Kind: models.ChangeDeleted, Path: "/app/old.txt"},
+		{Kind: models.ChangeChanged, Path: "/etc/os-release", Size: 7},
+		{Kind: models.ChangeAdded, Path: "/app/new.txt", Size: 3},
+	}, layers.Layers[1].Changes)
+	assert.Empty(t, layers.Layers[3].Changes)
+	assert.Nil(t, layers.Steps, "the config has no history")
+
+	layer, overwritten, ok := layers.Origin("/etc/os-release")
+	require.True(t, ok)
+	assert.Equal(t, 1, layer)
+	assert.Equal(t, []int{0}, overwritten)
+
+	layer, overwritten, ok = layers.Origin("/app/cache/b")
+	require.True(t, ok)
+	assert.Equal(t, 2, layer)
+	assert.Empty(t, overwritten)
+
+	_, _, ok = layers.Origin("/app/cache/a")
+	assert.False(t, ok, "an opaque directory hides the lower contents")
+	_, _, ok = layers.Origin("/app/old.txt")
+	assert.False(t, ok)
+}
+
+func TestParseImageArchive_LegacyLayout(t *testing.T) {
+	layer := buildTar(t, fileEntry("hello", "world"))
+	manifest := `[{"Config":"abc.json","Layers":["111/layer.tar","222/layer.tar"]}]`
+	archive := buildTar(t,
+		fileEntry("manifest.json", manifest),
+		dirEntry("111/"),
+		fileEntry("111/layer.tar", string(layer)),
+		dirEntry("222/"),
+		tarEntry{header: &tar.Header{Typeflag: tar.TypeSymlink, Name: "222/layer.tar", Linkname: "../111/layer.tar"}},
+	)
+
+	layers, err := parseImageArchive(bytes.NewReader(archive))
+	require.NoError(t, err)
+	require.Len(t, layers.Layers, 2)
+	assert.Equal(t, "111", layers.Layers[0].ID)
+	assert.Equal(t, []models.ContainerChange{{Kind: models.ChangeChanged, Path: "/hello", Size: 5}}, layers.Layers[1].Changes)
+
+	_, err = parseImageArchive(bytes.NewReader(buildTar(t, fileEntry("index.json", "{}"))))
+	assert.Error(t, err)
+}
+
+func TestImageSteps(t *testing.T) {
+	config := []byte(`{"history":[
+		{"created_by":"ADD rootfs.tar.gz /"},
+		{"created_by":"CMD [\"bash\"]","empty_layer":true},
+		{"created_by":"RUN apt-get install -y curl"}
+	]}`)
+	assert.Equal(t, []int{0, -1, 1}, imageSteps(config, 2))
+	assert.Nil(t, imageSteps(config, 3), "a history that misses layers is not used")
+	assert.Nil(t, imageSteps(nil, 1))
+}
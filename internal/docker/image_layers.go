@@ -0,0 +1,283 @@
+package docker
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+)
+
+// ImageLayer is a layer of an image with the paths it adds, overwrites and deletes
+type ImageLayer struct {
+	ID      string // digest of the layer archive, as named in the image archive
+	Size    int64  // total size of the files in the layer
+	Changes []models.ContainerChange
+}
+
+// ImageLayers tells which layers of an image wrote each path
+type ImageLayers struct {
+	Layers []ImageLayer
+	// Steps holds, for each step of the image history from oldest to newest, the index of the layer
+	// it created, or -1 for steps that only changed the configuration. It is nil when the image has no history.
+	Steps []int
+	// writers holds the layers that wrote each path of the final filesystem, oldest first
+	writers map[string][]int
+}
+
+// Origin returns the index of the layer that last wrote path, and the earlier layers it overwrote
+func (l *ImageLayers) Origin(filePath string) (layer int, overwritten []int, ok bool) {
+	writers := l.writers[cleanArchivePath(filePath)]
+	if len(writers) == 0 {
+		return 0, nil, false
+	}
+	return writers[len(writers)-1], writers[:len(writers)-1], true
+}
+
+// maxImageConfigSize bounds the JSON files of an image archive that are kept to find the image config
+const maxImageConfigSize = 4 << 20
+
+// AnalyzeLayers reads the layers of an image from `docker save`
+func AnalyzeLayers(image string) (*ImageLayers, error) {
+	cmd := Execute("save", image)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stdout pipe: %w", err)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to save image %s: %w", image, err)
+	}
+
+	layers, err := parseImageArchive(stdout)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil, fmt.Errorf("failed to read the layers of %s: %w", image, err)
+	}
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to save image %s: %w: %s", image, err, strings.TrimSpace(stderr.String()))
+	}
+	return layers, nil
+}
+
+// layerEntry is a path in a layer archive
+type layerEntry struct {
+	path     string
+	size     int64
+	isDir    bool
+	whiteout bool // the path is deleted
+	opaque   bool // the lower contents of the directory are hidden
+}
+
+// parseImageArchive reads an archive written by docker save, in the legacy or the OCI layout.
+// Layers are read as they come, since manifest.json, which orders them, may come last.
+func parseImageArchive(r io.Reader) (*ImageLayers, error) {
+	var manifest []struct {
+		Config string
+		Layers []string
+	}
+	archives := map[string][]layerEntry{}
+	configs := map[string][]byte{}
+	links := map[string]string{}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := path.Clean(header.Name)
+		switch {
+		case header.Typeflag == tar.TypeSymlink:
+			// The legacy layout links layers that several images share
+			links[name] = path.Join(path.Dir(name), header.Linkname)
+		case header.Typeflag != tar.TypeReg:
+		case name == "manifest.json":
+			if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+				return nil, fmt.Errorf("invalid manifest.json: %w", err)
+			}
+		default:
+			br := bufio.NewReader(tr)
+			if first, _ := br.Peek(1); len(first) == 1 && first[0] == '{' && header.Size <= maxImageConfigSize {
+				data, err := io.ReadAll(br)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", name, err)
+				}
+				configs[name] = data
+				continue
+			}
+			entries, isLayer, err := readLayerArchive(br)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			if isLayer {
+				archives[name] = entries
+			}
+		}
+	}
+	if len(manifest) == 0 {
+		return nil, errors.New("the image archive has no manifest")
+	}
+
+	order := make([]string, len(manifest[0].Layers))
+	for i, layer := range manifest[0].Layers {
+		layer = path.Clean(layer)
+		if target, ok := links[layer]; ok {
+			layer = target
+		}
+		order[i] = layer
+	}
+	layers := buildImageLayers(order, archives)
+	layers.Steps = imageSteps(configs[path.Clean(manifest[0].Config)], len(order))
+	return layers, nil
+}
+
+// imageSteps maps the steps of the history in an image config to the layers they created
+func imageSteps(config []byte, layers int) []int {
+	var parsed struct {
+		History []struct {
+			EmptyLayer bool `json:"empty_layer"`
+		} `json:"history"`
+	}
+	if err := json.Unmarshal(config, &parsed); err != nil {
+		return nil
+	}
+	steps := make([]int, len(parsed.History))
+	layer := 0
+	for i, step := range parsed.History {
+		if step.EmptyLayer {
+			steps[i] = -1
+			continue
+		}
+		steps[i] = layer
+		layer++
+	}
+	if layer != layers {
+		// Images imported without a history, or with one that does not account for every layer
+		return nil
+	}
+	return steps
+}
+
+// readLayerArchive reads the entries of a layer, which may be compressed.
+// isLayer is false for the other files of an image archive, like the JSON config.
+func readLayerArchive(r io.Reader) (entries []layerEntry, isLayer bool, err error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(4)
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, false, err
+		}
+		br = bufio.NewReader(gz)
+	case bytes.Equal(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return nil, false, errors.New("zstd compressed layers are not supported")
+	}
+	// Every tar header has the ustar magic at offset 257
+	header, _ := br.Peek(262)
+	if len(header) < 262 || !bytes.HasPrefix(header[257:], []byte("ustar")) {
+		return nil, false, nil
+	}
+
+	tr := tar.NewReader(br)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, true, err
+		}
+		name := cleanArchivePath(h.Name)
+		if name == "/" {
+			continue
+		}
+		dir, base := path.Split(name)
+		switch {
+		case base == whiteoutOpaque:
+			entries = append(entries, layerEntry{path: path.Clean(dir), opaque: true})
+		case strings.HasPrefix(base, whiteoutPrefix):
+			entries = append(entries, layerEntry{path: dir + strings.TrimPrefix(base, whiteoutPrefix), whiteout: true})
+		default:
+			entries = append(entries, layerEntry{path: name, size: h.Size, isDir: h.Typeflag == tar.TypeDir})
+		}
+	}
+	return entries, true, nil
+}
+
+// buildImageLayers applies the layers in order. Layers missing from the archive, like the
+// empty layers some builders write as a bare end-of-archive marker, have no changes.
+func buildImageLayers(order []string, archives map[string][]layerEntry) *ImageLayers {
+	result := &ImageLayers{writers: map[string][]int{}}
+	for i, name := range order {
+		layer := ImageLayer{ID: layerID(name)}
+		entries := archives[name]
+
+		// Deletions apply to the lower layers, whatever their order in the archive
+		for _, entry := range entries {
+			switch {
+			case entry.opaque:
+				result.remove(entry.path, false)
+			case entry.whiteout:
+				if _, ok := result.writers[entry.path]; ok {
+					layer.Changes = append(layer.Changes, models.ContainerChange{Kind: models.ChangeDeleted, Path: entry.path})
+				}
+				result.remove(entry.path, true)
+			}
+		}
+		for _, entry := range entries {
+			if entry.opaque || entry.whiteout {
+				continue
+			}
+			if _, ok := result.writers[entry.path]; !ok {
+				layer.Changes = append(layer.Changes, models.ContainerChange{Kind: models.ChangeAdded, Path: entry.path, Size: entry.size})
+			} else if !entry.isDir {
+				// Directories are listed again by every layer that writes into them
+				layer.Changes = append(layer.Changes, models.ContainerChange{Kind: models.ChangeChanged, Path: entry.path, Size: entry.size})
+			}
+			result.writers[entry.path] = append(result.writers[entry.path], i)
+			layer.Size += entry.size
+		}
+		result.Layers = append(result.Layers, layer)
+	}
+	return result
+}
+
+// remove forgets the writers below a directory, and of the path itself if withSelf is set
+func (l *ImageLayers) remove(filePath string, withSelf bool) {
+	if withSelf {
+		delete(l.writers, filePath)
+	}
+	prefix := strings.TrimSuffix(filePath, "/") + "/"
+	for p := range l.writers {
+		if strings.HasPrefix(p, prefix) {
+			delete(l.writers, p)
+		}
+	}
+}
+
+// layerID names a layer by its digest: "blobs/sha256/HEX" in the OCI layout, "HEX/layer.tar" in the legacy one
+func layerID(name string) string {
+	if path.Base(name) == "layer.tar" {
+		return path.Base(path.Dir(name))
+	}
+	return path.Base(name)
+}
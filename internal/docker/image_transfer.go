@@ -0,0 +1,295 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/client"
+)
+
+// LayerProgress is the state of a layer being pulled or pushed
+type LayerProgress struct {
+	ID     string
+	Status string // e.g. "Downloading", "Extracting", "Pull complete"
+	// Current and Total are the bytes of the running phase; Total is 0 when the daemon did not tell it
+	Current int64
+	Total   int64
+}
+
+// Done tells whether the layer needs nothing more
+func (l LayerProgress) Done() bool {
+	switch {
+	case l.Status == "Pull complete", l.Status == "Already exists", l.Status == "Pushed",
+		l.Status == "Layer already exists", strings.HasPrefix(l.Status, "Mounted from"):
+		return true
+	default:
+		return false
+	}
+}
+
+// ImageProgress is the progress of a pull or a push, built from the JSON messages the daemon streams
+type ImageProgress struct {
+	// Layers are in the order the daemon first mentioned them
+	Layers []LayerProgress
+	// Status holds the messages that are not about a layer, like the digest of the image
+	Status []string
+}
+
+// imageProgressMessage is a message of the JSON progress stream of the daemon
+type imageProgressMessage struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Progress *struct {
+		Current int64 `json:"current"`
+		Total   int64 `json:"total"`
+	} `json:"progressDetail"`
+	ErrorDetail *struct {
+		Message string `json:"message"`
+	} `json:"errorDetail"`
+	Error string `json:"error"`
+}
+
+// apply updates the progress with a message of the stream; messages that report a failure are returned as an error
+func (p *ImageProgress) apply(msg imageProgressMessage) error {
+	if msg.ErrorDetail != nil && msg.ErrorDetail.Message != "" {
+		return errors.New(msg.ErrorDetail.Message)
+	}
+	if msg.Error != "" {
+		return errors.New(msg.Error)
+	}
+	if msg.Status == "" {
+		return nil
+	}
+	// "Pulling from" is about the tag being pulled, not a layer
+	if msg.ID == "" || strings.HasPrefix(msg.Status, "Pulling from") {
+		status := msg.Status
+		if msg.ID != "" {
+			status = msg.ID + ": " + status
+		}
+		p.Status = append(p.Status, status)
+		return nil
+	}
+
+	i := 0
+	for i < len(p.Layers) && p.Layers[i].ID != msg.ID {
+		i++
+	}
+	if i == len(p.Layers) {
+		p.Layers = append(p.Layers, LayerProgress{ID: msg.ID})
+	}
+	layer := &p.Layers[i]
+	layer.Status = msg.Status
+	layer.Current, layer.Total = 0, 0
+	if msg.Progress != nil {
+		layer.Current, layer.Total = msg.Progress.Current, msg.Progress.Total
+	}
+	return nil
+}
+
+// followImageProgress reads the JSON progress stream of a pull or push and reports the progress after each message
+func followImageProgress(r io.Reader, progress func(ImageProgress)) error {
+	var current ImageProgress
+	decoder := json.NewDecoder(r)
+	for {
+		var msg imageProgressMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if err := current.apply(msg); err != nil {
+			return err
+		}
+		// The callee may keep the progress while the next message is applied
+		snapshot := current
+		snapshot.Layers = append([]LayerProgress(nil), current.Layers...)
+		snapshot.Status = append([]string(nil), current.Status...)
+		progress(snapshot)
+	}
+}
+
+// PullImage pulls ref like `docker pull`, reporting the progress of each layer.
+// Canceling ctx stops the pull.
+func PullImage(ctx context.Context, cli *client.Client, ref string, progress func(ImageProgress)) error {
+	stream, err := cli.ImagePull(ctx, ref, image.PullOptions{RegistryAuth: RegistryAuth(ref)})
+	if err != nil {
+		return fmt.Errorf("failed to pull %s: %w", ref, err)
+	}
+	defer func() { _ = stream.Close() }()
+	return followImageProgress(stream, progress)
+}
+
+// PushImage pushes ref like `docker push`, reporting the progress of each layer.
+// Canceling ctx stops the push.
+func PushImage(ctx context.Context, cli *client.Client, ref string, progress func(ImageProgress)) error {
+	stream, err := cli.ImagePush(ctx, ref, image.PushOptions{RegistryAuth: RegistryAuth(ref)})
+	if err != nil {
+		return fmt.Errorf("failed to push %s: %w", ref, err)
+	}
+	defer func() { _ = stream.Close() }()
+	return followImageProgress(stream, progress)
+}
+
+// TagImage creates the tag target for the image source, like `docker tag`
+func TagImage(ctx context.Context, cli *client.Client, source, target string) error {
+	if err := cli.ImageTag(ctx, source, target); err != nil {
+		return fmt.Errorf("failed to tag %s as %s: %w", source, target, err)
+	}
+	return nil
+}
+
+// dockerHubAuthKey is the key the docker CLI stores the credentials of Docker Hub under
+const dockerHubAuthKey = "https://index.docker.io/v1/"
+
+// RegistryHost returns the registry ref is pulled from and pushed to, like docker does:
+// the first element of the name when it looks like a host, Docker Hub otherwise
+func RegistryHost(ref string) string {
+	first, _, ok := strings.Cut(ref, "/")
+	if ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return first
+	}
+	return "docker.io"
+}
+
+// RegistryAuth returns the encoded credentials the docker CLI has for the registry of ref, read from its
+// config.json or credential helper. Without credentials it is the encoding of anonymous access.
+func RegistryAuth(ref string) string {
+	host := RegistryHost(ref)
+	authConfig, err := loadRegistryCredentials(dockerConfigDir(), host)
+	if err != nil {
+		slog.Info("No registry credentials, pulling and pushing anonymously",
+			slog.String("registry", host),
+			slog.Any("error", err))
+	}
+	encoded, err := registry.EncodeAuthConfig(authConfig)
+	if err != nil {
+		return ""
+	}
+	return encoded
+}
+
+// dockerConfigDir is where the docker CLI keeps config.json
+func dockerConfigDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".docker")
+}
+
+// dockerConfigFile is the part of config.json of the docker CLI that holds registry credentials
+type dockerConfigFile struct {
+	Auths map[string]struct {
+		Auth          string `json:"auth"`
+		IdentityToken string `json:"identitytoken"`
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// registryHostname strips the scheme and path from a key of config.json, so that keys written by older docker
+// versions, like "https://registry.example.com/v1/", match the host. It is the normalization of the docker CLI.
+func registryHostname(key string) string {
+	hostname := strings.TrimPrefix(key, "http://")
+	hostname = strings.TrimPrefix(hostname, "https://")
+	hostname, _, _ = strings.Cut(hostname, "/")
+	return hostname
+}
+
+// loadRegistryCredentials finds the credentials for host in the config of the docker CLI in dir.
+// Like the docker CLI, it asks the credential helper of the host or the credentials store first, and reads
+// the auths of config.json when they have nothing for the host.
+func loadRegistryCredentials(dir, host string) (registry.AuthConfig, error) {
+	// The docker CLI keeps everything of Docker Hub, credential helpers included, under its index server address
+	key := host
+	if host == "docker.io" {
+		key = dockerHubAuthKey
+	}
+	anonymous := registry.AuthConfig{ServerAddress: key}
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return anonymous, err
+	}
+	var config dockerConfigFile
+	if err := json.Unmarshal(data, &config); err != nil {
+		return anonymous, fmt.Errorf("failed to parse config.json: %w", err)
+	}
+
+	helper := config.CredHelpers[key]
+	if helper == "" {
+		helper = config.CredsStore
+	}
+	var helperErr error
+	if helper != "" {
+		authConfig, err := credentialsFromHelper(helper, key)
+		if err == nil {
+			return authConfig, nil
+		}
+		helperErr = err
+	}
+
+	auth, ok := config.Auths[key]
+	if !ok {
+		for k, a := range config.Auths {
+			if registryHostname(k) == registryHostname(key) {
+				auth, ok = a, true
+				break
+			}
+		}
+	}
+	if !ok {
+		if helperErr != nil {
+			return anonymous, helperErr
+		}
+		return anonymous, fmt.Errorf("no credentials for %s", key)
+	}
+	authConfig := registry.AuthConfig{ServerAddress: key, IdentityToken: auth.IdentityToken}
+	if auth.Auth != "" {
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return anonymous, fmt.Errorf("failed to decode the credentials for %s: %w", key, err)
+		}
+		authConfig.Username, authConfig.Password, _ = strings.Cut(string(decoded), ":")
+	}
+	return authConfig, nil
+}
+
+// credentialsFromHelper asks a docker credential helper, like docker-credential-osxkeychain, for the credentials of key
+func credentialsFromHelper(helper, key string) (registry.AuthConfig, error) {
+	anonymous := registry.AuthConfig{ServerAddress: key}
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(key)
+	output, err := cmd.Output()
+	if err != nil {
+		return anonymous, fmt.Errorf("credential helper %s failed: %w", helper, err)
+	}
+	var credentials struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(output), &credentials); err != nil {
+		return anonymous, fmt.Errorf("failed to parse the output of credential helper %s: %w", helper, err)
+	}
+	// Helpers return identity tokens with this user name
+	if credentials.Username == "<token>" {
+		return registry.AuthConfig{ServerAddress: key, IdentityToken: credentials.Secret}, nil
+	}
+	return registry.AuthConfig{ServerAddress: key, Username: credentials.Username, Password: credentials.Secret}, nil
+}
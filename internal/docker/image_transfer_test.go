@@ -0,0 +1,159 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowImageProgress(t *testing.T) {
+	stream := strings.Join([]string{
+		`{"status":"Pulling from library/alpine","id":"3.20"}`,
+		`{"status":"Pulling fs layer","progressDetail":{},"id":"a1b2c3"}`,
+		`{"status":"Pulling fs layer","progressDetail":{},"id":"d4e5f6"}`,
+		`{"status":"Downloading","progressDetail":{"current":1024,"total":4096},"progress":"[==>   ]","id":"a1b2c3"}`,
+		`{"status":"Already exists","progressDetail":{},"id":"d4e5f6"}`,
+		`{"status":"Extracting","progressDetail":{"current":2048,"total":4096},"id":"a1b2c3"}`,
+		`{"status":"Pull complete","progressDetail":{},"id":"a1b2c3"}`,
+		`{"status":"Digest: sha256:0123"}`,
+		`{"status":"Status: Downloaded newer image for alpine:3.20"}`,
+	}, "\n")
+
+	var reports []ImageProgress
+	err := followImageProgress(strings.NewReader(stream), func(p ImageProgress) {
+		reports = append(reports, p)
+	})
+	require.NoError(t, err)
+	require.Len(t, reports, 9)
+
+	downloading := reports[3]
+	require.Len(t, downloading.Layers, 2)
+	assert.Equal(t, LayerProgress{ID: "a1b2c3", Status: "Downloading", Current: 1024, Total: 4096}, downloading.Layers[0])
+	assert.False(t, downloading.Layers[0].Done())
+
+	last := reports[8]
+	assert.Equal(t, []LayerProgress{
+		{ID: "a1b2c3", Status: "Pull complete"},
+		{ID: "d4e5f6", Status: "Already exists"},
+	}, last.Layers)
+	assert.True(t, last.Layers[0].Done())
+	assert.Equal(t, []string{
+		"3.20: Pulling from library/alpine",
+		"Digest: sha256:0123",
+		"Status: Downloaded newer image for alpine:3.20",
+	}, last.Status)
+}
+
+func TestFollowImageProgress_Error(t *testing.T) {
+	stream := `{"status":"The push refers to repository [localhost:5000/app]"}
+{"status":"Preparing","progressDetail":{},"id":"a1b2c3"}
+{"errorDetail":{"message":"denied: requested access to the resource is denied"},"error":"denied: requested access to the resource is denied"}
+`
+	var last ImageProgress
+	err := followImageProgress(strings.NewReader(stream), func(p ImageProgress) { last = p })
+	require.EqualError(t, err, "denied: requested access to the resource is denied")
+	assert.Equal(t, []LayerProgress{{ID: "a1b2c3", Status: "Preparing"}}, last.Layers)
+}
+
+func TestRegistryHost(t *testing.T) {
+	tests := map[string]string{
+		"alpine":                        "docker.io",
+		"library/alpine:3.20":           "docker.io",
+		"tokuhirom/dcv":                 "docker.io",
+		"localhost/app":                 "localhost",
+		"localhost:5000/app:dev":        "localhost:5000",
+		"ghcr.io/tokuhirom/dcv:latest":  "ghcr.io",
+		"registry.example.com/team/app": "registry.example.com",
+	}
+	for ref, want := range tests {
+		assert.Equal(t, want, RegistryHost(ref), ref)
+	}
+}
+
+func TestLoadRegistryCredentials(t *testing.T) {
+	dir := t.TempDir()
+	config := `{"auths": {
+		"https://index.docker.io/v1/": {"auth": "dXNlcjpwYXNz"},
+		"ghcr.io": {"identitytoken": "token"}
+	}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0600))
+
+	auth, err := loadRegistryCredentials(dir, "docker.io")
+	require.NoError(t, err)
+	assert.Equal(t, "user", auth.Username)
+	assert.Equal(t, "pass", auth.Password)
+	assert.Equal(t, "https://index.docker.io/v1/", auth.ServerAddress)
+
+	auth, err = loadRegistryCredentials(dir, "ghcr.io")
+	require.NoError(t, err)
+	assert.Equal(t, "token", auth.IdentityToken)
+
+	// Registries without credentials, like a local registry, are used anonymously
+	auth, err = loadRegistryCredentials(dir, "localhost:5000")
+	assert.Error(t, err)
+	assert.Equal(t, "localhost:5000", auth.ServerAddress)
+	assert.Empty(t, auth.Username)
+}
+
+func TestLoadRegistryCredentials_NormalizedKeys(t *testing.T) {
+	dir := t.TempDir()
+	config := `{"auths": {
+		"https://registry.example.com": {"auth": "dXNlcjpwYXNz"},
+		"http://legacy.example.com/v1/": {"identitytoken": "token"}
+	}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0600))
+
+	auth, err := loadRegistryCredentials(dir, "registry.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "user", auth.Username)
+	assert.Equal(t, "registry.example.com", auth.ServerAddress)
+
+	auth, err = loadRegistryCredentials(dir, "legacy.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "token", auth.IdentityToken)
+}
+
+func TestLoadRegistryCredentials_CredentialHelpers(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake credential helper is a shell script")
+	}
+	// The fake helper knows only Docker Hub, under the server address the docker CLI uses
+	bin := t.TempDir()
+	script := `#!/bin/sh
+read key
+if [ "$key" = "https://index.docker.io/v1/" ]; then
+	echo '{"Username": "hub", "Secret": "secret"}'
+else
+	echo "credentials not found in native keychain"
+	exit 1
+fi
+`
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "docker-credential-fake"), []byte(script), 0755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	dir := t.TempDir()
+	config := `{
+		"credHelpers": {"https://index.docker.io/v1/": "fake"},
+		"credsStore": "fake",
+		"auths": {"https://registry.example.com": {"auth": "dXNlcjpwYXNz"}}
+	}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0600))
+
+	auth, err := loadRegistryCredentials(dir, "docker.io")
+	require.NoError(t, err)
+	assert.Equal(t, "hub", auth.Username)
+	assert.Equal(t, "secret", auth.Password)
+
+	// A host the store does not know falls back to the auths
+	auth, err = loadRegistryCredentials(dir, "registry.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "user", auth.Username)
+
+	_, err = loadRegistryCredentials(dir, "ghcr.io")
+	assert.Error(t, err)
+}
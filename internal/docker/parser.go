@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/tokuhirom/dcv/internal/models"
 )
@@ -238,3 +239,48 @@ func ParseImagesJSON(output []byte) ([]models.DockerImage, error) {
 
 	return images, nil
 }
+
+// ParseImageHistoryJSON parses the output of docker history --format json, newest step first
+func ParseImageHistoryJSON(output []byte) ([]models.ImageHistoryEntry, error) {
+	history := make([]models.ImageHistoryEntry, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	// The commands of RUN steps are not truncated, and may be long
+	scanner.Buffer(make([]byte, 0, 64*1024), 4<<20)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry models.ImageHistoryEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			// Skip invalid lines
+			continue
+		}
+
+		history = append(history, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// ParseContainerDiff parses the output of docker diff, e.g. "C /etc" and "A /etc/app.conf"
+func ParseContainerDiff(output []byte) []models.ContainerChange {
+	var changes []models.ContainerChange
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		kind, path, ok := strings.Cut(scanner.Text(), " ")
+		if !ok || path == "" {
+			continue
+		}
+		switch models.ChangeKind(kind) {
+		case models.ChangeAdded, models.ChangeChanged, models.ChangeDeleted:
+			changes = append(changes, models.ContainerChange{Kind: models.ChangeKind(kind), Path: path})
+		}
+	}
+	return changes
+}
@@ -2,6 +2,7 @@ package docker
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/tokuhirom/dcv/internal/models"
@@ -357,3 +358,37 @@ not valid json
 		})
 	}
 }
+
+func TestParseImageHistoryJSON(t *testing.T) {
+	long := strings.Repeat("x", 100*1024)
+	output := []byte(`{"Comment":"","CreatedAt":"2024-05-01T10:00:00+09:00","CreatedBy":"CMD [\"app\"]","CreatedSince":"2 weeks ago","ID":"sha256:abc","Size":"0B"}
+{"Comment":"buildkit.dockerfile.v0","CreatedAt":"2024-05-01T09:59:00+09:00","CreatedBy":"RUN /bin/sh -c ` + long + ` # buildkit","CreatedSince":"2 weeks ago","ID":"<missing>","Size":"412MB"}
+not json
+`)
+	history, err := ParseImageHistoryJSON(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("got %d entries, want 2", len(history))
+	}
+	if history[0].Instruction() != `CMD ["app"]` || history[0].SizeBytes() != 0 {
+		t.Errorf("unexpected first entry %+v", history[0])
+	}
+	if history[1].ID != "<missing>" || history[1].SizeBytes() != 412_000_000 {
+		t.Errorf("unexpected second entry %+v", history[1])
+	}
+}
+
+func TestParseContainerDiff(t *testing.T) {
+	output := []byte("C /etc\nA /etc/app.conf\nD /var/cache/apt\nC /etc/hosts\nnot a change\n\n")
+	want := []models.ContainerChange{
+		{Kind: models.ChangeChanged, Path: "/etc"},
+		{Kind: models.ChangeAdded, Path: "/etc/app.conf"},
+		{Kind: models.ChangeDeleted, Path: "/var/cache/apt"},
+		{Kind: models.ChangeChanged, Path: "/etc/hosts"},
+	}
+	if got := ParseContainerDiff(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseContainerDiff() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,282 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SignalArgs returns the docker arguments that deliver signal (e.g. "TERM") to a process.
+// pid is the PID reported by `docker top`, which lives in the PID namespace of the
+// Docker host (or of the host container for DinD). It is translated into the
+// container's own PID namespace before use; see ResolveContainerPID for hostLookup.
+// The native kill binary is preferred; the injected helper is used when the image has none.
+func SignalArgs(container *Container, pid string, signal string, hostLookup bool) ([]string, error) {
+	containerPID, err := ResolveContainerPID(container, pid, hostLookup)
+	if err != nil {
+		return nil, err
+	}
+
+	// kill -0 checks that the process exists and can be signalled without affecting it
+	_, err = ExecuteCaptured(container.OperationArgs("exec", "kill", "-0", containerPID)...)
+	if err == nil {
+		return container.OperationArgs("exec", "kill", "-"+signal, containerPID), nil
+	}
+	if !isExecutableNotFound(err) {
+		return nil, fmt.Errorf("cannot signal PID %s in the container: %w", containerPID, err)
+	}
+
+	helperPath := HelperPathFor(container)
+	_, helperErr := ExecuteCaptured(container.OperationArgs("exec", helperPath, "kill", "-0", containerPID)...)
+	if helperErr == nil {
+		return container.OperationArgs("exec", helperPath, "kill", "-"+signal, containerPID), nil
+	}
+	if isExecutableNotFound(helperErr) {
+		return nil, fmt.Errorf("the container has no kill binary and the dcv helper is not injected at %s; inject it with 'H' and try again", helperPath)
+	}
+	return nil, fmt.Errorf("cannot signal PID %s in the container with the dcv helper: %w", containerPID, helperErr)
+}
+
+// ContainerPIDError reports that a PID of `docker top` could not be located from inside the container.
+// Looking it up on the Docker host with hostLookup may still find it.
+type ContainerPIDError struct {
+	PID string
+	Err error
+}
+
+func (e *ContainerPIDError) Error() string {
+	return fmt.Sprintf("cannot locate PID %s inside the container: %v", e.PID, e.Err)
+}
+
+func (e *ContainerPIDError) Unwrap() error {
+	return e.Err
+}
+
+// ResolveContainerPID translates a PID reported by `docker top` into the PID
+// the process has inside the container's PID namespace.
+// /proc of the Docker host is read directly when dcv runs on it. Otherwise the process is located by
+// matching the process tree of `docker top` with the one read from /proc inside the container, and a
+// *ContainerPIDError is returned when that fails. Only with hostLookup, which the user confirms, is /proc
+// of the Docker host read in a helper container that shares its PID namespace.
+func ResolveContainerPID(container *Container, pid string, hostLookup bool) (string, error) {
+	if _, err := strconv.Atoi(pid); err != nil {
+		return "", fmt.Errorf("invalid PID %q", pid)
+	}
+
+	var status string
+	switch {
+	case container.IsDind():
+		// The inner daemon reports PIDs of the host container's namespace
+		output, err := ExecuteCaptured("exec", container.HostContainerID(), "cat", fmt.Sprintf("/proc/%s/status", pid))
+		if err != nil {
+			return "", fmt.Errorf("failed to read status of PID %s in host container %s: %w", pid, container.HostContainerID(), err)
+		}
+		status = string(output)
+	case hostLookup:
+		output, err := readHostProcess(container, pid)
+		if err != nil {
+			return "", err
+		}
+		status = output
+	default:
+		if local, ok := readLocalProcess(container, pid); ok {
+			status = local
+			break
+		}
+		containerPID, err := locateContainerPID(container, pid)
+		if err != nil {
+			return "", &ContainerPIDError{PID: pid, Err: err}
+		}
+		return strconv.Itoa(containerPID), nil
+	}
+
+	containerPID := parseNSpid(status)
+	if containerPID == "" {
+		return "", fmt.Errorf("cannot map PID %s into the container's PID namespace: kernel does not report NSpid", pid)
+	}
+	return containerPID, nil
+}
+
+// readLocalProcess returns /proc/<pid>/status when dcv runs on the Docker host and the process belongs to the container
+func readLocalProcess(container *Container, pid string) (string, bool) {
+	cgroup, err := os.ReadFile(fmt.Sprintf("/proc/%s/cgroup", pid))
+	if err != nil || !strings.Contains(string(cgroup), container.ContainerID()) {
+		return "", false
+	}
+	status, err := os.ReadFile(fmt.Sprintf("/proc/%s/status", pid))
+	if err != nil {
+		return "", false
+	}
+	return string(status), true
+}
+
+// containerProcessesScript prints the PID and parent PID of every process of the container from /proc,
+// in the columns of ps, using nothing but sh
+const containerProcessesScript = `echo "PID PPID"
+for f in /proc/[0-9]*/status; do
+  while read -r key value; do
+    if [ "$key" = PPid: ]; then pid=${f#/proc/}; echo "${pid%/status} $value"; break; fi
+  done < "$f"
+done 2>/dev/null`
+
+// locateContainerPID finds the process hostPID of `docker top` among the processes read inside the container
+func locateContainerPID(container *Container, hostPID string) (int, error) {
+	topOutput, err := ExecuteCaptured(container.OperationArgs("top")...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list the processes of the container: %w", err)
+	}
+	hostTree, err := parseProcessTable(string(topOutput))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse docker top: %w", err)
+	}
+
+	output, err := ExecuteCaptured(container.OperationArgs("exec", "sh", "-c", containerProcessesScript)...)
+	if err != nil {
+		if !isExecutableNotFound(err) {
+			return 0, fmt.Errorf("failed to read the processes in the container: %w", err)
+		}
+		output, err = ExecuteCaptured(HelperArgs(container, "ps")...)
+		if isExecutableNotFound(err) {
+			return 0, fmt.Errorf("the container has no sh and the dcv helper is not injected at %s; inject it with 'H'", HelperPathFor(container))
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read the processes in the container with the dcv helper: %w", err)
+		}
+	}
+	containerTree, err := parseProcessTable(string(output))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the processes in the container: %w", err)
+	}
+
+	target, _ := strconv.Atoi(hostPID)
+	return matchContainerPID(hostTree, containerTree, target)
+}
+
+// parseProcessTable returns the parent PID of each process in the output of ps, docker top or the helper's ps,
+// which have PID and PPID columns
+func parseProcessTable(output string) (map[int]int, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	header := strings.Fields(lines[0])
+	pidColumn, ppidColumn := slices.Index(header, "PID"), slices.Index(header, "PPID")
+	if pidColumn < 0 || ppidColumn < 0 {
+		return nil, fmt.Errorf("no PID and PPID columns in %q", lines[0])
+	}
+
+	parents := make(map[int]int)
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) <= max(pidColumn, ppidColumn) {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[pidColumn])
+		if err != nil {
+			continue
+		}
+		ppid, err := strconv.Atoi(fields[ppidColumn])
+		if err != nil {
+			continue
+		}
+		parents[pid] = ppid
+	}
+	return parents, nil
+}
+
+// matchContainerPID returns the container PID of the process target of the host tree. Both trees have the same
+// shape: the container's init and the processes of docker exec are the roots, whose parents are outside the
+// container, and siblings are paired in PID order, the order in which they started.
+func matchContainerPID(hostTree, containerTree map[int]int, target int) (int, error) {
+	if _, ok := hostTree[target]; !ok {
+		return 0, fmt.Errorf("PID %d is not a process of the container anymore", target)
+	}
+
+	// The ancestors of target, from its root down
+	var path []int
+	for pid := target; ; pid = hostTree[pid] {
+		path = append(path, pid)
+		if len(path) > len(hostTree) {
+			return 0, fmt.Errorf("the parents of PID %d form a loop", target)
+		}
+		if _, ok := hostTree[hostTree[pid]]; !ok {
+			break
+		}
+	}
+	slices.Reverse(path)
+
+	hostParent, containerParent := 0, 0
+	for _, pid := range path {
+		hostSiblings := childrenOf(hostTree, hostParent)
+		containerSiblings := childrenOf(containerTree, containerParent)
+		if len(hostSiblings) != len(containerSiblings) {
+			return 0, fmt.Errorf("the processes of the container changed while they were read; try again")
+		}
+		hostParent = pid
+		containerParent = containerSiblings[slices.Index(hostSiblings, pid)]
+	}
+	return containerParent, nil
+}
+
+// childrenOf returns the children of parent sorted by PID. The children of 0 are the roots of the tree,
+// whose parents are not in it.
+func childrenOf(tree map[int]int, parent int) []int {
+	var children []int
+	for pid, ppid := range tree {
+		if _, ok := tree[ppid]; !ok {
+			ppid = 0
+		}
+		if ppid == parent {
+			children = append(children, pid)
+		}
+	}
+	slices.Sort(children)
+	return children
+}
+
+// readHostProcess returns /proc/<pid>/cgroup and /proc/<pid>/status of a process reported by `docker top`,
+// read in a short-lived helper container that shares the PID and cgroup namespaces of the Docker host.
+// Rootless and user-namespace remapped daemons do not allow such a container.
+func readHostProcess(container *Container, pid string) (string, error) {
+	files := []string{fmt.Sprintf("/proc/%s/cgroup", pid), fmt.Sprintf("/proc/%s/status", pid)}
+	image, err := ensureHelperImage()
+	if err != nil {
+		return "", fmt.Errorf("cannot map PID %s into the container's PID namespace: %w", pid, err)
+	}
+	output, err := ExecuteCaptured(hostProcessArgs(image, files)...)
+	if err != nil {
+		return "", fmt.Errorf("the Docker daemon cannot run a container in the host's PID namespace, which rootless and user-namespace remapped daemons do not allow: %w", err)
+	}
+	if !strings.Contains(string(output), container.ContainerID()) {
+		return "", fmt.Errorf("PID %s does not belong to the container anymore", pid)
+	}
+	return string(output), nil
+}
+
+// hostProcessArgs runs the helper's cat in the PID and cgroup namespaces of the Docker host
+func hostProcessArgs(image string, files []string) []string {
+	args := []string{"run", "--rm", "--pid=host", "--cgroupns=host", "--network", "none", image, "cat"}
+	return append(args, files...)
+}
+
+// parseNSpid returns the innermost namespace PID from the contents of /proc/<pid>/status.
+// The NSpid line lists the PID in each nested namespace, outermost first.
+func parseNSpid(status string) string {
+	for _, line := range strings.Split(status, "\n") {
+		if !strings.HasPrefix(line, "NSpid:") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "NSpid:"))
+		if len(fields) == 0 {
+			return ""
+		}
+		return fields[len(fields)-1]
+	}
+	return ""
+}
+
+// isExecutableNotFound reports whether a docker exec failed because the command does not exist
+func isExecutableNotFound(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "executable file not found") ||
+		strings.Contains(msg, "no such file or directory")
+}
@@ -0,0 +1,112 @@
+package docker
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNSpid(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   string
+		expected string
+	}{
+		{
+			name:     "process in a container namespace",
+			status:   "Name:\tnginx\nPid:\t12345\nPPid:\t12300\nNSpid:\t12345\t7\nNSpgid:\t12345\t7\n",
+			expected: "7",
+		},
+		{
+			name:     "nested namespaces use the innermost PID",
+			status:   "Name:\tapp\nNSpid:\t4000\t300\t12\n",
+			expected: "12",
+		},
+		{
+			name:     "process in the host namespace",
+			status:   "Name:\tsystemd\nNSpid:\t1\n",
+			expected: "1",
+		},
+		{
+			name:     "kernel without NSpid",
+			status:   "Name:\told\nPid:\t42\n",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseNSpid(tt.status))
+		})
+	}
+}
+
+func TestIsExecutableNotFound(t *testing.T) {
+	assert.True(t, isExecutableNotFound(errors.New(`OCI runtime exec failed: exec failed: unable to start container process: exec: "kill": executable file not found in $PATH: unknown`)))
+	assert.True(t, isExecutableNotFound(errors.New(`exec: "/.dcv-helper": stat /.dcv-helper: no such file or directory: unknown`)))
+	assert.False(t, isExecutableNotFound(errors.New("kill: can't kill pid 7: No such process")))
+}
+
+func TestResolveContainerPID_InvalidPID(t *testing.T) {
+	container := NewContainer("0000000000000000000000000000000000000000000000000000000000000000", "web", "web", "running")
+	_, err := ResolveContainerPID(container, "1/../self", false)
+	assert.ErrorContains(t, err, `invalid PID "1/../self"`)
+}
+
+func TestHostProcessArgs(t *testing.T) {
+	assert.Equal(t,
+		[]string{"run", "--rm", "--pid=host", "--cgroupns=host", "--network", "none", "dcv-helper:protocol-2", "cat", "/proc/42/cgroup", "/proc/42/status"},
+		hostProcessArgs("dcv-helper:protocol-2", []string{"/proc/42/cgroup", "/proc/42/status"}))
+}
+
+func TestParseProcessTable(t *testing.T) {
+	top := "UID                 PID                 PPID                C                   STIME               TTY                 TIME                CMD\n" +
+		"root                4100                4080                0                   10:00               ?                   00:00:00            nginx: master process\n" +
+		"101                 4150                4100                0                   10:00               ?                   00:00:00            nginx: worker process\n"
+	parents, err := parseProcessTable(top)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{4100: 4080, 4150: 4100}, parents)
+
+	helper := "PID  PPID  USER   STAT  RSS   COMMAND\n1    0     root   S     1024  nginx: master process\n7    1     nginx  S     512   nginx: worker process\n"
+	parents, err = parseProcessTable(helper)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{1: 0, 7: 1}, parents)
+
+	_, err = parseProcessTable("USER COMMAND\n")
+	assert.Error(t, err)
+}
+
+func TestMatchContainerPID(t *testing.T) {
+	// init with two workers, and a shell of docker exec running top
+	hostTree := map[int]int{4100: 4080, 4150: 4100, 4160: 4100, 5200: 5190, 5230: 5200}
+	containerTree := map[int]int{1: 0, 7: 1, 8: 1, 20: 0, 26: 20}
+
+	for host, expected := range map[int]int{4100: 1, 4150: 7, 4160: 8, 5200: 20, 5230: 26} {
+		pid, err := matchContainerPID(hostTree, containerTree, host)
+		require.NoError(t, err)
+		assert.Equal(t, expected, pid, "host PID %d", host)
+	}
+
+	_, err := matchContainerPID(hostTree, containerTree, 9999)
+	assert.ErrorContains(t, err, "not a process of the container anymore")
+
+	// A worker that exited between reading the two trees
+	_, err = matchContainerPID(hostTree, map[int]int{1: 0, 7: 1, 20: 0, 26: 20}, 4160)
+	assert.ErrorContains(t, err, "changed while they were read")
+}
+
+func TestContainerProcessesScript(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("reads /proc")
+	}
+	output, err := exec.Command("sh", "-c", containerProcessesScript).Output()
+	require.NoError(t, err)
+	parents, err := parseProcessTable(string(output))
+	require.NoError(t, err)
+	assert.Equal(t, os.Getppid(), parents[os.Getpid()])
+}
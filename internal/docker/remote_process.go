@@ -0,0 +1,63 @@
+package docker
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RemoteCommand is a command run in a container with docker exec that prints the PID of its process in the
+// container on the first line of its output. Without a TTY, killing docker exec leaves that process running,
+// so long-running commands like tail -F and searches are stopped with Kill.
+type RemoteCommand struct {
+	// Args are the docker arguments that run the command
+	Args      []string
+	container *Container
+	helper    bool
+}
+
+// remoteShellCommand runs a command of the container through sh, which prints its PID and then becomes the command
+func remoteShellCommand(container *Container, command ...string) *RemoteCommand {
+	args := append([]string{"sh", "-c", `echo $$; exec "$@"`, "sh"}, command...)
+	return &RemoteCommand{Args: container.OperationArgs("exec", args...), container: container}
+}
+
+// remoteHelperCommand runs a command of the helper, which prints its PID first when asked with -print-pid
+func remoteHelperCommand(container *Container, command string, args ...string) *RemoteCommand {
+	return &RemoteCommand{
+		Args:      HelperArgs(container, "-print-pid", append([]string{command}, args...)...),
+		container: container,
+		helper:    true,
+	}
+}
+
+// ReadPID reads the PID the command prints before its output
+func (c *RemoteCommand) ReadPID(r *bufio.Reader) (int, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the process ID: %w", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil {
+		return 0, fmt.Errorf("unexpected process ID %q", strings.TrimSpace(line))
+	}
+	return pid, nil
+}
+
+// KillArgs returns the docker arguments that stop the process pid of the command in the container
+func (c *RemoteCommand) KillArgs(pid int) []string {
+	if c.helper {
+		return HelperArgs(c.container, "kill", strconv.Itoa(pid))
+	}
+	// kill is a builtin of every sh, while a kill binary is missing from many images
+	return c.container.OperationArgs("exec", "sh", "-c", `kill "$1"`, "sh", strconv.Itoa(pid))
+}
+
+// Kill stops the process pid of the command in the container
+func (c *RemoteCommand) Kill(pid int) error {
+	if _, err := ExecuteCaptured(c.KillArgs(pid)...); err != nil {
+		return fmt.Errorf("failed to stop process %d in the container: %w", pid, err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package docker
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// temporaryContainerLabels mark the containers dcv creates to browse images and volumes
+var temporaryContainerLabels = []string{imageBrowserLabel, volumeBrowserLabel}
+
+// ownerLabel records which dcv run created a temporary container, as "session/host/pid/started"
+const ownerLabel = "dcv.owner"
+
+// browserLifetime bounds how long a temporary container lives. The volume browser exits after it, and leftovers
+// older than it are removed on startup even when their owner cannot be checked.
+const browserLifetime = time.Hour
+
+// containerOwner identifies a running dcv
+type containerOwner struct {
+	session string
+	host    string
+	pid     int
+	started time.Time
+}
+
+// currentOwner is this dcv, which labels every container it creates
+var currentOwner = newContainerOwner()
+
+func newContainerOwner() containerOwner {
+	session := make([]byte, 8)
+	_, _ = rand.Read(session)
+	host, _ := os.Hostname()
+	return containerOwner{
+		session: hex.EncodeToString(session),
+		host:    host,
+		pid:     os.Getpid(),
+		started: time.Now(),
+	}
+}
+
+func (o containerOwner) String() string {
+	return fmt.Sprintf("%s/%s/%d/%d", o.session, o.host, o.pid, o.started.Unix())
+}
+
+func parseContainerOwner(value string) (containerOwner, bool) {
+	fields := strings.Split(value, "/")
+	if len(fields) != 4 {
+		return containerOwner{}, false
+	}
+	pid, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return containerOwner{}, false
+	}
+	started, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return containerOwner{}, false
+	}
+	return containerOwner{session: fields[0], host: fields[1], pid: pid, started: time.Unix(started, 0)}, true
+}
+
+// ownerArgs are the docker create/run arguments that label a temporary container with its owner
+func ownerArgs() []string {
+	return []string{"--label", ownerLabel + "=" + currentOwner.String()}
+}
+
+// gone reports whether the owner has exited. Owners on other hosts cannot be checked and count as running.
+func (o containerOwner) gone() bool {
+	if o.host != currentOwner.host {
+		return false
+	}
+	if !processRunning(o.pid) {
+		return true
+	}
+	// A process that started after the owner reuses its PID
+	if started, ok := processStartTime(o.pid); ok && started.After(o.started.Add(2*time.Second)) {
+		return true
+	}
+	return false
+}
+
+// temporaryContainer is a container listed by RemoveTemporaryContainers
+type temporaryContainer struct {
+	id      string
+	owner   string
+	created time.Time
+}
+
+// abandoned reports whether the container can be removed: its owner has exited, or it is older than
+// browserLifetime. Containers of this dcv are never abandoned.
+func (c temporaryContainer) abandoned(now time.Time) bool {
+	owner, ok := parseContainerOwner(c.owner)
+	if ok && owner.session == currentOwner.session {
+		return false
+	}
+	if ok && owner.gone() {
+		return true
+	}
+	return !c.created.IsZero() && now.Sub(c.created) > browserLifetime
+}
+
+// parseTemporaryContainers parses the `ps --format` output of listTemporaryContainers
+func parseTemporaryContainers(output []byte) []temporaryContainer {
+	var containers []temporaryContainer
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+		// An unparsable time leaves the container to its owner
+		created, _ := time.Parse("2006-01-02 15:04:05 -0700 MST", fields[2])
+		containers = append(containers, temporaryContainer{id: fields[0], owner: fields[1], created: created})
+	}
+	return containers
+}
+
+// RemoveTemporaryContainers removes the containers created to browse images and volumes that a previous dcv
+// left behind, for example when it was killed while browsing. Containers of other running dcv instances are kept.
+func RemoveTemporaryContainers() error {
+	var ids []string
+	now := time.Now()
+	// Label filters are combined with AND, so each label is listed on its own
+	for _, label := range temporaryContainerLabels {
+		output, err := ExecuteCaptured("ps", "-a", "--filter", "label="+label,
+			"--format", fmt.Sprintf("{{.ID}}\t{{.Label %q}}\t{{.CreatedAt}}", ownerLabel))
+		if err != nil {
+			return fmt.Errorf("failed to list the %s containers: %w", label, err)
+		}
+		for _, container := range parseTemporaryContainers(output) {
+			if container.abandoned(now) {
+				ids = append(ids, container.id)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	slog.Info("Removing leftover temporary containers", slog.Any("ids", ids))
+	if _, err := ExecuteCaptured(append([]string{"rm", "-f"}, ids...)...); err != nil {
+		return fmt.Errorf("failed to remove the leftover temporary containers: %w", err)
+	}
+	return nil
+}
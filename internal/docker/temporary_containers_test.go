@@ -0,0 +1,65 @@
+package docker
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerOwner(t *testing.T) {
+	owner, ok := parseContainerOwner(currentOwner.String())
+	require.True(t, ok)
+	assert.Equal(t, currentOwner.session, owner.session)
+	assert.Equal(t, currentOwner.pid, owner.pid)
+	assert.False(t, owner.gone())
+
+	_, ok = parseContainerOwner("")
+	assert.False(t, ok)
+}
+
+func TestTemporaryContainerAbandoned(t *testing.T) {
+	now := time.Now()
+	recent := now.Add(-time.Minute)
+	old := now.Add(-2 * browserLifetime)
+	other := containerOwner{session: "other", host: currentOwner.host, pid: currentOwner.pid, started: currentOwner.started}
+	remote := containerOwner{session: "other", host: currentOwner.host + "-remote", pid: 1, started: now}
+	reused := containerOwner{session: "other", host: currentOwner.host, pid: currentOwner.pid, started: time.Unix(1, 0)}
+
+	tests := []struct {
+		name      string
+		container temporaryContainer
+		abandoned bool
+	}{
+		{"own container", temporaryContainer{owner: currentOwner.String(), created: old}, false},
+		{"other running dcv", temporaryContainer{owner: other.String(), created: recent}, false},
+		{"other running dcv past the lifetime", temporaryContainer{owner: other.String(), created: old}, true},
+		{"owner on another host", temporaryContainer{owner: remote.String(), created: recent}, false},
+		{"owner whose PID was reused", temporaryContainer{owner: reused.String(), created: recent}, processStartKnown()},
+		{"no owner", temporaryContainer{created: recent}, false},
+		{"no owner past the lifetime", temporaryContainer{created: old}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.abandoned, tt.container.abandoned(now))
+		})
+	}
+}
+
+// processStartKnown reports whether processStartTime works on this system
+func processStartKnown() bool {
+	_, ok := processStartTime(currentOwner.pid)
+	return ok
+}
+
+func TestParseTemporaryContainers(t *testing.T) {
+	output := fmt.Sprintf("abc\t%s\t2024-05-01 10:20:30 +0900 JST\ndef\t\tbad time\n", currentOwner)
+	containers := parseTemporaryContainers([]byte(output))
+	require.Len(t, containers, 2)
+	assert.Equal(t, "abc", containers[0].id)
+	assert.Equal(t, currentOwner.String(), containers[0].owner)
+	assert.Equal(t, time.Date(2024, 5, 1, 1, 20, 30, 0, time.UTC), containers[0].created.UTC())
+	assert.True(t, containers[1].created.IsZero())
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package docker
+
+import (
+	"os"
+	"time"
+)
+
+// processRunning reports whether a process with the PID exists. FindProcess fails for missing processes on Windows.
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = process.Release()
+	return true
+}
+
+// processStartTime is not available on Windows, so owners are only checked by PID
+func processStartTime(int) (time.Time, bool) {
+	return time.Time{}, false
+}
@@ -0,0 +1,112 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// VolumeBrowserMountPath is where a browsed volume is mounted in the browser container
+const VolumeBrowserMountPath = "/volume"
+
+// volumeBrowserLabel marks the containers started to browse a volume
+const volumeBrowserLabel = "dcv.volume-browser"
+
+// helperImage is the image that contains nothing but the helper. It runs the volume browser and reads the
+// processes of the Docker host.
+// It is imported from the embedded binary, so nothing is pulled from a registry.
+func helperImage() string {
+	return fmt.Sprintf("dcv-helper:protocol-%d", HelperProtocolVersion)
+}
+
+// StartVolumeBrowser starts a short-lived container with the volume mounted at VolumeBrowserMountPath,
+// read-only unless readWrite is set. The container only runs the helper; remove it with StopVolumeBrowser.
+func StartVolumeBrowser(volume string, readWrite bool) (*Container, error) {
+	image, err := ensureHelperImage()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := ExecuteCaptured(volumeBrowserRunArgs(image, volume, readWrite)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start a container for volume %s: %w", volume, err)
+	}
+	id := lastLine(output)
+	name := "volume " + volume
+	return NewContainer(id, name, name, "running"), nil
+}
+
+// StopVolumeBrowser removes a container started by StartVolumeBrowser
+func StopVolumeBrowser(container *Container) error {
+	if _, err := ExecuteCaptured("rm", "-f", container.ContainerID()); err != nil {
+		return fmt.Errorf("failed to remove the volume browser container: %w", err)
+	}
+	return nil
+}
+
+func volumeBrowserRunArgs(image, volume string, readWrite bool) []string {
+	mount := fmt.Sprintf("type=volume,source=%s,target=%s", volume, VolumeBrowserMountPath)
+	if !readWrite {
+		mount += ",readonly"
+	}
+	args := []string{"run", "-d", "--rm", "--label", volumeBrowserLabel + "=" + volume}
+	args = append(args, ownerArgs()...)
+	return append(args,
+		"--network", "none",
+		"--mount", mount,
+		image, "sleep", strconv.Itoa(int(browserLifetime.Seconds())),
+	)
+}
+
+// ensureHelperImage imports the helper image unless the daemon already has it
+func ensureHelperImage() (string, error) {
+	image := helperImage()
+	if _, err := ExecuteCaptured("image", "inspect", "--format", "{{.Id}}", image); err == nil {
+		return image, nil
+	}
+
+	// The daemon may run on another architecture than dcv
+	arch := ""
+	if output, err := ExecuteCaptured("version", "--format", "{{.Server.Arch}}"); err == nil {
+		arch = strings.TrimSpace(string(output))
+	}
+	binary, err := GetHelperBinary(arch)
+	if err != nil {
+		return "", err
+	}
+	archive, err := helperImageArchive(binary)
+	if err != nil {
+		return "", fmt.Errorf("failed to create the helper image: %w", err)
+	}
+
+	slog.Info("Importing the helper image", slog.String("image", image), slog.String("arch", arch))
+	if _, err := ExecuteCapturedWithInput(archive, "import", "--change", fmt.Sprintf(`ENTRYPOINT ["%s"]`, GetHelperPath()), "-", image); err != nil {
+		return "", fmt.Errorf("failed to import the helper image: %w", err)
+	}
+	return image, nil
+}
+
+// helperImageArchive returns a root filesystem holding only the helper binary
+func helperImageArchive(binary []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     strings.TrimPrefix(GetHelperPath(), "/"),
+		Size:     int64(len(binary)),
+		Mode:     0755,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(binary); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
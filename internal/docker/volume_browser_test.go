@@ -0,0 +1,42 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumeBrowserRunArgs(t *testing.T) {
+	assert.Equal(t, []string{
+		"run", "-d", "--rm",
+		"--label", "dcv.volume-browser=data",
+		"--label", "dcv.owner=" + currentOwner.String(),
+		"--network", "none",
+		"--mount", "type=volume,source=data,target=/volume,readonly",
+		"dcv-helper:protocol-2", "sleep", "3600",
+	}, volumeBrowserRunArgs("dcv-helper:protocol-2", "data", false))
+
+	args := volumeBrowserRunArgs("dcv-helper:protocol-2", "data", true)
+	assert.Contains(t, args, "type=volume,source=data,target=/volume")
+}
+
+func TestHelperImageArchive(t *testing.T) {
+	archive, err := helperImageArchive([]byte("binary"))
+	require.NoError(t, err)
+
+	tr := tar.NewReader(bytes.NewReader(archive))
+	header, err := tr.Next()
+	require.NoError(t, err)
+	assert.Equal(t, ".dcv-helper", header.Name)
+	assert.Equal(t, int64(0755), header.Mode)
+	data, err := io.ReadAll(tr)
+	require.NoError(t, err)
+	assert.Equal(t, "binary", string(data))
+
+	_, err = tr.Next()
+	assert.Equal(t, io.EOF, err)
+}
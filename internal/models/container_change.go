@@ -0,0 +1,18 @@
+package models
+
+// ChangeKind is the kind of a filesystem change reported by docker diff
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "A"
+	ChangeChanged ChangeKind = "C"
+	ChangeDeleted ChangeKind = "D"
+)
+
+// ContainerChange is a path that a container changed relative to its image
+type ContainerChange struct {
+	Kind ChangeKind
+	Path string
+	// Size is the size of the file written, when known; docker diff does not tell it
+	Size int64
+}
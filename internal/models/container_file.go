@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
@@ -18,9 +19,12 @@ type ContainerFile struct {
 	Owner       string
 	Group       string
 	Links       string // Number of hard links
+	Inode       uint64 // 0 when unknown
 }
 
-// ParseLsOutput parses the output of ls -la command
+// ParseLsOutput parses the output of ls -la command. The modification time is read from
+// `ls -la --time-style=+%s`, which prints it as seconds since the epoch, or else from the
+// month, day and time or year of the default format.
 func ParseLsOutput(output string) []ContainerFile {
 	files := []ContainerFile{}
 	lines := strings.Split(strings.TrimSpace(output), "\n")
@@ -32,9 +36,9 @@ func ParseLsOutput(output string) []ContainerFile {
 
 		// Parse ls -la output format:
 		// drwxr-xr-x  2 root root 4096 Dec 15 10:30 dirname
-		// -rw-r--r--  1 root root  123 Dec 15 10:30 filename
+		// -rw-r--r--  1 root root  123 1734258600 filename
 		parts := strings.Fields(line)
-		if len(parts) < 9 {
+		if len(parts) < 7 {
 			continue
 		}
 
@@ -47,20 +51,32 @@ func ParseLsOutput(output string) []ContainerFile {
 			Group:       parts[3], // Group name
 		}
 
+		// Device files show "major, minor" in place of the size
+		if strings.HasSuffix(parts[4], ",") {
+			parts = append(parts[:4], parts[5:]...)
+			parts[4] = "0"
+		}
+
 		// Parse size (parts[4] contains the size in bytes)
 		if size, err := strconv.ParseInt(parts[4], 10, 64); err == nil {
 			file.Size = size
 		}
 
-		// Parse date and time (parts[5], [6], [7])
-		// Format can be: "Dec 15 10:30" or "Dec 15 2023" depending on age
-		// For now, we'll keep it simple and use current time
-		// TODO: Parse actual date/time from ls output
-		file.ModTime = time.Now()
+		// Parse the time: an epoch in parts[5], or "Dec 15 10:30" or "Dec 15 2023" in parts[5:8]
+		nameIndex := 6
+		if epoch, err := strconv.ParseInt(parts[5], 10, 64); err == nil {
+			file.ModTime = time.Unix(epoch, 0)
+		} else {
+			if len(parts) < 9 {
+				continue
+			}
+			file.ModTime = parseLsTime(parts[5], parts[6], parts[7], time.Now())
+			nameIndex = 8
+		}
 
 		// Get filename (handle spaces in filename)
-		// Everything from parts[8] onwards is the filename
-		file.Name = strings.Join(parts[8:], " ")
+		// Everything from the name index onwards is the filename
+		file.Name = strings.Join(parts[nameIndex:], " ")
 
 		// Handle symlinks (file -> target)
 		if strings.Contains(file.Name, " -> ") {
@@ -77,6 +93,30 @@ func ParseLsOutput(output string) []ContainerFile {
 	return files
 }
 
+// parseLsTime parses the modification time of ls -l, which shows the time of files from the last
+// six months and the year of older ones. It returns the zero time when the fields cannot be parsed.
+// It is the fallback for an ls without --time-style, like the one of BusyBox: the time is taken as UTC,
+// the zone containers run in unless TZ is set, and is off by the offset of containers that set TZ.
+func parseLsTime(month, day, timeOrYear string, now time.Time) time.Time {
+	now = now.UTC()
+	if strings.Contains(timeOrYear, ":") {
+		t, err := time.ParseInLocation("Jan 2 15:04 2006", fmt.Sprintf("%s %s %s %d", month, day, timeOrYear, now.Year()), time.UTC)
+		if err != nil {
+			return time.Time{}
+		}
+		// A time without a year is within the last six months, so a date ahead is from last year
+		if t.After(now.AddDate(0, 0, 1)) {
+			t = t.AddDate(-1, 0, 0)
+		}
+		return t
+	}
+	t, err := time.ParseInLocation("Jan 2 2006", fmt.Sprintf("%s %s %s", month, day, timeOrYear), time.UTC)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
 // GetDisplayName returns the display name with indicators
 func (f ContainerFile) GetDisplayName() string {
 	name := f.Name
@@ -93,8 +133,11 @@ func (f ContainerFile) GetSizeString() string {
 	if f.IsDir {
 		return "-"
 	}
+	return FormatSize(f.Size)
+}
 
-	size := f.Size
+// FormatSize returns a size in bytes as a human-readable string like ls -h
+func FormatSize(size int64) string {
 	if size < 1024 {
 		return strconv.FormatInt(size, 10)
 	}
@@ -108,3 +151,29 @@ func (f ContainerFile) GetSizeString() string {
 	}
 	return strconv.FormatFloat(float64(size)/float64(unit*unit*unit), 'f', 1, 64) + "G"
 }
+
+// GetOctalMode returns the permissions as an octal mode such as "755", or "" when they are unknown
+func (f ContainerFile) GetOctalMode() string {
+	perm := f.Permissions
+	if len(perm) != 10 {
+		return ""
+	}
+
+	var mode, special uint32
+	for i, c := range perm[1:] {
+		bit := uint32(1) << (8 - i)
+		switch c {
+		case 'r', 'w', 'x':
+			mode |= bit
+		case 's', 't':
+			mode |= bit
+			special |= 4 >> (i / 3)
+		case 'S', 'T':
+			special |= 4 >> (i / 3)
+		case '-':
+		default:
+			return ""
+		}
+	}
+	return strconv.FormatUint(uint64(special<<9|mode), 8)
+}
@@ -2,8 +2,10 @@ package models
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseLsOutput(t *testing.T) {
@@ -161,13 +163,34 @@ drwxr-xr-x  2 root root 4096 Dec 15 10:20 my folder`,
 					assert.Equal(t, tt.expected[i].Owner, result[i].Owner)
 					assert.Equal(t, tt.expected[i].Group, result[i].Group)
 					assert.Equal(t, tt.expected[i].Links, result[i].Links)
-					// ModTime is set to current time, so we don't compare it
+					// ModTime depends on the current year, see TestParseLsTime
 				}
 			}
 		})
 	}
 }
 
+func TestParseLsOutput_EpochTime(t *testing.T) {
+	// ls -la --time-style=+%s prints the modification time as seconds since the epoch
+	files := ParseLsOutput(`total 8
+-rw-r--r--  1 root root 1024 1734258600 config file.json
+lrwxrwxrwx  1 root root    7 1734258600 current -> app.yaml
+crw-rw-rw-  1 root root 1,   3 1734258600 null`)
+	require.Len(t, files, 3)
+	assert.Equal(t, "config file.json", files[0].Name)
+	assert.Equal(t, int64(1024), files[0].Size)
+	assert.True(t, files[0].ModTime.Equal(time.Date(2024, 12, 15, 10, 30, 0, 0, time.UTC)))
+	assert.Equal(t, "current", files[1].Name)
+	assert.Equal(t, "app.yaml", files[1].LinkTarget)
+	assert.Equal(t, "null", files[2].Name)
+	assert.Equal(t, int64(0), files[2].Size)
+
+	// Device files in the default format
+	files = ParseLsOutput("crw-rw-rw-  1 root root 1,   3 Dec 15 10:30 null")
+	require.Len(t, files, 1)
+	assert.Equal(t, "null", files[0].Name)
+}
+
 func TestGetSizeString(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -288,3 +311,38 @@ func TestGetDisplayName(t *testing.T) {
 		})
 	}
 }
+
+func TestGetOctalMode(t *testing.T) {
+	tests := []struct {
+		permissions string
+		expected    string
+	}{
+		{"-rw-r--r--", "644"},
+		{"drwxr-xr-x", "755"},
+		{"-rwsr-xr-x", "4755"},
+		{"drwxrwsr-x", "2775"},
+		{"drwxrwxrwt", "1777"},
+		{"-rwSr--r--", "4644"},
+		{"", ""},
+		{"-rw-r--r--+", ""},
+		{"-rw-q--r--", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.permissions, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ContainerFile{Permissions: tt.permissions}.GetOctalMode())
+		})
+	}
+}
+
+func TestParseLsTime(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	// Containers print UTC, whatever the zone of the host
+	assert.Equal(t, time.Date(2025, 3, 9, 10, 30, 0, 0, time.UTC), parseLsTime("Mar", "9", "10:30", now))
+	assert.Equal(t, time.Date(2025, 3, 9, 10, 30, 0, 0, time.UTC), parseLsTime("Mar", "9", "10:30", now.In(time.FixedZone("JST", 9*3600))))
+	// Recent files are shown without a year, so December is from last year in March
+	assert.Equal(t, time.Date(2024, 12, 15, 10, 30, 0, 0, time.UTC), parseLsTime("Dec", "15", "10:30", now))
+	assert.Equal(t, time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC), parseLsTime("Dec", "15", "2023", now))
+	assert.True(t, parseLsTime("15", "Dec", "2023", now).IsZero())
+}
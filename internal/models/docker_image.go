@@ -1,5 +1,11 @@
 package models
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // DockerImage represents an image from `docker images --format json`
 type DockerImage struct {
 	Containers   string `json:"Containers"`
@@ -25,3 +31,40 @@ func (i DockerImage) GetRepoTag() string {
 	}
 	return i.Repository + ":" + i.Tag
 }
+
+// dockerSizeUnits are the decimal units the docker CLI prints sizes with
+var dockerSizeUnits = []string{"B", "kB", "MB", "GB", "TB", "PB"}
+
+// ParseHumanSize parses a size printed by the docker CLI, like "77.9MB" or "0B".
+// It returns 0 for sizes it cannot parse, like "N/A".
+func ParseHumanSize(size string) int64 {
+	size = strings.TrimSpace(size)
+	end := strings.IndexFunc(size, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if end <= 0 {
+		return 0
+	}
+	value, err := strconv.ParseFloat(size[:end], 64)
+	if err != nil {
+		return 0
+	}
+	unit := strings.TrimSpace(size[end:])
+	multiplier := 1.0
+	for _, u := range dockerSizeUnits {
+		if strings.EqualFold(unit, u) {
+			return int64(value * multiplier)
+		}
+		multiplier *= 1000
+	}
+	return 0
+}
+
+// FormatHumanSize prints a size in bytes with decimal units, like the docker CLI does
+func FormatHumanSize(size int64) string {
+	value := float64(size)
+	unit := 0
+	for value >= 1000 && unit < len(dockerSizeUnits)-1 {
+		value /= 1000
+		unit++
+	}
+	return fmt.Sprintf("%.4g%s", value, dockerSizeUnits[unit])
+}
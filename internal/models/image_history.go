@@ -0,0 +1,45 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ImageHistoryEntry is a step of an image build from `docker history --format json`.
+// Every step has an entry, also those that only changed the image configuration and created no layer.
+type ImageHistoryEntry struct {
+	ID           string `json:"ID"`
+	CreatedAt    string `json:"CreatedAt"`
+	CreatedSince string `json:"CreatedSince"`
+	CreatedBy    string `json:"CreatedBy"`
+	Size         string `json:"Size"`
+	Comment      string `json:"Comment"`
+}
+
+// SizeBytes returns the size of the layer the step created
+func (h ImageHistoryEntry) SizeBytes() int64 {
+	return ParseHumanSize(h.Size)
+}
+
+var (
+	// buildArgsPrefix is how the classic builder records the build arguments of a RUN, like "|2 A=1 B=2 "
+	buildArgsPrefix = regexp.MustCompile(`^\|\d+ (\S+=\S* )*`)
+	whitespaceRun   = regexp.MustCompile(`\s+`)
+)
+
+// Instruction returns the Dockerfile instruction of the step on a single line,
+// like "RUN apt-get update" for "/bin/sh -c apt-get update"
+func (h ImageHistoryEntry) Instruction() string {
+	s := strings.TrimSpace(h.CreatedBy)
+	s = strings.TrimSuffix(s, "# buildkit")
+	s = buildArgsPrefix.ReplaceAllString(s, "")
+	switch {
+	case strings.HasPrefix(s, "/bin/sh -c #(nop) "):
+		s = strings.TrimPrefix(s, "/bin/sh -c #(nop) ")
+	case strings.HasPrefix(s, "/bin/sh -c "):
+		s = "RUN " + strings.TrimPrefix(s, "/bin/sh -c ")
+	case strings.HasPrefix(s, "RUN /bin/sh -c "):
+		s = "RUN " + strings.TrimPrefix(s, "RUN /bin/sh -c ")
+	}
+	return whitespaceRun.ReplaceAllString(strings.TrimSpace(s), " ")
+}
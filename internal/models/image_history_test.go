@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageHistoryEntry_Instruction(t *testing.T) {
+	tests := []struct {
+		createdBy string
+		want      string
+	}{
+		{`/bin/sh -c #(nop)  CMD ["nginx" "-g" "daemon off;"]`, `CMD ["nginx" "-g" "daemon off;"]`},
+		{"/bin/sh -c apt-get update \t&& apt-get install -y curl", "RUN apt-get update && apt-get install -y curl"},
+		{"|2 VERSION=1.2 TARGET= /bin/sh -c make install", "RUN make install"},
+		{"RUN /bin/sh -c npm ci # buildkit", "RUN npm ci"},
+		{"COPY . /app # buildkit", "COPY . /app"},
+		{"/bin/sh -c #(nop) ADD file:0b1f0a in / ", "ADD file:0b1f0a in /"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ImageHistoryEntry{CreatedBy: tt.createdBy}.Instruction(), tt.createdBy)
+	}
+}
+
+func TestParseHumanSize(t *testing.T) {
+	assert.Equal(t, int64(0), ParseHumanSize("0B"))
+	assert.Equal(t, int64(77_900_000), ParseHumanSize("77.9MB"))
+	assert.Equal(t, int64(1_230), ParseHumanSize("1.23kB"))
+	assert.Equal(t, int64(2_000_000_000), ParseHumanSize("2GB"))
+	assert.Equal(t, int64(0), ParseHumanSize("N/A"))
+
+	assert.Equal(t, "0B", FormatHumanSize(0))
+	assert.Equal(t, "999B", FormatHumanSize(999))
+	assert.Equal(t, "77.9MB", FormatHumanSize(77_900_000))
+	assert.Equal(t, "1.235GB", FormatHumanSize(1_234_567_890))
+}
@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+// changeNode is a path in the tree of filesystem changes of a container
+type changeNode struct {
+	name string
+	path string
+	// kind is empty for directories that docker diff did not list themselves
+	kind     models.ChangeKind
+	children []*changeNode
+
+	// Changes below this node, not counting the node itself
+	added   int
+	changed int
+	deleted int
+	// size is the size of a file, or the total size of the files below a directory, when the changes tell it
+	size int64
+}
+
+// counts renders the numbers of changes below the node, e.g. "+3 ~1 -2"
+func (n *changeNode) counts() string {
+	var parts []string
+	if n.added > 0 {
+		parts = append(parts, fmt.Sprintf("+%d", n.added))
+	}
+	if n.changed > 0 {
+		parts = append(parts, fmt.Sprintf("~%d", n.changed))
+	}
+	if n.deleted > 0 {
+		parts = append(parts, fmt.Sprintf("-%d", n.deleted))
+	}
+	return strings.Join(parts, " ")
+}
+
+// changeRow is a single visible row of the change tree
+type changeRow struct {
+	node      *changeNode
+	prefix    string
+	collapsed bool
+}
+
+// buildChangeTree arranges the changes by directory below a root node for "/".
+// Every node counts the changes of its descendants.
+func buildChangeTree(changes []models.ContainerChange) *changeNode {
+	root := &changeNode{name: "/", path: "/"}
+	nodes := map[string]*changeNode{"/": root}
+
+	var ensure func(path string) *changeNode
+	ensure = func(path string) *changeNode {
+		if node, ok := nodes[path]; ok {
+			return node
+		}
+		i := strings.LastIndex(path, "/")
+		parentPath := path[:i]
+		if parentPath == "" {
+			parentPath = "/"
+		}
+		parent := ensure(parentPath)
+		node := &changeNode{name: path[i+1:], path: path}
+		parent.children = append(parent.children, node)
+		nodes[path] = node
+		return node
+	}
+
+	for _, change := range changes {
+		path := strings.TrimSuffix(change.Path, "/")
+		if !strings.HasPrefix(path, "/") {
+			continue
+		}
+		node := ensure(path)
+		node.kind = change.Kind
+		node.size += change.Size
+
+		for parent := parentChangePath(path); ; parent = parentChangePath(parent) {
+			ancestor := nodes[parent]
+			switch change.Kind {
+			case models.ChangeAdded:
+				ancestor.added++
+			case models.ChangeChanged:
+				ancestor.changed++
+			case models.ChangeDeleted:
+				ancestor.deleted++
+			}
+			ancestor.size += change.Size
+			if parent == "/" {
+				break
+			}
+		}
+	}
+
+	var sortChildren func(n *changeNode)
+	sortChildren = func(n *changeNode) {
+		sort.Slice(n.children, func(i, j int) bool {
+			return n.children[i].name < n.children[j].name
+		})
+		for _, c := range n.children {
+			sortChildren(c)
+		}
+	}
+	sortChildren(root)
+	return root
+}
+
+// sortChangeTreeBySize orders the children of every node largest first, to find what makes a layer big
+func sortChangeTreeBySize(n *changeNode) {
+	sort.SliceStable(n.children, func(i, j int) bool {
+		return n.children[i].size > n.children[j].size
+	})
+	for _, c := range n.children {
+		sortChangeTreeBySize(c)
+	}
+}
+
+func parentChangePath(path string) string {
+	i := strings.LastIndex(path, "/")
+	if i <= 0 {
+		return "/"
+	}
+	return path[:i]
+}
+
+// flattenChangeTree returns the visible rows below root in display order.
+// Only directories in expanded show their children.
+func flattenChangeTree(root *changeNode, expanded map[string]bool) []changeRow {
+	var rows []changeRow
+	var walk func(n *changeNode, indent string)
+	walk = func(n *changeNode, indent string) {
+		for i, c := range n.children {
+			last := i == len(n.children)-1
+			prefix, childIndent := indent+"├─ ", indent+"│  "
+			if last {
+				prefix, childIndent = indent+"└─ ", indent+"   "
+			}
+			isCollapsed := len(c.children) > 0 && !expanded[c.path]
+			rows = append(rows, changeRow{node: c, prefix: prefix, collapsed: isCollapsed})
+			if len(c.children) > 0 && !isCollapsed {
+				walk(c, childIndent)
+			}
+		}
+	}
+	walk(root, "")
+	return rows
+}
@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"charm.land/bubbles/v2/table"
+	"charm.land/lipgloss/v2"
+
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+// changeTreeView is a table of a change tree whose directories expand and collapse.
+// The filesystem changes view and the image layer view embed it.
+type changeTreeView struct {
+	TableViewModel
+	root     *changeNode
+	expanded map[string]bool
+	rows     []changeRow
+	// showSize adds the SIZE column
+	showSize bool
+}
+
+// setTree shows a new tree. The expanded directories are kept, and so is the selected path when it is still visible.
+func (m *changeTreeView) setTree(model *Model, root *changeNode) {
+	selected := m.selectedPath()
+	m.root = root
+	if m.expanded == nil {
+		m.expanded = make(map[string]bool)
+	}
+	m.rebuild(model, selected)
+}
+
+// resetTree clears the tree, the expanded directories and the cursor
+func (m *changeTreeView) resetTree() {
+	m.root = nil
+	m.rows = nil
+	m.expanded = make(map[string]bool)
+	m.Cursor = 0
+	m.SetRows(nil, 0)
+}
+
+func (m *changeTreeView) selectedPath() string {
+	if m.Cursor < len(m.rows) {
+		return m.rows[m.Cursor].node.path
+	}
+	return ""
+}
+
+func (m *changeTreeView) selectedRow() *changeRow {
+	if m.Cursor < 0 || m.Cursor >= len(m.rows) {
+		return nil
+	}
+	return &m.rows[m.Cursor]
+}
+
+// rebuild flattens the tree and moves the cursor to the row of path when it is visible
+func (m *changeTreeView) rebuild(model *Model, path string) {
+	if m.root == nil {
+		return
+	}
+	m.rows = flattenChangeTree(m.root, m.expanded)
+	for i, row := range m.rows {
+		if row.node.path == path {
+			m.Cursor = i
+			break
+		}
+	}
+	m.SetRows(m.buildRows(), model.ViewHeight())
+}
+
+func (m *changeTreeView) buildRows() []table.Row {
+	rows := make([]table.Row, 0, len(m.rows))
+	for _, row := range m.rows {
+		node := row.node
+		marker := "  "
+		name := node.name
+		if len(node.children) > 0 {
+			name += "/"
+			if row.collapsed {
+				marker = "▸ "
+			} else {
+				marker = "▾ "
+			}
+		}
+		cells := table.Row{renderChangeKind(node.kind), row.prefix + marker + name}
+		if m.showSize {
+			size := "-"
+			if node.size > 0 {
+				size = models.FormatHumanSize(node.size)
+			}
+			cells = append(cells, size)
+		}
+		rows = append(rows, append(cells, node.counts()))
+	}
+	return rows
+}
+
+// renderTree renders the table, or empty when the tree has no changes
+func (m *changeTreeView) renderTree(model *Model, availableHeight int, empty string) string {
+	if m.root == nil {
+		return ""
+	}
+	if len(m.rows) == 0 {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(empty)
+	}
+
+	columns := []table.Column{
+		{Title: "", Width: 1},
+		{Title: "PATH", Width: -1},
+	}
+	if m.showSize {
+		columns = append(columns, table.Column{Title: "SIZE", Width: 9})
+	}
+	columns = append(columns, table.Column{Title: "CHANGES BELOW", Width: 16})
+	return m.RenderTable(model, columns, availableHeight, func(row, col int) lipgloss.Style {
+		if row == m.Cursor {
+			return tableSelectedCellStyle
+		}
+		return tableNormalCellStyle
+	})
+}
+
+// toggleSelected expands or collapses the selected directory. It reports false when a file is selected.
+func (m *changeTreeView) toggleSelected(model *Model) bool {
+	row := m.selectedRow()
+	if row == nil || len(row.node.children) == 0 {
+		return false
+	}
+	m.setExpanded(model, row.node.path, row.collapsed)
+	return true
+}
+
+// HandleCollapse collapses the selected directory, or selects its parent
+func (m *changeTreeView) HandleCollapse(model *Model) {
+	row := m.selectedRow()
+	if row == nil {
+		return
+	}
+	if len(row.node.children) > 0 && !row.collapsed {
+		m.setExpanded(model, row.node.path, false)
+		return
+	}
+	parent := parentChangePath(row.node.path)
+	for i, r := range m.rows {
+		if r.node.path == parent {
+			m.Cursor = i
+			m.SetRows(m.Rows, model.ViewHeight())
+			return
+		}
+	}
+}
+
+// HandleExpand expands the selected directory
+func (m *changeTreeView) HandleExpand(model *Model) {
+	row := m.selectedRow()
+	if row == nil || !row.collapsed {
+		return
+	}
+	m.setExpanded(model, row.node.path, true)
+}
+
+func (m *changeTreeView) setExpanded(model *Model, path string, expanded bool) {
+	if expanded {
+		m.expanded[path] = true
+	} else {
+		delete(m.expanded, path)
+	}
+	m.rebuild(model, path)
+}
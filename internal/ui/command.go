@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
@@ -150,12 +151,16 @@ func (m *CommandViewModel) executeCommand(model *Model) (tea.Model, tea.Cmd) {
 
 	case "q!", "quit!":
 		// Force quit without confirmation
-		return model, tea.Quit
+		return model, model.quit()
 
 	case "h", "help":
 		return model, model.helpViewModel.Show(model, model.currentView)
 
 	default:
+		// ":123" goes to a line of the shown file, like in vi
+		if line, err := strconv.Atoi(parts[0]); err == nil && model.currentView == FileContentView {
+			return model, model.fileContentViewModel.HandleGoToLine(model, line)
+		}
 		// Try to execute as a key handler command
 		return m.executeKeyHandlerCommand(model, parts[0])
 	}
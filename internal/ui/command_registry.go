@@ -42,6 +42,7 @@ func (m *Model) registerCommands() {
 		{m.fileContentHandlers, FileContentView},
 		{m.inspectViewHandlers, InspectView},
 		{m.helpViewHandlers, HelpView},
+		{m.helperListHandlers, HelperListView},
 	}
 
 	for _, viewHandlers := range allHandlers {
@@ -0,0 +1,264 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/viewport"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// diffContextLines is how many unchanged lines surround each change in a diff
+const diffContextLines = 3
+
+var (
+	diffAddedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	diffRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	diffHunkStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	diffHeaderStyle  = lipgloss.NewStyle().Bold(true)
+)
+
+// unifiedDiff returns the lines of a unified diff between two texts, or nil when they are equal
+func unifiedDiff(fromName, toName string, from, to []byte) []string {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffSplitLines(from),
+		B:        diffSplitLines(to),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  diffContextLines,
+	})
+	if err != nil || diff == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+}
+
+// diffSplitLines splits a text into lines that all end with a newline, as difflib expects
+func diffSplitLines(text []byte) []string {
+	lines := strings.SplitAfter(string(text), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		lines[len(lines)-1] += "\n"
+	}
+	return lines
+}
+
+// diffStats counts the added and removed lines of a unified diff
+func diffStats(lines []string) (added, removed int) {
+	inHunk := false
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case !inHunk:
+			// File headers
+		case strings.HasPrefix(line, "+"):
+			added++
+		case strings.HasPrefix(line, "-"):
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// renderDiffLine colors a line of a unified diff
+func renderDiffLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		return diffHeaderStyle.Render(line)
+	case strings.HasPrefix(line, "+"):
+		return diffAddedStyle.Render(line)
+	case strings.HasPrefix(line, "-"):
+		return diffRemovedStyle.Render(line)
+	case strings.HasPrefix(line, "@@"):
+		return diffHunkStyle.Render(line)
+	default:
+		return line
+	}
+}
+
+// contentDiffLines returns the diff of two file contents. Binary files are only compared by size.
+func contentDiffLines(fromName, toName string, from, to []byte) []string {
+	if isBinaryContent(from) || isBinaryContent(to) {
+		if bytes.Equal(from, to) {
+			return nil
+		}
+		return []string{fmt.Sprintf("Binary files differ: %d bytes -> %d bytes", len(from), len(to))}
+	}
+	return unifiedDiff(fromName, toName, from, to)
+}
+
+// sideBySideRow is a row of a diff shown side by side: the old line on the left, the new one on the right
+type sideBySideRow struct {
+	left, right string
+	// removed and added mark the sides that changed
+	removed, added bool
+	// header rows show the file names, full rows a hunk header or the summary of a binary diff
+	header, full bool
+}
+
+// sideBySideRows lays out the lines of a unified diff side by side.
+// Removed lines are paired with the lines added in their place.
+func sideBySideRows(lines []string) []sideBySideRow {
+	var rows []sideBySideRow
+	var removed, added []string
+	flush := func() {
+		for i := range max(len(removed), len(added)) {
+			var row sideBySideRow
+			if i < len(removed) {
+				row.left, row.removed = removed[i], true
+			}
+			if i < len(added) {
+				row.right, row.added = added[i], true
+			}
+			rows = append(rows, row)
+		}
+		removed, added = nil, nil
+	}
+
+	inHunk := false
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			inHunk = true
+			rows = append(rows, sideBySideRow{left: line, full: true})
+		case !inHunk && strings.HasPrefix(line, "--- "):
+			rows = append(rows, sideBySideRow{left: strings.TrimPrefix(line, "--- "), header: true})
+		case !inHunk && strings.HasPrefix(line, "+++ ") && len(rows) > 0 && rows[len(rows)-1].header:
+			rows[len(rows)-1].right = strings.TrimPrefix(line, "+++ ")
+		case !inHunk:
+			rows = append(rows, sideBySideRow{left: line, full: true})
+		case strings.HasPrefix(line, "-"):
+			removed = append(removed, line[1:])
+		case strings.HasPrefix(line, "+"):
+			added = append(added, line[1:])
+		default:
+			flush()
+			text := strings.TrimPrefix(line, " ")
+			rows = append(rows, sideBySideRow{left: text, right: text})
+		}
+	}
+	flush()
+	return rows
+}
+
+// renderSideBySideRow renders a row in two columns of the given total width
+func renderSideBySideRow(row sideBySideRow, width int) string {
+	if row.full {
+		return renderDiffLine(ansi.Truncate(row.left, width, "…"))
+	}
+	columnWidth := max((width-3)/2, 1)
+	cell := func(text string, style *lipgloss.Style) string {
+		text = ansi.Truncate(strings.ReplaceAll(text, "\t", "    "), columnWidth, "…")
+		text += strings.Repeat(" ", max(columnWidth-ansi.StringWidth(text), 0))
+		if style != nil {
+			return style.Render(text)
+		}
+		return text
+	}
+
+	var leftStyle, rightStyle *lipgloss.Style
+	switch {
+	case row.header:
+		leftStyle, rightStyle = &diffHeaderStyle, &diffHeaderStyle
+	default:
+		if row.removed {
+			leftStyle = &diffRemovedStyle
+		}
+		if row.added {
+			rightStyle = &diffAddedStyle
+		}
+	}
+	return cell(row.left, leftStyle) + " │ " + cell(row.right, rightStyle)
+}
+
+// diffPager scrolls through the lines of a diff below a one-line summary
+type diffPager struct {
+	lines   []string
+	scrollY int
+	// sideBySide shows the old and new versions next to each other instead of a unified diff
+	sideBySide bool
+}
+
+// rowCount is the number of rows the diff takes in the current layout
+func (p *diffPager) rowCount() int {
+	if p.sideBySide {
+		return len(sideBySideRows(p.lines))
+	}
+	return len(p.lines)
+}
+
+// HandleToggleSideBySide switches between the unified and the side by side layout
+func (p *diffPager) HandleToggleSideBySide(model *Model) tea.Cmd {
+	p.sideBySide = !p.sideBySide
+	p.scrollY = min(p.scrollY, p.maxScroll(model))
+	return nil
+}
+
+// pageHeight is the number of diff lines that fit below the summary
+func (p *diffPager) pageHeight(model *Model) int {
+	return max(model.ViewHeight()-1, 1)
+}
+
+func (p *diffPager) maxScroll(model *Model) int {
+	return max(p.rowCount()-p.pageHeight(model), 0)
+}
+
+func (p *diffPager) HandleUp() tea.Cmd {
+	if p.scrollY > 0 {
+		p.scrollY--
+	}
+	return nil
+}
+
+func (p *diffPager) HandleDown(model *Model) tea.Cmd {
+	if p.scrollY < p.maxScroll(model) {
+		p.scrollY++
+	}
+	return nil
+}
+
+func (p *diffPager) HandlePageUp(model *Model) tea.Cmd {
+	p.scrollY = max(p.scrollY-p.pageHeight(model), 0)
+	return nil
+}
+
+func (p *diffPager) HandlePageDown(model *Model) tea.Cmd {
+	p.scrollY = min(p.scrollY+p.pageHeight(model), p.maxScroll(model))
+	return nil
+}
+
+func (p *diffPager) HandleGoToBeginning() tea.Cmd {
+	p.scrollY = 0
+	return nil
+}
+
+func (p *diffPager) HandleGoToEnd(model *Model) tea.Cmd {
+	p.scrollY = p.maxScroll(model)
+	return nil
+}
+
+// renderLines renders the visible part of the diff
+func (p *diffPager) renderLines(model *Model, height int) string {
+	var rendered []string
+	if p.sideBySide {
+		for _, row := range sideBySideRows(p.lines) {
+			rendered = append(rendered, renderSideBySideRow(row, model.width))
+		}
+	} else {
+		rendered = make([]string, len(p.lines))
+		for i, line := range p.lines {
+			rendered[i] = renderDiffLine(line)
+		}
+	}
+	v := viewport.New(viewport.WithWidth(model.width), viewport.WithHeight(max(height, 1)))
+	v.SetContent(strings.Join(rendered, "\n"))
+	v.ScrollDown(p.scrollY)
+	return v.View()
+}
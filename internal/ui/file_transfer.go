@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+const (
+	fileTransferPollInterval   = 200 * time.Millisecond
+	fileTransferResultDuration = 5 * time.Second
+)
+
+// fileTransfer is a copy out of a container that runs in the background.
+// Its progress is shown in the footer, whatever view is open.
+type fileTransfer struct {
+	dest   string
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	progress docker.CopyProgress
+	done     bool
+	err      error
+}
+
+// fileTransferTickMsg polls the progress of a transfer
+type fileTransferTickMsg struct {
+	transfer *fileTransfer
+}
+
+// fileTransferExpiredMsg hides the result of a finished transfer
+type fileTransferExpiredMsg struct {
+	transfer *fileTransfer
+}
+
+// startFileTransfer runs a copy in the background. Only one copy runs at a time.
+func (m *Model) startFileTransfer(dest string, run func(ctx context.Context, progress func(docker.CopyProgress)) error) tea.Cmd {
+	if m.fileTransfer != nil && !m.fileTransfer.isDone() {
+		m.err = errors.New("another copy is running; press ctrl+c in the file browser to cancel it")
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	transfer := &fileTransfer{dest: dest, cancel: cancel}
+	m.fileTransfer = transfer
+	go func() {
+		err := run(ctx, transfer.update)
+		transfer.finish(err)
+		cancel()
+	}()
+	return pollFileTransfer(transfer)
+}
+
+func pollFileTransfer(transfer *fileTransfer) tea.Cmd {
+	return tea.Tick(fileTransferPollInterval, func(time.Time) tea.Msg {
+		return fileTransferTickMsg{transfer: transfer}
+	})
+}
+
+// handleFileTransferTick keeps polling a running transfer and reports the error of a failed one
+func (m *Model) handleFileTransferTick(msg fileTransferTickMsg) tea.Cmd {
+	if msg.transfer != m.fileTransfer {
+		return nil
+	}
+	if !msg.transfer.isDone() {
+		return pollFileTransfer(msg.transfer)
+	}
+	if err := msg.transfer.failure(); err != nil {
+		m.err = err
+	}
+	return tea.Tick(fileTransferResultDuration, func(time.Time) tea.Msg {
+		return fileTransferExpiredMsg{transfer: msg.transfer}
+	})
+}
+
+// cancelFileTransfer stops the running copy, if any
+func (m *Model) cancelFileTransfer() bool {
+	if m.fileTransfer == nil || m.fileTransfer.isDone() {
+		return false
+	}
+	m.fileTransfer.cancel()
+	return true
+}
+
+func (t *fileTransfer) update(progress docker.CopyProgress) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.progress = progress
+}
+
+func (t *fileTransfer) finish(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.done = true
+	t.err = err
+}
+
+func (t *fileTransfer) isDone() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.done
+}
+
+// failure returns the error of a transfer that was not canceled
+func (t *fileTransfer) failure() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err == nil || errors.Is(t.err, context.Canceled) {
+		return nil
+	}
+	return t.err
+}
+
+// status describes the transfer for the footer
+func (t *fileTransfer) status() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	counts := fmt.Sprintf("%d files, %s", t.progress.Files, models.ContainerFile{Size: t.progress.Bytes}.GetSizeString())
+	if t.progress.Skipped > 0 {
+		counts += fmt.Sprintf(", %d skipped", t.progress.Skipped)
+	}
+	switch {
+	case !t.done:
+		return fmt.Sprintf("Copying to %s: %s (%s)", t.dest, counts, t.progress.Path)
+	case errors.Is(t.err, context.Canceled):
+		return fmt.Sprintf("Copy to %s canceled after %s", t.dest, counts)
+	case t.err != nil:
+		return fmt.Sprintf("Copy to %s failed after %s", t.dest, counts)
+	default:
+		return fmt.Sprintf("Copied %s to %s", counts, t.dest)
+	}
+}
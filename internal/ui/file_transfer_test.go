@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+)
+
+func TestFileTransfer(t *testing.T) {
+	model := &Model{}
+	release := make(chan struct{})
+	cmd := model.startFileTransfer("/tmp/out", func(ctx context.Context, progress func(docker.CopyProgress)) error {
+		progress(docker.CopyProgress{Files: 2, Bytes: 2048, Path: "/app/logs"})
+		<-release
+		return errors.New("disk full")
+	})
+	require.NotNil(t, cmd)
+	transfer := model.fileTransfer
+	require.NotNil(t, transfer)
+
+	assert.Eventually(t, func() bool {
+		return transfer.status() == "Copying to /tmp/out: 2 files, 2.0K (/app/logs)"
+	}, time.Second, 10*time.Millisecond)
+
+	// One copy at a time
+	assert.Nil(t, model.startFileTransfer("/tmp/other", nil))
+	assert.Error(t, model.err)
+	model.err = nil
+
+	assert.NotNil(t, model.handleFileTransferTick(fileTransferTickMsg{transfer: transfer}))
+	close(release)
+	assert.Eventually(t, transfer.isDone, time.Second, 10*time.Millisecond)
+
+	assert.NotNil(t, model.handleFileTransferTick(fileTransferTickMsg{transfer: transfer}))
+	assert.EqualError(t, model.err, "disk full")
+	assert.Equal(t, "Copy to /tmp/out failed after 2 files, 2.0K", transfer.status())
+
+	// The result is hidden after a while
+	_, _ = model.Update(fileTransferExpiredMsg{transfer: transfer})
+	assert.Nil(t, model.fileTransfer)
+}
+
+func TestFileTransfer_Cancel(t *testing.T) {
+	model := &Model{}
+	model.startFileTransfer("/tmp/out", func(ctx context.Context, progress func(docker.CopyProgress)) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.True(t, model.cancelFileTransfer())
+	assert.Eventually(t, model.fileTransfer.isDone, time.Second, 10*time.Millisecond)
+	assert.False(t, model.cancelFileTransfer())
+
+	model.handleFileTransferTick(fileTransferTickMsg{transfer: model.fileTransfer})
+	assert.NoError(t, model.err, "canceling is not an error")
+	assert.Equal(t, "Copy to /tmp/out canceled after 0 files, 0", model.fileTransfer.status())
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+)
+
+// helperCommandReadyMsg is sent when the helper is ready to run a tool command in a container
+type helperCommandReadyMsg struct {
+	container  *docker.Container
+	args       []string
+	aggressive bool
+	err        error
+}
+
+// runHelperTool injects the helper when needed and shows the output of a helper command
+// in the command execution view. It makes the tools work in images without a shell or coreutils.
+func (m *Model) runHelperTool(container *docker.Container, command string, args ...string) tea.Cmd {
+	fileOperations := m.sharedFileOperations()
+
+	m.loading = true
+	return func() tea.Msg {
+		err := fileOperations.PrepareHelper(context.Background(), container)
+		return helperCommandReadyMsg{
+			container: container,
+			args:      docker.HelperArgs(container, command, args...),
+			err:       err,
+		}
+	}
+}
+
+// runFileCommand runs a file management tool such as rm, mv or chmod in a container,
+// with the container's own tool when it has one and with the helper otherwise.
+// Aggressive commands are confirmed in the command execution view before they run.
+func (m *Model) runFileCommand(container *docker.Container, aggressive bool, tool string, args ...string) tea.Cmd {
+	if m.fileBrowserViewModel.volumeName != "" {
+		// The container of a browsed volume has only the helper
+		return m.commandExecutionViewModel.ExecuteCommand(m, aggressive, docker.HelperArgs(container, tool, args...)...)
+	}
+
+	fileOperations := m.sharedFileOperations()
+
+	m.loading = true
+	return func() tea.Msg {
+		cmdArgs, err := fileOperations.FileCommandArgs(context.Background(), container, tool, args...)
+		return helperCommandReadyMsg{
+			container:  container,
+			args:       cmdArgs,
+			aggressive: aggressive,
+			err:        err,
+		}
+	}
+}
+
+// handleHelperCommandReady runs the prepared helper command
+func (m *Model) handleHelperCommandReady(msg helperCommandReadyMsg) tea.Cmd {
+	m.loading = false
+	if msg.err != nil {
+		m.err = fmt.Errorf("failed to prepare helper in %s: %w", msg.container.Title(), msg.err)
+		return nil
+	}
+	return m.commandExecutionViewModel.ExecuteCommand(m, msg.aggressive, msg.args...)
+}
@@ -21,6 +21,14 @@ func (m *Model) CmdStats(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	return m, m.statsViewModel.Show(m)
 }
 
+// CmdDindStats shows stats for all containers inside the current dind container
+func (m *Model) CmdDindStats(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != DindProcessListView || m.dindProcessListViewModel.hostContainer == nil {
+		return m, nil
+	}
+	return m, m.statsViewModel.ShowDind(m, m.dindProcessListViewModel.hostContainer)
+}
+
 func (m *Model) CmdDind(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	switch m.currentView {
 	case DockerContainerListView:
@@ -63,6 +63,8 @@ func (m *Model) CmdDelete(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	switch m.currentView {
 	case ImageListView:
 		return m, m.imageListViewModel.HandleDelete(m)
+	case ImageCleanupView:
+		return m, m.imageCleanupViewModel.HandleDelete(m)
 	case NetworkListView:
 		return m, m.networkListViewModel.HandleDelete(m)
 	case VolumeListView:
@@ -164,3 +166,67 @@ func (m *Model) getContainerByContainerAware() *docker.Container {
 	// this view model does not support container-aware functionality.
 	return nil
 }
+
+// CmdImageHistory shows the build steps of the selected image with the size of each layer
+func (m *Model) CmdImageHistory(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != ImageListView {
+		return m, nil
+	}
+	return m, m.imageListViewModel.HandleHistory(m)
+}
+
+// CmdOpenLayer shows the files the layer of the selected build step added, changed and deleted
+func (m *Model) CmdOpenLayer(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != ImageHistoryView {
+		return m, nil
+	}
+	return m, m.imageHistoryViewModel.HandleOpen(m)
+}
+
+// CmdPullImage asks for a reference and pulls it with per-layer progress
+func (m *Model) CmdPullImage(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != ImageListView {
+		return m, nil
+	}
+	return m, m.imageListViewModel.HandlePull(m)
+}
+
+// CmdTagImage asks for a new name for the selected image
+func (m *Model) CmdTagImage(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != ImageListView {
+		return m, nil
+	}
+	return m, m.imageListViewModel.HandleTag(m)
+}
+
+// CmdPushImage pushes the selected image with per-layer progress
+func (m *Model) CmdPushImage(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != ImageListView {
+		return m, nil
+	}
+	return m, m.imageListViewModel.HandlePush(m)
+}
+
+// CmdImageCleanup shows which images are dangling, unused or in use, to remove those that can go
+func (m *Model) CmdImageCleanup(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != ImageListView {
+		return m, nil
+	}
+	return m, m.imageCleanupViewModel.Show(m)
+}
+
+// CmdPrunePreview lists the images `docker image prune` would remove and asks before removing them
+func (m *Model) CmdPrunePreview(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != ImageCleanupView {
+		return m, nil
+	}
+	return m, m.imageCleanupViewModel.HandlePrunePreview(false)
+}
+
+// CmdPruneAllPreview lists the images `docker image prune --all` would remove and asks before removing them
+func (m *Model) CmdPruneAllPreview(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != ImageCleanupView {
+		return m, nil
+	}
+	return m, m.imageCleanupViewModel.HandlePrunePreview(true)
+}
@@ -11,11 +11,153 @@ import (
 // CmdFileBrowse is triggered when the user wants to browse files in a container
 // It loads the file browser view model for the specified container.
 func (m *Model) CmdFileBrowse(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch m.currentView {
+	case VolumeListView:
+		return m, m.volumeListViewModel.HandleBrowse(m, false)
+	case ImageListView:
+		return m, m.imageListViewModel.HandleBrowse(m)
+	}
 	return m, m.useContainerAware(func(container *docker.Container) tea.Cmd {
 		return m.fileBrowserViewModel.LoadContainer(m, container)
 	})
 }
 
+// CmdBrowseVolumeReadWrite browses the selected volume with write access, for copying files in and deleting them
+func (m *Model) CmdBrowseVolumeReadWrite(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != VolumeListView {
+		return m, nil
+	}
+	return m, m.volumeListViewModel.HandleBrowse(m, true)
+}
+
+// CmdToggleImageLayers shows which layer of the browsed image wrote each file
+func (m *Model) CmdToggleImageLayers(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileBrowserView {
+		return m, nil
+	}
+	return m, m.fileBrowserViewModel.HandleToggleLayers(m)
+}
+
+// CmdCycleFileSort sorts the file browser by the next of name, size, mtime and type
+func (m *Model) CmdCycleFileSort(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileBrowserView {
+		return m, nil
+	}
+	return m, m.fileBrowserViewModel.HandleCycleSort(m)
+}
+
+// CmdReverseFileSort reverses the sort order of the file browser
+func (m *Model) CmdReverseFileSort(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileBrowserView {
+		return m, nil
+	}
+	return m, m.fileBrowserViewModel.HandleReverseSort(m)
+}
+
+// CmdToggleDirsFirst lists directories before files in the file browser, or mixes them
+func (m *Model) CmdToggleDirsFirst(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileBrowserView {
+		return m, nil
+	}
+	return m, m.fileBrowserViewModel.HandleToggleDirsFirst(m)
+}
+
+// CmdToggleDotfiles hides or shows the dotfiles in the file browser
+func (m *Model) CmdToggleDotfiles(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileBrowserView {
+		return m, nil
+	}
+	return m, m.fileBrowserViewModel.HandleToggleDotfiles(m)
+}
+
+// CmdToggleOwnerColumns hides or shows the owner and group columns of the file browser
+func (m *Model) CmdToggleOwnerColumns(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileBrowserView {
+		return m, nil
+	}
+	return m, m.fileBrowserViewModel.HandleToggleOwnerColumns(m)
+}
+
+// CmdTogglePermissionsColumn hides or shows the permissions column of the file browser
+func (m *Model) CmdTogglePermissionsColumn(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileBrowserView {
+		return m, nil
+	}
+	return m, m.fileBrowserViewModel.HandleTogglePermissionsColumn(m)
+}
+
+// CmdDirectorySizes computes the disk usage of the directories in the file browser and sorts by it
+func (m *Model) CmdDirectorySizes(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileBrowserView {
+		return m, nil
+	}
+	return m, m.fileBrowserViewModel.HandleDirectorySizes(m)
+}
+
+// CmdToggleSelectFile selects or unselects the file under the cursor for copying
+func (m *Model) CmdToggleSelectFile(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch m.currentView {
+	case FileBrowserView:
+		return m, m.fileBrowserViewModel.HandleToggleSelect(m)
+	case ImageCleanupView:
+		return m, m.imageCleanupViewModel.HandleToggleSelect(m)
+	default:
+		return m, nil
+	}
+}
+
+// CmdSelectAllFiles selects all files of the directory, or none
+func (m *Model) CmdSelectAllFiles(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch m.currentView {
+	case FileBrowserView:
+		return m, m.fileBrowserViewModel.HandleSelectAll(m)
+	case ImageCleanupView:
+		return m, m.imageCleanupViewModel.HandleSelectAll(m)
+	default:
+		return m, nil
+	}
+}
+
+// CmdContainerChanges shows what the selected container changed relative to its image
+func (m *Model) CmdContainerChanges(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	return m, m.useContainerAware(func(container *docker.Container) tea.Cmd {
+		return m.containerChangesViewModel.Load(m, container)
+	})
+}
+
+// CmdDiffWithImage shows how the selected changed file differs from the image
+func (m *Model) CmdDiffWithImage(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != ContainerChangesView {
+		return m, nil
+	}
+	return m, m.containerChangesViewModel.HandleDiff(m)
+}
+
+// CmdDiffWith compares the shown file with the same path in another container or in the image
+func (m *Model) CmdDiffWith(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	vm := &m.fileContentViewModel
+	if m.currentView != FileContentView || vm.container == nil || vm.contentPath == "" {
+		return m, nil
+	}
+	return m, m.fileDiffTargetViewModel.Show(m, vm.container, vm.contentPath)
+}
+
+// CmdSelectDiffTarget compares the file with the selected container or image
+func (m *Model) CmdSelectDiffTarget(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileDiffTargetView {
+		return m, nil
+	}
+	return m, m.fileDiffTargetViewModel.HandleSelect(m)
+}
+
+// CmdToggleSideBySide switches the diff between the unified and the side by side layout
+func (m *Model) CmdToggleSideBySide(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileDiffView {
+		return m, nil
+	}
+	return m, m.fileDiffViewModel.HandleToggleSideBySide(m)
+}
+
 func (m *Model) CmdOpenFileOrDirectory(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	return m, m.fileBrowserViewModel.HandleOpenFileOrDirectory(m)
 }
@@ -23,3 +165,143 @@ func (m *Model) CmdOpenFileOrDirectory(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 func (m *Model) CmdGoToParentDirectory(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	return m, m.fileBrowserViewModel.HandleGoToParentDirectory(m)
 }
+
+// CmdEditFilePath opens the path bar of the file browser to jump to any path
+func (m *Model) CmdEditFilePath(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileBrowserView {
+		return m, nil
+	}
+	return m, m.fileBrowserViewModel.HandleEditPath()
+}
+
+// CmdFollowLink goes to where the symbolic link under the cursor points
+func (m *Model) CmdFollowLink(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileBrowserView {
+		return m, nil
+	}
+	return m, m.fileBrowserViewModel.HandleFollowLink(m)
+}
+
+// CmdGoToRealPath shows the current directory under its path without symbolic links
+func (m *Model) CmdGoToRealPath(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileBrowserView {
+		return m, nil
+	}
+	return m, m.fileBrowserViewModel.HandleGoToRealPath(m)
+}
+
+// CmdFindFiles opens the find form for the current directory of the file browser
+func (m *Model) CmdFindFiles(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileBrowserView || m.fileBrowserViewModel.browsingContainer == nil ||
+		!m.fileBrowserViewModel.requireRunning(m, m.fileBrowserViewModel.browsingContainer) {
+		return m, nil
+	}
+	m.fileSearchViewModel.Show(m, m.fileBrowserViewModel.browsingContainer, m.fileBrowserViewModel.currentPath, docker.FileSearchFind)
+	return m, nil
+}
+
+// CmdGrepFiles opens the content search form for the current directory of the file browser
+func (m *Model) CmdGrepFiles(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileBrowserView || m.fileBrowserViewModel.browsingContainer == nil ||
+		!m.fileBrowserViewModel.requireRunning(m, m.fileBrowserViewModel.browsingContainer) {
+		return m, nil
+	}
+	m.fileSearchViewModel.Show(m, m.fileBrowserViewModel.browsingContainer, m.fileBrowserViewModel.currentPath, docker.FileSearchGrep)
+	return m, nil
+}
+
+// CmdFollowFile streams the lines appended to the selected or shown file in the log view
+func (m *Model) CmdFollowFile(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch m.currentView {
+	case FileBrowserView:
+		return m, m.fileBrowserViewModel.HandleFollowFile(m)
+	case FileContentView:
+		vm := &m.fileContentViewModel
+		if vm.container == nil || vm.contentPath == "" || !m.fileBrowserViewModel.requireRunning(m, vm.container) {
+			return m, nil
+		}
+		return m, m.logViewModel.FollowFile(m, vm.container, vm.contentPath)
+	default:
+		return m, nil
+	}
+}
+
+// CmdOpenSearchHit opens the selected search hit, at the matching line for content searches
+func (m *Model) CmdOpenSearchHit(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileSearchView {
+		return m, nil
+	}
+	return m, m.fileSearchViewModel.HandleOpen(m)
+}
+
+// CmdOpenSearchHitDirectory shows the directory of the selected search hit in the file browser
+func (m *Model) CmdOpenSearchHitDirectory(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileSearchView {
+		return m, nil
+	}
+	return m, m.fileSearchViewModel.HandleOpenDirectory(m)
+}
+
+// CmdEditSearch reopens the search form
+func (m *Model) CmdEditSearch(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileSearchView {
+		return m, nil
+	}
+	return m, m.fileSearchViewModel.HandleEditSearch()
+}
+
+// CmdEditFile opens the shown file in $EDITOR, or reopens the editor from the diff of an edit
+func (m *Model) CmdEditFile(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch m.currentView {
+	case FileContentView:
+		vm := &m.fileContentViewModel
+		if vm.container == nil || vm.contentPath == "" || !m.fileBrowserViewModel.requireRunning(m, vm.container) {
+			return m, nil
+		}
+		return m, m.fileEditViewModel.Start(m, vm.container, vm.contentPath)
+	case FileEditView:
+		return m, m.fileEditViewModel.HandleEditAgain()
+	default:
+		return m, nil
+	}
+}
+
+// CmdWriteBackFile copies the edited file back into the container
+func (m *Model) CmdWriteBackFile(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileEditView {
+		return m, nil
+	}
+	return m, m.fileEditViewModel.HandleWriteBack(m)
+}
+
+// CmdToggleHexView switches the file content view between text and hex dump
+func (m *Model) CmdToggleHexView(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileContentView {
+		return m, nil
+	}
+	return m, m.fileContentViewModel.HandleToggleHexView()
+}
+
+// CmdLoadWholeFile reads a large file completely instead of page by page, after a confirmation
+func (m *Model) CmdLoadWholeFile(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileContentView {
+		return m, nil
+	}
+	return m, m.fileContentViewModel.HandleLoadWholeFile()
+}
+
+// CmdToggleLineNumbers shows or hides the line numbers of the file content view
+func (m *Model) CmdToggleLineNumbers(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileContentView {
+		return m, nil
+	}
+	return m, m.fileContentViewModel.HandleToggleLineNumbers()
+}
+
+// CmdTogglePrettyJSON switches the file content view between the raw and the indented JSON
+func (m *Model) CmdTogglePrettyJSON(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != FileContentView {
+		return m, nil
+	}
+	return m, m.fileContentViewModel.HandleTogglePrettyJSON(m)
+}
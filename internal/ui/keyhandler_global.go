@@ -41,6 +41,11 @@ func (m *Model) CmdCancel(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m, m.logViewModel.HandleCancel()
 	case HelperInjectorView:
 		return m, m.helperInjectorViewModel.HandleCancel()
+	case ImageTransferView:
+		return m, m.imageTransferViewModel.HandleCancel()
+	case FileBrowserView:
+		m.cancelFileTransfer()
+		return m, nil
 	default:
 		slog.Info("Cancel command not implemented for current view",
 			slog.String("current_view", m.currentView.String()))
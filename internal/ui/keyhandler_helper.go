@@ -12,3 +12,24 @@ func (m *Model) CmdInjectHelper(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m.helperInjectorViewModel.HandleInjectHelper(m, container)
 	})
 }
+
+// CmdHelpers lists the containers that carry an injected helper
+func (m *Model) CmdHelpers(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	return m, m.helperListViewModel.Show(m)
+}
+
+// CmdEjectHelper removes the helper from the selected container
+func (m *Model) CmdEjectHelper(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != HelperListView {
+		return m, nil
+	}
+	return m, m.helperListViewModel.HandleEject(m)
+}
+
+// CmdReinjectHelper replaces the helper of the selected container with the embedded binary
+func (m *Model) CmdReinjectHelper(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != HelperListView {
+		return m, nil
+	}
+	return m, m.helperListViewModel.HandleReinject(m)
+}
@@ -50,6 +50,28 @@ func (m *Model) CmdUp(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	case FileBrowserActionView:
 		m.fileBrowserActionViewModel.HandleUp()
 		return m, nil
+	case ProcessSignalView:
+		return m, m.processSignalViewModel.HandleUp()
+	case FileDiffTargetView:
+		return m, m.fileDiffTargetViewModel.HandleUp()
+	case FileSearchView:
+		return m, m.fileSearchViewModel.HandleUp(m)
+	case FileEditView:
+		return m, m.fileEditViewModel.HandleUp()
+	case ContainerChangesView:
+		return m, m.containerChangesViewModel.HandleUp(m)
+	case HelperListView:
+		return m, m.helperListViewModel.HandleUp(m)
+	case ImageHistoryView:
+		return m, m.imageHistoryViewModel.HandleUp(m)
+	case ImageLayerView:
+		return m, m.imageLayerViewModel.HandleUp(m)
+	case ImageTransferView:
+		return m, m.imageTransferViewModel.HandleUp()
+	case ImageCleanupView:
+		return m, m.imageCleanupViewModel.HandleUp(m)
+	case FileDiffView:
+		return m, m.fileDiffViewModel.HandleUp()
 	default:
 		slog.Info("Unhandled key up in current view",
 			slog.String("view", m.currentView.String()))
@@ -103,6 +125,28 @@ func (m *Model) CmdDown(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	case FileBrowserActionView:
 		m.fileBrowserActionViewModel.HandleDown()
 		return m, nil
+	case ProcessSignalView:
+		return m, m.processSignalViewModel.HandleDown()
+	case FileDiffTargetView:
+		return m, m.fileDiffTargetViewModel.HandleDown()
+	case FileSearchView:
+		return m, m.fileSearchViewModel.HandleDown(m)
+	case FileEditView:
+		return m, m.fileEditViewModel.HandleDown(m)
+	case ContainerChangesView:
+		return m, m.containerChangesViewModel.HandleDown(m)
+	case HelperListView:
+		return m, m.helperListViewModel.HandleDown(m)
+	case ImageHistoryView:
+		return m, m.imageHistoryViewModel.HandleDown(m)
+	case ImageLayerView:
+		return m, m.imageLayerViewModel.HandleDown(m)
+	case ImageTransferView:
+		return m, m.imageTransferViewModel.HandleDown(m)
+	case ImageCleanupView:
+		return m, m.imageCleanupViewModel.HandleDown(m)
+	case FileDiffView:
+		return m, m.fileDiffViewModel.HandleDown(m)
 	default:
 		slog.Info("Unhandled key down in current view",
 			slog.String("view", m.currentView.String()))
@@ -122,6 +166,10 @@ func (m *Model) CmdGoToEnd(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m, m.commandExecutionViewModel.HandleGoToEnd(m)
 	case HelperInjectorView:
 		return m, m.helperInjectorViewModel.HandleGoToEnd(m)
+	case FileEditView:
+		return m, m.fileEditViewModel.HandleGoToEnd(m)
+	case FileDiffView:
+		return m, m.fileDiffViewModel.HandleGoToEnd(m)
 	default:
 		slog.Info("GoToEnd not supported in current view",
 			slog.String("view", m.currentView.String()))
@@ -141,6 +189,10 @@ func (m *Model) CmdGoToBeginning(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m, m.commandExecutionViewModel.HandleGoToBeginning()
 	case HelperInjectorView:
 		return m, m.helperInjectorViewModel.HandleGoToBeginning()
+	case FileEditView:
+		return m, m.fileEditViewModel.HandleGoToBeginning()
+	case FileDiffView:
+		return m, m.fileDiffViewModel.HandleGoToBeginning()
 	default:
 		slog.Info("GoToBeginning not supported in current view",
 			slog.String("view", m.currentView.String()))
@@ -156,6 +208,10 @@ func (m *Model) CmdPageUp(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m, m.inspectViewModel.HandlePageUp(m)
 	case FileContentView:
 		return m, m.fileContentViewModel.HandlePageUp(m.Height)
+	case FileEditView:
+		return m, m.fileEditViewModel.HandlePageUp(m)
+	case FileDiffView:
+		return m, m.fileDiffViewModel.HandlePageUp(m)
 	default:
 		slog.Info("PageUp not supported in current view",
 			slog.String("view", m.currentView.String()))
@@ -171,6 +227,10 @@ func (m *Model) CmdPageDown(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m, m.inspectViewModel.HandlePageDown(m)
 	case FileContentView:
 		return m, m.fileContentViewModel.HandlePageDown(m.Height)
+	case FileEditView:
+		return m, m.fileEditViewModel.HandlePageDown(m)
+	case FileDiffView:
+		return m, m.fileDiffViewModel.HandlePageDown(m)
 	default:
 		slog.Info("PageDown not supported in current view",
 			slog.String("view", m.currentView.String()))
@@ -214,6 +274,28 @@ func (m *Model) CmdBack(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m, m.helperInjectorViewModel.HandleBack(m)
 	case FileBrowserActionView:
 		return m, m.fileBrowserActionViewModel.HandleBack(m)
+	case ProcessSignalView:
+		return m, m.processSignalViewModel.HandleBack(m)
+	case FileDiffTargetView:
+		return m, m.fileDiffTargetViewModel.HandleBack(m)
+	case FileSearchView:
+		return m, m.fileSearchViewModel.HandleBack(m)
+	case FileEditView:
+		return m, m.fileEditViewModel.HandleBack(m)
+	case ContainerChangesView:
+		return m, m.containerChangesViewModel.HandleBack(m)
+	case HelperListView:
+		return m, m.helperListViewModel.HandleBack(m)
+	case ImageHistoryView:
+		return m, m.imageHistoryViewModel.HandleBack(m)
+	case ImageLayerView:
+		return m, m.imageLayerViewModel.HandleBack(m)
+	case ImageTransferView:
+		return m, m.imageTransferViewModel.HandleBack(m)
+	case ImageCleanupView:
+		return m, m.imageCleanupViewModel.HandleBack(m)
+	case FileDiffView:
+		return m, m.fileDiffViewModel.HandleBack(m)
 	case ComposeProcessListView:
 		// Should not happen in ComposeProcessListView, but handle it gracefully
 		// This is the main view, nowhere to go back to
@@ -14,6 +14,8 @@ func (m *Model) CmdSearch(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m, m.logViewModel.HandleSearch()
 	case InspectView:
 		return m, m.inspectViewModel.HandleSearch()
+	case FileContentView:
+		return m, m.fileContentViewModel.HandleSearch()
 	default:
 		// Check if current view supports container search
 		vm := m.GetCurrentViewModel()
@@ -34,6 +36,8 @@ func (m *Model) CmdNextSearchResult(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m, m.logViewModel.HandleNextSearchResult(m)
 	case InspectView:
 		return m, m.inspectViewModel.HandleNextSearchResult(m)
+	case FileContentView:
+		return m, m.fileContentViewModel.HandleNextSearchResult(m)
 	default:
 		// Check if current view supports container search
 		vm := m.GetCurrentViewModel()
@@ -54,6 +58,8 @@ func (m *Model) CmdPrevSearchResult(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m, m.logViewModel.HandlePrevSearchResult(m)
 	case InspectView:
 		return m, m.inspectViewModel.HandlePrevSearchResult(m)
+	case FileContentView:
+		return m, m.fileContentViewModel.HandlePrevSearchResult(m)
 	default:
 		// Check if current view supports container search
 		vm := m.GetCurrentViewModel()
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	tea "charm.land/bubbletea/v2"
+)
+
+// Process tree and signal commands for the Top view
+
+func (m *Model) CmdToggleProcessTree(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch m.currentView {
+	case TopView:
+		m.topViewModel.HandleToggleTree()
+		return m, nil
+	default:
+		return m, nil
+	}
+}
+
+func (m *Model) CmdToggleCollapse(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch m.currentView {
+	case TopView:
+		m.topViewModel.HandleToggleCollapse()
+		return m, nil
+	case ContainerChangesView:
+		return m, m.containerChangesViewModel.HandleOpen(m)
+	case ImageLayerView:
+		return m, m.imageLayerViewModel.HandleOpen(m)
+	default:
+		return m, nil
+	}
+}
+
+func (m *Model) CmdCollapse(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch m.currentView {
+	case TopView:
+		m.topViewModel.HandleCollapse()
+		return m, nil
+	case ContainerChangesView:
+		m.containerChangesViewModel.HandleCollapse(m)
+		return m, nil
+	case ImageLayerView:
+		m.imageLayerViewModel.HandleCollapse(m)
+		return m, nil
+	default:
+		return m, nil
+	}
+}
+
+func (m *Model) CmdExpand(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch m.currentView {
+	case TopView:
+		m.topViewModel.HandleExpand()
+		return m, nil
+	case ContainerChangesView:
+		m.containerChangesViewModel.HandleExpand(m)
+		return m, nil
+	case ImageLayerView:
+		m.imageLayerViewModel.HandleExpand(m)
+		return m, nil
+	default:
+		return m, nil
+	}
+}
+
+func (m *Model) CmdSendSignal(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch m.currentView {
+	case TopView:
+		process := m.topViewModel.SelectedProcess()
+		if process == nil || m.topViewModel.container == nil {
+			return m, nil
+		}
+		m.processSignalViewModel.Initialize(m.topViewModel.container, *process)
+		m.SwitchView(ProcessSignalView)
+		return m, nil
+	default:
+		return m, nil
+	}
+}
+
+func (m *Model) CmdSelectSignal(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch m.currentView {
+	case ProcessSignalView:
+		return m, m.processSignalViewModel.HandleSelect(m)
+	default:
+		return m, nil
+	}
+}
+
+func (m *Model) CmdSignalHostLookup(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch m.currentView {
+	case ProcessSignalView:
+		return m, m.processSignalViewModel.HandleHostLookup()
+	default:
+		return m, nil
+	}
+}
@@ -30,6 +30,22 @@ func (m *Model) CmdLog(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	})
 }
 
+// CmdTogglePauseLogs stops and resumes the log view at its position while lines keep arriving
+func (m *Model) CmdTogglePauseLogs(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != LogView {
+		return m, nil
+	}
+	return m, m.logViewModel.HandleTogglePause(m)
+}
+
+// CmdSaveLogs saves the lines of the log view to a file
+func (m *Model) CmdSaveLogs(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != LogView {
+		return m, nil
+	}
+	return m, m.logViewModel.HandleExport(m)
+}
+
 // CmdShell executes a shell in the selected container
 // It defaults to /bin/sh, which is commonly available in containers.
 // If the container does not have /bin/sh, it will fail gracefully.
@@ -98,6 +114,13 @@ func (m *Model) CmdShowFileActions(_ tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 
 			// Initialize the file browser action view
 			m.fileBrowserActionViewModel.Initialize(&file, container, path)
+			m.fileBrowserActionViewModel.SetSelection(m.fileBrowserViewModel.selectedFiles())
+			if m.fileBrowserViewModel.volumeName != "" {
+				m.fileBrowserActionViewModel.restrictToVolume(m.fileBrowserViewModel.volumeReadWrite)
+			}
+			if m.fileBrowserViewModel.image != nil {
+				m.fileBrowserActionViewModel.restrictToImage()
+			}
 			m.SwitchView(FileBrowserActionView)
 			return m, nil
 		}
@@ -16,6 +16,7 @@ func (m *Model) initializeKeyHandlers() {
 		{[]string{"4"}, "docker networks", m.CmdNetworkLs},
 		{[]string{"5"}, "docker volumes", m.CmdVolumeLs},
 		{[]string{"6"}, "stats", m.CmdStats},
+		{[]string{"7"}, "injected helpers", m.CmdHelpers},
 	}
 	m.globalKeymap = m.createKeymap(m.globalHandlers)
 
@@ -37,6 +38,7 @@ func (m *Model) initializeKeyHandlers() {
 		{[]string{"n"}, "next match", m.CmdNextSearchResult},
 		{[]string{"N"}, "prev match", m.CmdPrevSearchResult},
 		{[]string{"H"}, "inject helper binary", m.CmdInjectHelper},
+		{[]string{"C"}, "filesystem changes", m.CmdContainerChanges},
 	}
 
 	// Docker Container List View
@@ -78,6 +80,8 @@ func (m *Model) initializeKeyHandlers() {
 		{[]string{"n"}, "next match", m.CmdNextSearchResult},
 		{[]string{"N"}, "prev match", m.CmdPrevSearchResult},
 		{[]string{"f"}, "filter", m.CmdFilter},
+		{[]string{"p"}, "pause/resume", m.CmdTogglePauseLogs},
+		{[]string{"w"}, "save to file", m.CmdSaveLogs},
 		{[]string{"esc"}, "back", m.CmdBack},
 		{[]string{"?"}, "help", m.CmdHelp},
 		{[]string{"ctrl+c"}, "cancel", m.CmdCancel},
@@ -91,6 +95,8 @@ func (m *Model) initializeKeyHandlers() {
 		{[]string{"enter"}, "view logs", m.CmdLog},
 		{[]string{"esc"}, "back", m.CmdBack},
 		{[]string{"?"}, "help", m.CmdHelp},
+
+		{[]string{"s"}, "stats of all containers in this dind", m.CmdDindStats},
 	}, containerOperations...)
 	m.dindListViewKeymap = m.createKeymap(m.dindListViewHandlers)
 
@@ -104,6 +110,11 @@ func (m *Model) initializeKeyHandlers() {
 		{[]string{"t"}, "sort by time", m.CmdSortByTime},
 		{[]string{"n"}, "sort by name", m.CmdSortByCommand},
 		{[]string{"R"}, "reverse sort", m.CmdReverseSort},
+		{[]string{"T"}, "toggle process tree", m.CmdToggleProcessTree},
+		{[]string{"enter"}, "collapse/expand subtree", m.CmdToggleCollapse},
+		{[]string{"left", "h"}, "collapse subtree", m.CmdCollapse},
+		{[]string{"right", "l"}, "expand subtree", m.CmdExpand},
+		{[]string{"s"}, "send signal", m.CmdSendSignal},
 		{[]string{"a"}, "toggle auto-refresh", m.CmdToggleAutoRefresh},
 		{[]string{"r"}, "refresh", m.CmdRefresh},
 		{[]string{"esc"}, "back", m.CmdBack},
@@ -148,7 +159,13 @@ func (m *Model) initializeKeyHandlers() {
 	m.imageListViewHandlers = []KeyConfig{
 		{[]string{"up", "k"}, "move up", m.CmdUp},
 		{[]string{"down", "j"}, "move down", m.CmdDown},
+		{[]string{"enter", "f"}, "browse files", m.CmdFileBrowse},
 		{[]string{"i"}, "inspect", m.CmdInspect},
+		{[]string{"L"}, "history and layers", m.CmdImageHistory},
+		{[]string{"p"}, "pull", m.CmdPullImage},
+		{[]string{"t"}, "tag", m.CmdTagImage},
+		{[]string{"P"}, "push", m.CmdPushImage},
+		{[]string{"c"}, "cleanup", m.CmdImageCleanup},
 		{[]string{"r"}, "refresh", m.CmdRefresh},
 		{[]string{"a"}, "toggle all", m.CmdToggleAll},
 		{[]string{"D"}, "delete", m.CmdDelete},
@@ -173,6 +190,8 @@ func (m *Model) initializeKeyHandlers() {
 	m.volumeListViewHandlers = []KeyConfig{
 		{[]string{"up", "k"}, "move up", m.CmdUp},
 		{[]string{"down", "j"}, "move down", m.CmdDown},
+		{[]string{"enter", "f"}, "browse files (read-only)", m.CmdFileBrowse},
+		{[]string{"F"}, "browse files (read-write)", m.CmdBrowseVolumeReadWrite},
 		{[]string{"i"}, "inspect", m.CmdInspect},
 		{[]string{"r"}, "refresh", m.CmdRefresh},
 		{[]string{"D"}, "delete", m.CmdDelete},
@@ -188,6 +207,23 @@ func (m *Model) initializeKeyHandlers() {
 		{[]string{"enter"}, "open", m.CmdOpenFileOrDirectory},
 		{[]string{"x"}, "show actions", m.CmdShowFileActions},
 		{[]string{"u"}, "parent directory", m.CmdGoToParentDirectory},
+		{[]string{"g"}, "go to path", m.CmdEditFilePath},
+		{[]string{"l"}, "follow link", m.CmdFollowLink},
+		{[]string{"P"}, "go to real path", m.CmdGoToRealPath},
+		{[]string{"F"}, "find files", m.CmdFindFiles},
+		{[]string{"G"}, "search file contents", m.CmdGrepFiles},
+		{[]string{"f"}, "follow file (tail -F)", m.CmdFollowFile},
+		{[]string{"L"}, "show image layers", m.CmdToggleImageLayers},
+		{[]string{"s"}, "sort by name/size/mtime/type", m.CmdCycleFileSort},
+		{[]string{"S"}, "reverse sort order", m.CmdReverseFileSort},
+		{[]string{"d"}, "directories first", m.CmdToggleDirsFirst},
+		{[]string{"."}, "show/hide dotfiles", m.CmdToggleDotfiles},
+		{[]string{"o"}, "show/hide owner columns", m.CmdToggleOwnerColumns},
+		{[]string{"p"}, "show/hide permissions column", m.CmdTogglePermissionsColumn},
+		{[]string{"U"}, "compute directory sizes (du)", m.CmdDirectorySizes},
+		{[]string{"space"}, "select/unselect", m.CmdToggleSelectFile},
+		{[]string{"A"}, "select all/none", m.CmdSelectAllFiles},
+		{[]string{"ctrl+c"}, "cancel copy", m.CmdCancel},
 		{[]string{"r"}, "refresh", m.CmdRefresh},
 		{[]string{"esc"}, "back", m.CmdBack},
 		{[]string{"?"}, "help", m.CmdHelp},
@@ -202,6 +238,16 @@ func (m *Model) initializeKeyHandlers() {
 		{[]string{"pgdown", " "}, "page down", m.CmdPageDown},
 		{[]string{"G"}, "go to end", m.CmdGoToEnd},
 		{[]string{"g"}, "go to beginning", m.CmdGoToBeginning},
+		{[]string{"/"}, "search", m.CmdSearch},
+		{[]string{"n"}, "next match", m.CmdNextSearchResult},
+		{[]string{"N"}, "prev match", m.CmdPrevSearchResult},
+		{[]string{"#"}, "toggle line numbers", m.CmdToggleLineNumbers},
+		{[]string{"p"}, "pretty-print JSON", m.CmdTogglePrettyJSON},
+		{[]string{"x"}, "toggle hex view", m.CmdToggleHexView},
+		{[]string{"L"}, "load whole file", m.CmdLoadWholeFile},
+		{[]string{"f"}, "follow file (tail -F)", m.CmdFollowFile},
+		{[]string{"e"}, "edit in $EDITOR", m.CmdEditFile},
+		{[]string{"D"}, "diff with...", m.CmdDiffWith},
 		{[]string{"esc"}, "back", m.CmdBack},
 		{[]string{"?"}, "help", m.CmdHelp},
 	}
@@ -273,6 +319,146 @@ func (m *Model) initializeKeyHandlers() {
 	}
 	m.fileBrowserActionKeymap = m.createKeymap(m.fileBrowserActionHandlers)
 
+	// Process Signal View
+	m.processSignalHandlers = []KeyConfig{
+		{[]string{"up", "k"}, "move up", m.CmdUp},
+		{[]string{"down", "j"}, "move down", m.CmdDown},
+		{[]string{"enter"}, "send signal", m.CmdSelectSignal},
+		{[]string{"h"}, "look up the PID on the Docker host", m.CmdSignalHostLookup},
+		{[]string{"esc"}, "cancel", m.CmdBack},
+		{[]string{"?"}, "help", m.CmdHelp},
+	}
+	m.processSignalKeymap = m.createKeymap(m.processSignalHandlers)
+
+	// File Search View
+	m.fileSearchHandlers = []KeyConfig{
+		{[]string{"up", "k"}, "move up", m.CmdUp},
+		{[]string{"down", "j"}, "move down", m.CmdDown},
+		{[]string{"enter"}, "open hit", m.CmdOpenSearchHit},
+		{[]string{"o"}, "open directory in file browser", m.CmdOpenSearchHitDirectory},
+		{[]string{"e"}, "edit search", m.CmdEditSearch},
+		{[]string{"r"}, "search again", m.CmdRefresh},
+		{[]string{"esc"}, "back", m.CmdBack},
+		{[]string{"?"}, "help", m.CmdHelp},
+	}
+	m.fileSearchKeymap = m.createKeymap(m.fileSearchHandlers)
+
+	// File Edit View
+	m.fileEditHandlers = []KeyConfig{
+		{[]string{"up", "k"}, "scroll up", m.CmdUp},
+		{[]string{"down", "j"}, "scroll down", m.CmdDown},
+		{[]string{"pgup"}, "page up", m.CmdPageUp},
+		{[]string{"pgdown", " "}, "page down", m.CmdPageDown},
+		{[]string{"G"}, "go to end", m.CmdGoToEnd},
+		{[]string{"g"}, "go to beginning", m.CmdGoToBeginning},
+		{[]string{"w"}, "write back to container", m.CmdWriteBackFile},
+		{[]string{"e"}, "edit again", m.CmdEditFile},
+		{[]string{"esc"}, "discard changes", m.CmdBack},
+		{[]string{"?"}, "help", m.CmdHelp},
+	}
+	m.fileEditKeymap = m.createKeymap(m.fileEditHandlers)
+
+	// Container Changes View
+	// `docker diff`
+	m.containerChangesHandlers = []KeyConfig{
+		{[]string{"up", "k"}, "move up", m.CmdUp},
+		{[]string{"down", "j"}, "move down", m.CmdDown},
+		{[]string{"enter"}, "open file / toggle directory", m.CmdToggleCollapse},
+		{[]string{"left", "h"}, "collapse directory", m.CmdCollapse},
+		{[]string{"right", "l"}, "expand directory", m.CmdExpand},
+		{[]string{"d"}, "diff with image", m.CmdDiffWithImage},
+		{[]string{"r"}, "refresh", m.CmdRefresh},
+		{[]string{"esc"}, "back", m.CmdBack},
+		{[]string{"?"}, "help", m.CmdHelp},
+	}
+	m.containerChangesKeymap = m.createKeymap(m.containerChangesHandlers)
+
+	// Injected Helpers View
+	m.helperListHandlers = []KeyConfig{
+		{[]string{"up", "k"}, "move up", m.CmdUp},
+		{[]string{"down", "j"}, "move down", m.CmdDown},
+		{[]string{"D"}, "eject helper", m.CmdEjectHelper},
+		{[]string{"u"}, "re-inject helper", m.CmdReinjectHelper},
+		{[]string{"r"}, "refresh", m.CmdRefresh},
+		{[]string{"esc"}, "back", m.CmdBack},
+		{[]string{"?"}, "help", m.CmdHelp},
+	}
+	m.helperListKeymap = m.createKeymap(m.helperListHandlers)
+
+	// File Diff View
+	m.fileDiffHandlers = []KeyConfig{
+		{[]string{"up", "k"}, "scroll up", m.CmdUp},
+		{[]string{"down", "j"}, "scroll down", m.CmdDown},
+		{[]string{"pgup"}, "page up", m.CmdPageUp},
+		{[]string{"pgdown", " "}, "page down", m.CmdPageDown},
+		{[]string{"G"}, "go to end", m.CmdGoToEnd},
+		{[]string{"g"}, "go to beginning", m.CmdGoToBeginning},
+		{[]string{"s"}, "side by side/unified", m.CmdToggleSideBySide},
+		{[]string{"esc"}, "back", m.CmdBack},
+		{[]string{"?"}, "help", m.CmdHelp},
+	}
+	m.fileDiffKeymap = m.createKeymap(m.fileDiffHandlers)
+
+	// Diff With View
+	m.fileDiffTargetHandlers = []KeyConfig{
+		{[]string{"up", "k"}, "move up", m.CmdUp},
+		{[]string{"down", "j"}, "move down", m.CmdDown},
+		{[]string{"enter"}, "compare", m.CmdSelectDiffTarget},
+		{[]string{"esc"}, "cancel", m.CmdBack},
+		{[]string{"?"}, "help", m.CmdHelp},
+	}
+	m.fileDiffTargetKeymap = m.createKeymap(m.fileDiffTargetHandlers)
+
+	// Image History View
+	// `docker history`
+	m.imageHistoryHandlers = []KeyConfig{
+		{[]string{"up", "k"}, "move up", m.CmdUp},
+		{[]string{"down", "j"}, "move down", m.CmdDown},
+		{[]string{"enter"}, "show layer files", m.CmdOpenLayer},
+		{[]string{"r"}, "refresh", m.CmdRefresh},
+		{[]string{"esc"}, "back", m.CmdBack},
+		{[]string{"?"}, "help", m.CmdHelp},
+	}
+	m.imageHistoryKeymap = m.createKeymap(m.imageHistoryHandlers)
+
+	// Image Layer View
+	m.imageLayerHandlers = []KeyConfig{
+		{[]string{"up", "k"}, "move up", m.CmdUp},
+		{[]string{"down", "j"}, "move down", m.CmdDown},
+		{[]string{"enter"}, "toggle directory", m.CmdToggleCollapse},
+		{[]string{"left", "h"}, "collapse directory", m.CmdCollapse},
+		{[]string{"right", "l"}, "expand directory", m.CmdExpand},
+		{[]string{"esc"}, "back", m.CmdBack},
+		{[]string{"?"}, "help", m.CmdHelp},
+	}
+	m.imageLayerKeymap = m.createKeymap(m.imageLayerHandlers)
+
+	// Image Transfer View
+	// pull and push progress
+	m.imageTransferHandlers = []KeyConfig{
+		{[]string{"up", "k"}, "scroll up", m.CmdUp},
+		{[]string{"down", "j"}, "scroll down", m.CmdDown},
+		{[]string{"ctrl+c"}, "cancel", m.CmdCancel},
+		{[]string{"esc"}, "back", m.CmdBack},
+		{[]string{"?"}, "help", m.CmdHelp},
+	}
+	m.imageTransferKeymap = m.createKeymap(m.imageTransferHandlers)
+
+	// Image Cleanup View
+	m.imageCleanupHandlers = []KeyConfig{
+		{[]string{"up", "k"}, "move up", m.CmdUp},
+		{[]string{"down", "j"}, "move down", m.CmdDown},
+		{[]string{"space"}, "select/unselect", m.CmdToggleSelectFile},
+		{[]string{"A"}, "select all removable/none", m.CmdSelectAllFiles},
+		{[]string{"D"}, "delete selected", m.CmdDelete},
+		{[]string{"p"}, "prune preview", m.CmdPrunePreview},
+		{[]string{"P"}, "prune --all preview", m.CmdPruneAllPreview},
+		{[]string{"r"}, "refresh", m.CmdRefresh},
+		{[]string{"esc"}, "back", m.CmdBack},
+		{[]string{"?"}, "help", m.CmdHelp},
+	}
+	m.imageCleanupKeymap = m.createKeymap(m.imageCleanupHandlers)
+
 	// Helper Injector View
 	m.helperInjectorHandlers = []KeyConfig{
 		{[]string{"up", "k"}, "scroll up", m.CmdUp},
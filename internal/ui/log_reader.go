@@ -11,6 +11,8 @@ import (
 	"time"
 
 	tea "charm.land/bubbletea/v2"
+
+	"github.com/tokuhirom/dcv/internal/docker"
 )
 
 // logReader manages log streaming from a container
@@ -21,13 +23,17 @@ type logReader struct {
 	lines  []string
 	mu     sync.Mutex
 	done   bool
+	// remote is set when the command runs in a container and prints the PID of its process there first
+	remote    *docker.RemoteCommand
+	remotePID int
 }
 
 // newLogReader creates a new log reader
-func newLogReader(cmd *exec.Cmd) (*logReader, error) {
+func newLogReader(cmd *exec.Cmd, remote *docker.RemoteCommand) (*logReader, error) {
 	lr := &logReader{
-		lines: make([]string, 0),
-		cmd:   cmd,
+		lines:  make([]string, 0),
+		cmd:    cmd,
+		remote: remote,
 	}
 
 	var err error
@@ -71,7 +77,17 @@ func (lr *logReader) readLogs() {
 	go func() {
 		defer wg.Done()
 		slog.Debug("Log reader started for stdout.")
-		scanner := bufio.NewScanner(lr.stdout)
+		reader := bufio.NewReader(lr.stdout)
+		if lr.remote != nil {
+			pid, err := lr.remote.ReadPID(reader)
+			lr.mu.Lock()
+			if err != nil {
+				lr.lines = append(lr.lines, fmt.Sprintf("[ERROR: %v]", err))
+			}
+			lr.remotePID = pid
+			lr.mu.Unlock()
+		}
+		scanner := bufio.NewScanner(reader)
 		for scanner.Scan() {
 			got := scanner.Text()
 			slog.Debug("Got stdout line",
@@ -120,6 +136,21 @@ func (lr *logReader) readLogs() {
 	lr.mu.Unlock()
 }
 
+// stopRemote stops the process of a remote command in the container, which outlives its docker exec
+func (lr *logReader) stopRemote() {
+	lr.mu.Lock()
+	remote, pid, done := lr.remote, lr.remotePID, lr.done
+	lr.mu.Unlock()
+	if remote == nil || pid == 0 || done {
+		return
+	}
+	go func() {
+		if err := remote.Kill(pid); err != nil {
+			slog.Warn("Failed to stop the remote process of the log reader", slog.Any("error", err))
+		}
+	}()
+}
+
 // getNewLines returns any new log lines
 func (lr *logReader) getNewLines(lastIndex int) ([]string, int, bool) {
 	lr.mu.Lock()
@@ -141,6 +172,15 @@ type LogReaderManager struct {
 
 // streamLogsReal creates a command that starts log streaming
 func (lrm *LogReaderManager) streamLogsReal(cmd *exec.Cmd) tea.Cmd {
+	return lrm.startLogReader(cmd, nil)
+}
+
+// streamRemoteCommand streams the output of a command that runs in a container until it is stopped
+func (lrm *LogReaderManager) streamRemoteCommand(command *docker.RemoteCommand) tea.Cmd {
+	return lrm.startLogReader(docker.Execute(command.Args...), command)
+}
+
+func (lrm *LogReaderManager) startLogReader(cmd *exec.Cmd, remote *docker.RemoteCommand) tea.Cmd {
 	return func() tea.Msg {
 		lrm.stopLogReader()
 
@@ -151,7 +191,7 @@ func (lrm *LogReaderManager) streamLogsReal(cmd *exec.Cmd) tea.Cmd {
 		slog.Debug("Creating new log reader",
 			slog.String("cmd", cmd.String()))
 
-		lr, err := newLogReader(cmd)
+		lr, err := newLogReader(cmd, remote)
 		if err != nil {
 			slog.Info("Failed to create log reader",
 				slog.String("cmd", cmd.String()),
@@ -180,6 +220,7 @@ func (lrm *LogReaderManager) stopLogReader() {
 			}
 			// Don't wait here as it might block
 		}
+		lrm.activeLogReader.stopRemote()
 		lrm.activeLogReader = nil
 		lrm.lastLogIndex = 0 // Reset the index too
 	}
@@ -34,6 +34,17 @@ const (
 	CommandActionView
 	ComposeProjectActionView
 	HelperInjectorView
+	ProcessSignalView
+	FileSearchView
+	FileEditView
+	ContainerChangesView
+	FileDiffView
+	HelperListView
+	FileDiffTargetView
+	ImageHistoryView
+	ImageLayerView
+	ImageTransferView
+	ImageCleanupView
 )
 
 // UI Chrome offsets for different views
@@ -83,6 +94,28 @@ func (view ViewType) String() string {
 		return "Compose Project Actions"
 	case HelperInjectorView:
 		return "Helper Injection"
+	case ProcessSignalView:
+		return "Process Signal"
+	case FileSearchView:
+		return "File Search"
+	case FileEditView:
+		return "File Edit"
+	case ContainerChangesView:
+		return "Filesystem Changes"
+	case FileDiffView:
+		return "File Diff"
+	case HelperListView:
+		return "Injected Helpers"
+	case FileDiffTargetView:
+		return "Diff With"
+	case ImageHistoryView:
+		return "Image History"
+	case ImageLayerView:
+		return "Image Layer"
+	case ImageTransferView:
+		return "Image Transfer"
+	case ImageCleanupView:
+		return "Image Cleanup"
 	default:
 		return "Unknown View"
 	}
@@ -118,10 +151,24 @@ type Model struct {
 	networkListViewModel          NetworkListViewModel
 	statsViewModel                StatsViewModel
 	volumeListViewModel           VolumeListViewModel
+	processSignalViewModel        ProcessSignalViewModel
+	fileDiffTargetViewModel       FileDiffTargetViewModel
+	fileSearchViewModel           FileSearchViewModel
+	fileEditViewModel             FileEditViewModel
+	containerChangesViewModel     ContainerChangesViewModel
+	fileDiffViewModel             FileDiffViewModel
+	helperListViewModel           HelperListViewModel
+	imageHistoryViewModel         ImageHistoryViewModel
+	imageLayerViewModel           ImageLayerViewModel
+	imageTransferViewModel        ImageTransferViewModel
+	imageCleanupViewModel         ImageCleanupViewModel
 
 	// Error state
 	err error
 
+	// fileTransfer is the copy out of a container running in the background, or the last one
+	fileTransfer *fileTransfer
+
 	// Window dimensions
 	width  int
 	Height int
@@ -175,6 +222,28 @@ type Model struct {
 	helperInjectorHandlers          []KeyConfig
 	fileBrowserActionKeymap         map[string]KeyHandler
 	fileBrowserActionHandlers       []KeyConfig
+	processSignalKeymap             map[string]KeyHandler
+	processSignalHandlers           []KeyConfig
+	fileSearchKeymap                map[string]KeyHandler
+	fileSearchHandlers              []KeyConfig
+	fileEditKeymap                  map[string]KeyHandler
+	fileEditHandlers                []KeyConfig
+	containerChangesKeymap          map[string]KeyHandler
+	containerChangesHandlers        []KeyConfig
+	fileDiffKeymap                  map[string]KeyHandler
+	fileDiffHandlers                []KeyConfig
+	fileDiffTargetKeymap            map[string]KeyHandler
+	fileDiffTargetHandlers          []KeyConfig
+	helperListKeymap                map[string]KeyHandler
+	helperListHandlers              []KeyConfig
+	imageHistoryKeymap              map[string]KeyHandler
+	imageHistoryHandlers            []KeyConfig
+	imageLayerKeymap                map[string]KeyHandler
+	imageLayerHandlers              []KeyConfig
+	imageTransferKeymap             map[string]KeyHandler
+	imageTransferHandlers           []KeyConfig
+	imageCleanupKeymap              map[string]KeyHandler
+	imageCleanupHandlers            []KeyConfig
 
 	// Command-line mode state
 	commandViewModel CommandViewModel
@@ -242,9 +311,32 @@ func (m *Model) Init() tea.Cmd {
 			return RefreshMsg{}
 		},
 		tea.RequestWindowSize,
+		removeTemporaryContainers,
 	)
 }
 
+// removeTemporaryContainers removes the image and volume browser containers a previous run left behind
+func removeTemporaryContainers() tea.Msg {
+	if err := docker.RemoveTemporaryContainers(); err != nil {
+		slog.Warn("Failed to remove leftover temporary containers", slog.Any("error", err))
+	}
+	return nil
+}
+
+// sharedFileOperations returns the FileOperations that keeps track of injected helpers,
+// creating one that uses the docker CLI when the Docker API client is not available
+func (m *Model) sharedFileOperations() *docker.FileOperations {
+	if m.fileOperations == nil {
+		m.fileOperations = docker.NewFileOperations(nil)
+	}
+	return m.fileOperations
+}
+
+// quit removes the container of a browsed image or volume, then quits
+func (m *Model) quit() tea.Cmd {
+	return tea.Sequence(m.fileBrowserViewModel.closeTemporaryContainer(), tea.Quit)
+}
+
 func (m *Model) SwitchView(view ViewType) {
 	if view == m.currentView {
 		slog.Info("SwitchView called with the same view, ignoring",
@@ -317,6 +409,28 @@ func (m *Model) GetCurrentViewModel() interface{} {
 		return &m.helperInjectorViewModel
 	case FileBrowserActionView:
 		return &m.fileBrowserActionViewModel
+	case ProcessSignalView:
+		return &m.processSignalViewModel
+	case FileSearchView:
+		return &m.fileSearchViewModel
+	case FileEditView:
+		return &m.fileEditViewModel
+	case ContainerChangesView:
+		return &m.containerChangesViewModel
+	case FileDiffView:
+		return &m.fileDiffViewModel
+	case HelperListView:
+		return &m.helperListViewModel
+	case FileDiffTargetView:
+		return &m.fileDiffTargetViewModel
+	case ImageHistoryView:
+		return &m.imageHistoryViewModel
+	case ImageLayerView:
+		return &m.imageLayerViewModel
+	case ImageTransferView:
+		return &m.imageTransferViewModel
+	case ImageCleanupView:
+		return &m.imageCleanupViewModel
 	default:
 		panic("GetCurrentViewModel called with unknown view: " + m.currentView.String())
 	}
@@ -363,6 +477,28 @@ func (m *Model) GetViewKeyHandlers(view ViewType) []KeyConfig {
 		return m.helperInjectorHandlers
 	case FileBrowserActionView:
 		return m.fileBrowserActionHandlers
+	case ProcessSignalView:
+		return m.processSignalHandlers
+	case FileSearchView:
+		return m.fileSearchHandlers
+	case FileEditView:
+		return m.fileEditHandlers
+	case ContainerChangesView:
+		return m.containerChangesHandlers
+	case FileDiffView:
+		return m.fileDiffHandlers
+	case HelperListView:
+		return m.helperListHandlers
+	case FileDiffTargetView:
+		return m.fileDiffTargetHandlers
+	case ImageHistoryView:
+		return m.imageHistoryHandlers
+	case ImageLayerView:
+		return m.imageLayerHandlers
+	case ImageTransferView:
+		return m.imageTransferHandlers
+	case ImageCleanupView:
+		return m.imageCleanupHandlers
 	default:
 		return nil
 	}
@@ -414,6 +550,28 @@ func (m *Model) GetViewKeymap(view ViewType) map[string]KeyHandler {
 		return m.helperInjectorKeymap
 	case FileBrowserActionView:
 		return m.fileBrowserActionKeymap
+	case ProcessSignalView:
+		return m.processSignalKeymap
+	case FileSearchView:
+		return m.fileSearchKeymap
+	case FileEditView:
+		return m.fileEditKeymap
+	case ContainerChangesView:
+		return m.containerChangesKeymap
+	case FileDiffView:
+		return m.fileDiffKeymap
+	case HelperListView:
+		return m.helperListKeymap
+	case FileDiffTargetView:
+		return m.fileDiffTargetKeymap
+	case ImageHistoryView:
+		return m.imageHistoryKeymap
+	case ImageLayerView:
+		return m.imageLayerKeymap
+	case ImageTransferView:
+		return m.imageTransferKeymap
+	case ImageCleanupView:
+		return m.imageCleanupKeymap
 	default:
 		return nil
 	}
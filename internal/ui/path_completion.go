@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+// maxShownCompletions bounds how many candidates of an ambiguous completion are listed
+const maxShownCompletions = 8
+
+// pathCompletion is the tab completion of a path input, shared by the path bar of the file browser
+// and the local path inputs of its actions
+type pathCompletion struct {
+	// candidates holds the candidates of the last ambiguous completion
+	candidates []string
+}
+
+// canComplete reports whether the input can be completed. Only the end is completed;
+// completing in the middle would be surprising.
+func canComplete(input string, cursor int) bool {
+	return cursor == len(input)
+}
+
+// apply completes input with complete, which returns the completed input and the candidates when more than
+// one entry matches. It returns the new input and the cursor at its end.
+func (c *pathCompletion) apply(input string, cursor int, complete func(input string) (string, []string)) (string, int) {
+	if !canComplete(input, cursor) {
+		return input, cursor
+	}
+	input, c.candidates = complete(input)
+	return input, len(input)
+}
+
+// reset forgets the candidates once the input changes
+func (c *pathCompletion) reset() {
+	c.candidates = nil
+}
+
+// render lists the candidates of an ambiguous completion on one line, or returns "" when there are none
+func (c *pathCompletion) render() string {
+	if len(c.candidates) == 0 {
+		return ""
+	}
+	shown := c.candidates
+	if len(shown) > maxShownCompletions {
+		shown = shown[:maxShownCompletions]
+	}
+	line := strings.Join(shown, "  ")
+	if len(c.candidates) > maxShownCompletions {
+		line += fmt.Sprintf("  ... and %d more", len(c.candidates)-maxShownCompletions)
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(line)
+}
+
+// expandHomeDir replaces a leading "~" with the user's home directory
+func expandHomeDir(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, path[1:]), nil
+}
+
+// completeLocalPath completes the last element of input against the local filesystem.
+// It returns the completed input, and the candidate names when more than one entry matches.
+// Directories are completed with a trailing slash so the next Tab descends into them.
+func completeLocalPath(input string) (string, []string) {
+	dirPart := ""
+	prefix := input
+	if idx := strings.LastIndex(input, "/"); idx >= 0 {
+		dirPart = input[:idx+1]
+		prefix = input[idx+1:]
+	} else if input == "~" {
+		return "~/", nil
+	}
+
+	lookupDir := "."
+	if dirPart != "" {
+		expanded, err := expandHomeDir(dirPart)
+		if err != nil {
+			return input, nil
+		}
+		lookupDir = expanded
+	}
+
+	entries, err := os.ReadDir(lookupDir)
+	if err != nil {
+		return input, nil
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return completeName(dirPart, prefix, names, func(name string) bool {
+		info, err := os.Stat(filepath.Join(lookupDir, name))
+		return err == nil && info.IsDir()
+	})
+}
+
+// completeContainerPath completes prefix against the entries of a container directory.
+// Links known to point to directories are completed like directories.
+func completeContainerPath(dirPart, prefix string, files []models.ContainerFile, links map[string]docker.ResolvedPath) (string, []string) {
+	names := make([]string, 0, len(files))
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		if file.Name == "." || file.Name == ".." {
+			continue
+		}
+		names = append(names, file.Name)
+		dirs[file.Name] = file.IsDir || links[file.Name].IsDir
+	}
+	return completeName(dirPart, prefix, names, func(name string) bool { return dirs[name] })
+}
+
+// completeName completes prefix, the last element of a path after dirPart, against the names of that directory.
+// It returns the completed input, and the candidate names when more than one entry matches.
+// isDir is only asked about the matching names.
+func completeName(dirPart, prefix string, entries []string, isDir func(name string) bool) (string, []string) {
+	var names []string
+	for _, name := range entries {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		// Hide dotfiles unless the user asked for them
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".") {
+			continue
+		}
+		names = append(names, name)
+	}
+
+	switch len(names) {
+	case 0:
+		return dirPart + prefix, nil
+	case 1:
+		completed := dirPart + names[0]
+		if isDir(names[0]) {
+			completed += "/"
+		}
+		return completed, nil
+	}
+
+	sort.Strings(names)
+	candidates := make([]string, len(names))
+	for i, name := range names {
+		candidates[i] = name
+		if isDir(name) {
+			candidates[i] += "/"
+		}
+	}
+	return dirPart + longestCommonPrefix(names), candidates
+}
+
+// longestCommonPrefix returns the longest prefix shared by all strings
+func longestCommonPrefix(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	prefix := values[0]
+	for _, v := range values[1:] {
+		for !strings.HasPrefix(v, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteLocalPath(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "config"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "compose.yaml"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "compose.override.yaml"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), nil, 0644))
+
+	t.Run("unique match is completed", func(t *testing.T) {
+		completed, candidates := completeLocalPath(dir + "/RE")
+		assert.Equal(t, dir+"/README.md", completed)
+		assert.Nil(t, candidates)
+	})
+
+	t.Run("directory gets a trailing slash", func(t *testing.T) {
+		completed, candidates := completeLocalPath(dir + "/conf")
+		assert.Equal(t, dir+"/config/", completed)
+		assert.Nil(t, candidates)
+	})
+
+	t.Run("ambiguous match completes the common prefix", func(t *testing.T) {
+		completed, candidates := completeLocalPath(dir + "/co")
+		assert.Equal(t, dir+"/co", completed)
+		assert.Equal(t, []string{"compose.override.yaml", "compose.yaml", "config/"}, candidates)
+
+		completed, candidates = completeLocalPath(dir + "/com")
+		assert.Equal(t, dir+"/compose.", completed)
+		assert.Equal(t, []string{"compose.override.yaml", "compose.yaml"}, candidates)
+	})
+
+	t.Run("dotfiles only when asked for", func(t *testing.T) {
+		_, candidates := completeLocalPath(dir + "/")
+		assert.NotContains(t, candidates, ".env")
+
+		completed, _ := completeLocalPath(dir + "/.e")
+		assert.Equal(t, dir+"/.env", completed)
+	})
+
+	t.Run("no match leaves the input alone", func(t *testing.T) {
+		completed, candidates := completeLocalPath(dir + "/zzz")
+		assert.Equal(t, dir+"/zzz", completed)
+		assert.Nil(t, candidates)
+	})
+
+	t.Run("missing directory leaves the input alone", func(t *testing.T) {
+		completed, candidates := completeLocalPath(dir + "/missing/fi")
+		assert.Equal(t, dir+"/missing/fi", completed)
+		assert.Nil(t, candidates)
+	})
+}
+
+func TestExpandHomeDir(t *testing.T) {
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	expanded, err := expandHomeDir("~/Downloads/file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "Downloads/file.txt"), expanded)
+
+	expanded, err = expandHomeDir("/tmp/file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/file.txt", expanded)
+}
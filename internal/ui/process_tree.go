@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"sort"
+
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+// processRow is a single visible row in the top view.
+// In flat mode every process maps to one row with no prefix. In tree mode
+// the row carries the tree drawing prefix and the CPU/memory totals of the
+// whole subtree rooted at the process.
+type processRow struct {
+	process     models.Process
+	prefix      string
+	depth       int
+	hasChildren bool
+	collapsed   bool
+	// descendants is the number of processes below this one in the tree
+	descendants int
+	totalCPU    float64
+	totalMem    float64
+}
+
+// processNode is an intermediate node used while building the tree
+type processNode struct {
+	process     models.Process
+	children    []*processNode
+	totalCPU    float64
+	totalMem    float64
+	descendants int
+}
+
+// buildProcessTree arranges processes by PPID and flattens the result into
+// rows in display order. Siblings are ordered with less, which receives
+// copies of the processes whose CPU/memory are the subtree totals.
+// Children of PIDs in collapsed are omitted from the result.
+func buildProcessTree(processes []models.Process, less func(a, b *models.Process) bool, collapsed map[string]bool) []processRow {
+	nodes := make(map[string]*processNode, len(processes))
+	order := make([]*processNode, 0, len(processes))
+	for _, p := range processes {
+		if _, exists := nodes[p.PID]; exists {
+			continue
+		}
+		node := &processNode{process: p}
+		nodes[p.PID] = node
+		order = append(order, node)
+	}
+
+	var roots []*processNode
+	for _, node := range order {
+		parent, ok := nodes[node.process.PPID]
+		if !ok || parent == node {
+			roots = append(roots, node)
+			continue
+		}
+		parent.children = append(parent.children, node)
+	}
+
+	// A PPID loop would leave processes unreachable from any root.
+	// Treat the first member of such a cycle as a root so nothing disappears.
+	reachable := make(map[*processNode]bool, len(order))
+	var mark func(n *processNode)
+	mark = func(n *processNode) {
+		if reachable[n] {
+			return
+		}
+		reachable[n] = true
+		for _, c := range n.children {
+			mark(c)
+		}
+	}
+	for _, r := range roots {
+		mark(r)
+	}
+	for _, node := range order {
+		if !reachable[node] {
+			if parent, ok := nodes[node.process.PPID]; ok {
+				parent.children = removeProcessNode(parent.children, node)
+			}
+			roots = append(roots, node)
+			mark(node)
+		}
+	}
+
+	var aggregate func(n *processNode)
+	aggregate = func(n *processNode) {
+		n.totalCPU = n.process.CPUPerc
+		n.totalMem = n.process.MemPerc
+		for _, c := range n.children {
+			aggregate(c)
+			n.totalCPU += c.totalCPU
+			n.totalMem += c.totalMem
+			n.descendants += c.descendants + 1
+		}
+	}
+	for _, r := range roots {
+		aggregate(r)
+	}
+
+	sortNodes := func(list []*processNode) {
+		sort.SliceStable(list, func(i, j int) bool {
+			a := list[i].process
+			a.CPUPerc, a.MemPerc = list[i].totalCPU, list[i].totalMem
+			b := list[j].process
+			b.CPUPerc, b.MemPerc = list[j].totalCPU, list[j].totalMem
+			return less(&a, &b)
+		})
+	}
+
+	var rows []processRow
+	var walk func(n *processNode, depth int, indent string, last bool)
+	walk = func(n *processNode, depth int, indent string, last bool) {
+		prefix := ""
+		childIndent := ""
+		if depth > 0 {
+			if last {
+				prefix = indent + "└─ "
+				childIndent = indent + "   "
+			} else {
+				prefix = indent + "├─ "
+				childIndent = indent + "│  "
+			}
+		}
+
+		isCollapsed := collapsed[n.process.PID] && len(n.children) > 0
+		rows = append(rows, processRow{
+			process:     n.process,
+			prefix:      prefix,
+			depth:       depth,
+			hasChildren: len(n.children) > 0,
+			collapsed:   isCollapsed,
+			descendants: n.descendants,
+			totalCPU:    n.totalCPU,
+			totalMem:    n.totalMem,
+		})
+
+		if isCollapsed {
+			return
+		}
+		sortNodes(n.children)
+		for i, c := range n.children {
+			walk(c, depth+1, childIndent, i == len(n.children)-1)
+		}
+	}
+
+	sortNodes(roots)
+	for i, r := range roots {
+		walk(r, 0, "", i == len(roots)-1)
+	}
+	return rows
+}
+
+func removeProcessNode(list []*processNode, target *processNode) []*processNode {
+	for i, n := range list {
+		if n == target {
+			return append(list[:i], list[i+1:]...)
+		}
+	}
+	return list
+}
@@ -95,6 +95,63 @@ func (m *SearchViewModel) PerformSearch(model *Model, logs []string, updateScrol
 	}
 }
 
+// findSearchMatches finds all search match positions in a line
+func (m *SearchViewModel) findSearchMatches(line string) [][]int {
+	var matches [][]int
+
+	if m.searchRegex {
+		pattern := m.searchText
+		if m.searchIgnoreCase {
+			pattern = "(?i)" + pattern
+		}
+		if re, err := regexp.Compile(pattern); err == nil {
+			matches = re.FindAllStringIndex(line, -1)
+		}
+	} else {
+		searchStr := m.searchText
+		lineToSearch := line
+
+		if m.searchIgnoreCase {
+			searchStr = strings.ToLower(searchStr)
+			lineToSearch = strings.ToLower(lineToSearch)
+		}
+
+		start := 0
+		for {
+			idx := strings.Index(lineToSearch[start:], searchStr)
+			if idx == -1 {
+				break
+			}
+			realIdx := start + idx
+			matches = append(matches, []int{realIdx, realIdx + len(searchStr)})
+			start = realIdx + 1
+		}
+	}
+
+	return matches
+}
+
+// renderSearchMatches highlights the search matches in a plain line
+func (m *SearchViewModel) renderSearchMatches(line string, matches [][]int) string {
+	var result strings.Builder
+	lastEnd := 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+		if start < lastEnd || start == end {
+			continue
+		}
+		result.WriteString(line[lastEnd:start])
+		result.WriteString(searchHighlightStyle.Render(line[start:end]))
+		lastEnd = end
+	}
+	result.WriteString(line[lastEnd:])
+	return result.String()
+}
+
+var searchHighlightStyle = lipgloss.NewStyle().
+	Background(lipgloss.Color("226")).
+	Foreground(lipgloss.Color("235"))
+
 func (m *SearchViewModel) InputEscape() {
 	m.searchMode = false
 	m.searchText = ""
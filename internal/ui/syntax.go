@@ -0,0 +1,389 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+)
+
+var (
+	syntaxCommentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	syntaxStringStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("76"))
+	syntaxNumberStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("141"))
+	syntaxKeywordStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	syntaxKeyStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("33")).Bold(true)
+)
+
+// syntaxLanguage describes just enough of a language to color it line by line
+type syntaxLanguage struct {
+	name string
+	// lineComments start a comment that runs to the end of the line.
+	// "#" only counts at the start of a line or after whitespace.
+	lineComments []string
+	// blockComment is the start and end of a comment that may span lines
+	blockComment [2]string
+	// quotes are the characters that delimit strings on a single line
+	quotes string
+	// multiLineQuotes delimit strings that may span lines
+	multiLineQuotes []string
+	keywords        map[string]bool
+	// key returns the end of a key at the start of the line, or -1.
+	// Keys are config keys, section headers or directives.
+	key func(line string) int
+}
+
+func keywordSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+var (
+	syntaxYAML = &syntaxLanguage{
+		name:         "yaml",
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+		keywords:     keywordSet("true", "false", "null", "yes", "no", "on", "off"),
+		key:          yamlKey,
+	}
+	syntaxJSON = &syntaxLanguage{
+		name:     "json",
+		quotes:   `"`,
+		keywords: keywordSet("true", "false", "null"),
+		key:      jsonKey,
+	}
+	syntaxTOML = &syntaxLanguage{
+		name:            "toml",
+		lineComments:    []string{"#"},
+		quotes:          `"'`,
+		multiLineQuotes: []string{`"""`, `'''`},
+		keywords:        keywordSet("true", "false"),
+		key:             tomlKey,
+	}
+	syntaxNginx = &syntaxLanguage{
+		name:         "nginx",
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+		keywords:     keywordSet("on", "off"),
+		key:          firstWord,
+	}
+	syntaxShell = &syntaxLanguage{
+		name:         "shell",
+		lineComments: []string{"#"},
+		quotes:       "\"'`",
+		keywords: keywordSet("if", "then", "else", "elif", "fi", "case", "esac", "for", "while", "until",
+			"do", "done", "in", "function", "select", "return", "exit", "export", "local", "readonly",
+			"set", "unset", "shift", "source", "exec", "trap"),
+	}
+	syntaxDockerfile = &syntaxLanguage{
+		name:         "dockerfile",
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+		key:          dockerfileInstruction,
+	}
+	syntaxPython = &syntaxLanguage{
+		name:            "python",
+		lineComments:    []string{"#"},
+		quotes:          `"'`,
+		multiLineQuotes: []string{`"""`, `'''`},
+		keywords: keywordSet("False", "None", "True", "and", "as", "assert", "async", "await", "break",
+			"class", "continue", "def", "del", "elif", "else", "except", "finally", "for", "from", "global",
+			"if", "import", "in", "is", "lambda", "nonlocal", "not", "or", "pass", "raise", "return", "try",
+			"while", "with", "yield", "self"),
+	}
+	syntaxGo = &syntaxLanguage{
+		name:            "go",
+		lineComments:    []string{"//"},
+		blockComment:    [2]string{"/*", "*/"},
+		quotes:          `"'`,
+		multiLineQuotes: []string{"`"},
+		keywords: keywordSet("break", "case", "chan", "const", "continue", "default", "defer", "else",
+			"fallthrough", "for", "func", "go", "goto", "if", "import", "interface", "map", "package", "range",
+			"return", "select", "struct", "switch", "type", "var", "true", "false", "nil", "iota"),
+	}
+)
+
+// detectSyntax picks the language of a file by its name, its shebang or, for JSON, its content.
+// It returns nil for files that are shown without colors.
+func detectSyntax(filePath string, data []byte) *syntaxLanguage {
+	base := strings.ToLower(path.Base(filePath))
+	switch path.Ext(base) {
+	case ".yaml", ".yml":
+		return syntaxYAML
+	case ".json":
+		return syntaxJSON
+	case ".toml":
+		return syntaxTOML
+	case ".sh", ".bash", ".zsh", ".ksh":
+		return syntaxShell
+	case ".py":
+		return syntaxPython
+	case ".go":
+		return syntaxGo
+	case ".dockerfile":
+		return syntaxDockerfile
+	case ".conf":
+		if base == "nginx.conf" || strings.Contains(filePath, "/nginx/") {
+			return syntaxNginx
+		}
+	}
+	switch {
+	case base == "dockerfile" || base == "containerfile" || strings.HasPrefix(base, "dockerfile."):
+		return syntaxDockerfile
+	case base == ".bashrc" || base == ".profile" || base == ".bash_profile" || base == ".zshrc":
+		return syntaxShell
+	}
+
+	if bytes.HasPrefix(data, []byte("#!")) {
+		shebang, _, _ := bytes.Cut(data, []byte("\n"))
+		fields := strings.Fields(string(shebang[2:]))
+		if len(fields) > 0 {
+			interpreter := path.Base(fields[0])
+			if interpreter == "env" && len(fields) > 1 {
+				interpreter = fields[len(fields)-1]
+			}
+			switch {
+			case strings.HasPrefix(interpreter, "python"):
+				return syntaxPython
+			case interpreter == "sh" || interpreter == "bash" || interpreter == "zsh" ||
+				interpreter == "ash" || interpreter == "dash" || interpreter == "ksh":
+				return syntaxShell
+			}
+		}
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+		return syntaxJSON
+	}
+	return nil
+}
+
+// syntaxStates returns the open multi-line construct at the start of every line,
+// so that any line can be colored without the lines above it.
+func (l *syntaxLanguage) syntaxStates(lines []string) []string {
+	if l.blockComment[0] == "" && len(l.multiLineQuotes) == 0 {
+		return nil
+	}
+	states := make([]string, len(lines))
+	state := ""
+	for i, line := range lines {
+		states[i] = state
+		_, state = l.highlight(line, state)
+	}
+	return states
+}
+
+// highlight colors a line. state is the closing delimiter of a comment or string
+// that is still open from the lines above; the returned state is the one after the line.
+func (l *syntaxLanguage) highlight(line, state string) (string, string) {
+	var out, plain strings.Builder
+	flush := func() {
+		out.WriteString(plain.String())
+		plain.Reset()
+	}
+	emit := func(style lipgloss.Style, text string) {
+		flush()
+		out.WriteString(style.Render(text))
+	}
+
+	i := 0
+	if state != "" {
+		style := syntaxStringStyle
+		if state == l.blockComment[1] {
+			style = syntaxCommentStyle
+		}
+		end := strings.Index(line, state)
+		if end < 0 {
+			return style.Render(line), state
+		}
+		i = end + len(state)
+		emit(style, line[:i])
+		state = ""
+	} else if l.key != nil {
+		if end := l.key(line); end > 0 {
+			indent := min(len(line)-len(strings.TrimLeft(line, " \t-")), end)
+			plain.WriteString(line[:indent])
+			emit(syntaxKeyStyle, line[indent:end])
+			i = end
+		}
+	}
+
+scan:
+	for i < len(line) {
+		rest := line[i:]
+		for _, prefix := range l.lineComments {
+			if strings.HasPrefix(rest, prefix) && (prefix != "#" || i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+				emit(syntaxCommentStyle, rest)
+				break scan
+			}
+		}
+		if l.blockComment[0] != "" && strings.HasPrefix(rest, l.blockComment[0]) {
+			end := strings.Index(rest[len(l.blockComment[0]):], l.blockComment[1])
+			if end < 0 {
+				emit(syntaxCommentStyle, rest)
+				return out.String(), l.blockComment[1]
+			}
+			n := len(l.blockComment[0]) + end + len(l.blockComment[1])
+			emit(syntaxCommentStyle, rest[:n])
+			i += n
+			continue
+		}
+		for _, quote := range l.multiLineQuotes {
+			if strings.HasPrefix(rest, quote) {
+				end := strings.Index(rest[len(quote):], quote)
+				if end < 0 {
+					emit(syntaxStringStyle, rest)
+					return out.String(), quote
+				}
+				n := len(quote) + end + len(quote)
+				emit(syntaxStringStyle, rest[:n])
+				i += n
+				continue scan
+			}
+		}
+
+		c := line[i]
+		switch {
+		case strings.IndexByte(l.quotes, c) >= 0:
+			n := quotedLength(rest)
+			emit(syntaxStringStyle, rest[:n])
+			i += n
+		case isDigit(c) && (i == 0 || !isWordChar(line[i-1])):
+			n := 1
+			for n < len(rest) && (isWordChar(rest[n]) || rest[n] == '.') {
+				n++
+			}
+			emit(syntaxNumberStyle, rest[:n])
+			i += n
+		case isWordChar(c):
+			n := 1
+			for n < len(rest) && isWordChar(rest[n]) {
+				n++
+			}
+			if l.keywords[rest[:n]] {
+				emit(syntaxKeywordStyle, rest[:n])
+			} else {
+				plain.WriteString(rest[:n])
+			}
+			i += n
+		default:
+			plain.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+	return out.String(), state
+}
+
+// quotedLength returns the length of the quoted string at the start of s,
+// or the rest of s when the quote is not closed.
+func quotedLength(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// yamlKey finds "key:" at the start of a line, also in list items ("- key: value")
+func yamlKey(line string) int {
+	start := len(line) - len(strings.TrimLeft(line, " \t-"))
+	rest := line[start:]
+	if rest == "" || rest[0] == '#' {
+		return -1
+	}
+	if rest[0] == '"' || rest[0] == '\'' {
+		n := quotedLength(rest)
+		if strings.HasPrefix(rest[n:], ":") {
+			return start + n
+		}
+		return -1
+	}
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case ':':
+			if i > 0 && (i+1 == len(rest) || rest[i+1] == ' ' || rest[i+1] == '\t') {
+				return start + i
+			}
+		case ' ', '\t':
+			if i+1 < len(rest) && rest[i+1] == '#' {
+				return -1
+			}
+		case '"', '\'', '{', '[':
+			return -1
+		}
+	}
+	return -1
+}
+
+// jsonKey finds a quoted key followed by a colon
+func jsonKey(line string) int {
+	start := len(line) - len(strings.TrimLeft(line, " \t"))
+	rest := line[start:]
+	if !strings.HasPrefix(rest, `"`) {
+		return -1
+	}
+	n := quotedLength(rest)
+	if strings.HasPrefix(strings.TrimLeft(rest[n:], " \t"), ":") {
+		return start + n
+	}
+	return -1
+}
+
+// tomlKey finds section headers and "key =" at the start of a line
+func tomlKey(line string) int {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+		return strings.LastIndex(line, "]") + 1
+	}
+	start := len(line) - len(strings.TrimLeft(line, " \t"))
+	eq := strings.Index(line, "=")
+	if eq <= start || strings.ContainsAny(line[start:eq], "#[") {
+		return -1
+	}
+	return start + len(strings.TrimRight(line[start:eq], " \t"))
+}
+
+// firstWord finds the directive at the start of a line, like in nginx.conf
+func firstWord(line string) int {
+	start := len(line) - len(strings.TrimLeft(line, " \t"))
+	end := start
+	for end < len(line) && (isWordChar(line[end]) || line[end] == '-') {
+		end++
+	}
+	if end == start {
+		return -1
+	}
+	return end
+}
+
+var dockerfileInstructions = keywordSet("FROM", "RUN", "CMD", "LABEL", "MAINTAINER", "EXPOSE", "ENV", "ADD", "COPY",
+	"ENTRYPOINT", "VOLUME", "USER", "WORKDIR", "ARG", "ONBUILD", "STOPSIGNAL", "HEALTHCHECK", "SHELL")
+
+// dockerfileInstruction finds the instruction at the start of a line in any case
+func dockerfileInstruction(line string) int {
+	end := firstWord(line)
+	if end < 0 || !dockerfileInstructions[strings.ToUpper(strings.TrimSpace(line[:end]))] {
+		return -1
+	}
+	return end
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectSyntax(t *testing.T) {
+	tests := []struct {
+		path string
+		data string
+		want *syntaxLanguage
+	}{
+		{"/app/config.yaml", "", syntaxYAML},
+		{"/app/compose.YML", "", syntaxYAML},
+		{"/app/package.json", "", syntaxJSON},
+		{"/app/pyproject.toml", "", syntaxTOML},
+		{"/etc/nginx/nginx.conf", "", syntaxNginx},
+		{"/etc/nginx/conf.d/default.conf", "", syntaxNginx},
+		{"/etc/resolv.conf", "", nil},
+		{"/entrypoint.sh", "", syntaxShell},
+		{"/root/.bashrc", "", syntaxShell},
+		{"/src/Dockerfile", "", syntaxDockerfile},
+		{"/src/Dockerfile.dev", "", syntaxDockerfile},
+		{"/app/main.py", "", syntaxPython},
+		{"/src/main.go", "", syntaxGo},
+		{"/usr/local/bin/start", "#!/bin/sh\nexec app\n", syntaxShell},
+		{"/usr/local/bin/manage", "#!/usr/bin/env python3\nprint(1)\n", syntaxPython},
+		{"/var/lib/app/state", `{"a":[1,2]}`, syntaxJSON},
+		{"/var/lib/app/state", `{"a":`, nil},
+		{"/etc/hostname", "web\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectSyntax(tt.path, []byte(tt.data)))
+		})
+	}
+}
+
+func TestSyntaxHighlight(t *testing.T) {
+	lines := []string{
+		"server:",
+		"  port: 8080 # default",
+		`  - name: "web"`,
+		"url: http://example.com#top",
+	}
+	for _, line := range lines {
+		highlighted, state := syntaxYAML.highlight(line, "")
+		assert.Equal(t, line, ansi.Strip(highlighted), "highlighting keeps the text")
+		assert.Empty(t, state)
+	}
+
+	highlighted, _ := syntaxYAML.highlight("  port: 8080 # default", "")
+	assert.Contains(t, highlighted, syntaxKeyStyle.Render("port"))
+	assert.Contains(t, highlighted, syntaxNumberStyle.Render("8080"))
+	assert.Contains(t, highlighted, syntaxCommentStyle.Render("# default"))
+
+	// "#" inside a word is not a comment
+	highlighted, _ = syntaxYAML.highlight("url: http://example.com#top", "")
+	assert.NotContains(t, highlighted, syntaxCommentStyle.Render("#top"))
+
+	highlighted, _ = syntaxJSON.highlight(`  "enabled": true,`, "")
+	assert.Contains(t, highlighted, syntaxKeyStyle.Render(`"enabled"`))
+	assert.Contains(t, highlighted, syntaxKeywordStyle.Render("true"))
+
+	highlighted, _ = syntaxDockerfile.highlight("run apt-get update", "")
+	assert.Contains(t, highlighted, syntaxKeyStyle.Render("run"))
+}
+
+func TestSyntaxStates(t *testing.T) {
+	lines := strings.Split("package main\n/* start\nstill comment\nend */ var x = `raw\nstring`", "\n")
+	states := syntaxGo.syntaxStates(lines)
+	assert.Equal(t, []string{"", "", "*/", "*/", "`"}, states)
+
+	highlighted, state := syntaxGo.highlight(lines[2], states[2])
+	assert.Equal(t, syntaxCommentStyle.Render("still comment"), highlighted)
+	assert.Equal(t, "*/", state)
+
+	highlighted, _ = syntaxGo.highlight(lines[3], states[3])
+	assert.Contains(t, highlighted, syntaxCommentStyle.Render("end */"))
+	assert.Contains(t, highlighted, syntaxKeywordStyle.Render("var"))
+
+	assert.Equal(t, []string{"", `"""`, ""}, syntaxPython.syntaxStates([]string{`doc = """a`, `b"""`, "x = 1"}))
+
+	// Languages without multi-line constructs need no states
+	assert.Nil(t, syntaxYAML.syntaxStates(lines))
+}
@@ -94,6 +94,34 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.helperInjectorViewModel.Complete(msg.success, msg.err)
 		return m, nil
 
+	case helperCommandReadyMsg:
+		return m, m.handleHelperCommandReady(msg)
+
+	// Search results keep streaming while a hit is opened in another view
+	case fileSearchStartedMsg:
+		return m, m.fileSearchViewModel.HandleStarted(m, msg)
+
+	case fileSearchOutputMsg:
+		return m, m.fileSearchViewModel.HandleOutput(m, msg)
+
+	case fileEditFetchedMsg:
+		return m, m.fileEditViewModel.HandleFetched(m, msg)
+
+	case fileEditorExitedMsg:
+		return m, m.fileEditViewModel.HandleEditorExited(m, msg)
+
+	case fileEditWrittenMsg:
+		return m, m.fileEditViewModel.HandleWritten(m, msg)
+
+	case fileTransferTickMsg:
+		return m, m.handleFileTransferTick(msg)
+
+	case fileTransferExpiredMsg:
+		if m.fileTransfer == msg.transfer {
+			m.fileTransfer = nil
+		}
+		return m, nil
+
 	case RefreshMsg:
 		// Handle refresh based on current view
 		m.loading = true
@@ -124,8 +152,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case FileBrowserView:
 			return m, m.fileBrowserViewModel.DoLoad(m)
 		case FileContentView:
-			// File content doesn't need refresh, it's static
-			return m, nil
+			return m, m.fileContentViewModel.Reload(m)
 		case InspectView:
 			// Inspect view doesn't need refresh, it's static
 			return m, nil
@@ -141,6 +168,24 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case ComposeProjectActionView:
 			// Action view doesn't need refresh
 			return m, nil
+		case ProcessSignalView, FileDiffTargetView:
+			// Menus don't need refresh
+			return m, nil
+		case FileSearchView:
+			m.loading = false
+			return m, m.fileSearchViewModel.HandleRefresh(m)
+		case ContainerChangesView:
+			return m, m.containerChangesViewModel.DoLoad(m)
+		case HelperListView:
+			return m, m.helperListViewModel.DoLoad(m)
+		case ImageHistoryView:
+			return m, m.imageHistoryViewModel.DoLoad(m)
+		case ImageCleanupView:
+			return m, m.imageCleanupViewModel.DoLoad(m)
+		case ImageLayerView:
+			// The layer was read once with docker save
+			m.loading = false
+			return m, nil
 		default:
 			m.loading = false
 			return m, nil
@@ -207,16 +252,50 @@ func (m *Model) handleKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// Handle the confirmation before a large file is read completely
+	if m.currentView == FileContentView && m.fileContentViewModel.confirmFullLoad {
+		switch msg.String() {
+		case "y", "Y":
+			return m, m.fileContentViewModel.HandleFullLoadConfirmation(m, true)
+		case "n", "N", "esc":
+			return m, m.fileContentViewModel.HandleFullLoadConfirmation(m, false)
+		default:
+			return m, nil
+		}
+	}
+
 	// Handle input mode in FileBrowserActionView
 	if m.currentView == FileBrowserActionView && m.fileBrowserActionViewModel.inputMode {
 		return m.fileBrowserActionViewModel.HandleInput(m, msg)
 	}
 
+	// Handle the path bar of the file browser
+	if m.currentView == FileBrowserView && m.fileBrowserViewModel.pathBar.active {
+		return m.fileBrowserViewModel.HandlePathInput(m, msg)
+	}
+
+	// Handle the reference prompt of the image list
+	if m.currentView == ImageListView && m.imageListViewModel.prompt.active {
+		return m.imageListViewModel.HandlePromptInput(m, msg)
+	}
+
+	// Handle the confirmation of a prune in the image cleanup
+	if m.currentView == ImageCleanupView && m.imageCleanupViewModel.preview != nil {
+		return m.imageCleanupViewModel.HandlePreviewKey(m, msg)
+	}
+
+	// Handle the file search form
+	if m.currentView == FileSearchView && m.fileSearchViewModel.formActive {
+		return m.fileSearchViewModel.HandleFormInput(m, msg)
+	}
+
 	// Handle search mode
 	if m.currentView == LogView && m.logViewModel.searchMode {
 		return m.handleSearchMode(msg, &m.logViewModel.SearchViewModel)
 	} else if m.currentView == InspectView && m.inspectViewModel.searchMode {
 		return m.handleSearchMode(msg, &m.inspectViewModel.SearchViewModel)
+	} else if m.currentView == FileContentView && m.fileContentViewModel.searchMode {
+		return m.handleSearchMode(msg, &m.fileContentViewModel.SearchViewModel)
 	}
 
 	// Handle container search mode
@@ -267,6 +346,8 @@ func (m *Model) handleSearchMode(msg tea.KeyPressMsg, searchViewModel *SearchVie
 			m.logViewModel.PerformSearch(m, m.logViewModel.logs, func(scrollY int) { m.logViewModel.logScrollY = scrollY })
 		case InspectView:
 			m.inspectViewModel.PerformSearch(m, strings.Split(m.inspectViewModel.inspectContent, "\n"), func(scrollY int) { m.inspectViewModel.inspectScrollY = scrollY })
+		case FileContentView:
+			m.fileContentViewModel.PerformSearch(m, m.fileContentViewModel.contentLines(), func(scrollY int) { m.fileContentViewModel.scrollY = scrollY })
 		default:
 			panic("unhandled default case")
 		}
@@ -328,7 +409,7 @@ func (m *Model) handleQuitConfirmation(msg tea.KeyPressMsg) (tea.Model, tea.Cmd)
 	case "y", "Y":
 		// Confirm quit
 		m.quitConfirmation = false
-		return m, tea.Quit
+		return m, m.quit()
 	case "n", "N", "esc":
 		// Cancel quit
 		m.quitConfirmation = false
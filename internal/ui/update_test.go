@@ -433,6 +433,17 @@ func TestQuitBehaviorInDifferentViews(t *testing.T) {
 	assert.Nil(t, cmd)
 }
 
+func TestQuitStopsVolumeBrowser(t *testing.T) {
+	model := Model{currentView: FileBrowserView, quitConfirmation: true}
+	model.fileBrowserViewModel.volumeName = "data"
+	model.fileBrowserViewModel.browsingContainer = docker.NewContainer("browser123", "volume data", "volume data", "running")
+
+	_, cmd := model.handleQuitConfirmation(newKeyPress("y"))
+	assert.NotNil(t, cmd)
+	assert.False(t, model.quitConfirmation)
+	assert.Empty(t, model.fileBrowserViewModel.volumeName, "the browser container is removed before quitting")
+}
+
 func TestFileBrowserParentDirectory(t *testing.T) {
 	// Test 'u' key to go to parent directory
 	dockerClient := docker.NewClient()
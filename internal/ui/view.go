@@ -144,6 +144,7 @@ func (m *Model) viewNavigationHeader() string {
 	navItems = append(navItems, createNavItem("4", "Networks", NetworkListView))
 	navItems = append(navItems, createNavItem("5", "Volumes", VolumeListView))
 	navItems = append(navItems, createNavItem("6", "Stats", StatsView))
+	navItems = append(navItems, createNavItem("7", "Helpers", HelperListView))
 
 	// Add toggle hint
 	toggleHint := helpStyle.Render("[H]ide navbar")
@@ -180,6 +181,7 @@ func (m *Model) getActiveNavigationView() ViewType {
 		NetworkListView,
 		VolumeListView,
 		StatsView,
+		HelperListView,
 	}
 
 	// If current view is a main nav view, return it
@@ -231,7 +233,7 @@ func (m *Model) viewTitle() string {
 	case TopView:
 		return m.topViewModel.Title()
 	case StatsView:
-		return "Stats"
+		return m.statsViewModel.Title()
 	case ComposeProjectListView:
 		return "Docker Compose Projects"
 	case DockerContainerListView:
@@ -263,6 +265,28 @@ func (m *Model) viewTitle() string {
 		return "Select Project Action"
 	case HelperInjectorView:
 		return "Helper Injection"
+	case ProcessSignalView:
+		return "Send Signal"
+	case FileSearchView:
+		return m.fileSearchViewModel.Title()
+	case FileEditView:
+		return m.fileEditViewModel.Title()
+	case ContainerChangesView:
+		return m.containerChangesViewModel.Title()
+	case FileDiffView:
+		return m.fileDiffViewModel.Title()
+	case HelperListView:
+		return m.helperListViewModel.Title()
+	case FileDiffTargetView:
+		return "Diff With"
+	case ImageHistoryView:
+		return m.imageHistoryViewModel.Title()
+	case ImageLayerView:
+		return m.imageLayerViewModel.Title()
+	case ImageTransferView:
+		return m.imageTransferViewModel.Title()
+	case ImageCleanupView:
+		return m.imageCleanupViewModel.Title()
 	default:
 		return "Unknown View"
 	}
@@ -279,7 +303,7 @@ func (m *Model) viewBody(availableHeight int) string {
 	}
 
 	// Handle error state
-	if m.err != nil && m.currentView != LogView && m.currentView != FileContentView {
+	if m.err != nil && m.currentView != LogView && m.currentView != FileContentView && m.currentView != FileEditView {
 		return "\n" + errorStyle.Render(fmt.Sprintf("Error: %v", m.err))
 	}
 
@@ -322,6 +346,28 @@ func (m *Model) viewBody(availableHeight int) string {
 		return m.composeProjectActionViewModel.render(m)
 	case HelperInjectorView:
 		return m.helperInjectorViewModel.render(m)
+	case ProcessSignalView:
+		return m.processSignalViewModel.render(m)
+	case FileSearchView:
+		return m.fileSearchViewModel.render(m, availableHeight)
+	case FileEditView:
+		return m.fileEditViewModel.render(m, availableHeight)
+	case ContainerChangesView:
+		return m.containerChangesViewModel.render(m, availableHeight)
+	case FileDiffView:
+		return m.fileDiffViewModel.render(m, availableHeight)
+	case HelperListView:
+		return m.helperListViewModel.render(m, availableHeight)
+	case FileDiffTargetView:
+		return m.fileDiffTargetViewModel.render(m)
+	case ImageHistoryView:
+		return m.imageHistoryViewModel.render(m, availableHeight)
+	case ImageLayerView:
+		return m.imageLayerViewModel.render(m, availableHeight)
+	case ImageTransferView:
+		return m.imageTransferViewModel.render(m, availableHeight)
+	case ImageCleanupView:
+		return m.imageCleanupViewModel.render(m, availableHeight)
 	default:
 		return "Unknown view"
 	}
@@ -343,6 +389,8 @@ func (m *Model) viewFooter() string {
 
 	if m.currentView == InspectView && m.inspectViewModel.searchMode {
 		return m.inspectViewModel.RenderSearchCmdLine()
+	} else if m.currentView == FileContentView && m.fileContentViewModel.searchMode {
+		return m.fileContentViewModel.RenderSearchCmdLine()
 	} else if m.commandViewModel.commandMode {
 		return m.commandViewModel.RenderCmdLine()
 	} else if m.currentView == HelpView {
@@ -367,6 +415,9 @@ func (m *Model) viewFooter() string {
 		if m.navbarHidden {
 			helpText += " | Press H to show navbar"
 		}
+		if m.fileTransfer != nil {
+			helpText = m.fileTransfer.status() + " | " + helpText
+		}
 		return helpStyle.Render(helpText)
 	}
 }
@@ -68,6 +68,17 @@ func (m *CommandActionViewModel) Initialize(container *docker.Container) {
 		},
 	})
 
+	m.actions = append(m.actions, CommandAction{
+		Key:         "C",
+		Name:        "Filesystem Changes",
+		Description: "Files changed since the container was created",
+		Aggressive:  false,
+		Handler: func(model *Model, c *docker.Container) tea.Cmd {
+			_, cmd := model.CmdContainerChanges(tea.KeyPressMsg{})
+			return cmd
+		},
+	})
+
 	m.actions = append(m.actions, CommandAction{
 		Key:         "H",
 		Name:        "Inject Helper",
@@ -135,6 +146,47 @@ func (m *CommandActionViewModel) Initialize(container *docker.Container) {
 				return cmd
 			},
 		})
+
+		// Inspection tools that work without a shell through the injected helper
+		m.actions = append(m.actions, CommandAction{
+			Key:         "E",
+			Name:        "Environment",
+			Description: "Show the environment of PID 1 (via helper)",
+			Aggressive:  false,
+			Handler: func(model *Model, c *docker.Container) tea.Cmd {
+				return model.runHelperTool(c, "env", "1")
+			},
+		})
+
+		m.actions = append(m.actions, CommandAction{
+			Key:         "T",
+			Name:        "Processes",
+			Description: "List processes from /proc (via helper)",
+			Aggressive:  false,
+			Handler: func(model *Model, c *docker.Container) tea.Cmd {
+				return model.runHelperTool(c, "ps")
+			},
+		})
+
+		m.actions = append(m.actions, CommandAction{
+			Key:         "N",
+			Name:        "Network Connections",
+			Description: "List sockets and their processes (via helper)",
+			Aggressive:  false,
+			Handler: func(model *Model, c *docker.Container) tea.Cmd {
+				return model.runHelperTool(c, "netstat")
+			},
+		})
+
+		m.actions = append(m.actions, CommandAction{
+			Key:         "X",
+			Name:        "Eject Helper",
+			Description: "Remove the injected helper binary",
+			Aggressive:  true,
+			Handler: func(model *Model, c *docker.Container) tea.Cmd {
+				return model.ejectHelper(c)
+			},
+		})
 	} else if container.GetState() == "paused" {
 		m.actions = append(m.actions, CommandAction{
 			Key:         "P",
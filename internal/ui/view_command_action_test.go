@@ -17,18 +17,23 @@ func TestCommandActionViewModel_Initialize(t *testing.T) {
 		expectedActions []string
 	}{
 		{
-			name:           "running container shows stop/restart/kill/pause actions",
+			name:           "running container shows stop/restart/kill/pause and helper tool actions",
 			containerState: "running",
 			expectedActions: []string{
 				"View Logs",
 				"Inspect",
 				"Browse Files",
+				"Filesystem Changes",
 				"Inject Helper",
 				"Execute Shell",
 				"Stop",
 				"Restart",
 				"Kill",
 				"Pause",
+				"Environment",
+				"Processes",
+				"Network Connections",
+				"Eject Helper",
 			},
 		},
 		{
@@ -38,6 +43,7 @@ func TestCommandActionViewModel_Initialize(t *testing.T) {
 				"View Logs",
 				"Inspect",
 				"Browse Files",
+				"Filesystem Changes",
 				"Inject Helper",
 				"Execute Shell",
 				"Unpause",
@@ -50,6 +56,7 @@ func TestCommandActionViewModel_Initialize(t *testing.T) {
 				"View Logs",
 				"Inspect",
 				"Browse Files",
+				"Filesystem Changes",
 				"Inject Helper",
 				"Execute Shell",
 				"Start",
@@ -63,6 +70,7 @@ func TestCommandActionViewModel_Initialize(t *testing.T) {
 				"View Logs",
 				"Inspect",
 				"Browse Files",
+				"Filesystem Changes",
 				"Inject Helper",
 				"Execute Shell",
 				"Start",
@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+// containerChangesLoadedMsg contains the output of docker diff
+type containerChangesLoadedMsg struct {
+	changes []models.ContainerChange
+	err     error
+}
+
+// containerChangeDiffMsg contains the diff of a changed file against the image
+type containerChangeDiffMsg struct {
+	path  string
+	lines []string
+	err   error
+}
+
+var (
+	changeAddedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	changeChangedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
+	changeDeletedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+// ContainerChangesViewModel shows what a container changed relative to its image as a tree
+type ContainerChangesViewModel struct {
+	changeTreeView
+	container *docker.Container
+	changes   []models.ContainerChange
+}
+
+// Load shows the filesystem changes of the container
+func (m *ContainerChangesViewModel) Load(model *Model, container *docker.Container) tea.Cmd {
+	m.container = container
+	m.changes = nil
+	m.resetTree()
+	model.SwitchView(ContainerChangesView)
+	return m.DoLoad(model)
+}
+
+// DoLoad runs docker diff
+func (m *ContainerChangesViewModel) DoLoad(model *Model) tea.Cmd {
+	model.loading = true
+	container := m.container
+	return func() tea.Msg {
+		changes, err := docker.GetContainerChanges(container)
+		return containerChangesLoadedMsg{changes: changes, err: err}
+	}
+}
+
+// Update handles messages for the changes view
+func (m *ContainerChangesViewModel) Update(model *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case containerChangesLoadedMsg:
+		model.loading = false
+		if msg.err != nil {
+			model.err = msg.err
+			return model, nil
+		}
+		model.err = nil
+		m.Loaded(model, msg.changes)
+		return model, nil
+
+	case containerChangeDiffMsg:
+		model.loading = false
+		if msg.err != nil {
+			model.err = msg.err
+			return model, nil
+		}
+		title := fmt.Sprintf("Diff: %s [%s] image -> container", msg.path, m.container.Title())
+		model.fileDiffViewModel.Show(model, title, msg.lines)
+		return model, nil
+
+	default:
+		return model, nil
+	}
+}
+
+// Loaded rebuilds the tree, keeping expanded directories and the selection
+func (m *ContainerChangesViewModel) Loaded(model *Model, changes []models.ContainerChange) {
+	m.changes = changes
+	m.setTree(model, buildChangeTree(changes))
+}
+
+func renderChangeKind(kind models.ChangeKind) string {
+	switch kind {
+	case models.ChangeAdded:
+		return changeAddedStyle.Render("A")
+	case models.ChangeChanged:
+		return changeChangedStyle.Render("C")
+	case models.ChangeDeleted:
+		return changeDeletedStyle.Render("D")
+	default:
+		return ""
+	}
+}
+
+func (m *ContainerChangesViewModel) render(model *Model, availableHeight int) string {
+	return m.renderTree(model, availableHeight, "The container has not changed its filesystem")
+}
+
+// HandleOpen expands or collapses a directory, or opens a file in the file content view
+func (m *ContainerChangesViewModel) HandleOpen(model *Model) tea.Cmd {
+	if m.toggleSelected(model) {
+		return nil
+	}
+	row := m.selectedRow()
+	if row == nil {
+		return nil
+	}
+	if row.node.kind == models.ChangeDeleted {
+		// Nothing to open in the container; show what was deleted instead
+		return m.HandleDiff(model)
+	}
+	return model.fileContentViewModel.LoadContainer(model, m.container, row.node.path)
+}
+
+// HandleDiff shows the content diff of the selected file against the image
+func (m *ContainerChangesViewModel) HandleDiff(model *Model) tea.Cmd {
+	row := m.selectedRow()
+	if row == nil || len(row.node.children) > 0 || row.node.kind == "" {
+		return nil
+	}
+
+	container := m.container
+	path := row.node.path
+	kind := row.node.kind
+	fileOperations := model.sharedFileOperations()
+	model.loading = true
+	return func() tea.Msg {
+		// Added files have no original, deleted files no current content
+		var original, current []byte
+		if kind != models.ChangeAdded {
+			file, err := docker.GetImageFileContent(container, path, docker.MaxFileContentSize)
+			if err != nil {
+				return containerChangeDiffMsg{err: fmt.Errorf("the original of %s is not available: %w", path, err)}
+			}
+			original = file.Data
+		}
+		if kind != models.ChangeDeleted {
+			file, err := fileOperations.GetFileContent(context.Background(), container, path, docker.MaxFileContentSize)
+			if err != nil {
+				return containerChangeDiffMsg{err: err}
+			}
+			current = file.Data
+		}
+		return containerChangeDiffMsg{
+			path:  path,
+			lines: contentDiffLines("image:"+path, "container:"+path, original, current),
+		}
+	}
+}
+
+func (m *ContainerChangesViewModel) HandleUp(model *Model) tea.Cmd {
+	return m.TableViewModel.HandleUp(model)
+}
+
+func (m *ContainerChangesViewModel) HandleDown(model *Model) tea.Cmd {
+	return m.TableViewModel.HandleDown(model)
+}
+
+func (m *ContainerChangesViewModel) HandleBack(model *Model) tea.Cmd {
+	model.SwitchToPreviousView()
+	return nil
+}
+
+func (m *ContainerChangesViewModel) Title() string {
+	if m.container == nil {
+		return "Filesystem Changes"
+	}
+	title := fmt.Sprintf("Filesystem Changes: %s", m.container.Title())
+	if m.root != nil {
+		title += fmt.Sprintf(" [%d added, %d changed, %d deleted]", m.root.added, m.root.changed, m.root.deleted)
+	}
+	return title
+}
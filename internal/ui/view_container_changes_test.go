@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+func testContainerChanges() []models.ContainerChange {
+	return []models.ContainerChange{
+		{Kind: models.ChangeChanged, Path: "/etc"},
+		{Kind: models.ChangeAdded, Path: "/etc/app.conf"},
+		{Kind: models.ChangeChanged, Path: "/etc/hosts"},
+		{Kind: models.ChangeChanged, Path: "/var"},
+		{Kind: models.ChangeDeleted, Path: "/var/cache/apt/archives/lock"},
+	}
+}
+
+func TestBuildChangeTree(t *testing.T) {
+	root := buildChangeTree(testContainerChanges())
+	assert.Equal(t, "+1 ~3 -1", root.counts())
+
+	require.Len(t, root.children, 2)
+	etc := root.children[0]
+	assert.Equal(t, "/etc", etc.path)
+	assert.Equal(t, models.ChangeChanged, etc.kind)
+	assert.Equal(t, "+1 ~1", etc.counts())
+
+	// Directories that docker diff does not list are created for the path
+	cache := root.children[1].children[0]
+	assert.Equal(t, "/var/cache", cache.path)
+	assert.Equal(t, models.ChangeKind(""), cache.kind)
+	assert.Equal(t, "-1", cache.counts())
+}
+
+func TestFlattenChangeTree(t *testing.T) {
+	root := buildChangeTree(testContainerChanges())
+
+	rows := flattenChangeTree(root, map[string]bool{})
+	require.Len(t, rows, 2)
+	assert.True(t, rows[0].collapsed)
+	assert.Equal(t, "├─ ", rows[0].prefix)
+	assert.Equal(t, "└─ ", rows[1].prefix)
+
+	rows = flattenChangeTree(root, map[string]bool{"/etc": true})
+	var paths []string
+	for _, row := range rows {
+		paths = append(paths, row.node.path)
+	}
+	assert.Equal(t, []string{"/etc", "/etc/app.conf", "/etc/hosts", "/var"}, paths)
+	assert.Equal(t, "│  ├─ ", rows[1].prefix)
+	assert.False(t, rows[2].collapsed, "files are never collapsed")
+}
+
+func TestContainerChangesViewModel(t *testing.T) {
+	container := docker.NewContainer("abc123", "web", "web", "running")
+
+	newModel := func() (*Model, *ContainerChangesViewModel) {
+		model := &Model{currentView: ContainerChangesView, viewHistory: []ViewType{DockerContainerListView}, Height: 30, width: 100}
+		vm := &model.containerChangesViewModel
+		vm.container = container
+		vm.expanded = make(map[string]bool)
+		vm.Loaded(model, testContainerChanges())
+		return model, vm
+	}
+
+	t.Run("starts collapsed with counts in the title", func(t *testing.T) {
+		model, vm := newModel()
+		assert.Len(t, vm.rows, 2)
+		assert.Contains(t, vm.Title(), "1 added, 3 changed, 1 deleted")
+
+		output := vm.render(model, 20)
+		assert.Contains(t, output, "▸ etc/")
+		assert.Contains(t, output, "+1 ~1")
+	})
+
+	t.Run("enter toggles directories", func(t *testing.T) {
+		model, vm := newModel()
+		assert.Nil(t, vm.HandleOpen(model))
+		assert.Len(t, vm.rows, 4)
+
+		vm.HandleDown(model)
+		vm.HandleCollapse(model)
+		assert.Equal(t, 0, vm.Cursor, "collapsing a file selects its directory")
+
+		vm.HandleCollapse(model)
+		assert.Len(t, vm.rows, 2)
+		vm.HandleExpand(model)
+		assert.Len(t, vm.rows, 4)
+	})
+
+	t.Run("refresh keeps the expanded directories", func(t *testing.T) {
+		model, vm := newModel()
+		vm.HandleExpand(model)
+		vm.HandleDown(model)
+		vm.HandleDown(model)
+
+		vm.Loaded(model, testContainerChanges())
+		assert.Len(t, vm.rows, 4)
+		assert.Equal(t, "/etc/hosts", vm.selectedPath())
+	})
+
+	t.Run("enter opens changed files", func(t *testing.T) {
+		model, vm := newModel()
+		vm.HandleExpand(model)
+		vm.HandleDown(model)
+
+		assert.NotNil(t, vm.HandleOpen(model))
+		assert.Equal(t, FileContentView, model.currentView)
+		assert.Equal(t, container, model.fileContentViewModel.container)
+	})
+
+	t.Run("diff is only available for files", func(t *testing.T) {
+		model, vm := newModel()
+		assert.Nil(t, vm.HandleDiff(model))
+	})
+
+	t.Run("diff results open the diff view", func(t *testing.T) {
+		model, vm := newModel()
+		lines := contentDiffLines("image:/etc/hosts", "container:/etc/hosts", []byte("a\n"), []byte("b\n"))
+		vm.Update(model, containerChangeDiffMsg{path: "/etc/hosts", lines: lines})
+
+		assert.Equal(t, FileDiffView, model.currentView)
+		assert.Contains(t, model.fileDiffViewModel.Title(), "Diff: /etc/hosts")
+		assert.Contains(t, model.fileDiffViewModel.render(model, 20), "1 lines added, 1 removed")
+
+		model.fileDiffViewModel.HandleBack(model)
+		assert.Equal(t, ContainerChangesView, model.currentView)
+	})
+}
+
+func TestContentDiffLines_Binary(t *testing.T) {
+	assert.Nil(t, contentDiffLines("a", "b", []byte{0, 1}, []byte{0, 1}))
+	assert.Equal(t, []string{"Binary files differ: 2 bytes -> 3 bytes"},
+		contentDiffLines("a", "b", []byte{0, 1}, []byte{0, 1, 2}))
+}
@@ -3,7 +3,10 @@ package ui
 import (
 	"context"
 	"fmt"
+	"path"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"charm.land/bubbles/v2/table"
@@ -22,10 +25,46 @@ type containerFilesLoadedMsg struct {
 
 type FileBrowserViewModel struct {
 	TableViewModel
+	// allFiles is the listing of the current directory; containerFiles are the entries shown from it
+	allFiles          []models.ContainerFile
 	containerFiles    []models.ContainerFile
 	currentPath       string
 	browsingContainer *docker.Container // The container we're browsing
 	pathHistory       []string
+	// selected holds the names of the entries of the current directory selected for copying
+	selected map[string]bool
+	// links tells where the symbolic links of the current directory lead, by entry name
+	links map[string]docker.ResolvedPath
+	// focusName is the entry to put the cursor on when the directory has been listed
+	focusName string
+	pathBar   pathBar
+
+	sortBy          fileSortKey
+	sortReverse     bool
+	dirsFirst       bool
+	hideDotfiles    bool
+	hideOwner       bool
+	hidePermissions bool
+	// dirSizes holds the disk usage of the directories computed with du, by path
+	dirSizes map[string]int64
+
+	// volumeName is set when a volume is browsed through a temporary container,
+	// which is removed when the file browser is left
+	volumeName      string
+	volumeReadWrite bool
+
+	// image is set when an image is browsed through a container that is created but never started.
+	// The files are listed from the export of the container, which is removed when the file browser is left.
+	image       *docker.ImageFilesystem
+	imageLayers *docker.ImageLayers
+	showLayers  bool
+}
+
+// imageLayersLoadedMsg contains the layers of the browsed image
+type imageLayersLoadedMsg struct {
+	image  *docker.ImageFilesystem
+	layers *docker.ImageLayers
+	err    error
 }
 
 // Update handles messages for the file browser view
@@ -41,6 +80,45 @@ func (m *FileBrowserViewModel) Update(model *Model, msg tea.Msg) (tea.Model, tea
 		}
 
 		m.Loaded(model, msg.files)
+		return model, m.resolveLinks(model)
+	case linksResolvedMsg:
+		m.linksResolved(model, msg)
+		return model, nil
+	case pathResolvedMsg:
+		if msg.err != nil {
+			model.loading = false
+			model.err = msg.err
+			return model, nil
+		}
+		return model, m.goTo(model, msg.resolved, msg.logical)
+	case directorySizesMsg:
+		m.directorySizesComputed(model, msg)
+		return model, nil
+	case pathCompletionListedMsg:
+		m.completionListed(msg)
+		return model, nil
+	case fileUploadedMsg:
+		model.loading = false
+		if msg.err != nil {
+			model.err = msg.err
+			return model, nil
+		}
+		model.err = nil
+		// Show the copied file in the listing
+		return model, m.DoLoad(model)
+	case imageLayersLoadedMsg:
+		model.loading = false
+		if msg.image != m.image {
+			return model, nil
+		}
+		if msg.err != nil {
+			model.err = msg.err
+			return model, nil
+		}
+		model.err = nil
+		m.imageLayers = msg.layers
+		m.showLayers = true
+		m.SetRows(m.buildRows(), model.ViewHeight())
 		return model, nil
 	default:
 		return model, nil
@@ -51,6 +129,7 @@ func (m *FileBrowserViewModel) Update(model *Model, msg tea.Msg) (tea.Model, tea
 func (m *FileBrowserViewModel) pushHistory(path string) {
 	m.pathHistory = append(m.pathHistory, path)
 	m.currentPath = path
+	m.selected = nil
 }
 
 // popHistory removes the last path from history and returns to the previous one
@@ -63,11 +142,21 @@ func (m *FileBrowserViewModel) popHistory() bool {
 	m.pathHistory = m.pathHistory[:len(m.pathHistory)-1]
 	// Set current path to the new last item
 	m.currentPath = m.pathHistory[len(m.pathHistory)-1]
+	m.selected = nil
 	return true
 }
 
 // render renders the file browser view
 func (m *FileBrowserViewModel) render(model *Model, availableHeight int) string {
+	if m.pathBar.active {
+		bar := m.renderPathBar()
+		return bar + m.renderListing(model, availableHeight-strings.Count(bar, "\n"))
+	}
+	return m.renderListing(model, availableHeight)
+}
+
+// renderListing renders the entries of the current directory
+func (m *FileBrowserViewModel) renderListing(model *Model, availableHeight int) string {
 	if len(m.containerFiles) == 0 {
 		var content strings.Builder
 		dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
@@ -77,37 +166,10 @@ func (m *FileBrowserViewModel) render(model *Model, availableHeight int) string
 		return content.String()
 	}
 
-	// Create columns based on screen width
-	// Minimum width needed: permissions(11) + size(10) + name(20 min) = 41
-	// Medium width adds: links(5) = 46
-	// Full width adds: owner(10) + group(10) = 66
-	var columns []table.Column
-
-	if model.width < 60 {
-		// Narrow screen: Show only essential columns
-		columns = []table.Column{
-			{Title: "PERMISSIONS", Width: 11},
-			{Title: "SIZE", Width: 10},
-			{Title: "NAME", Width: -1},
-		}
-	} else if model.width < 80 {
-		// Medium screen: Add links column
-		columns = []table.Column{
-			{Title: "PERMISSIONS", Width: 11},
-			{Title: "LINKS", Width: 5},
-			{Title: "SIZE", Width: 10},
-			{Title: "NAME", Width: -1},
-		}
-	} else {
-		// Wide screen: Show all columns
-		columns = []table.Column{
-			{Title: "PERMISSIONS", Width: 11},
-			{Title: "LINKS", Width: 5},
-			{Title: "OWNER", Width: 10},
-			{Title: "GROUP", Width: 10},
-			{Title: "SIZE", Width: 10},
-			{Title: "NAME", Width: -1},
-		}
+	fileColumns := m.fileColumns(model.width)
+	columns := make([]table.Column, len(fileColumns))
+	for i, c := range fileColumns {
+		columns[i] = c.Column
 	}
 
 	// Build rows based on visible columns
@@ -121,98 +183,135 @@ func (m *FileBrowserViewModel) render(model *Model, availableHeight int) string
 	})
 }
 
+var fileSelectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("220")).Bold(true)
+
 // buildRowsForWidth builds table rows based on screen width
 func (m *FileBrowserViewModel) buildRowsForWidth(width int) {
-	// Define styles for different file types
-	dirStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("33"))
-	linkStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("51"))
-
-	rows := make([]table.Row, 0, len(m.containerFiles))
-	for _, file := range m.containerFiles {
-		// Style the name based on file type
-		name := file.GetDisplayName()
-		if file.IsDir {
-			name = dirStyle.Render(name)
-		} else if file.LinkTarget != "" {
-			name = linkStyle.Render(name)
-		}
-
-		var row table.Row
-		if width < 60 {
-			// Narrow: permissions, size, name
-			row = table.Row{
-				file.Permissions,
-				file.GetSizeString(),
-				name,
-			}
-		} else if width < 80 {
-			// Medium: permissions, links, size, name
-			row = table.Row{
-				file.Permissions,
-				file.Links,
-				file.GetSizeString(),
-				name,
-			}
-		} else {
-			// Wide: all columns
-			row = table.Row{
-				file.Permissions,
-				file.Links,
-				file.Owner,
-				file.Group,
-				file.GetSizeString(),
-				name,
-			}
-		}
-		rows = append(rows, row)
-	}
-
 	// Update the table view model's rows
 	currentHeight := m.End - m.Start
 	if currentHeight <= 0 {
 		currentHeight = 20 // Default height
 	}
-	m.SetRows(rows, currentHeight)
+	m.SetRows(m.rowsFor(m.fileColumns(width)), currentHeight)
 }
 
-// buildRows builds the table rows from container files (for compatibility)
+// buildRows builds the table rows with all columns, for updates that do not know the screen width
 func (m *FileBrowserViewModel) buildRows() []table.Row {
-	// Default behavior - return all columns for backward compatibility
-	// This is used by tests and other parts that don't have width information
-
-	// Define styles for different file types
-	dirStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("33"))
-	linkStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("51"))
+	return m.rowsFor(m.fileColumns(fileBrowserFullWidth))
+}
 
-	rows := make([]table.Row, 0, len(m.containerFiles))
-	// Add rows
-	for _, file := range m.containerFiles {
-		// Style the name based on file type
-		name := file.GetDisplayName()
-		if file.IsDir {
-			name = dirStyle.Render(name)
-		} else if file.LinkTarget != "" {
-			name = linkStyle.Render(name)
+// layerLabel names the layer that wrote a file by its number, counted from the base layer,
+// like "3 (over 1)" when it overwrote the file of earlier layers
+func (m *FileBrowserViewModel) layerLabel(file models.ContainerFile) string {
+	layer, overwritten, ok := m.imageLayers.Origin(path.Join(m.currentPath, file.Name))
+	if !ok {
+		// Like /etc/hosts, which docker adds to every container
+		return "-"
+	}
+	label := strconv.Itoa(layer + 1)
+	if len(overwritten) > 0 {
+		numbers := make([]string, len(overwritten))
+		for i, l := range overwritten {
+			numbers[i] = strconv.Itoa(l + 1)
 		}
-
-		rows = append(rows, table.Row{
-			file.Permissions,
-			file.Links,
-			file.Owner,
-			file.Group,
-			file.GetSizeString(),
-			name,
-		})
+		label += " (over " + strings.Join(numbers, ",") + ")"
 	}
-	return rows
+	return label
 }
 
 func (m *FileBrowserViewModel) LoadContainer(model *Model, container *docker.Container) tea.Cmd {
+	stop := m.closeTemporaryContainer()
+	m.pathBar = pathBar{}
+	m.dirSizes = nil
+	m.browsingContainer = container
+	m.pathHistory = []string{}
+	m.pushHistory("/")
+	model.SwitchView(FileBrowserView)
+	return tea.Batch(stop, m.DoLoad(model))
+}
+
+// LoadVolume browses a volume mounted in a container started by docker.StartVolumeBrowser
+func (m *FileBrowserViewModel) LoadVolume(model *Model, container *docker.Container, volume string, readWrite bool) tea.Cmd {
+	stop := m.closeTemporaryContainer()
+	m.pathBar = pathBar{}
+	m.dirSizes = nil
 	m.browsingContainer = container
+	m.volumeName = volume
+	m.volumeReadWrite = readWrite
 	m.pathHistory = []string{}
+	m.Cursor = 0
+	m.pushHistory(docker.VolumeBrowserMountPath)
+	model.SwitchView(FileBrowserView)
+	return tea.Batch(stop, m.DoLoad(model))
+}
+
+// LoadImage browses the filesystem of an image opened by docker.OpenImageFilesystem
+func (m *FileBrowserViewModel) LoadImage(model *Model, image *docker.ImageFilesystem) tea.Cmd {
+	stop := m.closeTemporaryContainer()
+	m.pathBar = pathBar{}
+	m.dirSizes = nil
+	m.browsingContainer = image.Container
+	m.image = image
+	m.pathHistory = []string{}
+	m.Cursor = 0
 	m.pushHistory("/")
 	model.SwitchView(FileBrowserView)
-	return m.DoLoad(model)
+	return tea.Batch(stop, m.DoLoad(model))
+}
+
+// HandleToggleLayers shows which layer of the browsed image wrote each file, analyzing the layers first
+func (m *FileBrowserViewModel) HandleToggleLayers(model *Model) tea.Cmd {
+	if m.image == nil {
+		return nil
+	}
+	if m.imageLayers != nil {
+		m.showLayers = !m.showLayers
+		m.SetRows(m.buildRows(), model.ViewHeight())
+		return nil
+	}
+	model.loading = true
+	image := m.image
+	return func() tea.Msg {
+		layers, err := docker.AnalyzeLayers(image.Image)
+		return imageLayersLoadedMsg{image: image, layers: layers, err: err}
+	}
+}
+
+// requireRunning refuses the tools that run commands in the container of a browsed image,
+// since it is never started
+func (m *FileBrowserViewModel) requireRunning(model *Model, container *docker.Container) bool {
+	if m.image != nil && container == m.image.Container {
+		model.err = fmt.Errorf("not available for image %s: nothing runs in its container", m.image.Image)
+		return false
+	}
+	return true
+}
+
+// closeTemporaryContainer removes the container of a browsed volume or image in the background
+func (m *FileBrowserViewModel) closeTemporaryContainer() tea.Cmd {
+	if image := m.image; image != nil {
+		m.image = nil
+		m.imageLayers = nil
+		m.showLayers = false
+		return func() tea.Msg {
+			if err := image.Close(); err != nil {
+				return errorMsg{err: err}
+			}
+			return nil
+		}
+	}
+	if m.volumeName == "" || m.browsingContainer == nil {
+		return nil
+	}
+	container := m.browsingContainer
+	m.volumeName = ""
+	m.volumeReadWrite = false
+	return func() tea.Msg {
+		if err := docker.StopVolumeBrowser(container); err != nil {
+			return errorMsg{err: err}
+		}
+		return nil
+	}
 }
 
 func (m *FileBrowserViewModel) HandleBack(model *Model) tea.Cmd {
@@ -223,7 +322,7 @@ func (m *FileBrowserViewModel) HandleBack(model *Model) tea.Cmd {
 	}
 	// If no more history, go back to the previous view
 	model.SwitchToPreviousView()
-	return nil
+	return m.closeTemporaryContainer()
 }
 
 func (m *FileBrowserViewModel) HandleUp(model *Model) tea.Cmd {
@@ -266,7 +365,14 @@ func (m *FileBrowserViewModel) HandleOpenFileOrDirectory(model *Model) tea.Cmd {
 
 		newPath := filepath.Join(m.currentPath, file.Name)
 
-		if file.IsDir {
+		// A link to a directory is entered under its own path, so that going up returns through it
+		link, resolved := m.links[file.Name]
+		if file.LinkTarget != "" && resolved && link.Broken {
+			model.err = fmt.Errorf("%s is a broken link to %s", newPath, file.LinkTarget)
+			return nil
+		}
+
+		if file.IsDir || (file.LinkTarget != "" && resolved && link.IsDir) {
 			// Navigate into directory
 			m.pushHistory(newPath)
 			m.Cursor = 0
@@ -279,13 +385,91 @@ func (m *FileBrowserViewModel) HandleOpenFileOrDirectory(model *Model) tea.Cmd {
 	return nil
 }
 
+// HandleToggleSelect selects or unselects the entry under the cursor and moves to the next one
+func (m *FileBrowserViewModel) HandleToggleSelect(model *Model) tea.Cmd {
+	if m.Cursor >= len(m.containerFiles) {
+		return nil
+	}
+	name := m.containerFiles[m.Cursor].Name
+	if name != "." && name != ".." {
+		if m.selected[name] {
+			delete(m.selected, name)
+		} else {
+			if m.selected == nil {
+				m.selected = map[string]bool{}
+			}
+			m.selected[name] = true
+		}
+		m.SetRows(m.buildRows(), model.ViewHeight())
+	}
+	return m.HandleDown(model)
+}
+
+// HandleSelectAll selects every entry of the directory, or none when all are selected
+func (m *FileBrowserViewModel) HandleSelectAll(model *Model) tea.Cmd {
+	all := map[string]bool{}
+	for _, file := range m.containerFiles {
+		if file.Name != "." && file.Name != ".." {
+			all[file.Name] = true
+		}
+	}
+	if len(m.selected) == len(all) {
+		m.selected = nil
+	} else {
+		m.selected = all
+	}
+	m.SetRows(m.buildRows(), model.ViewHeight())
+	return nil
+}
+
+// selectedFiles returns the selected entries in listing order
+func (m *FileBrowserViewModel) selectedFiles() []models.ContainerFile {
+	var files []models.ContainerFile
+	for _, file := range m.containerFiles {
+		if m.selected[file.Name] {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// HandleFollowFile follows the selected file in the log view
+func (m *FileBrowserViewModel) HandleFollowFile(model *Model) tea.Cmd {
+	if m.Cursor >= len(m.containerFiles) || m.containerFiles[m.Cursor].IsDir || !m.requireRunning(model, m.browsingContainer) {
+		return nil
+	}
+	path := filepath.Join(m.currentPath, m.containerFiles[m.Cursor].Name)
+	return model.logViewModel.FollowFile(model, m.browsingContainer, path)
+}
+
 func (m *FileBrowserViewModel) Loaded(model *Model, files []models.ContainerFile) {
-	m.containerFiles = files
+	m.allFiles = files
+	m.links = nil
+	m.applyListing()
+	if m.focusName != "" {
+		if i := slices.IndexFunc(m.containerFiles, func(f models.ContainerFile) bool { return f.Name == m.focusName }); i >= 0 {
+			m.Cursor = i
+		}
+		m.focusName = ""
+	}
+	// Forget the selected entries that are gone
+	for name := range m.selected {
+		if !slices.ContainsFunc(files, func(f models.ContainerFile) bool { return f.Name == name }) {
+			delete(m.selected, name)
+		}
+	}
 	m.SetRows(m.buildRows(), model.ViewHeight())
 }
 
 func (m *FileBrowserViewModel) DoLoad(model *Model) tea.Cmd {
 	model.loading = true
+	if image := m.image; image != nil {
+		dir := m.currentPath
+		return func() tea.Msg {
+			files, err := image.List(dir)
+			return containerFilesLoadedMsg{files: files, err: err}
+		}
+	}
 	return func() tea.Msg {
 		// Use FileOperations for multi-strategy file listing if available
 		if model.fileOperations != nil {
@@ -298,7 +482,7 @@ func (m *FileBrowserViewModel) DoLoad(model *Model) tea.Cmd {
 		}
 
 		// Fallback to direct docker exec if FileOperations not available
-		args := m.browsingContainer.OperationArgs("exec", "ls", "-la", m.currentPath)
+		args := m.browsingContainer.OperationArgs("exec", "ls", "-la", strings.TrimSuffix(m.currentPath, "/")+"/")
 
 		output, err := docker.ExecuteCaptured(args...)
 		if err != nil {
@@ -317,8 +501,26 @@ func (m *FileBrowserViewModel) DoLoad(model *Model) tea.Cmd {
 }
 
 func (m *FileBrowserViewModel) Title() string {
+	selection := ""
+	if len(m.selected) > 0 {
+		selection = fmt.Sprintf(" (%d selected)", len(m.selected))
+	}
+	if m.image != nil {
+		title := fmt.Sprintf("File Browser: image %s [%s]", m.image.Image, m.currentPath)
+		if m.showLayers && m.imageLayers != nil {
+			title += fmt.Sprintf(" (%d layers)", len(m.imageLayers.Layers))
+		}
+		return title + m.listingSummary() + selection
+	}
+	if m.volumeName != "" {
+		mode := "read-only"
+		if m.volumeReadWrite {
+			mode = "read-write"
+		}
+		return fmt.Sprintf("File Browser: volume %s (%s) [%s]", m.volumeName, mode, m.currentPath) + m.listingSummary() + selection
+	}
 	if m.browsingContainer != nil {
-		return fmt.Sprintf("File Browser: %s [%s]", m.browsingContainer.Title(), m.currentPath)
+		return fmt.Sprintf("File Browser: %s [%s]", m.browsingContainer.Title(), m.currentPath) + m.listingSummary() + selection
 	}
 	return "File Browser"
 }
@@ -1,8 +1,10 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -13,6 +15,27 @@ import (
 	"github.com/tokuhirom/dcv/internal/models"
 )
 
+// fileUploadedMsg is sent when a local file has been copied into the container
+type fileUploadedMsg struct {
+	localPath    string
+	containerDir string
+	err          error
+}
+
+// fileInputKind identifies what the path entered in input mode is used for
+type fileInputKind int
+
+const (
+	fileInputCopyToLocal fileInputKind = iota
+	fileInputCopyFromLocal
+	fileInputArchive
+	fileInputRename
+	fileInputChmod
+	fileInputChown
+	fileInputMkdir
+	fileInputTouch
+)
+
 // FileBrowserAction represents a file operation
 type FileBrowserAction struct {
 	Key         string
@@ -28,12 +51,18 @@ type FileBrowserActionViewModel struct {
 	targetFile      *models.ContainerFile
 	targetContainer *docker.Container
 	containerPath   string
+	// selection holds the entries selected in the file browser; the copy actions apply to them instead of targetFile
+	selection []models.ContainerFile
 
 	// Input mode for destination path
 	inputMode      bool
+	inputKind      fileInputKind
 	inputBuffer    string
 	inputCursorPos int
 	inputPrompt    string
+	completion     pathCompletion
+	// conflicts holds the local paths a confirmed copy would overwrite, until the user decides what to do
+	conflicts []string
 }
 
 // Initialize sets up the action view with available commands for a file
@@ -42,6 +71,7 @@ func (m *FileBrowserActionViewModel) Initialize(file *models.ContainerFile, cont
 	m.targetContainer = container
 	m.containerPath = containerPath
 	m.selectedAction = 0
+	m.selection = nil
 
 	// Define available actions
 	m.actions = []FileBrowserAction{}
@@ -58,6 +88,49 @@ func (m *FileBrowserActionViewModel) Initialize(file *models.ContainerFile, cont
 		},
 	})
 
+	// Save as an archive
+	m.actions = append(m.actions, FileBrowserAction{
+		Key:         "Z",
+		Name:        "Save as .tar.gz",
+		Description: "Save to a gzip-compressed tar archive on the local machine",
+		Handler: func(model *Model, f *models.ContainerFile, c *docker.Container) tea.Cmd {
+			m.startArchiveInputMode()
+			return nil
+		},
+	})
+
+	// Copy from local machine into the current directory
+	m.actions = append(m.actions, FileBrowserAction{
+		Key:         "L",
+		Name:        "Copy from Local",
+		Description: "Copy a local file/directory into this directory",
+		Handler: func(model *Model, f *models.ContainerFile, c *docker.Container) tea.Cmd {
+			m.startUploadInputMode()
+			return nil
+		},
+	})
+
+	// Create entries in the current directory
+	m.actions = append(m.actions, FileBrowserAction{
+		Key:         "K",
+		Name:        "New Directory",
+		Description: "Create a directory in this directory",
+		Handler: func(model *Model, f *models.ContainerFile, c *docker.Container) tea.Cmd {
+			m.startManageInputMode(fileInputMkdir)
+			return nil
+		},
+	})
+
+	m.actions = append(m.actions, FileBrowserAction{
+		Key:         "N",
+		Name:        "New File",
+		Description: "Create an empty file in this directory",
+		Handler: func(model *Model, f *models.ContainerFile, c *docker.Container) tea.Cmd {
+			m.startManageInputMode(fileInputTouch)
+			return nil
+		},
+	})
+
 	// View file (if it's a file)
 	if !file.IsDir {
 		m.actions = append(m.actions, FileBrowserAction{
@@ -70,6 +143,16 @@ func (m *FileBrowserActionViewModel) Initialize(file *models.ContainerFile, cont
 				return model.fileContentViewModel.LoadContainer(model, c, fullPath)
 			},
 		})
+
+		m.actions = append(m.actions, FileBrowserAction{
+			Key:         "E",
+			Name:        "Edit",
+			Description: "Edit in $EDITOR and write back, keeping mode and ownership",
+			Handler: func(model *Model, f *models.ContainerFile, c *docker.Container) tea.Cmd {
+				model.SwitchToPreviousView()
+				return model.fileEditViewModel.Start(model, c, filepath.Join(containerPath, f.Name))
+			},
+		})
 	}
 
 	// Execute command in directory (if it's a directory)
@@ -85,92 +168,413 @@ func (m *FileBrowserActionViewModel) Initialize(file *models.ContainerFile, cont
 		})
 	}
 
+	// Tools of the injected helper; they work in images without coreutils
+	m.actions = append(m.actions, FileBrowserAction{
+		Key:         "S",
+		Name:        "Stat",
+		Description: "Show detailed file status (via helper)",
+		Handler: func(model *Model, f *models.ContainerFile, c *docker.Container) tea.Cmd {
+			model.SwitchToPreviousView()
+			return model.runHelperTool(c, "stat", filepath.Join(containerPath, f.Name))
+		},
+	})
+
+	if file.IsDir {
+		m.actions = append(m.actions, FileBrowserAction{
+			Key:         "F",
+			Name:        "Find",
+			Description: "Find files by name below this directory",
+			Handler: func(model *Model, f *models.ContainerFile, c *docker.Container) tea.Cmd {
+				model.SwitchToPreviousView()
+				model.fileSearchViewModel.Show(model, c, filepath.Join(containerPath, f.Name), docker.FileSearchFind)
+				return nil
+			},
+		})
+
+		m.actions = append(m.actions, FileBrowserAction{
+			Key:         "U",
+			Name:        "Disk Usage",
+			Description: "Show the size of this directory and its subdirectories (via helper)",
+			Handler: func(model *Model, f *models.ContainerFile, c *docker.Container) tea.Cmd {
+				model.SwitchToPreviousView()
+				return model.runHelperTool(c, "du", "-h", "-d", "1", filepath.Join(containerPath, f.Name))
+			},
+		})
+	} else {
+		m.actions = append(m.actions, FileBrowserAction{
+			Key:         "T",
+			Name:        "Follow",
+			Description: "Stream lines appended to the file, like tail -F",
+			Handler: func(model *Model, f *models.ContainerFile, c *docker.Container) tea.Cmd {
+				model.SwitchToPreviousView()
+				return model.logViewModel.FollowFile(model, c, filepath.Join(containerPath, f.Name))
+			},
+		})
+
+		m.actions = append(m.actions, FileBrowserAction{
+			Key:         "H",
+			Name:        "SHA-256",
+			Description: "Compute the SHA-256 checksum (via helper)",
+			Handler: func(model *Model, f *models.ContainerFile, c *docker.Container) tea.Cmd {
+				model.SwitchToPreviousView()
+				return model.runHelperTool(c, "sha256", filepath.Join(containerPath, f.Name))
+			},
+		})
+	}
+
+	m.actions = append(m.actions, FileBrowserAction{
+		Key:         "G",
+		Name:        "Grep",
+		Description: "Search file contents with a regular expression",
+		Handler: func(model *Model, f *models.ContainerFile, c *docker.Container) tea.Cmd {
+			model.SwitchToPreviousView()
+			model.fileSearchViewModel.Show(model, c, filepath.Join(containerPath, f.Name), docker.FileSearchGrep)
+			return nil
+		},
+	})
+
+	m.actions = append(m.actions, FileBrowserAction{
+		Key:         "R",
+		Name:        "Rename/Move",
+		Description: "Rename or move to another path",
+		Handler: func(model *Model, f *models.ContainerFile, c *docker.Container) tea.Cmd {
+			m.startManageInputMode(fileInputRename)
+			return nil
+		},
+	})
+
+	m.actions = append(m.actions, FileBrowserAction{
+		Key:         "M",
+		Name:        "Chmod",
+		Description: "Change permissions, e.g. 644 or u+x",
+		Handler: func(model *Model, f *models.ContainerFile, c *docker.Container) tea.Cmd {
+			m.startManageInputMode(fileInputChmod)
+			return nil
+		},
+	})
+
+	m.actions = append(m.actions, FileBrowserAction{
+		Key:         "O",
+		Name:        "Chown",
+		Description: "Change owner and group, e.g. app:app",
+		Handler: func(model *Model, f *models.ContainerFile, c *docker.Container) tea.Cmd {
+			m.startManageInputMode(fileInputChown)
+			return nil
+		},
+	})
+
 	// Delete file/directory
 	m.actions = append(m.actions, FileBrowserAction{
 		Key:         "D",
 		Name:        "Delete",
 		Description: "Delete file or directory",
 		Handler: func(model *Model, f *models.ContainerFile, c *docker.Container) tea.Cmd {
+			model.SwitchToPreviousView()
 			return m.handleDelete(model, f, c)
 		},
 	})
 }
 
+// restrictToVolume removes the actions that do not work in the container of a browsed volume.
+// It has no shell, and nothing may be written to a volume mounted read-only.
+func (m *FileBrowserActionViewModel) restrictToVolume(readWrite bool) {
+	writeActions := map[string]bool{
+		"Copy from Local": true, "Edit": true, "Delete": true, "New Directory": true, "New File": true,
+		"Rename/Move": true, "Chmod": true, "Chown": true,
+	}
+	actions := m.actions[:0]
+	for _, action := range m.actions {
+		if action.Name == "Execute Command" || (!readWrite && writeActions[action.Name]) {
+			continue
+		}
+		actions = append(actions, action)
+	}
+	m.actions = actions
+}
+
+// restrictToImage keeps the actions that only read files, since the container of a browsed image is never started
+func (m *FileBrowserActionViewModel) restrictToImage() {
+	actions := m.actions[:0]
+	for _, action := range m.actions {
+		if action.Name == "Copy to Local" || action.Name == "Save as .tar.gz" || action.Name == "View File" {
+			actions = append(actions, action)
+		}
+	}
+	m.actions = actions
+}
+
+// SetSelection makes the copy, move, permission and delete actions apply to the entries selected in the file browser
+func (m *FileBrowserActionViewModel) SetSelection(files []models.ContainerFile) {
+	m.selection = files
+	if len(files) == 0 {
+		return
+	}
+	for i := range m.actions {
+		switch m.actions[i].Name {
+		case "Copy to Local":
+			m.actions[i].Description = fmt.Sprintf("Copy the %d selected entries to local machine", len(files))
+		case "Save as .tar.gz":
+			m.actions[i].Description = fmt.Sprintf("Save the %d selected entries to a gzip-compressed tar archive", len(files))
+		case "Rename/Move":
+			m.actions[i].Description = fmt.Sprintf("Move the %d selected entries into another directory", len(files))
+		case "Chmod":
+			m.actions[i].Description = fmt.Sprintf("Change permissions of the %d selected entries", len(files))
+		case "Chown":
+			m.actions[i].Description = fmt.Sprintf("Change owner and group of the %d selected entries", len(files))
+		case "Delete":
+			m.actions[i].Description = fmt.Sprintf("Delete the %d selected entries", len(files))
+		}
+	}
+}
+
+// sourcePaths returns the container paths the copy, move, permission and delete actions apply to
+func (m *FileBrowserActionViewModel) sourcePaths() []string {
+	if len(m.selection) == 0 {
+		return []string{filepath.Join(m.containerPath, m.targetFile.Name)}
+	}
+	paths := make([]string, len(m.selection))
+	for i, file := range m.selection {
+		paths[i] = filepath.Join(m.containerPath, file.Name)
+	}
+	return paths
+}
+
+// downloadsDir is where copies go by default
+func downloadsDir() string {
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(homeDir, "Downloads")
+	}
+	return "/tmp"
+}
+
 // startInputMode starts the input mode for destination path
 func (m *FileBrowserActionViewModel) startInputMode(file *models.ContainerFile) {
 	m.inputMode = true
-	m.inputPrompt = fmt.Sprintf("Enter destination path for '%s': ", file.Name)
+	m.inputKind = fileInputCopyToLocal
+	m.completion.reset()
+	m.conflicts = nil
+	if len(m.selection) > 0 {
+		m.inputPrompt = fmt.Sprintf("Enter destination directory for %d entries: ", len(m.selection))
+		m.inputBuffer = downloadsDir() + string(filepath.Separator)
+	} else {
+		m.inputPrompt = fmt.Sprintf("Enter destination path for '%s': ", file.Name)
+		m.inputBuffer = filepath.Join(downloadsDir(), file.Name)
+	}
+	m.inputCursorPos = len(m.inputBuffer)
+}
+
+// startArchiveInputMode starts the input mode for the path of the archive
+func (m *FileBrowserActionViewModel) startArchiveInputMode() {
+	m.inputMode = true
+	m.inputKind = fileInputArchive
+	m.completion.reset()
+	m.conflicts = nil
+	name := m.targetFile.Name
+	if len(m.selection) > 0 {
+		name = filepath.Base(m.containerPath)
+		if name == "/" {
+			name = "root"
+		}
+	}
+	m.inputPrompt = "Enter path of the archive: "
+	m.inputBuffer = filepath.Join(downloadsDir(), name+".tar.gz")
+	m.inputCursorPos = len(m.inputBuffer)
+}
+
+// startUploadInputMode starts the input mode for the local source path
+func (m *FileBrowserActionViewModel) startUploadInputMode() {
+	m.inputMode = true
+	m.inputKind = fileInputCopyFromLocal
+	m.completion.reset()
+	m.inputPrompt = fmt.Sprintf("Enter local path to copy into '%s' (Tab to complete): ", m.containerPath)
 
-	// Set default path
-	homeDir, err := os.UserHomeDir()
-	if err == nil {
-		m.inputBuffer = filepath.Join(homeDir, "Downloads", file.Name)
+	// Start from the current working directory
+	if cwd, err := os.Getwd(); err == nil {
+		m.inputBuffer = cwd + "/"
 	} else {
-		m.inputBuffer = filepath.Join("/tmp", file.Name)
+		m.inputBuffer = ""
 	}
 	m.inputCursorPos = len(m.inputBuffer)
 }
 
-// handleCopyToLocal handles copying a file from container to local machine
-func (m *FileBrowserActionViewModel) handleCopyToLocal(model *Model, destPath string) tea.Cmd {
-	file := m.targetFile
-	container := m.targetContainer
+// startManageInputMode starts the input mode for a file management action
+func (m *FileBrowserActionViewModel) startManageInputMode(kind fileInputKind) {
+	m.inputMode = true
+	m.inputKind = kind
+	m.completion.reset()
+	m.conflicts = nil
+	m.inputBuffer = ""
+	single := len(m.selection) == 0
+
+	switch kind {
+	case fileInputMkdir:
+		m.inputPrompt = "Enter name of the new directory: "
+	case fileInputTouch:
+		m.inputPrompt = "Enter name of the new file: "
+	case fileInputRename:
+		if single {
+			m.inputPrompt = fmt.Sprintf("Enter new path for '%s': ", m.targetFile.Name)
+			m.inputBuffer = path.Join(m.containerPath, m.targetFile.Name)
+		} else {
+			m.inputPrompt = fmt.Sprintf("Enter directory to move %d entries into: ", len(m.selection))
+			m.inputBuffer = strings.TrimSuffix(m.containerPath, "/") + "/"
+		}
+	case fileInputChmod:
+		m.inputPrompt = "Enter mode (octal or symbolic): "
+		if single {
+			m.inputBuffer = m.targetFile.GetOctalMode()
+		}
+	case fileInputChown:
+		m.inputPrompt = "Enter owner[:group]: "
+		if single && m.targetFile.Owner != "" {
+			m.inputBuffer = m.targetFile.Owner
+			if m.targetFile.Group != "" {
+				m.inputBuffer += ":" + m.targetFile.Group
+			}
+		}
+	}
+	m.inputCursorPos = len(m.inputBuffer)
+}
 
-	// Build the source path
-	sourcePath := filepath.Join(m.containerPath, file.Name)
+// containerTarget resolves a path entered for a file management action against the current directory
+func (m *FileBrowserActionViewModel) containerTarget(input string) string {
+	if path.IsAbs(input) {
+		return path.Clean(input)
+	}
+	return path.Join(m.containerPath, input)
+}
 
-	// Expand tilde if present
-	if strings.HasPrefix(destPath, "~/") {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			model.err = fmt.Errorf("failed to get home directory: %w", err)
+// handleManageInput runs the file management command for the confirmed input.
+// Commands that change or move existing entries are confirmed before they run.
+func (m *FileBrowserActionViewModel) handleManageInput(model *Model, input string) tea.Cmd {
+	container := m.targetContainer
+	sources := m.sourcePaths()
+	m.inputMode = false
+	model.SwitchToPreviousView()
+
+	switch m.inputKind {
+	case fileInputMkdir:
+		return model.runFileCommand(container, false, "mkdir", "-p", m.containerTarget(input))
+	case fileInputTouch:
+		return model.runFileCommand(container, false, "touch", m.containerTarget(input))
+	case fileInputRename:
+		target := m.containerTarget(input)
+		if len(sources) == 1 && sources[0] == target {
 			return nil
 		}
-		destPath = filepath.Join(homeDir, destPath[2:])
+		model.fileBrowserViewModel.selected = nil
+		return model.runFileCommand(container, true, "mv", append(sources, target)...)
+	case fileInputChmod:
+		return model.runFileCommand(container, true, "chmod", append([]string{input}, sources...)...)
+	case fileInputChown:
+		return model.runFileCommand(container, true, "chown", append([]string{input}, sources...)...)
 	}
+	return nil
+}
+
+// handleCopyFromLocal copies a local file or directory into the current container directory
+func (m *FileBrowserActionViewModel) handleCopyFromLocal(model *Model, localPath string) tea.Cmd {
+	container := m.targetContainer
+	containerDir := m.containerPath
 
-	// Create parent directory if it doesn't exist
-	destDir := filepath.Dir(destPath)
-	if err := os.MkdirAll(destDir, 0755); err != nil {
-		model.err = fmt.Errorf("failed to create destination directory: %w", err)
+	localPath, err := expandHomeDir(localPath)
+	if err != nil {
+		model.err = fmt.Errorf("failed to get home directory: %w", err)
 		return nil
 	}
 
-	// Build the docker cp command
-	var args []string
-	if container.IsDind() {
-		// For dind containers: docker exec <container> docker cp <inner-container>:path -
-		// Then we need to extract it locally
-		args = append(container.OperationArgs("cp"),
-			fmt.Sprintf("%s:%s", container.ContainerID(), sourcePath), destPath)
-	} else {
-		// Regular container: docker cp <container>:path dest
-		args = []string{"cp", fmt.Sprintf("%s:%s", container.ContainerID(), sourcePath), destPath}
+	model.loading = true
+	return func() tea.Msg {
+		err := docker.CopyToContainer(container, localPath, containerDir)
+		return fileUploadedMsg{
+			localPath:    localPath,
+			containerDir: containerDir,
+			err:          err,
+		}
 	}
+}
 
-	// Execute the command
-	return model.commandExecutionViewModel.ExecuteCommand(model, false, args...)
+// completeInput completes the local path being typed
+func (m *FileBrowserActionViewModel) completeInput() {
+	m.inputBuffer, m.inputCursorPos = m.completion.apply(m.inputBuffer, m.inputCursorPos, completeLocalPath)
 }
 
-// handleDelete handles deleting a file or directory
-func (m *FileBrowserActionViewModel) handleDelete(model *Model, file *models.ContainerFile, container *docker.Container) tea.Cmd {
-	// Build the full path
-	fullPath := filepath.Join(m.containerPath, file.Name)
+// handleCopyToLocal copies the selected entries to the local machine in the background,
+// asking first what to do with local files that exist
+func (m *FileBrowserActionViewModel) handleCopyToLocal(model *Model, destPath string) tea.Cmd {
+	destPath, err := expandHomeDir(destPath)
+	if err != nil {
+		model.err = fmt.Errorf("failed to get home directory: %w", err)
+		return nil
+	}
 
-	// Build the rm command
-	var rmCmd string
-	if file.IsDir {
-		rmCmd = "rm -rf"
+	var conflicts []string
+	if m.inputKind == fileInputArchive {
+		if _, err := os.Lstat(destPath); err == nil {
+			conflicts = []string{destPath}
+		}
 	} else {
-		rmCmd = "rm -f"
+		conflicts = docker.LocalConflicts(m.sourcePaths(), destPath)
 	}
+	if len(conflicts) > 0 {
+		m.inputBuffer = destPath
+		m.inputCursorPos = len(destPath)
+		m.conflicts = conflicts
+		return nil
+	}
+	return m.startCopyToLocal(model, destPath, docker.ConflictOverwrite)
+}
 
-	// Execute the command
-	args := append(container.OperationArgs("exec"),
-		container.ContainerID(), "sh", "-c", fmt.Sprintf("%s %q", rmCmd, fullPath))
+// startCopyToLocal returns to the file browser and starts the copy
+func (m *FileBrowserActionViewModel) startCopyToLocal(model *Model, destPath string, policy docker.ConflictPolicy) tea.Cmd {
+	m.inputMode = false
+	m.completion.reset()
+	m.conflicts = nil
+	model.SwitchToPreviousView()
+	model.fileBrowserViewModel.selected = nil
+
+	container := m.targetContainer
+	sources := m.sourcePaths()
+	if m.inputKind == fileInputArchive {
+		if policy == docker.ConflictRename {
+			destPath = docker.UniqueLocalPath(destPath)
+		}
+		return model.startFileTransfer(destPath, func(ctx context.Context, progress func(docker.CopyProgress)) error {
+			return docker.ArchiveOut(ctx, container, sources, destPath, progress)
+		})
+	}
+	return model.startFileTransfer(destPath, func(ctx context.Context, progress func(docker.CopyProgress)) error {
+		return docker.CopyOut(ctx, container, sources, destPath, policy, progress)
+	})
+}
+
+// handleConflictKey applies the choice made for local files that exist
+func (m *FileBrowserActionViewModel) handleConflictKey(model *Model, msg tea.KeyPressMsg) tea.Cmd {
+	destPath := m.inputBuffer
+	switch {
+	case msg.Code == tea.KeyEsc:
+		// Back to editing the path
+		m.conflicts = nil
+	case msg.Text == "o":
+		return m.startCopyToLocal(model, destPath, docker.ConflictOverwrite)
+	case msg.Text == "s" && m.inputKind != fileInputArchive:
+		return m.startCopyToLocal(model, destPath, docker.ConflictSkip)
+	case msg.Text == "r":
+		return m.startCopyToLocal(model, destPath, docker.ConflictRename)
+	}
+	return nil
+}
+
+// handleDelete handles deleting a file or directory, or the selected entries
+func (m *FileBrowserActionViewModel) handleDelete(model *Model, file *models.ContainerFile, container *docker.Container) tea.Cmd {
+	paths := []string{filepath.Join(m.containerPath, file.Name)}
+	if len(m.selection) > 0 {
+		paths = m.sourcePaths()
+		model.fileBrowserViewModel.selected = nil
+	}
 
 	// Show confirmation dialog for delete (true = aggressive operation)
-	return model.commandExecutionViewModel.ExecuteCommand(model, true, args...)
+	return model.runFileCommand(container, true, "rm", append([]string{"-r", "-f"}, paths...)...)
 }
 
 // handleExecuteInDirectory handles executing a command in a specific directory
@@ -260,13 +664,25 @@ func (m *FileBrowserActionViewModel) renderInputMode() string {
 		Bold(true).
 		Foreground(lipgloss.Color("86"))
 
-	s.WriteString(titleStyle.Render("Copy File to Local"))
-	s.WriteString("\n\n")
-
-	// File info
 	infoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-	s.WriteString(infoStyle.Render(fmt.Sprintf("Source: %s/%s", m.containerPath, m.targetFile.Name)))
-	s.WriteString("\n\n")
+	switch m.inputKind {
+	case fileInputCopyFromLocal, fileInputMkdir, fileInputTouch:
+		s.WriteString(titleStyle.Render(m.inputTitle()))
+		s.WriteString("\n\n")
+		s.WriteString(infoStyle.Render(fmt.Sprintf("Destination: %s", m.containerPath)))
+		s.WriteString("\n\n")
+	default:
+		s.WriteString(titleStyle.Render(m.inputTitle()))
+		s.WriteString("\n\n")
+
+		// File info
+		if len(m.selection) > 0 {
+			s.WriteString(infoStyle.Render(fmt.Sprintf("Source: %d entries in %s", len(m.selection), m.containerPath)))
+		} else {
+			s.WriteString(infoStyle.Render(fmt.Sprintf("Source: %s/%s", m.containerPath, m.targetFile.Name)))
+		}
+		s.WriteString("\n\n")
+	}
 
 	// Input prompt
 	promptStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
@@ -288,13 +704,72 @@ func (m *FileBrowserActionViewModel) renderInputMode() string {
 	s.WriteString(inputStyle.Render(inputWithCursor))
 	s.WriteString("\n\n")
 
+	// Candidates of an ambiguous completion
+	if candidates := m.completion.render(); candidates != "" {
+		s.WriteString(candidates)
+		s.WriteString("\n\n")
+	}
+
 	// Help text
 	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	if len(m.conflicts) > 0 {
+		s.WriteString(m.renderConflicts())
+		return s.String()
+	}
 	s.WriteString(helpStyle.Render("Press Enter to confirm, Esc to cancel"))
 
 	return s.String()
 }
 
+// inputTitle names the action the input is for
+func (m *FileBrowserActionViewModel) inputTitle() string {
+	switch m.inputKind {
+	case fileInputCopyFromLocal:
+		return "Copy from Local"
+	case fileInputArchive:
+		return "Save as .tar.gz"
+	case fileInputRename:
+		return "Rename/Move"
+	case fileInputChmod:
+		return "Change Permissions"
+	case fileInputChown:
+		return "Change Owner"
+	case fileInputMkdir:
+		return "New Directory"
+	case fileInputTouch:
+		return "New File"
+	default:
+		return "Copy File to Local"
+	}
+}
+
+// renderConflicts lists the local paths that exist and the choices for them
+func (m *FileBrowserActionViewModel) renderConflicts() string {
+	var s strings.Builder
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("220")).Bold(true)
+	infoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	s.WriteString(warnStyle.Render(fmt.Sprintf("%d already exist locally:", len(m.conflicts))))
+	s.WriteString("\n")
+	const maxConflicts = 5
+	for i, c := range m.conflicts {
+		if i == maxConflicts {
+			s.WriteString(infoStyle.Render(fmt.Sprintf("  ... and %d more", len(m.conflicts)-maxConflicts)))
+			s.WriteString("\n")
+			break
+		}
+		s.WriteString(infoStyle.Render("  " + c))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	if m.inputKind == fileInputArchive {
+		s.WriteString(helpStyle.Render("[o] overwrite  [r] save under a new name  [esc] edit the path"))
+	} else {
+		s.WriteString(helpStyle.Render("[o] overwrite  [s] skip existing files  [r] copy under a new name  [esc] edit the path"))
+	}
+	return s.String()
+}
+
 func (m *FileBrowserActionViewModel) getFileType() string {
 	if m.targetFile.IsDir {
 		return "Directory"
@@ -323,13 +798,21 @@ func (m *FileBrowserActionViewModel) HandleDown() {
 func (m *FileBrowserActionViewModel) HandleSelect(model *Model) tea.Cmd {
 	// If in input mode, confirm the input
 	if m.inputMode {
-		destPath := strings.TrimSpace(m.inputBuffer)
-		if destPath != "" {
+		path := strings.TrimSpace(m.inputBuffer)
+		if path == "" {
+			return nil
+		}
+		switch m.inputKind {
+		case fileInputCopyFromLocal:
 			m.inputMode = false
+			m.completion.reset()
 			model.SwitchToPreviousView() // Go back to file browser
-			return m.handleCopyToLocal(model, destPath)
+			return m.handleCopyFromLocal(model, path)
+		case fileInputRename, fileInputChmod, fileInputChown, fileInputMkdir, fileInputTouch:
+			return m.handleManageInput(model, path)
 		}
-		return nil
+		// The copy starts once the path is confirmed and conflicts are resolved
+		return m.handleCopyToLocal(model, path)
 	}
 
 	// Otherwise, execute the selected action
@@ -348,6 +831,7 @@ func (m *FileBrowserActionViewModel) HandleBack(model *Model) tea.Cmd {
 		m.inputMode = false
 		m.inputBuffer = ""
 		m.inputCursorPos = 0
+		m.completion.reset()
 		return nil
 	}
 	// Return to file browser
@@ -360,8 +844,20 @@ func (m *FileBrowserActionViewModel) HandleInput(model *Model, msg tea.KeyPressM
 	if !m.inputMode {
 		return model, nil
 	}
+	if len(m.conflicts) > 0 {
+		return model, m.handleConflictKey(model, msg)
+	}
+
+	if msg.Code != tea.KeyTab {
+		m.completion.reset()
+	}
 
 	switch msg.Code {
+	case tea.KeyTab:
+		if m.inputKind == fileInputCopyFromLocal {
+			m.completeInput()
+		}
+
 	case tea.KeyEnter:
 		// Confirm input
 		return model, m.HandleSelect(model)
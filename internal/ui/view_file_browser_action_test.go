@@ -0,0 +1,410 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+func TestFileBrowserActionViewModel_CopyFromLocal(t *testing.T) {
+	container := docker.NewContainer("abc123", "web", "web", "running")
+	file := &models.ContainerFile{Name: "etc", IsDir: true}
+
+	t.Run("action is offered", func(t *testing.T) {
+		vm := &FileBrowserActionViewModel{}
+		vm.Initialize(file, container, "/app")
+
+		var names []string
+		for _, action := range vm.actions {
+			names = append(names, action.Name)
+		}
+		assert.Contains(t, names, "Copy from Local")
+	})
+
+	t.Run("selecting the action prompts for a local path", func(t *testing.T) {
+		model := &Model{currentView: FileBrowserActionView}
+		vm := &FileBrowserActionViewModel{}
+		vm.Initialize(file, container, "/app")
+		for i, action := range vm.actions {
+			if action.Name == "Copy from Local" {
+				vm.selectedAction = i
+			}
+		}
+
+		cmd := vm.HandleSelect(model)
+		assert.Nil(t, cmd)
+		assert.True(t, vm.inputMode)
+		assert.Equal(t, fileInputCopyFromLocal, vm.inputKind)
+
+		view := vm.render(model)
+		assert.Contains(t, view, "Copy from Local")
+		assert.Contains(t, view, "Destination: /app")
+	})
+
+	t.Run("tab completes the local path", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "upload-a.txt"), nil, 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "upload-b.txt"), nil, 0644))
+
+		model := &Model{currentView: FileBrowserActionView}
+		vm := &FileBrowserActionViewModel{}
+		vm.Initialize(file, container, "/app")
+		vm.startUploadInputMode()
+		vm.inputBuffer = dir + "/up"
+		vm.inputCursorPos = len(vm.inputBuffer)
+
+		vm.HandleInput(model, newSpecialKey(tea.KeyTab))
+		assert.Equal(t, dir+"/upload-", vm.inputBuffer)
+		assert.Equal(t, len(vm.inputBuffer), vm.inputCursorPos)
+		assert.Equal(t, []string{"upload-a.txt", "upload-b.txt"}, vm.completion.candidates)
+		assert.Contains(t, vm.render(model), "upload-b.txt")
+
+		// Typing narrows the choice and hides the candidates
+		vm.HandleInput(model, newKeyPress("b"))
+		assert.Nil(t, vm.completion.candidates)
+		vm.HandleInput(model, newSpecialKey(tea.KeyTab))
+		assert.Equal(t, dir+"/upload-b.txt", vm.inputBuffer)
+	})
+
+	t.Run("tab does nothing for copy to local", func(t *testing.T) {
+		model := &Model{currentView: FileBrowserActionView}
+		vm := &FileBrowserActionViewModel{}
+		vm.Initialize(file, container, "/app")
+		vm.startInputMode(file)
+		before := vm.inputBuffer
+
+		vm.HandleInput(model, newSpecialKey(tea.KeyTab))
+		assert.Equal(t, before, vm.inputBuffer)
+	})
+
+	t.Run("confirming starts the upload and returns to the browser", func(t *testing.T) {
+		model := &Model{currentView: FileBrowserView}
+		model.SwitchView(FileBrowserActionView)
+		vm := &FileBrowserActionViewModel{}
+		vm.Initialize(file, container, "/app")
+		vm.startUploadInputMode()
+		vm.inputBuffer = "/nonexistent/dcv-upload-test"
+
+		cmd := vm.HandleSelect(model)
+		require.NotNil(t, cmd)
+		assert.False(t, vm.inputMode)
+		assert.True(t, model.loading)
+		assert.Equal(t, FileBrowserView, model.currentView)
+
+		// The local path does not exist, so the copy fails before docker is called
+		msg, ok := cmd().(fileUploadedMsg)
+		require.True(t, ok)
+		assert.Equal(t, "/app", msg.containerDir)
+		assert.Error(t, msg.err)
+		assert.Contains(t, msg.err.Error(), "cannot access local path")
+	})
+}
+
+func TestFileBrowserViewModel_FileUploaded(t *testing.T) {
+	container := docker.NewContainer("abc123", "web", "web", "running")
+
+	t.Run("error is reported", func(t *testing.T) {
+		model := &Model{currentView: FileBrowserView, loading: true}
+		vm := &FileBrowserViewModel{browsingContainer: container, currentPath: "/app"}
+
+		_, cmd := vm.Update(model, fileUploadedMsg{err: errors.New("permission denied while copying")})
+		assert.Nil(t, cmd)
+		assert.False(t, model.loading)
+		assert.EqualError(t, model.err, "permission denied while copying")
+	})
+
+	t.Run("success refreshes the listing", func(t *testing.T) {
+		model := &Model{currentView: FileBrowserView, loading: true, err: errors.New("old")}
+		vm := &FileBrowserViewModel{browsingContainer: container, currentPath: "/app"}
+
+		_, cmd := vm.Update(model, fileUploadedMsg{localPath: "/tmp/a", containerDir: "/app"})
+		assert.NotNil(t, cmd)
+		assert.Nil(t, model.err)
+		assert.True(t, model.loading)
+	})
+}
+
+func TestFileBrowserActionViewModel_HelperTools(t *testing.T) {
+	container := docker.NewContainer("abc123", "web", "web", "running")
+
+	actionNames := func(vm *FileBrowserActionViewModel) []string {
+		var names []string
+		for _, action := range vm.actions {
+			names = append(names, action.Name)
+		}
+		return names
+	}
+
+	t.Run("directories offer find and disk usage", func(t *testing.T) {
+		vm := &FileBrowserActionViewModel{}
+		vm.Initialize(&models.ContainerFile{Name: "etc", IsDir: true}, container, "/")
+		names := actionNames(vm)
+		assert.Contains(t, names, "Stat")
+		assert.Contains(t, names, "Find")
+		assert.Contains(t, names, "Disk Usage")
+		assert.Contains(t, names, "Grep")
+		assert.NotContains(t, names, "Follow")
+		assert.NotContains(t, names, "SHA-256")
+	})
+
+	t.Run("files offer follow and checksum", func(t *testing.T) {
+		vm := &FileBrowserActionViewModel{}
+		vm.Initialize(&models.ContainerFile{Name: "app.log"}, container, "/var/log")
+		names := actionNames(vm)
+		assert.Contains(t, names, "Stat")
+		assert.Contains(t, names, "Follow")
+		assert.Contains(t, names, "SHA-256")
+		assert.Contains(t, names, "Grep")
+		assert.NotContains(t, names, "Find")
+		assert.NotContains(t, names, "Disk Usage")
+	})
+
+	t.Run("grep opens the search form", func(t *testing.T) {
+		model := &Model{currentView: FileBrowserActionView, viewHistory: []ViewType{FileBrowserView}}
+		vm := &FileBrowserActionViewModel{}
+		vm.Initialize(&models.ContainerFile{Name: "etc", IsDir: true}, container, "/")
+		for i, action := range vm.actions {
+			if action.Name == "Grep" {
+				vm.selectedAction = i
+			}
+		}
+
+		assert.Nil(t, vm.HandleSelect(model))
+		assert.Equal(t, FileSearchView, model.currentView)
+		assert.True(t, model.fileSearchViewModel.formActive)
+		assert.Equal(t, "/etc", model.fileSearchViewModel.fields[searchFieldDir].value)
+
+		model.SwitchToPreviousView()
+		assert.Equal(t, FileBrowserView, model.currentView)
+	})
+}
+
+func TestModel_HandleHelperCommandReady(t *testing.T) {
+	container := docker.NewContainer("abc123", "web", "web", "running")
+
+	t.Run("error is shown", func(t *testing.T) {
+		model := &Model{loading: true}
+		cmd := model.handleHelperCommandReady(helperCommandReadyMsg{
+			container: container,
+			err:       errors.New("read-only file system"),
+		})
+		assert.Nil(t, cmd)
+		assert.False(t, model.loading)
+		require.Error(t, model.err)
+		assert.Contains(t, model.err.Error(), "read-only file system")
+	})
+
+	t.Run("command is executed", func(t *testing.T) {
+		model := &Model{loading: true, currentView: FileBrowserView}
+		cmd := model.handleHelperCommandReady(helperCommandReadyMsg{
+			container: container,
+			args:      []string{"exec", "abc123", "/.dcv-helper", "ps"},
+		})
+		assert.NotNil(t, cmd)
+		assert.False(t, model.loading)
+		assert.Equal(t, CommandExecutionView, model.currentView)
+	})
+}
+
+func TestFileBrowserActionViewModel_RestrictToVolume(t *testing.T) {
+	container := docker.NewContainer("browser123", "volume data", "volume data", "running")
+	actionNames := func(vm *FileBrowserActionViewModel) []string {
+		var names []string
+		for _, action := range vm.actions {
+			names = append(names, action.Name)
+		}
+		return names
+	}
+
+	vm := &FileBrowserActionViewModel{}
+	vm.Initialize(&models.ContainerFile{Name: "app.db"}, container, "/volume")
+	vm.restrictToVolume(false)
+	names := actionNames(vm)
+	assert.Contains(t, names, "Copy to Local")
+	assert.Contains(t, names, "View File")
+	assert.NotContains(t, names, "Copy from Local")
+	assert.NotContains(t, names, "Edit")
+	assert.NotContains(t, names, "Delete")
+	assert.NotContains(t, names, "Rename/Move")
+	assert.NotContains(t, names, "New Directory")
+
+	vm.Initialize(&models.ContainerFile{Name: "backups", IsDir: true}, container, "/volume")
+	vm.restrictToVolume(true)
+	names = actionNames(vm)
+	assert.Contains(t, names, "Copy from Local")
+	assert.Contains(t, names, "Delete")
+	assert.NotContains(t, names, "Execute Command", "the container has no shell")
+}
+
+func TestFileBrowserActionViewModel_RestrictToImage(t *testing.T) {
+	container := docker.NewContainer("created123", "image alpine:3", "image alpine:3", "created")
+	vm := &FileBrowserActionViewModel{}
+	vm.Initialize(&models.ContainerFile{Name: "os-release"}, container, "/etc")
+	vm.restrictToImage()
+
+	var names []string
+	for _, action := range vm.actions {
+		names = append(names, action.Name)
+	}
+	assert.Equal(t, []string{"Copy to Local", "Save as .tar.gz", "View File"}, names)
+}
+
+func TestFileBrowserActionViewModel_CopySelectionToLocal(t *testing.T) {
+	container := docker.NewContainer("abc123", "web", "web", "running")
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), nil, 0644))
+
+	model := &Model{currentView: FileBrowserView}
+	model.SwitchView(FileBrowserActionView)
+	model.fileBrowserViewModel.selected = map[string]bool{"main.go": true, "go.mod": true}
+	vm := &model.fileBrowserActionViewModel
+	vm.Initialize(&models.ContainerFile{Name: "main.go"}, container, "/src")
+	vm.SetSelection([]models.ContainerFile{{Name: "go.mod"}, {Name: "main.go"}})
+	assert.Equal(t, []string{"/src/go.mod", "/src/main.go"}, vm.sourcePaths())
+
+	vm.startInputMode(vm.targetFile)
+	assert.Equal(t, "Enter destination directory for 2 entries: ", vm.inputPrompt)
+	assert.True(t, strings.HasSuffix(vm.inputBuffer, string(filepath.Separator)))
+
+	// An existing file needs a decision before the copy starts
+	vm.inputBuffer = dir
+	assert.Nil(t, vm.HandleSelect(model))
+	assert.Equal(t, []string{filepath.Join(dir, "main.go")}, vm.conflicts)
+	assert.Equal(t, FileBrowserActionView, model.currentView)
+	assert.Contains(t, vm.render(model), "[s] skip existing files")
+
+	// Esc goes back to editing the path
+	vm.HandleInput(model, newSpecialKey(tea.KeyEsc))
+	assert.Empty(t, vm.conflicts)
+	assert.True(t, vm.inputMode)
+
+	vm.HandleSelect(model)
+	_, cmd := vm.HandleInput(model, newKeyPress("s"))
+	assert.NotNil(t, cmd)
+	assert.False(t, vm.inputMode)
+	assert.Equal(t, FileBrowserView, model.currentView)
+	assert.Empty(t, model.fileBrowserViewModel.selected)
+	require.NotNil(t, model.fileTransfer)
+	assert.Equal(t, dir, model.fileTransfer.dest)
+
+	// The copy must have stopped writing into dir before the test cleans it up
+	model.fileTransfer.cancel()
+	require.Eventually(t, model.fileTransfer.isDone, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestFileBrowserActionViewModel_SaveArchive(t *testing.T) {
+	container := docker.NewContainer("abc123", "web", "web", "running")
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "logs.tar.gz")
+	require.NoError(t, os.WriteFile(archive, nil, 0644))
+
+	model := &Model{currentView: FileBrowserView}
+	model.SwitchView(FileBrowserActionView)
+	vm := &model.fileBrowserActionViewModel
+	vm.Initialize(&models.ContainerFile{Name: "logs", IsDir: true}, container, "/var")
+	vm.startArchiveInputMode()
+	assert.True(t, strings.HasSuffix(vm.inputBuffer, "logs.tar.gz"))
+
+	vm.inputBuffer = archive
+	assert.Nil(t, vm.HandleSelect(model))
+	assert.Equal(t, []string{archive}, vm.conflicts)
+	assert.NotContains(t, vm.render(model), "skip")
+
+	// Skipping makes no sense for one archive
+	_, cmd := vm.HandleInput(model, newKeyPress("s"))
+	assert.Nil(t, cmd)
+	assert.NotEmpty(t, vm.conflicts)
+}
+
+func TestFileBrowserActionViewModel_DeleteInVolume(t *testing.T) {
+	model := NewModel(FileBrowserView)
+	model.fileBrowserViewModel.volumeName = "data"
+	container := docker.NewContainer("browser123", "volume data", "volume data", "running")
+
+	vm := &FileBrowserActionViewModel{containerPath: "/volume"}
+	vm.handleDelete(model, &models.ContainerFile{Name: "old", IsDir: true}, container)
+	assert.Equal(t, []string{"exec", "browser123", "/.dcv-helper", "rm", "-r", "-f", "/volume/old"},
+		model.commandExecutionViewModel.pendingArgs)
+}
+
+func TestFileBrowserActionViewModel_ManageFiles(t *testing.T) {
+	container := docker.NewContainer("browser123", "volume data", "volume data", "running")
+	file := &models.ContainerFile{Name: "app.conf", Permissions: "-rw-r--r--", Owner: "app", Group: "staff"}
+	newModel := func() (*Model, *FileBrowserActionViewModel) {
+		model := NewModel(FileBrowserView)
+		model.fileBrowserViewModel.volumeName = "data"
+		model.SwitchView(FileBrowserActionView)
+		vm := &model.fileBrowserActionViewModel
+		vm.Initialize(file, container, "/volume/etc")
+		return model, vm
+	}
+
+	t.Run("rename is confirmed first", func(t *testing.T) {
+		model, vm := newModel()
+		vm.startManageInputMode(fileInputRename)
+		assert.Equal(t, "/volume/etc/app.conf", vm.inputBuffer)
+		assert.Contains(t, vm.render(model), "Rename/Move")
+
+		vm.inputBuffer = "../app.conf.bak"
+		assert.Nil(t, vm.HandleSelect(model))
+		assert.False(t, vm.inputMode)
+		assert.Equal(t, CommandExecutionView, model.currentView)
+		assert.True(t, model.commandExecutionViewModel.pendingConfirmation)
+		assert.Equal(t, []string{"exec", "browser123", "/.dcv-helper", "mv", "/volume/etc/app.conf", "/volume/app.conf.bak"},
+			model.commandExecutionViewModel.pendingArgs)
+	})
+
+	t.Run("current mode and owner are suggested", func(t *testing.T) {
+		model, vm := newModel()
+		vm.startManageInputMode(fileInputChmod)
+		assert.Equal(t, "644", vm.inputBuffer)
+		vm.inputBuffer = "u+x"
+		vm.HandleSelect(model)
+		assert.Equal(t, []string{"exec", "browser123", "/.dcv-helper", "chmod", "u+x", "/volume/etc/app.conf"},
+			model.commandExecutionViewModel.pendingArgs)
+
+		_, vm = newModel()
+		vm.startManageInputMode(fileInputChown)
+		assert.Equal(t, "app:staff", vm.inputBuffer)
+	})
+
+	t.Run("creating entries needs no confirmation", func(t *testing.T) {
+		model, vm := newModel()
+		vm.startManageInputMode(fileInputMkdir)
+		assert.Empty(t, vm.inputBuffer)
+		vm.inputBuffer = "conf.d"
+		assert.NotNil(t, vm.HandleSelect(model))
+		assert.Equal(t, CommandExecutionView, model.currentView)
+		assert.False(t, model.commandExecutionViewModel.pendingConfirmation)
+	})
+
+	t.Run("selected entries are deleted together", func(t *testing.T) {
+		model, vm := newModel()
+		model.fileBrowserViewModel.selected = map[string]bool{"a.conf": true, "b.conf": true}
+		vm.SetSelection([]models.ContainerFile{{Name: "a.conf"}, {Name: "b.conf"}})
+		vm.handleDelete(model, file, container)
+		assert.Equal(t, []string{"exec", "browser123", "/.dcv-helper", "rm", "-r", "-f", "/volume/etc/a.conf", "/volume/etc/b.conf"},
+			model.commandExecutionViewModel.pendingArgs)
+		assert.Empty(t, model.fileBrowserViewModel.selected)
+	})
+
+	t.Run("running containers resolve the tool first", func(t *testing.T) {
+		model, vm := newModel()
+		model.fileBrowserViewModel.volumeName = ""
+		vm.startManageInputMode(fileInputTouch)
+		vm.inputBuffer = "/tmp/ready"
+		assert.NotNil(t, vm.HandleSelect(model))
+		assert.True(t, model.loading)
+		assert.Equal(t, FileBrowserView, model.currentView)
+	})
+}
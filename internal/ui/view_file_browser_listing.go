@@ -0,0 +1,334 @@
+package ui
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/table"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+// fileSortKey is what the entries of the file browser are sorted by
+type fileSortKey int
+
+const (
+	// sortByName keeps the order of ls, which sorts by name
+	sortByName fileSortKey = iota
+	sortBySize
+	sortByModTime
+	sortByType
+)
+
+func (k fileSortKey) String() string {
+	switch k {
+	case sortBySize:
+		return "size"
+	case sortByModTime:
+		return "mtime"
+	case sortByType:
+		return "type"
+	default:
+		return "name"
+	}
+}
+
+// fileBrowserFullWidth is a width that shows every column
+const fileBrowserFullWidth = 80
+
+// directorySizesMsg contains the disk usage of the directories of a listing, by path
+type directorySizesMsg struct {
+	dir   string
+	sizes map[string]int64
+	err   error
+}
+
+// fileColumn is a column of the listing with the value it shows for each entry
+type fileColumn struct {
+	table.Column
+	value func(file models.ContainerFile) string
+}
+
+// applyListing filters and sorts the listed entries into the shown ones, keeping the cursor on its entry
+func (m *FileBrowserViewModel) applyListing() {
+	current := ""
+	if m.Cursor < len(m.containerFiles) {
+		current = m.containerFiles[m.Cursor].Name
+	}
+
+	files := make([]models.ContainerFile, 0, len(m.allFiles))
+	for _, file := range m.allFiles {
+		if m.hideDotfiles && strings.HasPrefix(file.Name, ".") && file.Name != "." && file.Name != ".." {
+			continue
+		}
+		files = append(files, file)
+	}
+	slices.SortStableFunc(files, m.compareFiles)
+	m.containerFiles = files
+
+	if i := slices.IndexFunc(files, func(f models.ContainerFile) bool { return f.Name == current }); i >= 0 {
+		m.Cursor = i
+	}
+}
+
+// compareFiles orders two entries of the listing; "." and ".." always come first
+func (m *FileBrowserViewModel) compareFiles(a, b models.ContainerFile) int {
+	if c := cmp.Compare(dotRank(a.Name), dotRank(b.Name)); c != 0 {
+		return c
+	}
+	if m.dirsFirst && m.isDir(a) != m.isDir(b) {
+		if m.isDir(a) {
+			return -1
+		}
+		return 1
+	}
+
+	var c int
+	switch m.sortBy {
+	case sortBySize:
+		// Largest first, which is what is looked for when a disk fills up
+		c = cmp.Compare(m.entrySize(b), m.entrySize(a))
+	case sortByModTime:
+		// Newest first
+		c = b.ModTime.Compare(a.ModTime)
+	case sortByType:
+		c = cmp.Or(cmp.Compare(m.typeRank(a), m.typeRank(b)), cmp.Compare(path.Ext(a.Name), path.Ext(b.Name)))
+	}
+	if m.sortReverse {
+		c = -c
+	}
+	return cmp.Or(c, compareNames(a.Name, b.Name))
+}
+
+// dotRank keeps "." and ".." at the top of the listing whatever it is sorted by
+func dotRank(name string) int {
+	switch name {
+	case ".":
+		return 0
+	case "..":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// compareNames orders names case-insensitively, like ls in most locales
+func compareNames(a, b string) int {
+	return cmp.Or(cmp.Compare(strings.ToLower(a), strings.ToLower(b)), cmp.Compare(a, b))
+}
+
+// isDir tells whether an entry is a directory or a link to one
+func (m *FileBrowserViewModel) isDir(file models.ContainerFile) bool {
+	if file.IsDir {
+		return true
+	}
+	link, ok := m.links[file.Name]
+	return file.LinkTarget != "" && ok && link.IsDir
+}
+
+// typeRank orders directories before links and links before files
+func (m *FileBrowserViewModel) typeRank(file models.ContainerFile) int {
+	switch {
+	case file.IsDir:
+		return 0
+	case file.LinkTarget != "":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// entrySize is the size of a file, or of what a directory holds once its size has been computed
+func (m *FileBrowserViewModel) entrySize(file models.ContainerFile) int64 {
+	if size, ok := m.dirSizes[path.Join(m.currentPath, file.Name)]; ok && file.IsDir {
+		return size
+	}
+	return file.Size
+}
+
+// sizeString shows the computed size of a directory, or "-" when it is not known
+func (m *FileBrowserViewModel) sizeString(file models.ContainerFile) string {
+	if size, ok := m.dirSizes[path.Join(m.currentPath, file.Name)]; ok && file.IsDir {
+		return models.FormatSize(size)
+	}
+	return file.GetSizeString()
+}
+
+// fileColumns returns the columns that fit the screen width, without those that were turned off.
+// Minimum width needed: permissions(11) + size(10) + name(20 min) = 41
+// Medium width adds: links(5) = 46
+// Full width adds: owner(10) + group(10) = 66
+func (m *FileBrowserViewModel) fileColumns(width int) []fileColumn {
+	var columns []fileColumn
+	if !m.hidePermissions {
+		columns = append(columns, fileColumn{table.Column{Title: "PERMISSIONS", Width: 11},
+			func(f models.ContainerFile) string { return f.Permissions }})
+	}
+	if width >= 60 {
+		columns = append(columns, fileColumn{table.Column{Title: "LINKS", Width: 5},
+			func(f models.ContainerFile) string { return f.Links }})
+	}
+	if width >= 80 && !m.hideOwner {
+		columns = append(columns,
+			fileColumn{table.Column{Title: "OWNER", Width: 10}, func(f models.ContainerFile) string { return f.Owner }},
+			fileColumn{table.Column{Title: "GROUP", Width: 10}, func(f models.ContainerFile) string { return f.Group }})
+	}
+	columns = append(columns, fileColumn{table.Column{Title: "SIZE", Width: 10}, m.sizeString})
+	if m.sortBy == sortByModTime {
+		columns = append(columns, fileColumn{table.Column{Title: "MODIFIED", Width: 16}, formatModTime})
+	}
+	if m.showLayers && m.imageLayers != nil {
+		columns = append(columns, fileColumn{table.Column{Title: "LAYER", Width: 14}, m.layerLabel})
+	}
+	return append(columns, fileColumn{table.Column{Title: "NAME", Width: -1}, m.renderName})
+}
+
+// formatModTime shows the modification time of an entry, "-" when ls did not tell it
+func formatModTime(file models.ContainerFile) string {
+	if file.ModTime.IsZero() {
+		return "-"
+	}
+	// Listings of ls, the helper and image archives agree in the local zone
+	return file.ModTime.Local().Format("2006-01-02 15:04")
+}
+
+// rowsFor builds the rows of the shown entries for the columns
+func (m *FileBrowserViewModel) rowsFor(columns []fileColumn) []table.Row {
+	rows := make([]table.Row, 0, len(m.containerFiles))
+	for _, file := range m.containerFiles {
+		row := make(table.Row, len(columns))
+		for i, c := range columns {
+			row[i] = c.value(file)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// relist applies the sort order and filters and shows the result
+func (m *FileBrowserViewModel) relist(model *Model) {
+	m.applyListing()
+	m.SetRows(m.buildRows(), model.ViewHeight())
+}
+
+// HandleCycleSort sorts the entries by the next of name, size, mtime and type
+func (m *FileBrowserViewModel) HandleCycleSort(model *Model) tea.Cmd {
+	m.sortBy = (m.sortBy + 1) % (sortByType + 1)
+	m.sortReverse = false
+	m.relist(model)
+	return nil
+}
+
+// HandleReverseSort reverses the sort order
+func (m *FileBrowserViewModel) HandleReverseSort(model *Model) tea.Cmd {
+	m.sortReverse = !m.sortReverse
+	m.relist(model)
+	return nil
+}
+
+// HandleToggleDirsFirst lists directories before files, or mixes them
+func (m *FileBrowserViewModel) HandleToggleDirsFirst(model *Model) tea.Cmd {
+	m.dirsFirst = !m.dirsFirst
+	m.relist(model)
+	return nil
+}
+
+// HandleToggleDotfiles hides or shows the entries whose names start with a dot
+func (m *FileBrowserViewModel) HandleToggleDotfiles(model *Model) tea.Cmd {
+	m.hideDotfiles = !m.hideDotfiles
+	m.relist(model)
+	return nil
+}
+
+// HandleToggleOwnerColumns hides or shows the owner and group columns
+func (m *FileBrowserViewModel) HandleToggleOwnerColumns(model *Model) tea.Cmd {
+	m.hideOwner = !m.hideOwner
+	m.SetRows(m.buildRows(), model.ViewHeight())
+	return nil
+}
+
+// HandleTogglePermissionsColumn hides or shows the permissions column
+func (m *FileBrowserViewModel) HandleTogglePermissionsColumn(model *Model) tea.Cmd {
+	m.hidePermissions = !m.hidePermissions
+	m.SetRows(m.buildRows(), model.ViewHeight())
+	return nil
+}
+
+// HandleDirectorySizes computes the disk usage of the directories of the current directory,
+// with du or the helper in containers and from the export of images, and sorts by size
+func (m *FileBrowserViewModel) HandleDirectorySizes(model *Model) tea.Cmd {
+	if m.browsingContainer == nil {
+		return nil
+	}
+	var paths []string
+	for _, file := range m.allFiles {
+		if file.IsDir && file.Name != "." && file.Name != ".." {
+			paths = append(paths, path.Join(m.currentPath, file.Name))
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	model.loading = true
+	dir := m.currentPath
+	if image := m.image; image != nil {
+		return func() tea.Msg {
+			return directorySizesMsg{dir: dir, sizes: image.DirectorySizes(paths...)}
+		}
+	}
+	container := m.browsingContainer
+	fileOperations := model.sharedFileOperations()
+	return func() tea.Msg {
+		sizes, err := fileOperations.DirectorySizes(context.Background(), container, paths...)
+		return directorySizesMsg{dir: dir, sizes: sizes, err: err}
+	}
+}
+
+// directorySizesComputed shows the sizes of the directories, largest first
+func (m *FileBrowserViewModel) directorySizesComputed(model *Model, msg directorySizesMsg) {
+	model.loading = false
+	if msg.err != nil {
+		model.err = msg.err
+		return
+	}
+	if msg.dir != m.currentPath {
+		return
+	}
+	model.err = nil
+	if m.dirSizes == nil {
+		m.dirSizes = make(map[string]int64, len(msg.sizes))
+	}
+	for p, size := range msg.sizes {
+		m.dirSizes[p] = size
+	}
+	m.sortBy = sortBySize
+	m.sortReverse = false
+	m.relist(model)
+}
+
+// listingSummary tells how the listing differs from the order of ls, "" when it does not
+func (m *FileBrowserViewModel) listingSummary() string {
+	var parts []string
+	if m.sortBy != sortByName {
+		parts = append(parts, "by "+m.sortBy.String())
+	}
+	if m.sortReverse {
+		parts = append(parts, "reversed")
+	}
+	if m.dirsFirst {
+		parts = append(parts, "dirs first")
+	}
+	if m.hideDotfiles {
+		parts = append(parts, "dotfiles hidden")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", strings.Join(parts, ", "))
+}
@@ -0,0 +1,354 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+// linksResolvedMsg tells where the symbolic links of a listed directory lead
+type linksResolvedMsg struct {
+	dir   string
+	links []docker.ResolvedPath
+	err   error
+}
+
+// pathResolvedMsg is sent when a path to go to has been resolved
+type pathResolvedMsg struct {
+	resolved docker.ResolvedPath
+	// logical keeps the path as it was given instead of going to its real path
+	logical bool
+	err     error
+}
+
+// pathCompletionListedMsg contains the entries of a directory for completing the path bar
+type pathCompletionListedMsg struct {
+	dir   string
+	files []models.ContainerFile
+	err   error
+}
+
+var brokenLinkStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+// pathBar is the editable path of the file browser
+type pathBar struct {
+	active     bool
+	value      string
+	cursor     int
+	completion pathCompletion
+	// pending is the input whose completion waits for a directory listing
+	pending string
+	// cacheDir and cacheFiles keep the last directory listed for completion
+	cacheDir   string
+	cacheFiles []models.ContainerFile
+}
+
+// resolver returns a function that follows the symbolic links of paths in what is browsed.
+// Resolving links runs on every listing, so the helper is used only when it is already injected.
+func (m *FileBrowserViewModel) resolver(model *Model) func(paths ...string) ([]docker.ResolvedPath, error) {
+	if image := m.image; image != nil {
+		return func(paths ...string) ([]docker.ResolvedPath, error) {
+			return image.Resolve(paths...), nil
+		}
+	}
+	container := m.browsingContainer
+	fileOperations := model.sharedFileOperations()
+	return func(paths ...string) ([]docker.ResolvedPath, error) {
+		return fileOperations.ResolvePaths(context.Background(), container, paths...)
+	}
+}
+
+// resolveLinks finds out which links of the listed directory point to directories and which are broken
+func (m *FileBrowserViewModel) resolveLinks(model *Model) tea.Cmd {
+	var paths []string
+	for _, file := range m.containerFiles {
+		if file.LinkTarget != "" {
+			paths = append(paths, path.Join(m.currentPath, file.Name))
+		}
+	}
+	if len(paths) == 0 || m.browsingContainer == nil {
+		return nil
+	}
+	dir := m.currentPath
+	resolve := m.resolver(model)
+	return func() tea.Msg {
+		links, err := resolve(paths...)
+		return linksResolvedMsg{dir: dir, links: links, err: err}
+	}
+}
+
+// linksResolved shows where the links of the current directory lead
+func (m *FileBrowserViewModel) linksResolved(model *Model, msg linksResolvedMsg) {
+	if msg.dir != m.currentPath {
+		return
+	}
+	if msg.err != nil {
+		// The links are still shown with their targets
+		slog.Debug("Failed to resolve links", slog.String("dir", msg.dir), slog.Any("error", msg.err))
+		return
+	}
+	m.links = make(map[string]docker.ResolvedPath, len(msg.links))
+	for _, link := range msg.links {
+		m.links[path.Base(link.Path)] = link
+	}
+	m.SetRows(m.buildRows(), model.ViewHeight())
+}
+
+// renderName styles the name of an entry by its type, marking links to directories and broken links
+func (m *FileBrowserViewModel) renderName(file models.ContainerFile) string {
+	dirStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("33"))
+	linkStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("51"))
+
+	name := file.GetDisplayName()
+	link, resolved := m.links[file.Name]
+	switch {
+	case file.IsDir:
+		name = dirStyle.Render(name)
+	case file.LinkTarget != "" && resolved && link.Broken:
+		name = brokenLinkStyle.Render(name + " (broken)")
+	case file.LinkTarget != "" && resolved && link.IsDir:
+		name = linkStyle.Render(file.Name + "/ -> " + file.LinkTarget)
+	case file.LinkTarget != "":
+		name = linkStyle.Render(name)
+	}
+	if m.selected[file.Name] {
+		name = fileSelectedStyle.Render("+ ") + name
+	}
+	return name
+}
+
+// HandleFollowLink goes to where the link under the cursor points: into a directory,
+// or to the directory of a file with the cursor on it
+func (m *FileBrowserViewModel) HandleFollowLink(model *Model) tea.Cmd {
+	if m.Cursor >= len(m.containerFiles) {
+		return nil
+	}
+	file := m.containerFiles[m.Cursor]
+	if file.LinkTarget == "" {
+		model.err = fmt.Errorf("%s is not a symbolic link", file.Name)
+		return nil
+	}
+	if link, ok := m.links[file.Name]; ok {
+		return m.goTo(model, link, false)
+	}
+	return m.resolveAndGo(model, path.Join(m.currentPath, file.Name), false)
+}
+
+// HandleGoToRealPath replaces the current directory, reached through symbolic links, with its real path
+func (m *FileBrowserViewModel) HandleGoToRealPath(model *Model) tea.Cmd {
+	if m.browsingContainer == nil {
+		return nil
+	}
+	return m.resolveAndGo(model, m.currentPath, false)
+}
+
+// resolveAndGo resolves p in the background and then goes to it
+func (m *FileBrowserViewModel) resolveAndGo(model *Model, p string, logical bool) tea.Cmd {
+	model.loading = true
+	resolve := m.resolver(model)
+	return func() tea.Msg {
+		resolved, err := resolve(p)
+		if err == nil && len(resolved) == 0 {
+			err = fmt.Errorf("failed to resolve %s", p)
+		}
+		if err != nil && logical {
+			// Without readlink or the helper, a typed path is listed as a directory as it is
+			slog.Debug("Failed to resolve path", slog.String("path", p), slog.Any("error", err))
+			return pathResolvedMsg{resolved: docker.ResolvedPath{Path: p, RealPath: p, IsDir: true}, logical: true}
+		}
+		if err != nil {
+			return pathResolvedMsg{err: err}
+		}
+		return pathResolvedMsg{resolved: resolved[0], logical: logical}
+	}
+}
+
+// goTo shows the directory of a resolved path, with the cursor on the file when it is not a directory.
+// A logical path keeps the symbolic links it was given with, so that going up returns through them.
+func (m *FileBrowserViewModel) goTo(model *Model, resolved docker.ResolvedPath, logical bool) tea.Cmd {
+	model.loading = false
+	if resolved.Broken {
+		model.err = fmt.Errorf("%s: no such file or directory", resolved.Path)
+		return nil
+	}
+	target := resolved.RealPath
+	if logical {
+		target = resolved.Path
+	}
+	focus := ""
+	if !resolved.IsDir {
+		target, focus = path.Dir(target), path.Base(target)
+	}
+	if target == m.currentPath && focus == "" {
+		return nil
+	}
+	model.err = nil
+	m.pushHistory(target)
+	m.Cursor = 0
+	m.focusName = focus
+	return m.DoLoad(model)
+}
+
+// HandleEditPath opens the path bar with the current directory
+func (m *FileBrowserViewModel) HandleEditPath() tea.Cmd {
+	if m.browsingContainer == nil {
+		return nil
+	}
+	value := m.currentPath
+	if value != "/" {
+		value += "/"
+	}
+	m.pathBar = pathBar{active: true, value: value, cursor: len(value), cacheDir: m.pathBar.cacheDir, cacheFiles: m.pathBar.cacheFiles}
+	return nil
+}
+
+// HandlePathInput edits the path bar. Tab completes the last element from the directory listing.
+func (m *FileBrowserViewModel) HandlePathInput(model *Model, msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	bar := &m.pathBar
+	if msg.Code != tea.KeyTab {
+		bar.completion.reset()
+		bar.pending = ""
+	}
+
+	switch {
+	case msg.Code == tea.KeyEnter:
+		bar.active = false
+		target := strings.TrimSpace(bar.value)
+		if target == "" {
+			return model, nil
+		}
+		if !path.IsAbs(target) {
+			target = path.Join(m.currentPath, target)
+		}
+		return model, m.resolveAndGo(model, path.Clean(target), true)
+	case msg.Code == tea.KeyEsc:
+		bar.active = false
+	case msg.Code == tea.KeyTab:
+		return model, m.completePath(model)
+	case msg.Code == tea.KeyBackspace || isCtrlKey(msg, 'h'):
+		if bar.cursor > 0 {
+			bar.value = bar.value[:bar.cursor-1] + bar.value[bar.cursor:]
+			bar.cursor--
+		}
+	case msg.Code == tea.KeyDelete:
+		if bar.cursor < len(bar.value) {
+			bar.value = bar.value[:bar.cursor] + bar.value[bar.cursor+1:]
+		}
+	case msg.Code == tea.KeyLeft || isCtrlKey(msg, 'b'):
+		if bar.cursor > 0 {
+			bar.cursor--
+		}
+	case msg.Code == tea.KeyRight || isCtrlKey(msg, 'f'):
+		if bar.cursor < len(bar.value) {
+			bar.cursor++
+		}
+	case msg.Code == tea.KeyHome || isCtrlKey(msg, 'a'):
+		bar.cursor = 0
+	case msg.Code == tea.KeyEnd || isCtrlKey(msg, 'e'):
+		bar.cursor = len(bar.value)
+	case isCtrlKey(msg, 'u'):
+		bar.value = bar.value[bar.cursor:]
+		bar.cursor = 0
+	case msg.Code == tea.KeySpace:
+		bar.value = bar.value[:bar.cursor] + " " + bar.value[bar.cursor:]
+		bar.cursor++
+	case len(msg.Text) > 0:
+		bar.value = bar.value[:bar.cursor] + msg.Text + bar.value[bar.cursor:]
+		bar.cursor += len(msg.Text)
+	}
+	return model, nil
+}
+
+// completionDir splits the path bar into the directory to list and the element to complete
+func (m *FileBrowserViewModel) completionDir(input string) (dir, dirPart, prefix string) {
+	if idx := strings.LastIndex(input, "/"); idx >= 0 {
+		dirPart, prefix = input[:idx+1], input[idx+1:]
+	} else {
+		prefix = input
+	}
+	dir = path.Clean(dirPart)
+	if !path.IsAbs(dirPart) {
+		dir = path.Join(m.currentPath, dirPart)
+	}
+	return dir, dirPart, prefix
+}
+
+// completePath completes the path bar from the listing of its directory, listing it first when needed
+func (m *FileBrowserViewModel) completePath(model *Model) tea.Cmd {
+	bar := &m.pathBar
+	if !canComplete(bar.value, bar.cursor) {
+		return nil
+	}
+	dir, _, _ := m.completionDir(bar.value)
+	switch dir {
+	case m.currentPath:
+		m.applyCompletion(m.containerFiles, m.links)
+		return nil
+	case bar.cacheDir:
+		m.applyCompletion(bar.cacheFiles, nil)
+		return nil
+	}
+
+	bar.pending = bar.value
+	if image := m.image; image != nil {
+		return func() tea.Msg {
+			files, err := image.List(dir)
+			return pathCompletionListedMsg{dir: dir, files: files, err: err}
+		}
+	}
+	container := m.browsingContainer
+	fileOperations := model.sharedFileOperations()
+	return func() tea.Msg {
+		files, err := fileOperations.ListFiles(context.Background(), container, dir)
+		return pathCompletionListedMsg{dir: dir, files: files, err: err}
+	}
+}
+
+// completionListed completes the path bar once the directory it names has been listed
+func (m *FileBrowserViewModel) completionListed(msg pathCompletionListedMsg) {
+	bar := &m.pathBar
+	if msg.err != nil {
+		slog.Debug("Failed to list directory for completion", slog.String("dir", msg.dir), slog.Any("error", msg.err))
+		bar.pending = ""
+		return
+	}
+	bar.cacheDir = msg.dir
+	bar.cacheFiles = msg.files
+	if !bar.active || bar.pending != bar.value {
+		return
+	}
+	bar.pending = ""
+	m.applyCompletion(msg.files, nil)
+}
+
+func (m *FileBrowserViewModel) applyCompletion(files []models.ContainerFile, links map[string]docker.ResolvedPath) {
+	bar := &m.pathBar
+	_, dirPart, prefix := m.completionDir(bar.value)
+	bar.value, bar.cursor = bar.completion.apply(bar.value, bar.cursor, func(string) (string, []string) {
+		return completeContainerPath(dirPart, prefix, files, links)
+	})
+}
+
+// renderPathBar renders the path bar and the candidates of an ambiguous completion
+func (m *FileBrowserViewModel) renderPathBar() string {
+	bar := m.pathBar
+	var s strings.Builder
+	promptStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
+	s.WriteString(promptStyle.Render("Go to: "))
+	s.WriteString(bar.value[:bar.cursor] + "█" + bar.value[bar.cursor:])
+	s.WriteString("\n")
+
+	if candidates := bar.completion.render(); candidates != "" {
+		s.WriteString(candidates)
+		s.WriteString("\n")
+	}
+	return s.String()
+}
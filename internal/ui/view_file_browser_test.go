@@ -3,7 +3,9 @@ package ui
 import (
 	"strings"
 	"testing"
+	"time"
 
+	tea "charm.land/bubbletea/v2"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/tokuhirom/dcv/internal/docker"
@@ -573,3 +575,335 @@ func TestFileBrowserViewModel_DoLoad(t *testing.T) {
 		// We don't execute it in tests as it would require a real container
 	})
 }
+
+func TestFileBrowserViewModel_LoadVolume(t *testing.T) {
+	model := &Model{
+		currentView: VolumeListView,
+		viewHistory: []ViewType{VolumeListView},
+	}
+	vm := &FileBrowserViewModel{}
+	container := docker.NewContainer("browser123", "volume data", "volume data", "running")
+
+	cmd := vm.LoadVolume(model, container, "data", false)
+	assert.NotNil(t, cmd)
+	assert.Equal(t, FileBrowserView, model.currentView)
+	assert.Equal(t, "/volume", vm.currentPath)
+	assert.Equal(t, "File Browser: volume data (read-only) [/volume]", vm.Title())
+
+	vm.volumeReadWrite = true
+	assert.Equal(t, "File Browser: volume data (read-write) [/volume]", vm.Title())
+
+	// Leaving the browser removes the container
+	cmd = vm.HandleBack(model)
+	assert.NotNil(t, cmd)
+	assert.Equal(t, VolumeListView, model.currentView)
+	assert.Empty(t, vm.volumeName)
+}
+
+func TestFileBrowserViewModel_LoadImage(t *testing.T) {
+	model := &Model{
+		currentView: ImageListView,
+		viewHistory: []ViewType{ImageListView},
+	}
+	vm := &FileBrowserViewModel{}
+	image := &docker.ImageFilesystem{
+		Image:     "alpine:3",
+		Container: docker.NewContainer("created123", "image alpine:3", "image alpine:3", "created"),
+	}
+
+	cmd := vm.LoadImage(model, image)
+	assert.NotNil(t, cmd)
+	assert.Equal(t, FileBrowserView, model.currentView)
+	assert.Equal(t, image.Container, vm.browsingContainer)
+	assert.Equal(t, "File Browser: image alpine:3 [/]", vm.Title())
+
+	// Nothing runs in the container of an image
+	assert.False(t, vm.requireRunning(model, image.Container))
+	assert.ErrorContains(t, model.err, "not available for image alpine:3")
+	model.err = nil
+	assert.True(t, vm.requireRunning(model, docker.NewContainer("web123", "web", "web", "running")))
+	assert.NoError(t, model.err)
+
+	// Leaving the browser removes the container
+	cmd = vm.HandleBack(model)
+	assert.NotNil(t, cmd)
+	assert.Equal(t, ImageListView, model.currentView)
+	assert.Nil(t, vm.image)
+	assert.Nil(t, vm.HandleToggleLayers(model), "layers are only shown for images")
+}
+
+func TestFileBrowserViewModel_Selection(t *testing.T) {
+	model := &Model{Height: 30}
+	vm := &FileBrowserViewModel{
+		browsingContainer: docker.NewContainer("abc123", "web", "web", "running"),
+	}
+	vm.pushHistory("/app")
+	vm.Loaded(model, []models.ContainerFile{
+		{Name: "..", IsDir: true},
+		{Name: "config", IsDir: true},
+		{Name: "main.go"},
+		{Name: "README.md"},
+	})
+
+	// ".." cannot be selected, but the cursor moves on
+	vm.HandleToggleSelect(model)
+	assert.Empty(t, vm.selected)
+	assert.Equal(t, 1, vm.Cursor)
+
+	vm.HandleToggleSelect(model)
+	vm.HandleDown(model)
+	vm.HandleToggleSelect(model)
+	assert.Equal(t, []string{"config", "README.md"}, fileNames(vm.selectedFiles()))
+	assert.Equal(t, "File Browser: web [/app] (2 selected)", vm.Title())
+
+	// Unselecting
+	vm.Cursor = 1
+	vm.HandleToggleSelect(model)
+	assert.Equal(t, []string{"README.md"}, fileNames(vm.selectedFiles()))
+
+	vm.HandleSelectAll(model)
+	assert.Len(t, vm.selectedFiles(), 3)
+	vm.HandleSelectAll(model)
+	assert.Empty(t, vm.selectedFiles())
+
+	// Entries that are gone after a reload are unselected, and changing the directory clears the selection
+	vm.HandleSelectAll(model)
+	vm.Loaded(model, []models.ContainerFile{{Name: "main.go"}})
+	assert.Len(t, vm.selected, 1)
+	vm.pushHistory("/tmp")
+	assert.Empty(t, vm.selected)
+}
+
+func fileNames(files []models.ContainerFile) []string {
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func TestFileBrowserViewModel_Symlinks(t *testing.T) {
+	model := &Model{Height: 30}
+	vm := &FileBrowserViewModel{
+		browsingContainer: docker.NewContainer("abc123", "web", "web", "running"),
+	}
+	vm.pushHistory("/")
+	vm.Loaded(model, []models.ContainerFile{
+		{Name: "bin", LinkTarget: "usr/bin"},
+		{Name: "current", LinkTarget: "/opt/missing"},
+		{Name: "vi", LinkTarget: "/etc/alternatives/vi"},
+		{Name: "etc", IsDir: true},
+	})
+	assert.NotNil(t, vm.resolveLinks(model), "links are resolved after listing")
+
+	vm.linksResolved(model, linksResolvedMsg{dir: "/", links: []docker.ResolvedPath{
+		{Path: "/bin", RealPath: "/usr/bin", IsDir: true},
+		{Path: "/current", Broken: true},
+		{Path: "/vi", RealPath: "/usr/bin/vim.basic"},
+	}})
+	assert.Contains(t, vm.renderName(vm.containerFiles[0]), "bin/ -> usr/bin")
+	assert.Contains(t, vm.renderName(vm.containerFiles[1]), "current -> /opt/missing (broken)")
+
+	// Links of another directory are ignored
+	vm.linksResolved(model, linksResolvedMsg{dir: "/usr", links: []docker.ResolvedPath{{Path: "/usr/bin", Broken: true}}})
+	assert.False(t, vm.links["bin"].Broken)
+
+	// A broken link cannot be opened
+	vm.Cursor = 1
+	assert.Nil(t, vm.HandleOpenFileOrDirectory(model))
+	assert.ErrorContains(t, model.err, "/current is a broken link to /opt/missing")
+	model.err = nil
+
+	// A link to a directory is entered under its own path, so that ".." leads back to "/"
+	vm.Cursor = 0
+	assert.NotNil(t, vm.HandleOpenFileOrDirectory(model))
+	assert.Equal(t, "/bin", vm.currentPath)
+
+	// Following a link to a file shows its directory with the cursor on it
+	vm.pushHistory("/")
+	vm.Loaded(model, []models.ContainerFile{{Name: "vi", LinkTarget: "/etc/alternatives/vi"}})
+	vm.links = map[string]docker.ResolvedPath{"vi": {Path: "/vi", RealPath: "/usr/bin/vim.basic"}}
+	assert.NotNil(t, vm.HandleFollowLink(model))
+	assert.Equal(t, "/usr/bin", vm.currentPath)
+	vm.Loaded(model, []models.ContainerFile{{Name: "vi"}, {Name: "vim.basic"}})
+	assert.Equal(t, 1, vm.Cursor)
+
+	// Only links can be followed
+	assert.Nil(t, vm.HandleFollowLink(model))
+	assert.ErrorContains(t, model.err, "vim.basic is not a symbolic link")
+}
+
+func TestFileBrowserViewModel_GoTo(t *testing.T) {
+	model := &Model{Height: 30}
+	vm := &FileBrowserViewModel{
+		browsingContainer: docker.NewContainer("abc123", "web", "web", "running"),
+	}
+	vm.pushHistory("/bin")
+
+	// The real path replaces the links of the current directory
+	assert.NotNil(t, vm.goTo(model, docker.ResolvedPath{Path: "/bin", RealPath: "/usr/bin", IsDir: true}, false))
+	assert.Equal(t, "/usr/bin", vm.currentPath)
+
+	// A typed path keeps its links
+	assert.NotNil(t, vm.goTo(model, docker.ResolvedPath{Path: "/bin", RealPath: "/usr/bin", IsDir: true}, true))
+	assert.Equal(t, "/bin", vm.currentPath)
+
+	assert.Nil(t, vm.goTo(model, docker.ResolvedPath{Path: "/nowhere", Broken: true}, true))
+	assert.ErrorContains(t, model.err, "/nowhere: no such file or directory")
+	assert.Equal(t, []string{"/bin", "/usr/bin", "/bin"}, vm.pathHistory)
+}
+
+func TestFileBrowserViewModel_PathBar(t *testing.T) {
+	model := &Model{Height: 30}
+	vm := &FileBrowserViewModel{
+		browsingContainer: docker.NewContainer("abc123", "web", "web", "running"),
+	}
+	vm.pushHistory("/etc")
+	vm.Loaded(model, []models.ContainerFile{
+		{Name: "nginx", IsDir: true},
+		{Name: "nsswitch.conf"},
+		{Name: "hosts"},
+	})
+
+	vm.HandleEditPath()
+	assert.True(t, vm.pathBar.active)
+	assert.Equal(t, "/etc/", vm.pathBar.value)
+
+	// Entries of the current directory are completed without listing it again
+	vm.HandlePathInput(model, newKeyPress("n"))
+	_, cmd := vm.HandlePathInput(model, newSpecialKey(tea.KeyTab))
+	assert.Nil(t, cmd)
+	assert.Equal(t, "/etc/n", vm.pathBar.value)
+	assert.Equal(t, []string{"nginx/", "nsswitch.conf"}, vm.pathBar.completion.candidates)
+	vm.HandlePathInput(model, newKeyPress("g"))
+	vm.HandlePathInput(model, newSpecialKey(tea.KeyTab))
+	assert.Equal(t, "/etc/nginx/", vm.pathBar.value)
+
+	// Other directories are listed first
+	_, cmd = vm.HandlePathInput(model, newSpecialKey(tea.KeyTab))
+	assert.NotNil(t, cmd)
+	vm.completionListed(pathCompletionListedMsg{dir: "/etc/nginx", files: []models.ContainerFile{{Name: "nginx.conf"}}})
+	assert.Equal(t, "/etc/nginx/nginx.conf", vm.pathBar.value)
+	assert.Contains(t, vm.renderPathBar(), "/etc/nginx/nginx.conf█")
+
+	_, cmd = vm.HandlePathInput(model, newSpecialKey(tea.KeyEnter))
+	assert.NotNil(t, cmd)
+	assert.False(t, vm.pathBar.active)
+	assert.True(t, model.loading)
+
+	vm.HandleEditPath()
+	vm.HandlePathInput(model, newSpecialKey(tea.KeyEsc))
+	assert.False(t, vm.pathBar.active)
+}
+
+func TestCompleteContainerPath(t *testing.T) {
+	files := []models.ContainerFile{
+		{Name: ".."},
+		{Name: "lib", LinkTarget: "usr/lib"},
+		{Name: "lib64", LinkTarget: "usr/lib64"},
+		{Name: ".profile"},
+	}
+	links := map[string]docker.ResolvedPath{"lib": {IsDir: true}}
+
+	completed, candidates := completeContainerPath("/", "li", files, links)
+	assert.Equal(t, "/lib", completed)
+	assert.Equal(t, []string{"lib/", "lib64"}, candidates)
+
+	completed, candidates = completeContainerPath("/", ".", files, links)
+	assert.Equal(t, "/.profile", completed)
+	assert.Nil(t, candidates)
+}
+
+func TestFileBrowserViewModel_Listing(t *testing.T) {
+	model := &Model{Height: 30}
+	vm := &FileBrowserViewModel{
+		browsingContainer: docker.NewContainer("abc123", "web", "web", "running"),
+	}
+	vm.pushHistory("/var")
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	vm.Loaded(model, []models.ContainerFile{
+		{Name: ".", IsDir: true, Size: 4096},
+		{Name: "..", IsDir: true, Size: 4096},
+		{Name: ".cache", IsDir: true, Size: 4096, ModTime: day},
+		{Name: "app.log", Size: 300, ModTime: day.AddDate(0, 0, 2)},
+		{Name: "current", LinkTarget: "app.log", ModTime: day},
+		{Name: "lib", IsDir: true, Size: 4096, ModTime: day.AddDate(0, 0, 1)},
+		{Name: "Makefile", Size: 10, ModTime: day},
+	})
+	assert.Equal(t, []string{".", "..", ".cache", "app.log", "current", "lib", "Makefile"}, fileNames(vm.containerFiles))
+	assert.Equal(t, "File Browser: web [/var]", vm.Title())
+
+	// The cursor stays on its entry
+	vm.Cursor = 3
+	vm.HandleCycleSort(model)
+	assert.Equal(t, sortBySize, vm.sortBy)
+	assert.Equal(t, []string{".", "..", ".cache", "lib", "app.log", "Makefile", "current"}, fileNames(vm.containerFiles))
+	assert.Equal(t, "app.log", vm.containerFiles[vm.Cursor].Name)
+
+	vm.HandleReverseSort(model)
+	assert.Equal(t, []string{".", "..", "current", "Makefile", "app.log", ".cache", "lib"}, fileNames(vm.containerFiles))
+	assert.Equal(t, "File Browser: web [/var] (by size, reversed)", vm.Title())
+
+	vm.HandleCycleSort(model)
+	assert.Equal(t, []string{".", "..", "app.log", "lib", ".cache", "current", "Makefile"}, fileNames(vm.containerFiles))
+	assert.Contains(t, vm.buildRows()[2], "2024-05-03 00:00", "the modification time is shown when sorting by it")
+
+	vm.HandleCycleSort(model)
+	assert.Equal(t, []string{".", "..", "lib", ".cache", "current", "Makefile", "app.log"}, fileNames(vm.containerFiles))
+
+	vm.HandleCycleSort(model)
+	vm.HandleToggleDirsFirst(model)
+	vm.HandleToggleDotfiles(model)
+	assert.Equal(t, []string{".", "..", "lib", "app.log", "current", "Makefile"}, fileNames(vm.containerFiles))
+	assert.Equal(t, "File Browser: web [/var] (dirs first, dotfiles hidden)", vm.Title())
+
+	// Reloading keeps the order and filters
+	vm.Loaded(model, vm.allFiles)
+	assert.Equal(t, []string{".", "..", "lib", "app.log", "current", "Makefile"}, fileNames(vm.containerFiles))
+}
+
+func TestFileBrowserViewModel_Columns(t *testing.T) {
+	vm := &FileBrowserViewModel{currentPath: "/"}
+	titles := func(width int) []string {
+		var titles []string
+		for _, c := range vm.fileColumns(width) {
+			titles = append(titles, c.Title)
+		}
+		return titles
+	}
+	assert.Equal(t, []string{"PERMISSIONS", "SIZE", "NAME"}, titles(40))
+	assert.Equal(t, []string{"PERMISSIONS", "LINKS", "SIZE", "NAME"}, titles(70))
+	assert.Equal(t, []string{"PERMISSIONS", "LINKS", "OWNER", "GROUP", "SIZE", "NAME"}, titles(120))
+
+	model := &Model{Height: 30}
+	vm.HandleToggleOwnerColumns(model)
+	vm.HandleTogglePermissionsColumn(model)
+	assert.Equal(t, []string{"LINKS", "SIZE", "NAME"}, titles(120))
+}
+
+func TestFileBrowserViewModel_DirectorySizes(t *testing.T) {
+	model := &Model{Height: 30}
+	vm := &FileBrowserViewModel{
+		browsingContainer: docker.NewContainer("abc123", "web", "web", "running"),
+	}
+	vm.pushHistory("/var")
+	vm.Loaded(model, []models.ContainerFile{
+		{Name: "..", IsDir: true, Size: 4096},
+		{Name: "cache", IsDir: true, Size: 4096},
+		{Name: "lib", IsDir: true, Size: 4096},
+		{Name: "run.pid", Size: 6},
+	})
+
+	vm.Update(model, directorySizesMsg{dir: "/var", sizes: map[string]int64{"/var/cache": 2048, "/var/lib": 3 << 30}})
+	assert.False(t, model.loading)
+	assert.Equal(t, sortBySize, vm.sortBy, "computing sizes sorts by them")
+	assert.Equal(t, []string{"..", "lib", "cache", "run.pid"}, fileNames(vm.containerFiles))
+	rows := vm.buildRows()
+	assert.Equal(t, "3.0G", rows[1][4])
+	assert.Equal(t, "2.0K", rows[2][4])
+
+	// Sizes of a directory that was left are ignored
+	vm.pushHistory("/")
+	vm.Update(model, directorySizesMsg{dir: "/var", sizes: map[string]int64{"/var/run.pid": 1}})
+	assert.NotContains(t, vm.dirSizes, "/var/run.pid")
+}
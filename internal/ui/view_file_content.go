@@ -1,8 +1,15 @@
 package ui
 
 import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"charm.land/bubbles/v2/viewport"
 	tea "charm.land/bubbletea/v2"
@@ -13,16 +20,53 @@ import (
 
 // fileContentLoadedMsg contains the loaded file content
 type fileContentLoadedMsg struct {
-	content string
-	path    string
-	err     error
+	file *docker.FileContent
+	path string
+	err  error
 }
 
 type FileContentViewModel struct {
+	SearchViewModel
+
 	container   *docker.Container
 	content     string
 	contentPath string
 	scrollY     int
+
+	// lines caches content split into lines
+	lines []string
+
+	// data is the raw file content; content is what is displayed
+	data      []byte
+	fileSize  int64
+	truncated bool
+	binary    bool
+	hexMode   bool
+	// pretty shows JSON indented
+	pretty bool
+
+	// syntax colors the text, nil for plain text.
+	// syntaxStates holds the multi-line comment or string open at the start of each line.
+	syntax          *syntaxLanguage
+	syntaxStates    []string
+	hideLineNumbers bool
+
+	// targetLine is the 1-based line to show and highlight after loading, 0 for none
+	targetLine int
+
+	// reloading keeps the scroll position when the file is read again
+	reloading     bool
+	reloadScrollY int
+
+	// paged is set for large files, of which only the window starting at windowStart is in data.
+	// lineOffsets holds the file offset of each displayed line.
+	fileOperations *docker.FileOperations
+	paged          bool
+	windowStart    int64
+	lineOffsets    []int64
+	pageLoading    bool
+	// confirmFullLoad asks before a large file is read completely
+	confirmFullLoad bool
 }
 
 // Update handles messages for the file content view
@@ -37,7 +81,21 @@ func (m *FileContentViewModel) Update(model *Model, msg tea.Msg) (tea.Model, tea
 			model.err = nil
 		}
 
-		m.Loaded(msg.content, msg.path)
+		m.LoadedFile(msg.file, msg.path)
+		return model, nil
+	case fileContentPageMsg:
+		model.loading = false
+		if !msg.first && (!m.paged || msg.path != m.contentPath) {
+			// The user opened another file meanwhile
+			return model, nil
+		}
+		if msg.err != nil {
+			m.pageLoading = false
+			model.err = msg.err
+			return model, nil
+		}
+		model.err = nil
+		m.LoadedPage(model, msg)
 		return model, nil
 	default:
 		return model, nil
@@ -46,8 +104,8 @@ func (m *FileContentViewModel) Update(model *Model, msg tea.Msg) (tea.Model, tea
 
 // render renders the file content view
 func (m *FileContentViewModel) render(model *Model) string {
-	if model.err != nil {
-		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	if model.err != nil && m.content == "" {
 		return errorStyle.Render(fmt.Sprintf("Error: %v", model.err))
 	}
 
@@ -55,53 +113,130 @@ func (m *FileContentViewModel) render(model *Model) string {
 	if height < 1 {
 		height = 1
 	}
+
+	var s strings.Builder
+	if m.confirmFullLoad {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("226")).Bold(true).Render(m.fullLoadPrompt()))
+		s.WriteString("\n")
+		height = max(height-1, 1)
+	}
+	if model.err != nil {
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", model.err)))
+		s.WriteString("\n")
+		height = max(height-1, 1)
+	}
+
+	lines := m.contentLines()
+	end := min(m.scrollY+height, len(lines))
+	currentMatch := -1
+	if len(m.searchResults) > 0 && m.currentSearchIdx < len(m.searchResults) {
+		currentMatch = m.searchResults[m.currentSearchIdx]
+	}
+	lineNumStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	gutterWidth := len(strconv.Itoa(len(lines)))
+
+	var body strings.Builder
+	for i := m.scrollY; i < end; i++ {
+		if m.showLineNumbers() {
+			marker := " "
+			if i == currentMatch {
+				marker = lipgloss.NewStyle().Foreground(lipgloss.Color("226")).Render("▶")
+			}
+			body.WriteString(marker + lineNumStyle.Render(fmt.Sprintf("%*d ", gutterWidth, i+1)))
+		}
+		body.WriteString(m.renderLine(i, lines[i]))
+		if i < end-1 {
+			body.WriteString("\n")
+		}
+	}
+
 	v := viewport.New(viewport.WithWidth(model.width), viewport.WithHeight(height))
-	v.SetContent(m.content)
-	v.ScrollDown(m.scrollY)
-	return v.View()
+	v.SetContent(body.String())
+	s.WriteString(v.View())
+	return s.String()
+}
+
+// showLineNumbers tells whether line numbers are shown. They are unknown in the middle of a paged file.
+func (m *FileContentViewModel) showLineNumbers() bool {
+	return !m.hideLineNumbers && !m.hexMode && m.windowStart == 0
+}
+
+// renderLine colors a line. The target line and search matches take precedence over syntax colors.
+func (m *FileContentViewModel) renderLine(i int, line string) string {
+	if i == m.targetLine-1 && !m.hexMode {
+		return fileContentTargetLineStyle.Render(line)
+	}
+	if m.searchText != "" && !m.searchMode {
+		if matches := m.findSearchMatches(line); len(matches) > 0 {
+			return m.renderSearchMatches(line, matches)
+		}
+	}
+	if m.syntax == nil {
+		return line
+	}
+	state := ""
+	if i < len(m.syntaxStates) {
+		state = m.syntaxStates[i]
+	}
+	highlighted, _ := m.syntax.highlight(line, state)
+	return highlighted
+}
+
+// contentLines returns the displayed content split into lines
+func (m *FileContentViewModel) contentLines() []string {
+	if m.lines == nil {
+		return strings.Split(m.content, "\n")
+	}
+	return m.lines
 }
 
 func (m *FileContentViewModel) LoadContainer(model *Model, container *docker.Container, path string) tea.Cmd {
+	return m.LoadContainerAtLine(model, container, path, 0)
+}
+
+// Reload reads the shown file again, e.g. after it was edited, keeping the scroll position
+func (m *FileContentViewModel) Reload(model *Model) tea.Cmd {
+	if m.container == nil || m.contentPath == "" {
+		model.loading = false
+		return nil
+	}
+	scrollY := m.scrollY
+	cmd := m.LoadContainerAtLine(model, m.container, m.contentPath, m.targetLine)
+	m.reloading = true
+	m.reloadScrollY = scrollY
+	return cmd
+}
+
+// LoadContainerAtLine loads a file and scrolls to the given 1-based line, which is highlighted
+func (m *FileContentViewModel) LoadContainerAtLine(model *Model, container *docker.Container, path string, line int) tea.Cmd {
 	model.SwitchView(FileContentView)
 	model.loading = true
 	m.scrollY = 0
 	m.container = container
+	m.targetLine = line
+	m.reloading = false
+	m.resetPaging()
 
+	fileOperations := model.sharedFileOperations()
+	m.fileOperations = fileOperations
 	return func() tea.Msg {
-		// Try docker cp first (works for files without needing exec permissions)
-		var args []string
-		if container.IsDind() {
-			// For DinD: docker exec <host> docker cp <container>:<path> -
-			args = []string{"exec", container.HostContainerID(), "docker", "cp", fmt.Sprintf("%s:%s", container.ContainerID(), path), "-"}
-		} else {
-			// For normal containers: docker cp <container>:<path> -
-			args = []string{"cp", fmt.Sprintf("%s:%s", container.ContainerID(), path), "-"}
+		// Large files are read page by page. Files of unknown size, like those in /proc, are read as usual.
+		// Pages are read by commands in the container, so files of a container that never started are truncated instead.
+		if size, err := fileOperations.GetFileSize(context.Background(), container, path); err == nil && size > docker.MaxFileContentSize && container.GetState() != "created" {
+			return loadPaged(fileOperations, container, path, size)
 		}
 
-		output, err := docker.ExecuteCaptured(args...)
-		if err == nil {
-			return fileContentLoadedMsg{
-				content: string(output),
-				path:    path,
-				err:     nil,
-			}
-		}
-
-		// Fallback to cat if docker cp fails (e.g., for special files like /proc/*)
-		args = container.OperationArgs("exec", "cat", path)
-		output, err = docker.ExecuteCaptured(args...)
+		file, err := fileOperations.GetFileContent(context.Background(), container, path, docker.MaxFileContentSize)
 		if err != nil {
 			return fileContentLoadedMsg{
-				content: "",
-				path:    path,
-				err:     fmt.Errorf("failed to read file: %w", err),
+				path: path,
+				err:  fmt.Errorf("failed to read file: %w", err),
 			}
 		}
 
 		return fileContentLoadedMsg{
-			content: string(output),
-			path:    path,
-			err:     nil,
+			file: file,
+			path: path,
 		}
 	}
 }
@@ -110,29 +245,33 @@ func (m *FileContentViewModel) HandleUp() tea.Cmd {
 	if m.scrollY > 0 {
 		m.scrollY--
 	}
-	return nil
+	return m.loadPrevious()
 }
 
 func (m *FileContentViewModel) HandleDown(height int) tea.Cmd {
-	lines := strings.Split(m.content, "\n")
-	maxScroll := len(lines) - (height - 5)
+	maxScroll := len(m.contentLines()) - (height - 5)
 	if m.scrollY < maxScroll && maxScroll > 0 {
 		m.scrollY++
 	}
-	return nil
+	return m.loadNext(height)
 }
 
 func (m *FileContentViewModel) HandleGoToBeginning() tea.Cmd {
 	m.scrollY = 0
+	if m.paged {
+		return m.goToBeginningPaged()
+	}
 	return nil
 }
 
 func (m *FileContentViewModel) HandleGoToEnd(height int) tea.Cmd {
-	lines := strings.Split(m.content, "\n")
-	maxScroll := len(lines) - (height - 5)
+	maxScroll := len(m.contentLines()) - (height - 5)
 	if maxScroll > 0 {
 		m.scrollY = maxScroll
 	}
+	if m.paged {
+		return m.goToEndPaged()
+	}
 	return nil
 }
 
@@ -143,12 +282,11 @@ func (m *FileContentViewModel) HandlePageUp(height int) tea.Cmd {
 	} else {
 		m.scrollY = 0
 	}
-	return nil
+	return m.loadPrevious()
 }
 
 func (m *FileContentViewModel) HandlePageDown(height int) tea.Cmd {
-	lines := strings.Split(m.content, "\n")
-	maxScroll := len(lines) - (height - 5)
+	maxScroll := len(m.contentLines()) - (height - 5)
 	pageSize := height - 5
 
 	if m.scrollY+pageSize < maxScroll {
@@ -156,32 +294,256 @@ func (m *FileContentViewModel) HandlePageDown(height int) tea.Cmd {
 	} else if maxScroll > 0 {
 		m.scrollY = maxScroll
 	}
-	return nil
+	return m.loadNext(height)
 }
 
 func (m *FileContentViewModel) HandleBack(model *Model) tea.Cmd {
 	model.SwitchToPreviousView()
 	m.content = ""
 	m.contentPath = ""
-	m.contentPath = ""
+	m.data = nil
+	m.lines = nil
+	m.syntax = nil
+	m.syntaxStates = nil
+	m.pretty = false
 	m.scrollY = 0
+	m.targetLine = 0
+	m.resetPaging()
+	m.InputEscape()
 	return nil
 }
 
+// resetPaging leaves paged mode
+func (m *FileContentViewModel) resetPaging() {
+	m.paged = false
+	m.windowStart = 0
+	m.lineOffsets = nil
+	m.pageLoading = false
+	m.confirmFullLoad = false
+}
+
+// HandleToggleHexView switches between the text and the hex dump of the file
+func (m *FileContentViewModel) HandleToggleHexView() tea.Cmd {
+	offset := m.lineOffset(m.scrollY)
+	m.hexMode = !m.hexMode
+	m.setContent()
+	m.scrollY = 0
+	if m.paged {
+		// Stay at the same place in the file
+		m.scrollY = m.lineAt(offset)
+	}
+	return nil
+}
+
+// HandleTogglePrettyJSON switches between the file as it is and indented JSON
+func (m *FileContentViewModel) HandleTogglePrettyJSON(model *Model) tea.Cmd {
+	if !m.pretty && !json.Valid(m.data) {
+		if m.truncated || m.paged {
+			model.err = errors.New("cannot pretty-print: only the beginning of the JSON file was read")
+		} else {
+			model.err = errors.New("cannot pretty-print: the file is not valid JSON")
+		}
+		return nil
+	}
+	model.err = nil
+	m.pretty = !m.pretty
+	m.hexMode = false
+	m.setContent()
+	m.scrollY = 0
+	return nil
+}
+
+// HandleToggleLineNumbers shows or hides the line numbers
+func (m *FileContentViewModel) HandleToggleLineNumbers() tea.Cmd {
+	m.hideLineNumbers = !m.hideLineNumbers
+	return nil
+}
+
+// HandleGoToLine scrolls to a 1-based line and highlights it
+func (m *FileContentViewModel) HandleGoToLine(model *Model, line int) tea.Cmd {
+	lines := len(m.contentLines())
+	line = max(min(line, lines), 1)
+	m.targetLine = line
+	maxScroll := max(lines-(model.Height-5), 0)
+	m.scrollY = min(max(line-1-fileContentContextLines, 0), maxScroll)
+	return nil
+}
+
+func (m *FileContentViewModel) HandleSearch() tea.Cmd {
+	m.ClearSearch()
+	return nil
+}
+
+func (m *FileContentViewModel) HandleNextSearchResult(model *Model) tea.Cmd {
+	if len(m.searchResults) > 0 {
+		m.currentSearchIdx = (m.currentSearchIdx + 1) % len(m.searchResults)
+		m.scrollToSearchResult(model)
+	}
+	return nil
+}
+
+func (m *FileContentViewModel) HandlePrevSearchResult(model *Model) tea.Cmd {
+	if len(m.searchResults) > 0 {
+		m.currentSearchIdx--
+		if m.currentSearchIdx < 0 {
+			m.currentSearchIdx = len(m.searchResults) - 1
+		}
+		m.scrollToSearchResult(model)
+	}
+	return nil
+}
+
+// scrollToSearchResult centers the current search result
+func (m *FileContentViewModel) scrollToSearchResult(model *Model) {
+	targetLine := m.searchResults[m.currentSearchIdx]
+	m.scrollY = max(targetLine-model.Height/2+3, 0)
+}
+
 func (m *FileContentViewModel) Title() string {
 	containerTitle := ""
 	if m.container != nil {
 		containerTitle = m.container.Title()
 	}
-	return fmt.Sprintf("File: [%d/%d] %s [%s] ",
-		m.scrollY, len(strings.Split(m.content, "\n")),
+	var flags string
+	if m.syntax != nil && !m.hexMode {
+		flags += "[" + m.syntax.name + "] "
+	}
+	if m.pretty && !m.hexMode {
+		flags += "[pretty] "
+	}
+	if m.hexMode {
+		flags += "[hex] "
+	} else if m.binary {
+		flags += "[binary] "
+	}
+	if m.truncated && m.fileSize < 0 {
+		flags += fmt.Sprintf("[first %d bytes] ", len(m.data))
+	} else if m.truncated {
+		flags += fmt.Sprintf("[first %d of %d bytes] ", len(m.data), m.fileSize)
+	}
+	if m.paged {
+		flags += m.windowTitle()
+	}
+	title := fmt.Sprintf("File: [%d/%d] %s [%s] %s",
+		m.scrollY, len(m.contentLines()),
 		m.contentPath,
 		containerTitle,
+		flags,
 	)
+
+	if m.searchText != "" && !m.searchMode {
+		searchInfo := fmt.Sprintf("| Search: %s", m.searchText)
+		if len(m.searchResults) > 0 {
+			searchInfo += fmt.Sprintf(" (%d/%d)", m.currentSearchIdx+1, len(m.searchResults))
+		} else {
+			searchInfo += " (no matches)"
+		}
+		if m.searchIgnoreCase {
+			searchInfo += " [i]"
+		}
+		if m.searchRegex {
+			searchInfo += " [re]"
+		}
+		title += searchInfo
+	}
+	return title
 }
 
 func (m *FileContentViewModel) Loaded(content string, path string) {
-	m.content = content
+	m.LoadedFile(&docker.FileContent{Data: []byte(content), Size: int64(len(content))}, path)
+}
+
+// LoadedFile shows a file read from a container. Binary files start in hex dump mode.
+func (m *FileContentViewModel) LoadedFile(file *docker.FileContent, path string) {
+	m.resetPaging()
+	m.data = file.Data
+	m.fileSize = file.Size
+	m.truncated = file.Truncated
+	m.binary = isBinaryContent(file.Data)
+	m.hexMode = m.binary
 	m.contentPath = path
+	if m.pretty && !json.Valid(m.data) {
+		m.pretty = false
+	}
+	m.setContent()
 	m.scrollY = 0
+	if m.reloading {
+		m.reloading = false
+		m.scrollY = min(m.reloadScrollY, max(len(m.lines)-1, 0))
+	} else if m.targetLine > 0 && !m.hexMode {
+		// Keep a few lines of context above the target
+		m.scrollY = max(m.targetLine-1-fileContentContextLines, 0)
+	}
+}
+
+// fileContentContextLines is how many lines are shown above a target line
+const fileContentContextLines = 3
+
+var fileContentTargetLineStyle = lipgloss.NewStyle().Background(lipgloss.Color("57")).Foreground(lipgloss.Color("229"))
+
+// setContent updates the displayed text and its colors for the current mode
+func (m *FileContentViewModel) setContent() {
+	m.lineOffsets = nil
+	if m.paged {
+		m.content, m.lineOffsets = m.pagedContent()
+	} else {
+		m.content = m.displayContent()
+	}
+	m.lines = strings.Split(m.content, "\n")
+	m.syntax = nil
+	m.syntaxStates = nil
+	if m.hexMode {
+		return
+	}
+	if m.pretty {
+		m.syntax = syntaxJSON
+	} else {
+		m.syntax = detectSyntax(m.contentPath, m.data)
+	}
+	if m.syntax != nil {
+		m.syntaxStates = m.syntax.syntaxStates(m.lines)
+	}
+	// Search results are line numbers of the previous content
+	m.InputEscape()
+}
+
+// displayContent returns the text shown for the raw file content
+func (m *FileContentViewModel) displayContent() string {
+	if m.hexMode {
+		return strings.TrimSuffix(hex.Dump(m.data), "\n")
+	}
+	if m.pretty {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, m.data, "", "  "); err == nil {
+			return buf.String()
+		}
+	}
+	return string(m.data)
+}
+
+// binarySniffLen is how much of a file is inspected to tell binary from text
+const binarySniffLen = 8000
+
+// isBinaryContent guesses whether data is binary, like git does:
+// text never contains NUL bytes. Invalid UTF-8 also counts as binary.
+func isBinaryContent(data []byte) bool {
+	sample := data
+	if len(sample) > binarySniffLen {
+		sample = sample[:binarySniffLen]
+	}
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return true
+	}
+	if utf8.Valid(sample) {
+		return false
+	}
+	// A sample cut from a longer file may end in the middle of a character
+	if len(sample) < len(data) {
+		for i := 1; i < utf8.UTFMax && i < len(sample); i++ {
+			if utf8.Valid(sample[:len(sample)-i]) {
+				return false
+			}
+		}
+	}
+	return true
 }
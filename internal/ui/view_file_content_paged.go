@@ -0,0 +1,267 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+// Files larger than docker.MaxFileContentSize are shown page by page: only a window
+// of the file is held in memory and the next page is read while scrolling.
+const (
+	// fileContentPageSize is how much is read at once, a multiple of the hex dump row width
+	fileContentPageSize int64 = 256 * 1024
+	// fileContentMaxWindow is how much of a paged file is kept in memory
+	fileContentMaxWindow = 4 * fileContentPageSize
+	// fileContentPageMargin is how many lines before an edge of the window the next page is read
+	fileContentPageMargin = 100
+)
+
+// fileContentPageMsg contains a range of a file shown page by page
+type fileContentPageMsg struct {
+	path   string
+	size   int64
+	offset int64
+	data   []byte
+	// first is the first page after the file was opened
+	first bool
+	// replace discards the loaded window instead of extending it, e.g. when jumping to the end
+	replace bool
+	// toEnd scrolls to the end of the new window
+	toEnd bool
+	err   error
+}
+
+// loadPaged reads the first page of a large file
+func loadPaged(fileOperations *docker.FileOperations, container *docker.Container, path string, size int64) tea.Msg {
+	data, err := fileOperations.ReadFileRange(context.Background(), container, path, 0, fileContentPageSize)
+	if err != nil {
+		return fileContentLoadedMsg{path: path, err: fmt.Errorf("failed to read file: %w", err)}
+	}
+	return fileContentPageMsg{path: path, size: size, data: data, first: true}
+}
+
+// LoadedPage shows a range of a large file, extending the window when the range is next to it
+func (m *FileContentViewModel) LoadedPage(model *Model, msg fileContentPageMsg) {
+	m.pageLoading = false
+	if msg.first {
+		m.paged = true
+		m.fileSize = msg.size
+		m.truncated = false
+		m.pretty = false
+		m.binary = isBinaryContent(msg.data)
+		m.hexMode = m.binary
+		m.contentPath = msg.path
+		m.windowStart = 0
+		m.data = msg.data
+		m.setContent()
+		m.scrollY = 0
+		return
+	}
+
+	topOffset := m.lineOffset(m.scrollY)
+	windowEnd := m.windowStart + int64(len(m.data))
+	switch {
+	case msg.replace:
+		m.windowStart = msg.offset
+		m.data = msg.data
+	case msg.offset == windowEnd:
+		m.data = append(m.data, msg.data...)
+		if excess := int64(len(m.data)) - fileContentMaxWindow; excess > 0 {
+			// Keep the window aligned to hex dump rows
+			excess = (excess + 15) / 16 * 16
+			m.data = m.data[excess:]
+			m.windowStart += excess
+		}
+	case msg.offset+int64(len(msg.data)) == m.windowStart:
+		m.data = append(msg.data, m.data...)
+		if int64(len(m.data)) > fileContentMaxWindow {
+			m.data = m.data[:fileContentMaxWindow]
+		}
+		m.windowStart = msg.offset
+	default:
+		// The window changed while the page was read
+		return
+	}
+	m.setContent()
+
+	switch {
+	case msg.toEnd:
+		m.scrollY = max(len(m.lines)-(model.Height-5), 0)
+	case msg.replace:
+		m.scrollY = 0
+	default:
+		m.scrollY = m.lineAt(topOffset)
+	}
+}
+
+// pagedContent returns the displayed text of the loaded window and the file offset of each line.
+// Lines cut by the edges of the window are left out until the neighbouring page is read.
+func (m *FileContentViewModel) pagedContent() (string, []int64) {
+	var lines []string
+	var offsets []int64
+	if m.hexMode {
+		for i := 0; i < len(m.data); i += 16 {
+			offset := m.windowStart + int64(i)
+			row := strings.TrimSuffix(hex.Dump(m.data[i:min(i+16, len(m.data))]), "\n")
+			lines = append(lines, fmt.Sprintf("%08x", offset)+row[8:])
+			offsets = append(offsets, offset)
+		}
+		return strings.Join(lines, "\n"), offsets
+	}
+
+	text := m.data
+	start := m.windowStart
+	if start > 0 {
+		if i := bytes.IndexByte(text, '\n'); i >= 0 {
+			text = text[i+1:]
+			start += int64(i + 1)
+		}
+	}
+	if start+int64(len(text)) < m.fileSize {
+		if i := bytes.LastIndexByte(text, '\n'); i >= 0 {
+			text = text[:i]
+		}
+	}
+	offsets = append(offsets, start)
+	for i, b := range text {
+		if b == '\n' {
+			offsets = append(offsets, start+int64(i+1))
+		}
+	}
+	return string(text), offsets
+}
+
+// lineOffset returns the file offset of a displayed line in paged mode
+func (m *FileContentViewModel) lineOffset(line int) int64 {
+	if line < len(m.lineOffsets) {
+		return m.lineOffsets[line]
+	}
+	return m.windowStart
+}
+
+// lineAt returns the displayed line containing a file offset
+func (m *FileContentViewModel) lineAt(offset int64) int {
+	line := 0
+	for i, o := range m.lineOffsets {
+		if o > offset {
+			break
+		}
+		line = i
+	}
+	return line
+}
+
+// readPage reads a range of the shown file in the background
+func (m *FileContentViewModel) readPage(offset, length int64, replace, toEnd bool) tea.Cmd {
+	m.pageLoading = true
+	fileOperations := m.fileOperations
+	container := m.container
+	path := m.contentPath
+	size := m.fileSize
+	return func() tea.Msg {
+		data, err := fileOperations.ReadFileRange(context.Background(), container, path, offset, length)
+		return fileContentPageMsg{
+			path:    path,
+			size:    size,
+			offset:  offset,
+			data:    data,
+			replace: replace,
+			toEnd:   toEnd,
+			err:     err,
+		}
+	}
+}
+
+// loadNext reads the next page when the view is close to the end of the window
+func (m *FileContentViewModel) loadNext(height int) tea.Cmd {
+	if !m.paged || m.pageLoading {
+		return nil
+	}
+	windowEnd := m.windowStart + int64(len(m.data))
+	if m.scrollY+(height-5) >= len(m.lines)-fileContentPageMargin && windowEnd < m.fileSize {
+		return m.readPage(windowEnd, fileContentPageSize, false, false)
+	}
+	return nil
+}
+
+// loadPrevious reads the previous page when the view is close to the beginning of the window
+func (m *FileContentViewModel) loadPrevious() tea.Cmd {
+	if !m.paged || m.pageLoading {
+		return nil
+	}
+	if m.scrollY < fileContentPageMargin && m.windowStart > 0 {
+		offset := max(m.windowStart-fileContentPageSize, 0)
+		return m.readPage(offset, m.windowStart-offset, false, false)
+	}
+	return nil
+}
+
+// goToEndPaged reads the last page of the file without reading what is between
+func (m *FileContentViewModel) goToEndPaged() tea.Cmd {
+	if m.windowStart+int64(len(m.data)) >= m.fileSize || m.pageLoading {
+		return nil
+	}
+	offset := max(m.fileSize-fileContentPageSize, 0) / 16 * 16
+	return m.readPage(offset, m.fileSize-offset, true, true)
+}
+
+// goToBeginningPaged reads the first page of the file again
+func (m *FileContentViewModel) goToBeginningPaged() tea.Cmd {
+	if m.windowStart == 0 || m.pageLoading {
+		return nil
+	}
+	return m.readPage(0, fileContentPageSize, true, false)
+}
+
+// HandleLoadWholeFile asks before reading a large file completely
+func (m *FileContentViewModel) HandleLoadWholeFile() tea.Cmd {
+	if m.paged {
+		m.confirmFullLoad = true
+	}
+	return nil
+}
+
+// HandleFullLoadConfirmation reads the whole file when confirmed
+func (m *FileContentViewModel) HandleFullLoadConfirmation(model *Model, confirmed bool) tea.Cmd {
+	m.confirmFullLoad = false
+	if !confirmed {
+		return nil
+	}
+	model.loading = true
+	m.targetLine = 0
+	m.reloading = false
+	fileOperations := m.fileOperations
+	container := m.container
+	path := m.contentPath
+	size := m.fileSize
+	return func() tea.Msg {
+		file, err := fileOperations.GetFileContent(context.Background(), container, path, size)
+		if err != nil {
+			return fileContentLoadedMsg{path: path, err: fmt.Errorf("failed to read file: %w", err)}
+		}
+		return fileContentLoadedMsg{file: file, path: path}
+	}
+}
+
+// fullLoadPrompt is the warning shown before a large file is read completely
+func (m *FileContentViewModel) fullLoadPrompt() string {
+	return fmt.Sprintf("Load all %s of %s into memory? This may take a while. (y/n)",
+		models.ContainerFile{Size: m.fileSize}.GetSizeString(), m.contentPath)
+}
+
+// windowTitle describes the loaded range of a paged file
+func (m *FileContentViewModel) windowTitle() string {
+	title := fmt.Sprintf("[bytes %d-%d of %d] ", m.windowStart, m.windowStart+int64(len(m.data)), m.fileSize)
+	if m.pageLoading {
+		title += "[loading...] "
+	}
+	return title
+}
@@ -1,9 +1,11 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/charmbracelet/x/ansi"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/tokuhirom/dcv/internal/docker"
@@ -201,6 +203,56 @@ func TestFileContentViewModel_Loaded(t *testing.T) {
 	})
 }
 
+func TestFileContentViewModel_LoadedBinary(t *testing.T) {
+	t.Run("binary file starts in hex mode", func(t *testing.T) {
+		vm := &FileContentViewModel{}
+		vm.LoadedFile(&docker.FileContent{Data: []byte("\x7fELF\x02\x01\x01\x00\x00"), Size: 9}, "/bin/app")
+
+		assert.True(t, vm.binary)
+		assert.True(t, vm.hexMode)
+		assert.Equal(t, "00000000  7f 45 4c 46 02 01 01 00  00                       |.ELF.....|", vm.content)
+		assert.Contains(t, vm.Title(), "[hex]")
+	})
+
+	t.Run("toggle between hex and text", func(t *testing.T) {
+		vm := &FileContentViewModel{}
+		vm.Loaded("hello", "/etc/motd")
+		assert.False(t, vm.hexMode)
+		assert.Equal(t, "hello", vm.content)
+
+		vm.HandleToggleHexView()
+		assert.True(t, vm.hexMode)
+		assert.Contains(t, vm.content, "68 65 6c 6c 6f")
+
+		vm.HandleToggleHexView()
+		assert.False(t, vm.hexMode)
+		assert.Equal(t, "hello", vm.content)
+	})
+
+	t.Run("truncated file is flagged in the title", func(t *testing.T) {
+		vm := &FileContentViewModel{}
+		vm.LoadedFile(&docker.FileContent{Data: []byte("abc"), Size: 100, Truncated: true}, "/var/log/big.log")
+
+		assert.Contains(t, vm.Title(), "[first 3 of 100 bytes]")
+
+		// cat was stopped at the limit, so the size is unknown
+		vm.LoadedFile(&docker.FileContent{Data: []byte("abc"), Size: -1, Truncated: true}, "/proc/kcore")
+		assert.Contains(t, vm.Title(), "[first 3 bytes]")
+	})
+}
+
+func TestIsBinaryContent(t *testing.T) {
+	assert.False(t, isBinaryContent([]byte("plain text\n")))
+	assert.False(t, isBinaryContent([]byte("日本語のテキスト")))
+	assert.False(t, isBinaryContent(nil))
+	assert.True(t, isBinaryContent([]byte("abc\x00def")))
+	assert.True(t, isBinaryContent([]byte{0xff, 0xfe, 0x41}))
+
+	// A multi-byte character cut at the end of the sample is still text
+	long := []byte(strings.Repeat("a", binarySniffLen-1) + "あ")
+	assert.False(t, isBinaryContent(long))
+}
+
 func TestFileContentViewModel_Title(t *testing.T) {
 	container := docker.NewContainer("test123", "test-container", "test-container", "running")
 	vm := &FileContentViewModel{
@@ -498,3 +550,207 @@ func TestFileContentViewModel_PageNavigation(t *testing.T) {
 		})
 	}
 }
+
+func TestFileContentViewModel_SmartViewer(t *testing.T) {
+	newModel := func(path, content string) (*Model, *FileContentViewModel) {
+		model := &Model{currentView: FileContentView, width: 100, Height: 20}
+		vm := &model.fileContentViewModel
+		vm.Loaded(content, path)
+		return model, vm
+	}
+
+	t.Run("line numbers and syntax name", func(t *testing.T) {
+		model, vm := newModel("/app/config.yaml", "a: 1\nb: 2")
+		assert.Same(t, syntaxYAML, vm.syntax)
+		assert.Contains(t, vm.Title(), "[yaml]")
+		assert.Contains(t, ansi.Strip(vm.render(model)), " 1 a: 1")
+
+		vm.HandleToggleLineNumbers()
+		assert.NotContains(t, ansi.Strip(vm.render(model)), " 1 a: 1")
+	})
+
+	t.Run("search jumps between matches", func(t *testing.T) {
+		var lines []string
+		for i := 1; i <= 100; i++ {
+			lines = append(lines, fmt.Sprintf("line %d", i))
+		}
+		model, vm := newModel("/app/file.txt", strings.Join(lines, "\n"))
+
+		vm.HandleSearch()
+		vm.AppendString("line 5")
+		vm.searchMode = false
+		vm.PerformSearch(model, vm.contentLines(), func(scrollY int) { vm.scrollY = scrollY })
+		assert.Equal(t, []int{4, 49, 50, 51, 52, 53, 54, 55, 56, 57, 58}, vm.searchResults)
+
+		vm.HandleNextSearchResult(model)
+		assert.Equal(t, 49-model.Height/2+3, vm.scrollY)
+		assert.Contains(t, vm.Title(), "Search: line 5 (2/11)")
+
+		vm.HandlePrevSearchResult(model)
+		vm.HandlePrevSearchResult(model)
+		assert.Equal(t, 10, vm.currentSearchIdx)
+	})
+
+	t.Run("pretty-prints valid JSON only", func(t *testing.T) {
+		model, vm := newModel("/app/data", `{"a":{"b":[1,2]}}`)
+		assert.Same(t, syntaxJSON, vm.syntax)
+
+		vm.HandleTogglePrettyJSON(model)
+		assert.True(t, vm.pretty)
+		assert.Equal(t, "{\n  \"a\": {\n    \"b\": [\n      1,\n      2\n    ]\n  }\n}", vm.content)
+		assert.Contains(t, vm.Title(), "[pretty]")
+
+		vm.HandleTogglePrettyJSON(model)
+		assert.Equal(t, `{"a":{"b":[1,2]}}`, vm.content)
+
+		model, vm = newModel("/app/data.json", `{"a":`)
+		vm.HandleTogglePrettyJSON(model)
+		assert.False(t, vm.pretty)
+		assert.ErrorContains(t, model.err, "not valid JSON")
+		assert.Contains(t, ansi.Strip(vm.render(model)), `{"a":`, "the content is still shown")
+	})
+
+	t.Run("go to line", func(t *testing.T) {
+		model, vm := newModel("/app/file.txt", strings.Repeat("x\n", 99)+"x")
+
+		vm.HandleGoToLine(model, 50)
+		assert.Equal(t, 50, vm.targetLine)
+		assert.Equal(t, 50-1-fileContentContextLines, vm.scrollY)
+
+		vm.HandleGoToLine(model, 1000)
+		assert.Equal(t, 100, vm.targetLine)
+		assert.Equal(t, 100-(model.Height-5), vm.scrollY)
+	})
+
+	t.Run(":N in command mode goes to the line", func(t *testing.T) {
+		model, vm := newModel("/app/file.txt", strings.Repeat("x\n", 99)+"x")
+		model.commandViewModel.commandBuffer = ":42"
+		model.commandViewModel.executeCommand(model)
+		assert.Equal(t, 42, vm.targetLine)
+	})
+}
+
+func TestFileContentViewModel_Paged(t *testing.T) {
+	// A file of numbered 16-byte lines, larger than what is read at once
+	lineCount := int(fileContentMaxWindow/16) * 3
+	var file strings.Builder
+	for i := 0; i < lineCount; i++ {
+		fmt.Fprintf(&file, "line %010d\n", i)
+	}
+	data := []byte(file.String())
+	size := int64(len(data))
+	page := func(offset, length int64) []byte {
+		return data[offset:min(offset+length, size)]
+	}
+
+	newModel := func() (*Model, *FileContentViewModel) {
+		model := NewModel(FileContentView)
+		model.initializeKeyHandlers()
+		model.width = 100
+		model.Height = 30
+		vm := &model.fileContentViewModel
+		vm.container = docker.NewContainer("abc123", "web", "web", "running")
+		vm.Update(model, fileContentPageMsg{path: "/var/log/app.log", size: size, data: page(0, fileContentPageSize), first: true})
+		return model, vm
+	}
+
+	t.Run("shows the first page", func(t *testing.T) {
+		model, vm := newModel()
+		assert.True(t, vm.paged)
+		assert.Equal(t, "line 0000000000", vm.lines[0])
+		assert.Len(t, vm.lines, int(fileContentPageSize/16))
+		assert.Contains(t, vm.Title(), fmt.Sprintf("[bytes 0-%d of %d]", fileContentPageSize, size))
+		assert.Contains(t, ansi.Strip(vm.render(model)), "1 line 0000000000")
+	})
+
+	t.Run("reads the next page near the end of the window", func(t *testing.T) {
+		model, vm := newModel()
+		assert.Nil(t, vm.HandleDown(model.Height))
+
+		vm.scrollY = len(vm.lines) - fileContentPageMargin
+		assert.NotNil(t, vm.HandleDown(model.Height))
+		assert.True(t, vm.pageLoading)
+		assert.Nil(t, vm.HandleDown(model.Height), "only one page is read at a time")
+
+		top := vm.lines[vm.scrollY]
+		vm.Update(model, fileContentPageMsg{path: "/var/log/app.log", size: size, offset: fileContentPageSize, data: page(fileContentPageSize, fileContentPageSize)})
+		assert.False(t, vm.pageLoading)
+		assert.Equal(t, top, vm.lines[vm.scrollY], "the view stays at the same line")
+		assert.Len(t, vm.lines, int(2*fileContentPageSize/16))
+	})
+
+	t.Run("keeps a bounded window", func(t *testing.T) {
+		model, vm := newModel()
+		for offset := fileContentPageSize; offset < 2*fileContentMaxWindow; offset += fileContentPageSize {
+			vm.Update(model, fileContentPageMsg{path: "/var/log/app.log", size: size, offset: offset, data: page(offset, fileContentPageSize)})
+		}
+		assert.Equal(t, fileContentMaxWindow, int64(len(vm.data)))
+		assert.Equal(t, fileContentMaxWindow, vm.windowStart)
+		// The line at the start of the window may be cut, so it is left out
+		assert.Equal(t, fmt.Sprintf("line %010d", fileContentMaxWindow/16+1), vm.lines[0])
+		assert.False(t, vm.showLineNumbers(), "line numbers are unknown in the middle of the file")
+
+		// Scrolling up reads the previous page
+		vm.scrollY = 1
+		assert.NotNil(t, vm.HandleUp())
+		top := vm.lines[vm.scrollY]
+		vm.Update(model, fileContentPageMsg{path: "/var/log/app.log", size: size, offset: fileContentMaxWindow - fileContentPageSize, data: page(fileContentMaxWindow-fileContentPageSize, fileContentPageSize)})
+		assert.Equal(t, fileContentMaxWindow-fileContentPageSize, vm.windowStart)
+		assert.Equal(t, top, vm.lines[vm.scrollY])
+	})
+
+	t.Run("G reads the end of the file only", func(t *testing.T) {
+		model, vm := newModel()
+		assert.NotNil(t, vm.HandleGoToEnd(model.Height))
+
+		offset := size - fileContentPageSize
+		vm.Update(model, fileContentPageMsg{path: "/var/log/app.log", size: size, offset: offset, data: page(offset, fileContentPageSize), replace: true, toEnd: true})
+		assert.Equal(t, offset, vm.windowStart)
+		assert.Equal(t, fmt.Sprintf("line %010d", lineCount-1), vm.lines[len(vm.lines)-2])
+		assert.Equal(t, len(vm.lines)-(model.Height-5), vm.scrollY)
+		assert.Nil(t, vm.HandleGoToEnd(model.Height), "the end is already loaded")
+
+		assert.NotNil(t, vm.HandleGoToBeginning())
+	})
+
+	t.Run("drops lines cut by the window", func(t *testing.T) {
+		model, vm := newModel()
+		vm.Update(model, fileContentPageMsg{path: "/var/log/app.log", size: size, offset: 24, data: page(24, 64), replace: true})
+		assert.Equal(t, []string{"line 0000000002", "line 0000000003", "line 0000000004"}, vm.lines)
+	})
+
+	t.Run("hex dump shows file offsets", func(t *testing.T) {
+		model, vm := newModel()
+		vm.Update(model, fileContentPageMsg{path: "/var/log/app.log", size: size, offset: 4096, data: page(4096, 64), replace: true})
+		vm.scrollY = 2
+		vm.HandleToggleHexView()
+		assert.Equal(t, "00001000  6c 69 6e 65 20 30 30 30  30 30 30 30 32 35 36 0a  |line 0000000256.|", vm.lines[0])
+		assert.Equal(t, 3, vm.scrollY, "the view stays at the same place: line 259 starts at 0x1030")
+	})
+
+	t.Run("asks before loading the whole file", func(t *testing.T) {
+		model, vm := newModel()
+		model.handleKeyPress(newKeyPress("L"))
+		assert.True(t, vm.confirmFullLoad)
+		assert.Contains(t, ansi.Strip(vm.render(model)), "Load all 3.0M of /var/log/app.log into memory?")
+
+		model.handleKeyPress(newKeyPress("j"))
+		assert.True(t, vm.confirmFullLoad, "other keys are ignored")
+
+		model.handleKeyPress(newKeyPress("n"))
+		assert.False(t, vm.confirmFullLoad)
+		assert.True(t, vm.paged)
+
+		vm.HandleLoadWholeFile()
+		assert.NotNil(t, vm.HandleFullLoadConfirmation(model, true))
+		vm.Update(model, fileContentLoadedMsg{file: &docker.FileContent{Data: data, Size: size}, path: "/var/log/app.log"})
+		assert.False(t, vm.paged)
+		assert.Len(t, vm.lines, lineCount+1)
+	})
+
+	t.Run("pretty-printing needs the whole file", func(t *testing.T) {
+		model, vm := newModel()
+		vm.HandleTogglePrettyJSON(model)
+		assert.ErrorContains(t, model.err, "only the beginning")
+	})
+}
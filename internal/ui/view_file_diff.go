@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// FileDiffViewModel shows a read-only diff between two versions of a file
+type FileDiffViewModel struct {
+	diffPager
+	title string
+}
+
+// Show switches to the diff view
+func (m *FileDiffViewModel) Show(model *Model, title string, lines []string) {
+	m.title = title
+	m.lines = lines
+	m.scrollY = 0
+	model.SwitchView(FileDiffView)
+}
+
+func (m *FileDiffViewModel) render(model *Model, availableHeight int) string {
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	if len(m.lines) == 0 {
+		return dimStyle.Render("The files are identical")
+	}
+
+	var s strings.Builder
+	added, removed := diffStats(m.lines)
+	s.WriteString(dimStyle.Render(fmt.Sprintf("%d lines added, %d removed", added, removed)))
+	s.WriteString("\n")
+	s.WriteString(m.renderLines(model, availableHeight-1))
+	return s.String()
+}
+
+func (m *FileDiffViewModel) HandleBack(model *Model) tea.Cmd {
+	model.SwitchToPreviousView()
+	m.lines = nil
+	m.scrollY = 0
+	return nil
+}
+
+func (m *FileDiffViewModel) Title() string {
+	return m.title
+}
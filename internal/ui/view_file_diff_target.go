@@ -0,0 +1,225 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+)
+
+// diffTargetsLoadedMsg contains the containers the shown file can be compared with
+type diffTargetsLoadedMsg struct {
+	container *docker.Container
+	peers     []docker.PeerContainer
+	err       error
+}
+
+// fileComparedMsg contains the diff of the shown file against another container or the image
+type fileComparedMsg struct {
+	title string
+	lines []string
+	err   error
+}
+
+// diffTarget is what a file is compared with
+type diffTarget struct {
+	// container is nil for the image of the compared container
+	container *docker.Container
+	label     string
+	detail    string
+}
+
+// FileDiffTargetViewModel picks the container, or the image, to compare the shown file with
+type FileDiffTargetViewModel struct {
+	container *docker.Container
+	path      string
+	targets   []diffTarget
+	selected  int
+
+	listing   bool
+	comparing bool
+	err       error
+}
+
+// Show opens the menu for a file of a container and lists the other running containers in the background
+func (m *FileDiffTargetViewModel) Show(model *Model, container *docker.Container, path string) tea.Cmd {
+	m.container = container
+	m.path = path
+	m.selected = 0
+	m.listing = true
+	m.comparing = false
+	m.err = nil
+	// The image is always there to compare with, even when listing the containers fails
+	m.targets = []diffTarget{{label: "image", detail: "the file as it is in the image of " + container.Title()}}
+	model.SwitchView(FileDiffTargetView)
+
+	return func() tea.Msg {
+		peers, err := docker.PeerContainers(container)
+		return diffTargetsLoadedMsg{container: container, peers: peers, err: err}
+	}
+}
+
+// Update handles messages for the menu
+func (m *FileDiffTargetViewModel) Update(model *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case diffTargetsLoadedMsg:
+		if msg.container != m.container {
+			return model, nil
+		}
+		m.listing = false
+		if msg.err != nil {
+			m.err = msg.err
+			return model, nil
+		}
+		m.targets = append(m.targets, diffTargetsFor(msg.peers)...)
+		return model, nil
+	case fileComparedMsg:
+		m.comparing = false
+		if msg.err != nil {
+			// Keep the menu open so that another container can be picked
+			m.err = msg.err
+			return model, nil
+		}
+		// Remove the menu from history so ESC returns to the file
+		model.SwitchToPreviousView()
+		model.fileDiffViewModel.Show(model, msg.title, msg.lines)
+		return model, nil
+	default:
+		return model, nil
+	}
+}
+
+// diffTargetsFor describes the containers a file can be compared with
+func diffTargetsFor(peers []docker.PeerContainer) []diffTarget {
+	targets := make([]diffTarget, 0, len(peers))
+	for _, peer := range peers {
+		detail := peer.Image
+		if peer.SameImage {
+			detail += " (same image)"
+		}
+		targets = append(targets, diffTarget{container: peer.Container, label: peer.Container.Title(), detail: detail})
+	}
+	return targets
+}
+
+func (m *FileDiffTargetViewModel) render(model *Model) string {
+	if m.container == nil {
+		return "No file selected"
+	}
+
+	var s strings.Builder
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("7")).
+		Background(lipgloss.Color("4")).
+		Width(model.width).
+		Padding(0, 1)
+	s.WriteString(headerStyle.Render("Diff " + m.path + " with..."))
+	s.WriteString("\n\n")
+
+	infoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	s.WriteString(infoStyle.Render(fmt.Sprintf("Container: %s\n", m.container.Title())))
+	s.WriteString("\n")
+
+	for i, target := range m.targets {
+		prefix := "  "
+		style := lipgloss.NewStyle()
+		if target.container == nil {
+			style = style.Foreground(lipgloss.Color("39"))
+		}
+		if i == m.selected {
+			prefix = "> "
+			style = style.Bold(true).Background(lipgloss.Color("237"))
+		}
+		s.WriteString(style.Render(fmt.Sprintf("%s%-30s %s", prefix, target.label, target.detail)))
+		s.WriteString("\n")
+	}
+
+	statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	switch {
+	case m.comparing:
+		s.WriteString("\n")
+		s.WriteString(statusStyle.Render("⠋ Reading both files..."))
+		s.WriteString("\n")
+	case m.err != nil:
+		s.WriteString("\n")
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		s.WriteString("\n")
+	case m.listing:
+		s.WriteString("\n")
+		s.WriteString(statusStyle.Render("⠋ Listing running containers..."))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	footerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	s.WriteString(footerStyle.Render("Use ↑/↓ to select, Enter to compare, Esc to cancel"))
+	return s.String()
+}
+
+// HandleUp moves selection up
+func (m *FileDiffTargetViewModel) HandleUp() tea.Cmd {
+	if m.selected > 0 {
+		m.selected--
+	}
+	return nil
+}
+
+// HandleDown moves selection down
+func (m *FileDiffTargetViewModel) HandleDown() tea.Cmd {
+	if m.selected < len(m.targets)-1 {
+		m.selected++
+	}
+	return nil
+}
+
+// HandleSelect reads the file on both sides in the background and diffs them;
+// the result arrives as fileComparedMsg
+func (m *FileDiffTargetViewModel) HandleSelect(model *Model) tea.Cmd {
+	if m.comparing || m.selected < 0 || m.selected >= len(m.targets) {
+		return nil
+	}
+
+	target := m.targets[m.selected]
+	container := m.container
+	path := m.path
+	fileOperations := model.sharedFileOperations()
+	m.comparing = true
+	m.err = nil
+	return func() tea.Msg {
+		ctx := context.Background()
+		current, err := fileOperations.GetFileContent(ctx, container, path, docker.MaxFileContentSize)
+		if err != nil {
+			return fileComparedMsg{err: fmt.Errorf("failed to read %s in %s: %w", path, container.Title(), err)}
+		}
+
+		var other *docker.FileContent
+		if target.container == nil {
+			other, err = docker.GetImageFileContent(container, path, docker.MaxFileContentSize)
+		} else {
+			other, err = fileOperations.GetFileContent(ctx, target.container, path, docker.MaxFileContentSize)
+		}
+		if err != nil {
+			return fileComparedMsg{err: fmt.Errorf("%s is not available in %s: %w", path, target.label, err)}
+		}
+
+		title := fmt.Sprintf("Diff: %s %s -> %s", path, target.label, container.Title())
+		if current.Truncated || other.Truncated {
+			title += fmt.Sprintf(" (first %d bytes)", docker.MaxFileContentSize)
+		}
+		return fileComparedMsg{
+			title: title,
+			lines: contentDiffLines(target.label+":"+path, container.Title()+":"+path, other.Data, current.Data),
+		}
+	}
+}
+
+// HandleBack returns to the file
+func (m *FileDiffTargetViewModel) HandleBack(model *Model) tea.Cmd {
+	model.SwitchToPreviousView()
+	return nil
+}
@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+)
+
+func TestFileDiffTargetViewModel(t *testing.T) {
+	container := docker.NewContainer("abc123", "web-1", "web-1", "running")
+	newModel := func() (*Model, *FileDiffTargetViewModel) {
+		model := &Model{currentView: FileContentView, viewHistory: []ViewType{FileBrowserView}, Height: 30, width: 100}
+		vm := &model.fileDiffTargetViewModel
+		assert.NotNil(t, vm.Show(model, container, "/etc/nginx/nginx.conf"))
+		return model, vm
+	}
+
+	t.Run("the image is listed before the other containers", func(t *testing.T) {
+		model, vm := newModel()
+		assert.Equal(t, FileDiffTargetView, model.currentView)
+		assert.Contains(t, vm.render(model), "Listing running containers")
+
+		vm.Update(model, diffTargetsLoadedMsg{container: container, peers: []docker.PeerContainer{
+			{Container: docker.NewContainer("def456", "web-2", "web-2", "running"), Image: "nginx:1.27", SameImage: true},
+			{Container: docker.NewContainer("aaa111", "db", "db", "running"), Image: "postgres:16"},
+		}})
+		var labels []string
+		for _, target := range vm.targets {
+			labels = append(labels, target.label)
+		}
+		assert.Equal(t, []string{"image", "web-2", "db"}, labels)
+		output := vm.render(model)
+		assert.Contains(t, output, "nginx:1.27 (same image)")
+		assert.NotContains(t, output, "Listing")
+
+		vm.HandleUp()
+		assert.Equal(t, 0, vm.selected)
+		vm.HandleDown()
+		vm.HandleDown()
+		vm.HandleDown()
+		assert.Equal(t, 2, vm.selected)
+	})
+
+	t.Run("containers listed for another file are ignored", func(t *testing.T) {
+		model, vm := newModel()
+		vm.Update(model, diffTargetsLoadedMsg{container: docker.NewContainer("other", "other", "other", "running"),
+			peers: []docker.PeerContainer{{Container: container}}})
+		assert.Len(t, vm.targets, 1)
+		assert.True(t, vm.listing)
+	})
+
+	t.Run("errors keep the menu open", func(t *testing.T) {
+		model, vm := newModel()
+		vm.Update(model, fileComparedMsg{err: errors.New("/etc/nginx/nginx.conf is not available in db")})
+		assert.Equal(t, FileDiffTargetView, model.currentView)
+		assert.Contains(t, vm.render(model), "not available in db")
+	})
+
+	t.Run("the diff replaces the menu", func(t *testing.T) {
+		model, vm := newModel()
+		lines := contentDiffLines("web-2:/etc/nginx/nginx.conf", "web-1:/etc/nginx/nginx.conf", []byte("a\nb\n"), []byte("a\nc\n"))
+		vm.Update(model, fileComparedMsg{title: "Diff: /etc/nginx/nginx.conf web-2 -> web-1", lines: lines})
+		assert.Equal(t, FileDiffView, model.currentView)
+		assert.Equal(t, "Diff: /etc/nginx/nginx.conf web-2 -> web-1", model.fileDiffViewModel.Title())
+
+		model.fileDiffViewModel.HandleBack(model)
+		assert.Equal(t, FileContentView, model.currentView)
+	})
+}
+
+func TestSideBySideRows(t *testing.T) {
+	lines := contentDiffLines("image:/app.conf", "web:/app.conf",
+		[]byte("listen 80\nworkers 2\nlog off\ntimeout 5\n"),
+		[]byte("listen 80\nworkers 4\nlog on\nextra 1\ntimeout 5\n"))
+	assert.Equal(t, []sideBySideRow{
+		{left: "image:/app.conf", right: "web:/app.conf", header: true},
+		{left: "@@ -1,4 +1,5 @@", full: true},
+		{left: "listen 80", right: "listen 80"},
+		{left: "workers 2", right: "workers 4", removed: true, added: true},
+		{left: "log off", right: "log on", removed: true, added: true},
+		{right: "extra 1", added: true},
+		{left: "timeout 5", right: "timeout 5"},
+	}, sideBySideRows(lines))
+
+	assert.Equal(t, []sideBySideRow{{left: "Binary files differ: 1 bytes -> 2 bytes", full: true}},
+		sideBySideRows([]string{"Binary files differ: 1 bytes -> 2 bytes"}))
+
+	model := &Model{Height: 30, width: 41}
+	vm := &FileDiffViewModel{}
+	vm.Show(model, "Diff", lines)
+	vm.HandleToggleSideBySide(model)
+	output := vm.render(model, 20)
+	assert.Contains(t, stripANSI(output), "workers 2           │ workers 4")
+	for _, line := range strings.Split(output, "\n") {
+		assert.LessOrEqual(t, len([]rune(stripANSI(line))), 41)
+	}
+}
@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+)
+
+// fileEditFetchedMsg is sent when a file has been copied out of a container for editing
+type fileEditFetchedMsg struct {
+	file *docker.EditableFile
+	err  error
+}
+
+// fileEditorExitedMsg is sent when the editor exits
+type fileEditorExitedMsg struct {
+	err error
+}
+
+// fileEditWrittenMsg is sent when the edited file has been copied back into the container
+type fileEditWrittenMsg struct {
+	err error
+}
+
+// FileEditViewModel edits a container file in $EDITOR and shows the changes before writing them back
+type FileEditViewModel struct {
+	diffPager
+	container *docker.Container
+	file      *docker.EditableFile
+	edited    []byte
+	writing   bool
+}
+
+// Start copies the file out of the container and opens it in the editor
+func (m *FileEditViewModel) Start(model *Model, container *docker.Container, path string) tea.Cmd {
+	if m.file != nil {
+		// An earlier edit was abandoned
+		m.file.Cleanup()
+		m.file = nil
+	}
+	m.container = container
+	model.loading = true
+	return func() tea.Msg {
+		file, err := docker.FetchFileForEdit(container, path)
+		return fileEditFetchedMsg{file: file, err: err}
+	}
+}
+
+// HandleFetched launches the editor on the local copy
+func (m *FileEditViewModel) HandleFetched(model *Model, msg fileEditFetchedMsg) tea.Cmd {
+	model.loading = false
+	if msg.err != nil {
+		model.err = fmt.Errorf("cannot edit file: %w", msg.err)
+		return nil
+	}
+	m.file = msg.file
+	return m.launchEditor()
+}
+
+// launchEditor suspends the TUI while the editor runs
+func (m *FileEditViewModel) launchEditor() tea.Cmd {
+	return tea.ExecProcess(editorCommand(m.file.LocalPath), func(err error) tea.Msg {
+		return fileEditorExitedMsg{err: err}
+	})
+}
+
+// editorCommand returns the command that opens path in $EDITOR, or vi when it is not set.
+// $EDITOR may carry arguments, like "code --wait".
+func editorCommand(path string) *exec.Cmd {
+	fields := strings.Fields(os.Getenv("EDITOR"))
+	if len(fields) == 0 {
+		fields = []string{"vi"}
+	}
+	return exec.Command(fields[0], append(fields[1:], path)...)
+}
+
+// HandleEditorExited shows the changes, or ends the edit when there are none
+func (m *FileEditViewModel) HandleEditorExited(model *Model, msg fileEditorExitedMsg) tea.Cmd {
+	if m.file == nil {
+		return nil
+	}
+	if msg.err != nil {
+		m.close(model)
+		model.err = fmt.Errorf("editor failed, the file was not changed: %w", msg.err)
+		return nil
+	}
+
+	data, changed, err := m.file.Edited()
+	if err != nil {
+		m.close(model)
+		model.err = err
+		return nil
+	}
+	if !changed {
+		m.close(model)
+		return nil
+	}
+
+	m.edited = data
+	m.lines = contentDiffLines("a"+m.file.ContainerPath, "b"+m.file.ContainerPath, m.file.Original, data)
+	m.scrollY = 0
+	model.err = nil
+	model.SwitchView(FileEditView)
+	return nil
+}
+
+// HandleWriteBack copies the edited file into the container
+func (m *FileEditViewModel) HandleWriteBack(model *Model) tea.Cmd {
+	if m.file == nil || m.writing {
+		return nil
+	}
+	m.writing = true
+	model.loading = true
+	model.err = nil
+
+	file := m.file
+	container := m.container
+	data := m.edited
+	return func() tea.Msg {
+		return fileEditWrittenMsg{err: file.WriteBack(container, data)}
+	}
+}
+
+// HandleWritten ends the edit and refreshes the view it was started from
+func (m *FileEditViewModel) HandleWritten(model *Model, msg fileEditWrittenMsg) tea.Cmd {
+	m.writing = false
+	model.loading = false
+	if msg.err != nil {
+		// Stay in the diff so that the user can retry or keep editing
+		model.err = msg.err
+		return nil
+	}
+	m.close(model)
+	return func() tea.Msg {
+		return RefreshMsg{}
+	}
+}
+
+// HandleEditAgain reopens the editor with the edited content
+func (m *FileEditViewModel) HandleEditAgain() tea.Cmd {
+	if m.file == nil || m.writing {
+		return nil
+	}
+	return m.launchEditor()
+}
+
+// HandleBack discards the changes
+func (m *FileEditViewModel) HandleBack(model *Model) tea.Cmd {
+	if m.writing {
+		return nil
+	}
+	m.close(model)
+	return nil
+}
+
+// close removes the local copy and leaves the diff view
+func (m *FileEditViewModel) close(model *Model) {
+	if m.file != nil {
+		m.file.Cleanup()
+	}
+	m.file = nil
+	m.edited = nil
+	m.lines = nil
+	m.scrollY = 0
+	if model.currentView == FileEditView {
+		model.SwitchToPreviousView()
+	}
+}
+
+func (m *FileEditViewModel) render(model *Model, availableHeight int) string {
+	if m.file == nil {
+		return ""
+	}
+
+	var s strings.Builder
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	added, removed := diffStats(m.lines)
+	s.WriteString(dimStyle.Render(fmt.Sprintf("%d lines added, %d removed. Press w to write back (mode %04o, owner %d:%d kept), e to edit again, esc to discard.",
+		added, removed, m.file.Mode&0o7777, m.file.UID, m.file.GID)))
+	s.WriteString("\n")
+	height := availableHeight - 1
+	if model.err != nil {
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", model.err)))
+		s.WriteString("\n")
+		height--
+	}
+	s.WriteString(m.renderLines(model, height))
+	return s.String()
+}
+
+func (m *FileEditViewModel) Title() string {
+	if m.file == nil {
+		return "Edit File"
+	}
+	containerTitle := ""
+	if m.container != nil {
+		containerTitle = m.container.Title()
+	}
+	return fmt.Sprintf("Edit: %s [%s]", m.file.ContainerPath, containerTitle)
+}
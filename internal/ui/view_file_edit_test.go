@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+)
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("EDITOR", "code --wait")
+	assert.Equal(t, []string{"code", "--wait", "/tmp/a.conf"}, editorCommand("/tmp/a.conf").Args)
+
+	t.Setenv("EDITOR", "")
+	assert.Equal(t, []string{"vi", "/tmp/a.conf"}, editorCommand("/tmp/a.conf").Args)
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	lines := unifiedDiff("a/app.conf", "b/app.conf", []byte("a\nb\nc\n"), []byte("a\nB\nc\n"))
+	assert.Equal(t, []string{
+		"--- a/app.conf",
+		"+++ b/app.conf",
+		"@@ -1,3 +1,3 @@",
+		" a",
+		"-b",
+		"+B",
+		" c",
+	}, lines)
+
+	added, removed := diffStats(lines)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 1, removed)
+
+	assert.Nil(t, unifiedDiff("a", "b", []byte("same\n"), []byte("same\n")))
+}
+
+func TestFileEditViewModel(t *testing.T) {
+	container := docker.NewContainer("abc123", "web", "web", "running")
+
+	newSession := func(t *testing.T, edited string) (*Model, *FileEditViewModel, string) {
+		dir := t.TempDir()
+		localPath := filepath.Join(dir, "app.conf")
+		require.NoError(t, os.WriteFile(localPath, []byte(edited), 0600))
+
+		model := &Model{currentView: FileContentView, viewHistory: []ViewType{FileBrowserView}, Height: 30, width: 100}
+		vm := &model.fileEditViewModel
+		vm.container = container
+		vm.file = &docker.EditableFile{
+			ContainerPath: "/app/app.conf",
+			LocalPath:     localPath,
+			Original:      []byte("port = 80\n"),
+			Mode:          0o644,
+		}
+		return model, vm, dir
+	}
+
+	t.Run("unchanged file ends the edit", func(t *testing.T) {
+		model, vm, dir := newSession(t, "port = 80\n")
+
+		vm.HandleEditorExited(model, fileEditorExitedMsg{})
+		assert.Nil(t, vm.file)
+		assert.Equal(t, FileContentView, model.currentView)
+		_, err := os.Stat(dir)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("changes are shown as a diff", func(t *testing.T) {
+		model, vm, _ := newSession(t, "port = 8080\n")
+
+		vm.HandleEditorExited(model, fileEditorExitedMsg{})
+		assert.Equal(t, FileEditView, model.currentView)
+		assert.Contains(t, vm.lines, "-port = 80")
+		assert.Contains(t, vm.lines, "+port = 8080")
+
+		output := vm.render(model, 20)
+		assert.Contains(t, output, "1 lines added, 1 removed")
+		assert.Contains(t, output, "mode 0644")
+		assert.Contains(t, vm.Title(), "Edit: /app/app.conf")
+	})
+
+	t.Run("editor failure discards the edit", func(t *testing.T) {
+		model, vm, _ := newSession(t, "port = 8080\n")
+
+		vm.HandleEditorExited(model, fileEditorExitedMsg{err: errors.New("exit status 1")})
+		assert.Nil(t, vm.file)
+		assert.ErrorContains(t, model.err, "editor failed")
+	})
+
+	t.Run("write back failure keeps the diff", func(t *testing.T) {
+		model, vm, _ := newSession(t, "port = 8080\n")
+		vm.HandleEditorExited(model, fileEditorExitedMsg{})
+
+		assert.NotNil(t, vm.HandleWriteBack(model))
+		assert.True(t, vm.writing)
+		assert.Nil(t, vm.HandleWriteBack(model), "write back runs only once at a time")
+
+		vm.HandleWritten(model, fileEditWrittenMsg{err: errors.New("read-only file system")})
+		assert.False(t, vm.writing)
+		assert.Equal(t, FileEditView, model.currentView)
+		assert.NotNil(t, vm.file)
+		assert.Contains(t, vm.render(model, 20), "read-only file system")
+	})
+
+	t.Run("successful write back returns to the file", func(t *testing.T) {
+		model, vm, _ := newSession(t, "port = 8080\n")
+		vm.HandleEditorExited(model, fileEditorExitedMsg{})
+		vm.HandleWriteBack(model)
+
+		cmd := vm.HandleWritten(model, fileEditWrittenMsg{})
+		require.NotNil(t, cmd)
+		assert.IsType(t, RefreshMsg{}, cmd())
+		assert.Nil(t, vm.file)
+		assert.Equal(t, FileContentView, model.currentView)
+	})
+
+	t.Run("escape discards the changes", func(t *testing.T) {
+		model, vm, dir := newSession(t, "port = 8080\n")
+		vm.HandleEditorExited(model, fileEditorExitedMsg{})
+
+		vm.HandleBack(model)
+		assert.Nil(t, vm.file)
+		assert.Equal(t, FileContentView, model.currentView)
+		_, err := os.Stat(dir)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
@@ -0,0 +1,584 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"charm.land/bubbles/v2/table"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+)
+
+// maxFileSearchResults caps the number of hits kept; the search is stopped beyond it
+const maxFileSearchResults = 1000
+
+// fileSearchStartedMsg is sent when the search process has been started
+type fileSearchStartedMsg struct {
+	generation int
+	cmd        *exec.Cmd
+	remote     *docker.RemoteCommand
+	remotePID  int
+	reader     *bufio.Reader
+	stderr     *bytes.Buffer
+	err        error
+}
+
+// fileSearchOutputMsg carries the hits read since the last message
+type fileSearchOutputMsg struct {
+	generation int
+	hits       []docker.FileSearchHit
+	done       bool
+	err        error
+	stderr     string
+}
+
+// searchFormField is a single line text field of the search form
+type searchFormField struct {
+	label  string
+	value  string
+	cursor int
+}
+
+// FileSearchViewModel finds files by name or searches their contents below a directory
+// and lists the hits as they arrive
+type FileSearchViewModel struct {
+	TableViewModel
+
+	container *docker.Container
+	kind      docker.FileSearchKind
+
+	// Form shown before the search runs
+	formActive bool
+	fields     []searchFormField
+	focus      int
+	formErr    error
+
+	search    *docker.FileSearch
+	hits      []docker.FileSearchHit
+	running   bool
+	truncated bool
+	err       error
+
+	// generation identifies the current search; messages of older searches are ignored
+	generation int
+	cmd        *exec.Cmd
+	// remote and remotePID stop the search in the container, which outlives its docker exec
+	remote    *docker.RemoteCommand
+	remotePID int
+	reader    *bufio.Reader
+	stderr    *bytes.Buffer
+}
+
+// Form field indexes
+const (
+	searchFieldDir = iota
+	searchFieldPattern
+	// find only
+	searchFieldRegex
+	searchFieldDepth
+	searchFieldType
+	// grep only
+	searchFieldIgnoreCase = searchFieldRegex
+)
+
+// Show opens the search form for a directory of the container
+func (m *FileSearchViewModel) Show(model *Model, container *docker.Container, dir string, kind docker.FileSearchKind) {
+	m.stop()
+	m.container = container
+	m.kind = kind
+	m.search = nil
+	m.hits = nil
+	m.err = nil
+	m.truncated = false
+	m.SetRows(nil, 0)
+
+	if kind == docker.FileSearchGrep {
+		m.fields = []searchFormField{
+			{label: "Directory", value: dir},
+			{label: "Pattern (regexp)"},
+			{label: "Ignore case (y/n)", value: "n"},
+		}
+	} else {
+		m.fields = []searchFormField{
+			{label: "Directory", value: dir},
+			{label: "Name (glob)"},
+			{label: "Regexp on path (y/n)", value: "n"},
+			{label: "Max depth", value: ""},
+			{label: "Type (f/d)", value: ""},
+		}
+	}
+	for i := range m.fields {
+		m.fields[i].cursor = len(m.fields[i].value)
+	}
+	m.focus = searchFieldPattern
+	m.formActive = true
+	m.formErr = nil
+
+	model.SwitchView(FileSearchView)
+}
+
+// query builds the search query from the form
+func (m *FileSearchViewModel) query() (docker.FileSearchQuery, error) {
+	q := docker.FileSearchQuery{
+		Kind:     m.kind,
+		Dir:      strings.TrimSpace(m.fields[searchFieldDir].value),
+		Pattern:  m.fields[searchFieldPattern].value,
+		MaxDepth: -1,
+	}
+	if m.kind == docker.FileSearchGrep {
+		q.IgnoreCase = isYes(m.fields[searchFieldIgnoreCase].value)
+		return q, nil
+	}
+
+	q.Regex = isYes(m.fields[searchFieldRegex].value)
+	if depth := strings.TrimSpace(m.fields[searchFieldDepth].value); depth != "" {
+		n, err := strconv.Atoi(depth)
+		if err != nil || n < 0 {
+			return q, fmt.Errorf("max depth must be a non-negative number: %q", depth)
+		}
+		q.MaxDepth = n
+	}
+	q.Type = strings.TrimSpace(m.fields[searchFieldType].value)
+	return q, nil
+}
+
+func isYes(s string) bool {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return s == "y" || s == "yes"
+}
+
+// submit validates the form and starts the search
+func (m *FileSearchViewModel) submit(model *Model) tea.Cmd {
+	q, err := m.query()
+	if err == nil {
+		m.search, err = docker.NewFileSearch(q)
+	}
+	if err != nil {
+		m.formErr = err
+		return nil
+	}
+	m.formActive = false
+	m.formErr = nil
+	return m.start(model)
+}
+
+// start runs the current search, replacing the results of an earlier run
+func (m *FileSearchViewModel) start(model *Model) tea.Cmd {
+	m.stop()
+	m.generation++
+	m.hits = nil
+	m.err = nil
+	m.truncated = false
+	m.running = true
+	m.Cursor = 0
+	m.SetRows(nil, model.ViewHeight())
+
+	generation := m.generation
+	search := m.search
+	container := m.container
+	fileOperations := model.sharedFileOperations()
+	return func() tea.Msg {
+		remote, err := search.Command(context.Background(), fileOperations, container)
+		if err != nil {
+			return fileSearchStartedMsg{generation: generation, err: err}
+		}
+
+		cmd := exec.Command("docker", remote.Args...)
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return fileSearchStartedMsg{generation: generation, err: fmt.Errorf("failed to create stdout pipe: %w", err)}
+		}
+		stderr := &bytes.Buffer{}
+		cmd.Stderr = stderr
+		if err := cmd.Start(); err != nil {
+			return fileSearchStartedMsg{generation: generation, err: fmt.Errorf("failed to start search: %w", err)}
+		}
+		reader := bufio.NewReader(stdout)
+		pid, err := remote.ReadPID(reader)
+		if err != nil {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			if message := strings.TrimSpace(stderr.String()); message != "" {
+				err = fmt.Errorf("search failed: %s", message)
+			}
+			return fileSearchStartedMsg{generation: generation, err: err}
+		}
+		return fileSearchStartedMsg{
+			generation: generation,
+			cmd:        cmd,
+			remote:     remote,
+			remotePID:  pid,
+			reader:     reader,
+			stderr:     stderr,
+		}
+	}
+}
+
+// readFileSearchOutput reads hits until a batch is ready or the search ends
+func readFileSearchOutput(generation int, search *docker.FileSearch, cmd *exec.Cmd, reader *bufio.Reader, stderr *bytes.Buffer) tea.Cmd {
+	return func() tea.Msg {
+		var hits []docker.FileSearchHit
+		for {
+			line, err := reader.ReadString('\n')
+			if hit, ok := search.ParseLine(strings.TrimRight(line, "\r\n")); ok {
+				hits = append(hits, hit)
+			}
+			if err != nil {
+				// stderr is complete once the process has been waited for
+				waitErr := cmd.Wait()
+				return fileSearchOutputMsg{
+					generation: generation,
+					hits:       hits,
+					done:       true,
+					err:        waitErr,
+					stderr:     strings.TrimSpace(stderr.String()),
+				}
+			}
+			// Deliver what we have when the pipe is drained so results show up while searching
+			if len(hits) >= 100 || (len(hits) > 0 && reader.Buffered() == 0) {
+				return fileSearchOutputMsg{generation: generation, hits: hits}
+			}
+		}
+	}
+}
+
+// HandleStarted begins streaming the output of a started search
+func (m *FileSearchViewModel) HandleStarted(model *Model, msg fileSearchStartedMsg) tea.Cmd {
+	if msg.generation != m.generation {
+		// A newer search replaced this one
+		if msg.cmd != nil && msg.cmd.Process != nil {
+			_ = msg.cmd.Process.Kill()
+			go func() { _ = msg.cmd.Wait() }()
+			stopRemoteSearch(msg.remote, msg.remotePID)
+		}
+		return nil
+	}
+	if msg.err != nil {
+		m.running = false
+		m.err = msg.err
+		return nil
+	}
+	m.cmd = msg.cmd
+	m.remote = msg.remote
+	m.remotePID = msg.remotePID
+	m.reader = msg.reader
+	m.stderr = msg.stderr
+	return readFileSearchOutput(msg.generation, m.search, m.cmd, m.reader, m.stderr)
+}
+
+// HandleOutput adds streamed hits and schedules the next read
+func (m *FileSearchViewModel) HandleOutput(model *Model, msg fileSearchOutputMsg) tea.Cmd {
+	if msg.generation != m.generation {
+		return nil
+	}
+
+	for _, hit := range msg.hits {
+		if len(m.hits) >= maxFileSearchResults {
+			if !m.truncated {
+				m.truncated = true
+				m.kill()
+			}
+			break
+		}
+		m.hits = append(m.hits, hit)
+	}
+	m.SetRows(m.buildRows(), model.ViewHeight())
+
+	if !msg.done {
+		return readFileSearchOutput(msg.generation, m.search, m.cmd, m.reader, m.stderr)
+	}
+
+	m.running = false
+	m.cmd = nil
+	m.remote = nil
+	m.reader = nil
+	m.stderr = nil
+	// find and grep exit non-zero for unreadable files or no matches; only report real failures
+	if msg.err != nil && len(m.hits) == 0 && !m.truncated && msg.stderr != "" {
+		m.err = fmt.Errorf("search failed: %s", msg.stderr)
+	}
+	return nil
+}
+
+// kill stops the running search process and the search in the container; its output is drained by the pending read
+func (m *FileSearchViewModel) kill() {
+	if m.cmd != nil && m.cmd.Process != nil {
+		_ = m.cmd.Process.Kill()
+		stopRemoteSearch(m.remote, m.remotePID)
+	}
+}
+
+// stopRemoteSearch stops the find or grep in the container in the background
+func stopRemoteSearch(remote *docker.RemoteCommand, pid int) {
+	if remote == nil || pid == 0 {
+		return
+	}
+	go func() {
+		if err := remote.Kill(pid); err != nil {
+			slog.Warn("Failed to stop the search in the container", slog.Any("error", err))
+		}
+	}()
+}
+
+// stop abandons the running search
+func (m *FileSearchViewModel) stop() {
+	if m.running {
+		m.kill()
+		// Ignore the output that is still in flight
+		m.generation++
+	}
+	m.running = false
+	m.cmd = nil
+	m.remote = nil
+	m.reader = nil
+	m.stderr = nil
+}
+
+func (m *FileSearchViewModel) buildRows() []table.Row {
+	rows := make([]table.Row, 0, len(m.hits))
+	for _, hit := range m.hits {
+		if m.kind == docker.FileSearchGrep {
+			rows = append(rows, table.Row{hit.Path, strconv.Itoa(hit.Line), strings.TrimSpace(hit.Text)})
+		} else {
+			rows = append(rows, table.Row{hit.Path})
+		}
+	}
+	return rows
+}
+
+// render renders the search form or the results
+func (m *FileSearchViewModel) render(model *Model, availableHeight int) string {
+	if m.formActive {
+		return m.renderForm()
+	}
+
+	var s strings.Builder
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	status := fmt.Sprintf("%d hits", len(m.hits))
+	switch {
+	case m.running:
+		status = fmt.Sprintf("Searching... %d hits so far", len(m.hits))
+	case m.truncated:
+		status = fmt.Sprintf("Stopped after the first %d hits; narrow the search", len(m.hits))
+	case len(m.hits) == 0 && m.err == nil:
+		status = "No matches"
+	}
+	s.WriteString(dimStyle.Render(status))
+	s.WriteString("\n")
+
+	if m.err != nil {
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		s.WriteString("\n")
+	}
+	if len(m.hits) == 0 {
+		return s.String()
+	}
+
+	var columns []table.Column
+	if m.kind == docker.FileSearchGrep {
+		columns = []table.Column{
+			{Title: "PATH", Width: 40},
+			{Title: "LINE", Width: 6},
+			{Title: "TEXT", Width: -1},
+		}
+	} else {
+		columns = []table.Column{
+			{Title: "PATH", Width: -1},
+		}
+	}
+
+	s.WriteString(m.RenderTable(model, columns, availableHeight-1, func(row, col int) lipgloss.Style {
+		if row == m.Cursor {
+			return tableSelectedCellStyle
+		}
+		return tableNormalCellStyle
+	}))
+	return s.String()
+}
+
+// renderForm renders the search form
+func (m *FileSearchViewModel) renderForm() string {
+	var s strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86"))
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Width(22)
+	focusedLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("220")).Bold(true).Width(22)
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	if m.kind == docker.FileSearchGrep {
+		s.WriteString(titleStyle.Render("Search File Contents"))
+	} else {
+		s.WriteString(titleStyle.Render("Find Files"))
+	}
+	s.WriteString("\n\n")
+
+	for i, field := range m.fields {
+		value := field.value
+		if i == m.focus {
+			s.WriteString(focusedLabelStyle.Render(field.label))
+			value = value[:field.cursor] + "█" + value[field.cursor:]
+		} else {
+			s.WriteString(labelStyle.Render(field.label))
+		}
+		s.WriteString(" ")
+		s.WriteString(value)
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+
+	if m.formErr != nil {
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.formErr)))
+		s.WriteString("\n\n")
+	}
+
+	s.WriteString(helpStyle.Render("Tab/↑/↓ to move between fields, Enter to search, Esc to cancel"))
+	return s.String()
+}
+
+// HandleFormInput edits the search form
+func (m *FileSearchViewModel) HandleFormInput(model *Model, msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	field := &m.fields[m.focus]
+
+	switch {
+	case msg.Code == tea.KeyEnter:
+		return model, m.submit(model)
+	case msg.Code == tea.KeyEsc:
+		m.formActive = false
+		if m.search == nil {
+			// Nothing was searched yet; leave the search view
+			model.SwitchToPreviousView()
+		}
+		return model, nil
+	case msg.String() == "shift+tab" || msg.Code == tea.KeyUp:
+		m.focus = (m.focus + len(m.fields) - 1) % len(m.fields)
+	case msg.Code == tea.KeyTab || msg.Code == tea.KeyDown:
+		m.focus = (m.focus + 1) % len(m.fields)
+	case msg.Code == tea.KeyBackspace || isCtrlKey(msg, 'h'):
+		if field.cursor > 0 {
+			field.value = field.value[:field.cursor-1] + field.value[field.cursor:]
+			field.cursor--
+		}
+	case msg.Code == tea.KeyDelete:
+		if field.cursor < len(field.value) {
+			field.value = field.value[:field.cursor] + field.value[field.cursor+1:]
+		}
+	case msg.Code == tea.KeyLeft || isCtrlKey(msg, 'b'):
+		if field.cursor > 0 {
+			field.cursor--
+		}
+	case msg.Code == tea.KeyRight || isCtrlKey(msg, 'f'):
+		if field.cursor < len(field.value) {
+			field.cursor++
+		}
+	case msg.Code == tea.KeyHome || isCtrlKey(msg, 'a'):
+		field.cursor = 0
+	case msg.Code == tea.KeyEnd || isCtrlKey(msg, 'e'):
+		field.cursor = len(field.value)
+	case isCtrlKey(msg, 'u'):
+		field.value = field.value[field.cursor:]
+		field.cursor = 0
+	case msg.Code == tea.KeySpace:
+		field.value = field.value[:field.cursor] + " " + field.value[field.cursor:]
+		field.cursor++
+	case len(msg.Text) > 0:
+		field.value = field.value[:field.cursor] + msg.Text + field.value[field.cursor:]
+		field.cursor += len(msg.Text)
+	}
+	return model, nil
+}
+
+// HandleEditSearch reopens the form with the current search
+func (m *FileSearchViewModel) HandleEditSearch() tea.Cmd {
+	m.formActive = true
+	m.formErr = nil
+	return nil
+}
+
+// HandleRefresh runs the search again
+func (m *FileSearchViewModel) HandleRefresh(model *Model) tea.Cmd {
+	if m.formActive || m.search == nil {
+		return nil
+	}
+	return m.start(model)
+}
+
+// HandleOpen opens the selected hit. Grep hits open at the matching line.
+func (m *FileSearchViewModel) HandleOpen(model *Model) tea.Cmd {
+	if m.Cursor >= len(m.hits) {
+		return nil
+	}
+	hit := m.hits[m.Cursor]
+	if m.kind == docker.FileSearchFind && m.search.Query.Type == "d" {
+		return m.openInFileBrowser(model, hit.Path)
+	}
+	return model.fileContentViewModel.LoadContainerAtLine(model, m.container, hit.Path, hit.Line)
+}
+
+// HandleOpenDirectory shows the directory of the selected hit in the file browser
+func (m *FileSearchViewModel) HandleOpenDirectory(model *Model) tea.Cmd {
+	if m.Cursor >= len(m.hits) {
+		return nil
+	}
+	hit := m.hits[m.Cursor]
+	dir := filepath.Dir(hit.Path)
+	if m.kind == docker.FileSearchFind && m.search.Query.Type == "d" {
+		dir = hit.Path
+	}
+	return m.openInFileBrowser(model, dir)
+}
+
+func (m *FileSearchViewModel) openInFileBrowser(model *Model, dir string) tea.Cmd {
+	browser := &model.fileBrowserViewModel
+	if browser.browsingContainer != m.container {
+		browser.browsingContainer = m.container
+		browser.pathHistory = nil
+	}
+	browser.pushHistory(dir)
+	browser.Cursor = 0
+	model.SwitchView(FileBrowserView)
+	return browser.DoLoad(model)
+}
+
+// HandleBack stops a running search and leaves the view
+func (m *FileSearchViewModel) HandleBack(model *Model) tea.Cmd {
+	m.stop()
+	model.SwitchToPreviousView()
+	return nil
+}
+
+func (m *FileSearchViewModel) HandleUp(model *Model) tea.Cmd {
+	return m.TableViewModel.HandleUp(model)
+}
+
+func (m *FileSearchViewModel) HandleDown(model *Model) tea.Cmd {
+	return m.TableViewModel.HandleDown(model)
+}
+
+func (m *FileSearchViewModel) Title() string {
+	containerTitle := ""
+	if m.container != nil {
+		containerTitle = m.container.Title()
+	}
+	if m.search == nil || m.formActive {
+		if m.kind == docker.FileSearchGrep {
+			return fmt.Sprintf("Search File Contents [%s]", containerTitle)
+		}
+		return fmt.Sprintf("Find Files [%s]", containerTitle)
+	}
+
+	q := m.search.Query
+	if q.Kind == docker.FileSearchGrep {
+		return fmt.Sprintf("Grep: %q in %s [%s]", q.Pattern, q.Dir, containerTitle)
+	}
+	return fmt.Sprintf("Find: %q in %s [%s]", q.Pattern, q.Dir, containerTitle)
+}
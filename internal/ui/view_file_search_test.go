@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+)
+
+func TestFileSearchViewModel_Form(t *testing.T) {
+	container := docker.NewContainer("abc123", "web", "web", "running")
+
+	t.Run("show opens the form with the directory", func(t *testing.T) {
+		model := &Model{currentView: FileBrowserView, Height: 20}
+		vm := &model.fileSearchViewModel
+		vm.Show(model, container, "/etc", docker.FileSearchFind)
+
+		assert.Equal(t, FileSearchView, model.currentView)
+		assert.True(t, vm.formActive)
+		assert.Equal(t, searchFieldPattern, vm.focus)
+		assert.Equal(t, "/etc", vm.fields[searchFieldDir].value)
+		assert.Contains(t, vm.render(model, 20), "Find Files")
+	})
+
+	t.Run("typing fills the focused field and enter starts the search", func(t *testing.T) {
+		model := &Model{currentView: FileBrowserView, Height: 20}
+		vm := &model.fileSearchViewModel
+		vm.Show(model, container, "/etc", docker.FileSearchGrep)
+
+		for _, key := range []string{"l", "i", "s", "t", "e", "n"} {
+			vm.HandleFormInput(model, newKeyPress(key))
+		}
+		vm.HandleFormInput(model, newSpecialKey(tea.KeyTab))
+		vm.HandleFormInput(model, newSpecialKey(tea.KeyBackspace))
+		vm.HandleFormInput(model, newKeyPress("y"))
+		assert.Equal(t, "listen", vm.fields[searchFieldPattern].value)
+		assert.Equal(t, "y", vm.fields[searchFieldIgnoreCase].value)
+
+		_, cmd := vm.HandleFormInput(model, newSpecialKey(tea.KeyEnter))
+		assert.NotNil(t, cmd)
+		assert.False(t, vm.formActive)
+		assert.True(t, vm.running)
+		require.NotNil(t, vm.search)
+		assert.Equal(t, "listen", vm.search.Query.Pattern)
+		assert.True(t, vm.search.Query.IgnoreCase)
+		assert.Contains(t, vm.Title(), `Grep: "listen" in /etc`)
+	})
+
+	t.Run("invalid depth keeps the form open", func(t *testing.T) {
+		model := &Model{currentView: FileBrowserView, Height: 20}
+		vm := &model.fileSearchViewModel
+		vm.Show(model, container, "/", docker.FileSearchFind)
+		vm.fields[searchFieldPattern].value = "*.conf"
+		vm.fields[searchFieldDepth].value = "deep"
+
+		_, cmd := vm.HandleFormInput(model, newSpecialKey(tea.KeyEnter))
+		assert.Nil(t, cmd)
+		assert.True(t, vm.formActive)
+		assert.ErrorContains(t, vm.formErr, "max depth")
+		assert.Contains(t, vm.render(model, 20), "max depth must be a non-negative number")
+	})
+
+	t.Run("escape before searching leaves the view", func(t *testing.T) {
+		model := &Model{currentView: FileBrowserView, Height: 20}
+		vm := &model.fileSearchViewModel
+		vm.Show(model, container, "/", docker.FileSearchFind)
+
+		vm.HandleFormInput(model, newSpecialKey(tea.KeyEsc))
+		assert.False(t, vm.formActive)
+		assert.Equal(t, FileBrowserView, model.currentView)
+	})
+}
+
+func TestFileSearchViewModel_Results(t *testing.T) {
+	container := docker.NewContainer("abc123", "web", "web", "running")
+
+	newRunningSearch := func(t *testing.T, kind docker.FileSearchKind) (*Model, *FileSearchViewModel) {
+		model := &Model{currentView: FileBrowserView, Height: 20, width: 120}
+		vm := &model.fileSearchViewModel
+		vm.Show(model, container, "/etc", kind)
+		vm.fields[searchFieldPattern].value = "conf"
+		vm.HandleFormInput(model, newSpecialKey(tea.KeyEnter))
+		require.True(t, vm.running)
+		return model, vm
+	}
+
+	t.Run("hits are added as they stream in", func(t *testing.T) {
+		model, vm := newRunningSearch(t, docker.FileSearchGrep)
+
+		vm.HandleOutput(model, fileSearchOutputMsg{
+			generation: vm.generation,
+			hits:       []docker.FileSearchHit{{Path: "/etc/a.conf", Line: 3, Text: "conf = 1"}},
+			done:       true,
+		})
+		assert.False(t, vm.running)
+		assert.Len(t, vm.hits, 1)
+		assert.Len(t, vm.Rows, 1)
+
+		output := vm.render(model, 20)
+		assert.Contains(t, output, "1 hits")
+		assert.Contains(t, output, "/etc/a.conf")
+	})
+
+	t.Run("stale output is ignored", func(t *testing.T) {
+		model, vm := newRunningSearch(t, docker.FileSearchFind)
+
+		cmd := vm.HandleOutput(model, fileSearchOutputMsg{
+			generation: vm.generation - 1,
+			hits:       []docker.FileSearchHit{{Path: "/old"}},
+		})
+		assert.Nil(t, cmd)
+		assert.Empty(t, vm.hits)
+	})
+
+	t.Run("results are capped", func(t *testing.T) {
+		model, vm := newRunningSearch(t, docker.FileSearchFind)
+
+		hits := make([]docker.FileSearchHit, maxFileSearchResults+10)
+		for i := range hits {
+			hits[i] = docker.FileSearchHit{Path: fmt.Sprintf("/etc/%d.conf", i)}
+		}
+		vm.HandleOutput(model, fileSearchOutputMsg{generation: vm.generation, hits: hits, done: true})
+		assert.Len(t, vm.hits, maxFileSearchResults)
+		assert.True(t, vm.truncated)
+		assert.Contains(t, vm.render(model, 20), "Stopped after the first 1000 hits")
+	})
+
+	t.Run("failure without hits is reported", func(t *testing.T) {
+		model, vm := newRunningSearch(t, docker.FileSearchFind)
+
+		vm.HandleOutput(model, fileSearchOutputMsg{
+			generation: vm.generation,
+			done:       true,
+			err:        errors.New("exit status 1"),
+			stderr:     "find: '/nope': No such file or directory",
+		})
+		assert.ErrorContains(t, vm.err, "No such file or directory")
+	})
+
+	t.Run("no matches is not an error", func(t *testing.T) {
+		model, vm := newRunningSearch(t, docker.FileSearchGrep)
+
+		vm.HandleOutput(model, fileSearchOutputMsg{
+			generation: vm.generation,
+			done:       true,
+			err:        errors.New("exit status 1"),
+		})
+		assert.Nil(t, vm.err)
+		assert.Contains(t, vm.render(model, 20), "No matches")
+	})
+
+	t.Run("enter opens a grep hit at the matching line", func(t *testing.T) {
+		model, vm := newRunningSearch(t, docker.FileSearchGrep)
+		vm.HandleOutput(model, fileSearchOutputMsg{
+			generation: vm.generation,
+			hits:       []docker.FileSearchHit{{Path: "/etc/a.conf", Line: 42, Text: "conf"}},
+			done:       true,
+		})
+
+		cmd := vm.HandleOpen(model)
+		assert.NotNil(t, cmd)
+		assert.Equal(t, FileContentView, model.currentView)
+		assert.Equal(t, 42, model.fileContentViewModel.targetLine)
+
+		model.SwitchToPreviousView()
+		assert.Equal(t, FileSearchView, model.currentView)
+	})
+
+	t.Run("directory of a hit opens in the file browser", func(t *testing.T) {
+		model, vm := newRunningSearch(t, docker.FileSearchFind)
+		vm.HandleOutput(model, fileSearchOutputMsg{
+			generation: vm.generation,
+			hits:       []docker.FileSearchHit{{Path: "/etc/nginx/nginx.conf"}},
+			done:       true,
+		})
+
+		cmd := vm.HandleOpenDirectory(model)
+		assert.NotNil(t, cmd)
+		assert.Equal(t, FileBrowserView, model.currentView)
+		assert.Equal(t, "/etc/nginx", model.fileBrowserViewModel.currentPath)
+	})
+}
+
+func TestFileContentViewModel_TargetLine(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 50; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+
+	vm := &FileContentViewModel{targetLine: 20}
+	vm.LoadedFile(&docker.FileContent{Data: []byte(strings.Join(lines, "\n"))}, "/app/file.txt")
+	assert.Equal(t, 20-1-fileContentContextLines, vm.scrollY)
+
+	assert.Equal(t, fileContentTargetLineStyle.Render("line 20"), vm.renderLine(19, vm.lines[19]))
+	assert.Equal(t, "line 19", vm.renderLine(18, vm.lines[18]))
+}
@@ -8,7 +8,7 @@ import (
 	"log/slog"
 	"os"
 	"os/exec"
-	"path/filepath"
+	"path"
 	"runtime"
 	"strings"
 
@@ -35,6 +35,11 @@ type HelperInjectorViewModel struct {
 	pendingCommands [][]string
 	tempFile        string // Store temp file to clean up later
 	helperPath      string // Path where helper will be injected
+	arch            string // Architecture of the injected binary, "" for the runtime's
+	// fileOperations learns about the injected helper, so that it is verified and listed
+	fileOperations *docker.FileOperations
+	// probing is set while other locations are tried because the helper path is not writable
+	probing bool
 }
 
 func (m *HelperInjectorViewModel) render(model *Model) string {
@@ -118,16 +123,23 @@ func (m *HelperInjectorViewModel) render(model *Model) string {
 		if m.success {
 			content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Bold(true).Render("✓ Helper binary injected successfully"))
 			content.WriteString("\n")
-			content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render("The helper is now available at: /.dcv-helper"))
+			content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render("The helper is now available at: " + m.helperPath))
 		} else {
 			content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true).Render("✗ Helper injection failed"))
 			if m.err != nil {
 				content.WriteString("\n")
 				content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render(fmt.Sprintf("Error: %v", m.err)))
 			}
-			content.WriteString("\n\n")
-			content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("💡 Tip: Check if the container has write permissions to /"))
+			// A HelperInjectError already tells why each location was refused
+			var injectErr *docker.HelperInjectError
+			if !errors.As(m.err, &injectErr) {
+				content.WriteString("\n\n")
+				content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("💡 Tip: Check if the container has write permissions to " + path.Dir(m.helperPath)))
+			}
 		}
+	} else if m.probing {
+		content.WriteString("\n")
+		content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("⠋ Looking for a writable location..."))
 	} else if m.currentStep > 0 {
 		content.WriteString("\n")
 		content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("⠋ Injecting helper binary..."))
@@ -169,91 +181,27 @@ func (m *HelperInjectorViewModel) render(model *Model) string {
 
 // buildCommands returns the list of commands needed to inject the helper
 func (m *HelperInjectorViewModel) buildCommands(container *docker.Container, tempFile string) [][]string {
-	if container.IsDind() {
-		return [][]string{
-			{"docker", "cp", tempFile, fmt.Sprintf("%s:%s", container.HostContainerID(), m.helperPath)},
-			{"docker", "exec", container.HostContainerID(), "docker", "cp", m.helperPath, fmt.Sprintf("%s:%s", container.ContainerID(), m.helperPath)},
-		}
-	} else {
-		return [][]string{
-			{"docker", "cp", tempFile, fmt.Sprintf("%s:%s", container.ContainerID(), m.helperPath)},
-		}
+	var commands [][]string
+	for _, args := range docker.HelperInjectCommands(container, tempFile, m.helperPath) {
+		commands = append(commands, append([]string{"docker"}, args...))
 	}
+	return commands
 }
 
-// detectArch tries to detect the container's architecture
-func (m *HelperInjectorViewModel) detectArch(ctx context.Context, dockerClient *client.Client, container *docker.Container) string {
-	// Inspect container to get architecture
-	inspect, err := dockerClient.ContainerInspect(ctx, container.ContainerID())
-	if err != nil {
-		slog.Debug("Failed to inspect container for architecture", "error", err)
-		return ""
-	}
-
-	// Architecture is in format like "amd64", "arm64", etc.
-	if inspect.Platform != "" {
-		// Platform might be like "linux/amd64"
-		parts := strings.Split(inspect.Platform, "/")
-		if len(parts) > 1 {
-			return parts[1]
-		}
-	}
-
-	// Try to get from image config
-	if inspect.Config.Labels != nil {
-		if arch, ok := inspect.Config.Labels["architecture"]; ok {
-			return arch
-		}
-	}
-
-	// Default detection failed
-	return ""
-}
-
-// getHelperTempFile creates a temporary file with the helper binary and returns the temp directory and file path
-func (m *HelperInjectorViewModel) getHelperTempFile(ctx context.Context, dockerClient *client.Client, container *docker.Container) (string, string, error) {
+// getHelperTempFile writes the helper binary for the container's architecture into a temporary file
+func (m *HelperInjectorViewModel) getHelperTempFile(ctx context.Context, dockerClient *client.Client, container *docker.Container) (string, error) {
 	// Detect container architecture (default to runtime arch)
-	arch := m.detectArch(ctx, dockerClient, container)
+	arch := docker.DetectContainerArch(ctx, dockerClient, container)
+	m.arch = arch
 	if arch == "" {
-		arch = runtime.GOARCH
 		slog.Info("Using runtime architecture",
-			slog.String("arch", arch))
+			slog.String("arch", runtime.GOARCH))
 	} else {
 		slog.Info("Detected container architecture",
 			slog.String("arch", arch))
 	}
 
-	// Get the embedded binary
-	binaryData, err := docker.GetHelperBinary(arch)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to get helper binary: %w", err)
-	}
-
-	// Create a temporary file directly (not a directory)
-	tempFile, err := os.CreateTemp("", "dcv-helper-*")
-	if err != nil {
-		return "", "", fmt.Errorf("failed to create temp file: %w", err)
-	}
-	defer func() {
-		_ = tempFile.Close()
-	}()
-
-	// Write the binary data
-	if _, err := tempFile.Write(binaryData); err != nil {
-		_ = os.Remove(tempFile.Name())
-		return "", "", fmt.Errorf("failed to write helper binary to temp file: %w", err)
-	}
-
-	// Make it executable
-	if err := tempFile.Chmod(0755); err != nil {
-		_ = os.Remove(tempFile.Name())
-		return "", "", fmt.Errorf("failed to make temp file executable: %w", err)
-	}
-
-	// Get the directory for cleanup purposes
-	tempDir := filepath.Dir(tempFile.Name())
-
-	return tempDir, tempFile.Name(), nil
+	return docker.WriteHelperTempFile(arch)
 }
 
 // HandleInjectHelper starts the helper injection process
@@ -268,7 +216,10 @@ func (m *HelperInjectorViewModel) HandleInjectHelper(model *Model, container *do
 	m.err = nil
 	m.currentStep = 0
 	m.currentCmdStr = ""
-	m.helperPath = "/.dcv-helper"
+	m.helperPath = docker.HelperPathFor(container)
+	m.arch = ""
+	m.probing = false
+	m.fileOperations = model.sharedFileOperations()
 
 	// Build commands using the moved logic
 	if model.dockerSDKClient == nil {
@@ -279,7 +230,7 @@ func (m *HelperInjectorViewModel) HandleInjectHelper(model *Model, container *do
 
 	// Get temporary file path for helper binary
 	ctx := context.Background()
-	_, tempFile, err := m.getHelperTempFile(ctx, model.dockerSDKClient, container)
+	tempFile, err := m.getHelperTempFile(ctx, model.dockerSDKClient, container)
 	if err != nil {
 		m.err = err
 		m.done = true
@@ -302,6 +253,9 @@ func (m *HelperInjectorViewModel) executeNextCommand() tea.Cmd {
 		// All commands executed
 		m.done = true
 		m.success = true
+		if m.fileOperations != nil {
+			m.fileOperations.RecordInjection(m.container, m.arch)
+		}
 		return nil
 	}
 
@@ -460,6 +414,10 @@ func (m *HelperInjectorViewModel) ExecOutput(output string, exitCode int) tea.Cm
 
 	// Check if command succeeded
 	if exitCode != 0 {
+		if !m.probing && m.fileOperations != nil {
+			m.output = append(m.output, fmt.Sprintf("ERROR: Exit code %d", exitCode))
+			return m.injectElsewhere()
+		}
 		m.done = true
 		m.success = false
 		m.err = fmt.Errorf("command failed with exit code %d: %s", exitCode, m.currentCmdStr)
@@ -473,10 +431,27 @@ func (m *HelperInjectorViewModel) ExecOutput(output string, exitCode int) tea.Cm
 	return m.executeNextCommand()
 }
 
+// injectElsewhere lets FileOperations find a location that is writable and executable,
+// e.g. /tmp of a container with a read-only root filesystem
+func (m *HelperInjectorViewModel) injectElsewhere() tea.Cmd {
+	m.probing = true
+	m.output = append(m.output, fmt.Sprintf("Cannot inject at %s, looking for another location...", m.helperPath))
+	container := m.container
+	fileOperations := m.fileOperations
+	return func() tea.Msg {
+		err := fileOperations.InjectHelper(context.Background(), container)
+		return helperInjectorCompleteMsg{success: err == nil, err: err}
+	}
+}
+
 func (m *HelperInjectorViewModel) Complete(success bool, err error) {
 	m.done = true
 	m.success = success
 	m.err = err
+	if success && m.container != nil {
+		m.helperPath = docker.HelperPathFor(m.container)
+		m.output = append(m.output, fmt.Sprintf("Injected at %s", m.helperPath))
+	}
 
 	// Clean up temp file on completion
 	if m.tempFile != "" {
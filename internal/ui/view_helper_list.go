@@ -0,0 +1,200 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"charm.land/bubbles/v2/table"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+)
+
+// helpersLoadedMsg contains the containers that carry a helper
+type helpersLoadedMsg struct {
+	helpers []docker.HelperStatus
+	err     error
+}
+
+// helperReinjectedMsg is sent when a helper has been replaced with the embedded binary
+type helperReinjectedMsg struct {
+	container *docker.Container
+	err       error
+}
+
+var (
+	helperOkStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	helperWarningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
+	helperErrorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+// HelperListViewModel lists the containers that carry the injected helper,
+// so that outdated helpers can be replaced and all of them removed when done
+type HelperListViewModel struct {
+	TableViewModel
+	helpers []docker.HelperStatus
+}
+
+// Show switches to the helper list and scans the containers
+func (m *HelperListViewModel) Show(model *Model) tea.Cmd {
+	m.helpers = nil
+	m.Cursor = 0
+	m.SetRows(nil, 0)
+	model.SwitchView(HelperListView)
+	return m.DoLoad(model)
+}
+
+// DoLoad asks the helpers of the running containers, and of the containers dcv injected one into, for their status
+func (m *HelperListViewModel) DoLoad(model *Model) tea.Cmd {
+	model.loading = true
+	fileOperations := model.sharedFileOperations()
+	return func() tea.Msg {
+		listed, err := model.dockerClient.ListContainers(false)
+		if err != nil {
+			return helpersLoadedMsg{err: err}
+		}
+		containers := make([]*docker.Container, 0, len(listed))
+		for _, c := range listed {
+			containers = append(containers, docker.NewContainer(c.ID, c.Names, c.Names, c.State))
+		}
+		return helpersLoadedMsg{helpers: fileOperations.FindHelpers(context.Background(), containers)}
+	}
+}
+
+// Update handles messages for the helper list
+func (m *HelperListViewModel) Update(model *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case helpersLoadedMsg:
+		model.loading = false
+		if msg.err != nil {
+			model.err = msg.err
+			return model, nil
+		}
+		model.err = nil
+		m.Loaded(model, msg.helpers)
+		return model, nil
+
+	case helperReinjectedMsg:
+		model.loading = false
+		if msg.err != nil {
+			model.err = fmt.Errorf("failed to re-inject helper into %s: %w", msg.container.Title(), msg.err)
+			return model, nil
+		}
+		return model, m.DoLoad(model)
+
+	default:
+		return model, nil
+	}
+}
+
+// Loaded shows the scanned helpers
+func (m *HelperListViewModel) Loaded(model *Model, helpers []docker.HelperStatus) {
+	m.helpers = helpers
+	rows := make([]table.Row, 0, len(helpers))
+	for _, h := range helpers {
+		injected := "-"
+		if !h.InjectedAt.IsZero() {
+			injected = h.InjectedAt.Format("15:04:05")
+		}
+		checksum := h.Checksum
+		if len(checksum) > 12 {
+			checksum = checksum[:12]
+		}
+		rows = append(rows, table.Row{h.Container.Title(), h.Version, renderHelperState(h.State()), h.Arch, checksum, injected})
+	}
+	m.SetRows(rows, model.ViewHeight())
+}
+
+func renderHelperState(state string) string {
+	switch state {
+	case "ok":
+		return helperOkStyle.Render(state)
+	case "outdated", "mismatch", "unverified":
+		return helperWarningStyle.Render(state)
+	default:
+		return helperErrorStyle.Render(state)
+	}
+}
+
+func (m *HelperListViewModel) render(model *Model, availableHeight int) string {
+	if len(m.helpers) == 0 {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("No running container carries the helper")
+	}
+
+	columns := []table.Column{
+		{Title: "CONTAINER", Width: -1},
+		{Title: "VERSION", Width: 8},
+		{Title: "STATE", Width: 10},
+		{Title: "ARCH", Width: 6},
+		{Title: "SHA256", Width: 12},
+		{Title: "INJECTED", Width: 8},
+	}
+	body := m.RenderTable(model, columns, availableHeight-1, func(row, col int) lipgloss.Style {
+		if row == m.Cursor {
+			return tableSelectedCellStyle
+		}
+		return tableNormalCellStyle
+	})
+
+	// Explain what is wrong with the selected helper
+	if selected := m.selectedHelper(); selected != nil && selected.Err != nil {
+		body += "\n" + helperWarningStyle.Render(selected.Err.Error())
+	}
+	return body
+}
+
+func (m *HelperListViewModel) selectedHelper() *docker.HelperStatus {
+	if m.Cursor < 0 || m.Cursor >= len(m.helpers) {
+		return nil
+	}
+	return &m.helpers[m.Cursor]
+}
+
+// HandleEject removes the helper from the selected container after confirmation
+func (m *HelperListViewModel) HandleEject(model *Model) tea.Cmd {
+	selected := m.selectedHelper()
+	if selected == nil {
+		return nil
+	}
+	return model.ejectHelper(selected.Container)
+}
+
+// HandleReinject replaces the helper of the selected container with the embedded binary
+func (m *HelperListViewModel) HandleReinject(model *Model) tea.Cmd {
+	selected := m.selectedHelper()
+	if selected == nil {
+		return nil
+	}
+	container := selected.Container
+	fileOperations := model.sharedFileOperations()
+	model.loading = true
+	return func() tea.Msg {
+		err := fileOperations.ReinjectHelper(context.Background(), container)
+		return helperReinjectedMsg{container: container, err: err}
+	}
+}
+
+func (m *HelperListViewModel) HandleUp(model *Model) tea.Cmd {
+	return m.TableViewModel.HandleUp(model)
+}
+
+func (m *HelperListViewModel) HandleDown(model *Model) tea.Cmd {
+	return m.TableViewModel.HandleDown(model)
+}
+
+func (m *HelperListViewModel) HandleBack(model *Model) tea.Cmd {
+	model.SwitchToPreviousView()
+	return nil
+}
+
+func (m *HelperListViewModel) Title() string {
+	return fmt.Sprintf("Injected Helpers (%d)", len(m.helpers))
+}
+
+// ejectHelper makes the helper remove itself from the container, after confirmation
+func (m *Model) ejectHelper(container *docker.Container) tea.Cmd {
+	args := docker.EjectHelperArgs(container)
+	m.sharedFileOperations().ForgetHelper(container)
+	return m.commandExecutionViewModel.ExecuteCommand(m, true, args...)
+}
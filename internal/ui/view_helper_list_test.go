@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+)
+
+func TestHelperListViewModel(t *testing.T) {
+	web := docker.NewContainer("abc123", "web", "web", "running")
+	worker := docker.NewContainer("def456", "worker", "worker", "running")
+	helpers := []docker.HelperStatus{
+		{Container: web, Version: "1.7.0", Arch: "amd64", Checksum: "0123456789abcdef", Expected: "0123456789abcdef",
+			InjectedAt: time.Date(2026, 1, 2, 15, 4, 5, 0, time.Local)},
+		{Container: worker, Version: "1.5.0", Arch: "amd64", Err: errors.New("injected helper is outdated")},
+	}
+
+	model := NewModel(DockerContainerListView)
+	model.width = 120
+	model.Height = 30
+	model.SwitchView(HelperListView)
+	vm := &model.helperListViewModel
+	vm.Update(model, helpersLoadedMsg{helpers: helpers})
+
+	assert.Equal(t, "Injected Helpers (2)", vm.Title())
+	require.Len(t, vm.Rows, 2)
+	assert.Equal(t, "web", vm.Rows[0][0])
+	assert.Equal(t, "0123456789ab", vm.Rows[0][4])
+	assert.Equal(t, "15:04:05", vm.Rows[0][5])
+	assert.Equal(t, "-", vm.Rows[1][5], "helpers found in the container were not injected by this dcv")
+
+	vm.Cursor = 1
+	assert.Contains(t, vm.render(model, 20), "injected helper is outdated")
+
+	t.Run("eject is confirmed first", func(t *testing.T) {
+		assert.Nil(t, vm.HandleEject(model))
+		assert.Equal(t, CommandExecutionView, model.currentView)
+		assert.True(t, model.commandExecutionViewModel.pendingConfirmation)
+		assert.Equal(t, []string{"exec", "def456", "/.dcv-helper", "rm", "-f", "/.dcv-helper"},
+			model.commandExecutionViewModel.pendingArgs)
+	})
+}
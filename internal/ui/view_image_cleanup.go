@@ -0,0 +1,317 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/table"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+// imageUsagesLoadedMsg contains the images classified for cleanup
+type imageUsagesLoadedMsg struct {
+	usages []docker.ImageUsage
+	err    error
+}
+
+var imageClassStyles = map[docker.ImageClass]lipgloss.Style{
+	docker.ImageDangling: lipgloss.NewStyle().Foreground(lipgloss.Color("196")),
+	docker.ImageUnused:   lipgloss.NewStyle().Foreground(lipgloss.Color("220")),
+	docker.ImageInUse:    lipgloss.NewStyle().Foreground(lipgloss.Color("42")),
+}
+
+// prunePreview is what `docker image prune` would remove, shown before anything is removed
+type prunePreview struct {
+	all    bool
+	images []docker.ImageUsage
+}
+
+// ImageCleanupViewModel classifies images as dangling, unused or in use and removes those that can go
+type ImageCleanupViewModel struct {
+	TableViewModel
+	usages []docker.ImageUsage
+	// selected holds the IDs of the images to remove
+	selected map[string]bool
+	// preview is set while a prune waits for confirmation
+	preview *prunePreview
+}
+
+// Show switches to the cleanup of images
+func (m *ImageCleanupViewModel) Show(model *Model) tea.Cmd {
+	m.usages = nil
+	m.selected = make(map[string]bool)
+	m.preview = nil
+	m.Cursor = 0
+	m.SetRows(nil, 0)
+	model.SwitchView(ImageCleanupView)
+	return m.DoLoad(model)
+}
+
+// DoLoad lists the images and the containers that use them
+func (m *ImageCleanupViewModel) DoLoad(model *Model) tea.Cmd {
+	cli := model.dockerSDKClient
+	if cli == nil {
+		model.loading = false
+		model.err = errors.New("the image cleanup needs a connection to the Docker API")
+		return nil
+	}
+	model.loading = true
+	return func() tea.Msg {
+		usages, err := docker.ImageUsages(context.Background(), cli)
+		return imageUsagesLoadedMsg{usages: usages, err: err}
+	}
+}
+
+// Update handles messages for the cleanup view
+func (m *ImageCleanupViewModel) Update(model *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case imageUsagesLoadedMsg:
+		model.loading = false
+		if msg.err != nil {
+			model.err = msg.err
+			return model, nil
+		}
+		model.err = nil
+		m.Loaded(model, msg.usages)
+		return model, nil
+	default:
+		return model, nil
+	}
+}
+
+// Loaded shows the images, keeping the selection of those that are still there
+func (m *ImageCleanupViewModel) Loaded(model *Model, usages []docker.ImageUsage) {
+	m.usages = usages
+	selected := make(map[string]bool, len(m.selected))
+	for _, usage := range usages {
+		if m.selected[usage.ID] && usage.Class != docker.ImageInUse {
+			selected[usage.ID] = true
+		}
+	}
+	m.selected = selected
+	m.SetRows(m.buildRows(), model.ViewHeight())
+}
+
+// shortImageID is the ID as docker images shows it
+func shortImageID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return id
+}
+
+func (m *ImageCleanupViewModel) buildRows() []table.Row {
+	rows := make([]table.Row, 0, len(m.usages))
+	for _, usage := range m.usages {
+		mark := ""
+		if m.selected[usage.ID] {
+			mark = "✓"
+		}
+		name := usage.Name()
+		if len(usage.RepoTags) > 1 {
+			name += fmt.Sprintf(" (+%d tags)", len(usage.RepoTags)-1)
+		}
+		rows = append(rows, table.Row{
+			mark,
+			usage.Class.String(),
+			name,
+			shortImageID(usage.ID),
+			usage.Created.Format("2006-01-02"),
+			models.FormatHumanSize(usage.Size),
+			models.FormatHumanSize(usage.UniqueSize),
+			strings.Join(usage.Containers, ", "),
+		})
+	}
+	return rows
+}
+
+func (m *ImageCleanupViewModel) render(model *Model, availableHeight int) string {
+	if m.preview != nil {
+		return m.renderPreview(availableHeight)
+	}
+	if len(m.usages) == 0 {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("No images found.")
+	}
+
+	columns := []table.Column{
+		{Title: "", Width: 1},
+		{Title: "CLASS", Width: 8},
+		{Title: "IMAGE", Width: -1},
+		{Title: "IMAGE ID", Width: 12},
+		{Title: "CREATED", Width: 10},
+		{Title: "SIZE", Width: 9},
+		{Title: "UNIQUE", Width: 9},
+		{Title: "CONTAINERS", Width: -1},
+	}
+	return m.RenderTable(model, columns, availableHeight, func(row, col int) lipgloss.Style {
+		if row == m.Cursor {
+			return tableSelectedCellStyle
+		}
+		if col == 1 && row < len(m.usages) {
+			return imageClassStyles[m.usages[row].Class]
+		}
+		return tableNormalCellStyle
+	})
+}
+
+// renderPreview lists the images the prune would remove
+func (m *ImageCleanupViewModel) renderPreview(availableHeight int) string {
+	command := "docker image prune"
+	if m.preview.all {
+		command += " --all"
+	}
+	infoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	promptStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
+
+	var s strings.Builder
+	if len(m.preview.images) == 0 {
+		s.WriteString(fmt.Sprintf("%s would remove nothing.\n\n", command))
+		s.WriteString(infoStyle.Render("Press any key to go back"))
+		return s.String()
+	}
+
+	s.WriteString(fmt.Sprintf("%s would remove %d images, %s or more:\n\n", command, len(m.preview.images), models.FormatHumanSize(reclaimableSize(m.preview.images))))
+	// Leave room for the heading and the prompt
+	shown := m.preview.images
+	if limit := max(availableHeight-5, 1); len(shown) > limit {
+		shown = shown[:limit-1]
+	}
+	for _, usage := range shown {
+		s.WriteString(fmt.Sprintf("  %-8s %s  %-40s %9s\n", usage.Class, shortImageID(usage.ID), usage.Name(), models.FormatHumanSize(usage.Size)))
+	}
+	if hidden := len(m.preview.images) - len(shown); hidden > 0 {
+		s.WriteString(infoStyle.Render(fmt.Sprintf("  ... and %d more", hidden)))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	s.WriteString(promptStyle.Render("Remove these images? (y/n)"))
+	return s.String()
+}
+
+// reclaimableSize is what removing the images frees at least; layers they share with other images may stay
+func reclaimableSize(usages []docker.ImageUsage) int64 {
+	var total int64
+	for _, usage := range usages {
+		if usage.Class != docker.ImageInUse {
+			total += usage.UniqueSize
+		}
+	}
+	return total
+}
+
+// selectedUsages returns the selected images in the order of the list
+func (m *ImageCleanupViewModel) selectedUsages() []docker.ImageUsage {
+	var selected []docker.ImageUsage
+	for _, usage := range m.usages {
+		if m.selected[usage.ID] {
+			selected = append(selected, usage)
+		}
+	}
+	return selected
+}
+
+// HandleToggleSelect selects or unselects the image under the cursor; images in use cannot be selected
+func (m *ImageCleanupViewModel) HandleToggleSelect(model *Model) tea.Cmd {
+	if m.Cursor < 0 || m.Cursor >= len(m.usages) {
+		return nil
+	}
+	usage := m.usages[m.Cursor]
+	if usage.Class != docker.ImageInUse {
+		if m.selected[usage.ID] {
+			delete(m.selected, usage.ID)
+		} else {
+			m.selected[usage.ID] = true
+		}
+		m.SetRows(m.buildRows(), model.ViewHeight())
+	}
+	return m.TableViewModel.HandleDown(model)
+}
+
+// HandleSelectAll selects every image that can be removed, or none when they all are selected
+func (m *ImageCleanupViewModel) HandleSelectAll(model *Model) tea.Cmd {
+	removable := docker.PruneCandidates(m.usages, true)
+	if len(m.selected) == len(removable) {
+		m.selected = make(map[string]bool)
+	} else {
+		for _, usage := range removable {
+			m.selected[usage.ID] = true
+		}
+	}
+	m.SetRows(m.buildRows(), model.ViewHeight())
+	return nil
+}
+
+// HandleDelete removes the selected images, or the one under the cursor when none is selected, after confirmation
+func (m *ImageCleanupViewModel) HandleDelete(model *Model) tea.Cmd {
+	targets := m.selectedUsages()
+	if len(targets) == 0 && m.Cursor < len(m.usages) && m.usages[m.Cursor].Class != docker.ImageInUse {
+		targets = []docker.ImageUsage{m.usages[m.Cursor]}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	return model.commandExecutionViewModel.ExecuteCommand(model, true, removeImageArgs(targets)...)
+}
+
+// removeImageArgs are the arguments of `docker rmi` that remove the images
+func removeImageArgs(usages []docker.ImageUsage) []string {
+	args := []string{"rmi"}
+	for _, usage := range usages {
+		args = append(args, usage.RemoveArgs()...)
+	}
+	return args
+}
+
+// HandlePrunePreview lists what `docker image prune` would remove, with all what `docker image prune --all` would
+func (m *ImageCleanupViewModel) HandlePrunePreview(all bool) tea.Cmd {
+	m.preview = &prunePreview{all: all, images: docker.PruneCandidates(m.usages, all)}
+	return nil
+}
+
+// HandlePreviewKey removes the previewed images on y and closes the preview on any other key.
+// Exactly the listed images are removed, even if others became prunable since.
+func (m *ImageCleanupViewModel) HandlePreviewKey(model *Model, msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	preview := m.preview
+	m.preview = nil
+	if (msg.String() != "y" && msg.String() != "Y") || len(preview.images) == 0 {
+		return model, nil
+	}
+	// The preview was the confirmation
+	return model, model.commandExecutionViewModel.ExecuteCommand(model, false, removeImageArgs(preview.images)...)
+}
+
+func (m *ImageCleanupViewModel) HandleUp(model *Model) tea.Cmd {
+	return m.TableViewModel.HandleUp(model)
+}
+
+func (m *ImageCleanupViewModel) HandleDown(model *Model) tea.Cmd {
+	return m.TableViewModel.HandleDown(model)
+}
+
+func (m *ImageCleanupViewModel) HandleBack(model *Model) tea.Cmd {
+	model.SwitchToPreviousView()
+	return nil
+}
+
+func (m *ImageCleanupViewModel) Title() string {
+	if m.usages == nil {
+		return "Image Cleanup"
+	}
+	counts := make(map[docker.ImageClass]int)
+	for _, usage := range m.usages {
+		counts[usage.Class]++
+	}
+	title := fmt.Sprintf("Image Cleanup [%d dangling, %d unused, %d in use; %s reclaimable",
+		counts[docker.ImageDangling], counts[docker.ImageUnused], counts[docker.ImageInUse], models.FormatHumanSize(reclaimableSize(m.usages)))
+	if selected := m.selectedUsages(); len(selected) > 0 {
+		title += fmt.Sprintf("; %d selected, %s", len(selected), models.FormatHumanSize(reclaimableSize(selected)))
+	}
+	return title + "]"
+}
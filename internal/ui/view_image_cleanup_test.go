@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+)
+
+func testImageUsages() []docker.ImageUsage {
+	return []docker.ImageUsage{
+		{ID: "sha256:0123456789abcdef", Size: 200_000_000, UniqueSize: 200_000_000, Class: docker.ImageDangling},
+		{ID: "sha256:tool", RepoTags: []string{"tool:dev", "tool:latest"}, Size: 500_000_000, UniqueSize: 300_000_000, Class: docker.ImageUnused},
+		{ID: "sha256:app", RepoTags: []string{"app:latest"}, Size: 300_000_000, UniqueSize: 100_000_000, Class: docker.ImageInUse, Containers: []string{"web-1", "web-2"}},
+	}
+}
+
+func newImageCleanupTestModel() *Model {
+	model := &Model{
+		currentView: ImageCleanupView,
+		viewHistory: []ViewType{ImageListView, ImageCleanupView},
+		Height:      30,
+		width:       120,
+	}
+	model.imageCleanupViewModel.selected = make(map[string]bool)
+	model.imageCleanupViewModel.Loaded(model, testImageUsages())
+	return model
+}
+
+func TestImageCleanupViewModel_Loaded(t *testing.T) {
+	model := newImageCleanupTestModel()
+	vm := &model.imageCleanupViewModel
+
+	require.Len(t, vm.Rows, 3)
+	assert.Equal(t, "0123456789ab", vm.Rows[0][3])
+	assert.Equal(t, "tool:dev (+1 tags)", vm.Rows[1][2])
+	assert.Equal(t, "web-1, web-2", vm.Rows[2][7])
+	assert.Equal(t, "Image Cleanup [1 dangling, 1 unused, 1 in use; 500MB reclaimable]", vm.Title())
+	assert.Contains(t, stripANSI(vm.render(model, 20)), "dangling")
+}
+
+func TestImageCleanupViewModel_Select(t *testing.T) {
+	model := newImageCleanupTestModel()
+	vm := &model.imageCleanupViewModel
+
+	vm.HandleToggleSelect(model)
+	assert.True(t, vm.selected["sha256:0123456789abcdef"])
+	assert.Equal(t, 1, vm.Cursor)
+	assert.Equal(t, "✓", vm.Rows[0][0])
+
+	// Images in use cannot be selected
+	vm.Cursor = 2
+	vm.HandleToggleSelect(model)
+	assert.False(t, vm.selected["sha256:app"])
+
+	vm.HandleSelectAll(model)
+	assert.Len(t, vm.selected, 2)
+	assert.Equal(t, "Image Cleanup [1 dangling, 1 unused, 1 in use; 500MB reclaimable; 2 selected, 500MB]", vm.Title())
+
+	vm.HandleDelete(model)
+	assert.Equal(t, CommandExecutionView, model.currentView)
+	assert.True(t, model.commandExecutionViewModel.pendingConfirmation)
+	assert.Equal(t, []string{"rmi", "sha256:0123456789abcdef", "tool:dev", "tool:latest"}, model.commandExecutionViewModel.pendingArgs)
+
+	// Selecting everything again clears the selection
+	vm.HandleSelectAll(model)
+	assert.Empty(t, vm.selected)
+}
+
+func TestImageCleanupViewModel_PrunePreview(t *testing.T) {
+	model := newImageCleanupTestModel()
+	vm := &model.imageCleanupViewModel
+
+	vm.HandlePrunePreview(false)
+	view := stripANSI(vm.render(model, 20))
+	assert.Contains(t, view, "docker image prune would remove 1 images, 200MB or more:")
+	assert.NotContains(t, view, "tool:dev")
+
+	// Anything but y cancels
+	vm.HandlePreviewKey(model, tea.KeyPressMsg{Code: 'n', Text: "n"})
+	assert.Nil(t, vm.preview)
+	assert.Equal(t, ImageCleanupView, model.currentView)
+
+	vm.HandlePrunePreview(true)
+	view = stripANSI(vm.render(model, 20))
+	assert.Contains(t, view, "docker image prune --all would remove 2 images, 500MB or more:")
+	assert.Contains(t, view, "tool:dev")
+	assert.NotContains(t, view, "app:latest")
+
+	_, cmd := vm.HandlePreviewKey(model, tea.KeyPressMsg{Code: 'y', Text: "y"})
+	assert.NotNil(t, cmd)
+	assert.Equal(t, CommandExecutionView, model.currentView)
+	assert.False(t, model.commandExecutionViewModel.pendingConfirmation, "the preview was the confirmation")
+}
@@ -0,0 +1,252 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"charm.land/bubbles/v2/table"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+// imageHistoryLoadedMsg contains the build steps of an image
+type imageHistoryLoadedMsg struct {
+	image   string
+	history []models.ImageHistoryEntry
+	err     error
+}
+
+// imageHistoryLayersMsg contains the analyzed layers of an image, to open the files of a step
+type imageHistoryLayersMsg struct {
+	image  string
+	layers *docker.ImageLayers
+	step   int
+	err    error
+}
+
+// largestLayerCount is how many of the largest layers are highlighted
+const largestLayerCount = 3
+
+var largeLayerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true)
+
+// ImageHistoryViewModel shows the build steps of an image with the size of the layer each created
+type ImageHistoryViewModel struct {
+	TableViewModel
+	image string
+	// history holds the steps newest first, like docker history
+	history []models.ImageHistoryEntry
+	// largest holds the indexes of the largest layers in history
+	largest []int
+	// layers is read with docker save when the files of a step are first asked for
+	layers *docker.ImageLayers
+}
+
+// Show switches to the history of an image
+func (m *ImageHistoryViewModel) Show(model *Model, image string) tea.Cmd {
+	m.image = image
+	m.history = nil
+	m.largest = nil
+	m.layers = nil
+	m.Cursor = 0
+	m.SetRows(nil, 0)
+	model.SwitchView(ImageHistoryView)
+	return m.DoLoad(model)
+}
+
+// DoLoad runs docker history
+func (m *ImageHistoryViewModel) DoLoad(model *Model) tea.Cmd {
+	model.loading = true
+	image := m.image
+	return func() tea.Msg {
+		history, err := model.dockerClient.GetImageHistory(image)
+		return imageHistoryLoadedMsg{image: image, history: history, err: err}
+	}
+}
+
+// Update handles messages for the history view
+func (m *ImageHistoryViewModel) Update(model *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case imageHistoryLoadedMsg:
+		model.loading = false
+		if msg.image != m.image {
+			return model, nil
+		}
+		if msg.err != nil {
+			model.err = msg.err
+			return model, nil
+		}
+		model.err = nil
+		m.Loaded(model, msg.history)
+		return model, nil
+
+	case imageHistoryLayersMsg:
+		model.loading = false
+		if msg.image != m.image {
+			return model, nil
+		}
+		if msg.err != nil {
+			model.err = msg.err
+			return model, nil
+		}
+		model.err = nil
+		m.layers = msg.layers
+		return model, m.openStep(model, msg.step)
+
+	default:
+		return model, nil
+	}
+}
+
+// Loaded shows the steps and finds the largest layers
+func (m *ImageHistoryViewModel) Loaded(model *Model, history []models.ImageHistoryEntry) {
+	m.history = history
+
+	m.largest = nil
+	for i, entry := range history {
+		if entry.SizeBytes() > 0 {
+			m.largest = append(m.largest, i)
+		}
+	}
+	slices.SortStableFunc(m.largest, func(a, b int) int {
+		return cmp.Compare(history[b].SizeBytes(), history[a].SizeBytes())
+	})
+	m.largest = m.largest[:min(len(m.largest), largestLayerCount)]
+
+	m.SetRows(m.buildRows(), model.ViewHeight())
+}
+
+// totalSize is the sum of the sizes of the layers
+func (m *ImageHistoryViewModel) totalSize() int64 {
+	var total int64
+	for _, entry := range m.history {
+		total += entry.SizeBytes()
+	}
+	return total
+}
+
+func (m *ImageHistoryViewModel) buildRows() []table.Row {
+	total := m.totalSize()
+	rows := make([]table.Row, 0, len(m.history))
+	for i, entry := range m.history {
+		share := "-"
+		if size := entry.SizeBytes(); size > 0 && total > 0 {
+			share = fmt.Sprintf("%.1f%%", float64(size)*100/float64(total))
+		}
+		rows = append(rows, table.Row{
+			strconv.Itoa(len(m.history) - i),
+			entry.CreatedSince,
+			entry.Size,
+			share,
+			entry.Instruction(),
+		})
+	}
+	return rows
+}
+
+func (m *ImageHistoryViewModel) render(model *Model, availableHeight int) string {
+	if len(m.history) == 0 {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("The image has no history")
+	}
+
+	columns := []table.Column{
+		{Title: "STEP", Width: 4},
+		{Title: "CREATED", Width: 14},
+		{Title: "SIZE", Width: 9},
+		{Title: "SHARE", Width: 6},
+		{Title: "CREATED BY", Width: -1},
+	}
+	return m.RenderTable(model, columns, availableHeight, func(row, col int) lipgloss.Style {
+		if row == m.Cursor {
+			return tableSelectedCellStyle
+		}
+		if slices.Contains(m.largest, row) {
+			return largeLayerStyle
+		}
+		return tableNormalCellStyle
+	})
+}
+
+// HandleOpen shows the files the layer of the selected step added, changed and deleted.
+// The layers are read with docker save the first time, which takes a while for large images.
+func (m *ImageHistoryViewModel) HandleOpen(model *Model) tea.Cmd {
+	if m.Cursor < 0 || m.Cursor >= len(m.history) {
+		return nil
+	}
+	step := len(m.history) - 1 - m.Cursor
+	if m.layers != nil {
+		return m.openStep(model, step)
+	}
+
+	model.loading = true
+	image := m.image
+	return func() tea.Msg {
+		layers, err := docker.AnalyzeLayers(image)
+		return imageHistoryLayersMsg{image: image, layers: layers, step: step, err: err}
+	}
+}
+
+// openStep shows the layer of a step, counted from the oldest
+func (m *ImageHistoryViewModel) openStep(model *Model, step int) tea.Cmd {
+	entry := m.history[len(m.history)-1-step]
+	layer, ok := m.layerOfStep(step)
+	if !ok {
+		model.err = fmt.Errorf("step %d (%s) did not create a layer", step+1, entry.Instruction())
+		return nil
+	}
+	title := fmt.Sprintf("Layer: %s step %d, %s: %s", m.image, step+1, entry.Size, entry.Instruction())
+	model.imageLayerViewModel.Show(model, title, m.layers.Layers[layer])
+	return nil
+}
+
+// layerOfStep finds the layer a step created. The image config tells which steps created none;
+// without it the steps that added nothing are assumed to be those.
+func (m *ImageHistoryViewModel) layerOfStep(step int) (int, bool) {
+	if m.layers == nil {
+		return 0, false
+	}
+	if len(m.layers.Steps) == len(m.history) {
+		layer := m.layers.Steps[step]
+		return layer, layer >= 0
+	}
+
+	layer := -1
+	count := 0
+	for i := len(m.history) - 1; i >= 0; i-- {
+		if m.history[i].SizeBytes() == 0 {
+			continue
+		}
+		if len(m.history)-1-i == step {
+			layer = count
+		}
+		count++
+	}
+	if count != len(m.layers.Layers) || layer < 0 {
+		return 0, false
+	}
+	return layer, true
+}
+
+func (m *ImageHistoryViewModel) HandleUp(model *Model) tea.Cmd {
+	return m.TableViewModel.HandleUp(model)
+}
+
+func (m *ImageHistoryViewModel) HandleDown(model *Model) tea.Cmd {
+	return m.TableViewModel.HandleDown(model)
+}
+
+func (m *ImageHistoryViewModel) HandleBack(model *Model) tea.Cmd {
+	model.SwitchToPreviousView()
+	return nil
+}
+
+func (m *ImageHistoryViewModel) Title() string {
+	if len(m.history) == 0 {
+		return "Image History: " + m.image
+	}
+	return fmt.Sprintf("Image History: %s [%d steps, %s]", m.image, len(m.history), models.FormatHumanSize(m.totalSize()))
+}
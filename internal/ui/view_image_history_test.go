@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+// testImageHistory is the output of docker history, newest step first
+func testImageHistory() []models.ImageHistoryEntry {
+	return []models.ImageHistoryEntry{
+		{CreatedSince: "2 hours ago", CreatedBy: `CMD ["app"]`, Size: "0B"},
+		{CreatedSince: "2 hours ago", CreatedBy: "COPY app /usr/bin/app # buildkit", Size: "12MB"},
+		{CreatedSince: "2 hours ago", CreatedBy: "RUN /bin/sh -c apt-get install -y build-essential # buildkit", Size: "400MB"},
+		{CreatedSince: "3 weeks ago", CreatedBy: "/bin/sh -c #(nop)  ENV LANG=C.UTF-8", Size: "0B"},
+		{CreatedSince: "3 weeks ago", CreatedBy: "/bin/sh -c #(nop) ADD file:abc in / ", Size: "80MB"},
+	}
+}
+
+func newImageHistoryTestModel() *Model {
+	return &Model{
+		currentView: ImageHistoryView,
+		viewHistory: []ViewType{ImageListView, ImageHistoryView},
+		Height:      30,
+		width:       120,
+	}
+}
+
+func TestImageHistoryViewModel_Loaded(t *testing.T) {
+	model := newImageHistoryTestModel()
+	vm := &model.imageHistoryViewModel
+	vm.image = "app:latest"
+	vm.Loaded(model, testImageHistory())
+
+	// The largest layers, largest first; empty steps are never highlighted
+	assert.Equal(t, []int{2, 4, 1}, vm.largest)
+
+	require.Len(t, vm.Rows, 5)
+	assert.Equal(t, "5", vm.Rows[0][0])
+	assert.Equal(t, "-", vm.Rows[0][3])
+	assert.Equal(t, "400MB", vm.Rows[2][2])
+	assert.Equal(t, "81.3%", vm.Rows[2][3])
+	assert.Equal(t, "RUN apt-get install -y build-essential", vm.Rows[2][4])
+	assert.Equal(t, "1", vm.Rows[4][0])
+
+	assert.Equal(t, "Image History: app:latest [5 steps, 492MB]", vm.Title())
+	assert.Contains(t, stripANSI(vm.render(model, 20)), "COPY app /usr/bin/app")
+}
+
+func TestImageHistoryViewModel_LayerOfStep(t *testing.T) {
+	vm := &ImageHistoryViewModel{history: testImageHistory()}
+
+	_, ok := vm.layerOfStep(0)
+	assert.False(t, ok, "no layers before docker save")
+
+	t.Run("from the image config", func(t *testing.T) {
+		vm.layers = &docker.ImageLayers{
+			Layers: make([]docker.ImageLayer, 3),
+			Steps:  []int{0, -1, 1, 2, -1},
+		}
+		layer, ok := vm.layerOfStep(2)
+		assert.True(t, ok)
+		assert.Equal(t, 1, layer)
+		_, ok = vm.layerOfStep(1)
+		assert.False(t, ok, "ENV creates no layer")
+	})
+
+	t.Run("from the sizes", func(t *testing.T) {
+		vm.layers = &docker.ImageLayers{Layers: make([]docker.ImageLayer, 3)}
+		layer, ok := vm.layerOfStep(3)
+		assert.True(t, ok)
+		assert.Equal(t, 2, layer)
+		_, ok = vm.layerOfStep(4)
+		assert.False(t, ok, "CMD creates no layer")
+
+		// The sizes do not tell the layers apart when they disagree with the archive
+		vm.layers = &docker.ImageLayers{Layers: make([]docker.ImageLayer, 4)}
+		_, ok = vm.layerOfStep(0)
+		assert.False(t, ok)
+	})
+}
+
+func TestImageLayerViewModel(t *testing.T) {
+	model := newImageHistoryTestModel()
+	vm := &model.imageLayerViewModel
+	vm.Show(model, "Layer: app:latest step 3", docker.ImageLayer{Changes: []models.ContainerChange{
+		{Kind: models.ChangeAdded, Path: "/etc/apt/sources.list", Size: 2_000},
+		{Kind: models.ChangeAdded, Path: "/usr/lib/gcc/cc1", Size: 30_000_000},
+		{Kind: models.ChangeAdded, Path: "/usr/bin/make", Size: 250_000},
+		{Kind: models.ChangeDeleted, Path: "/var/lib/apt/lists/lock"},
+	}})
+	assert.Equal(t, ImageLayerView, model.currentView)
+
+	// Largest first
+	require.Len(t, vm.rows, 3)
+	assert.Equal(t, "/usr", vm.rows[0].node.path)
+	assert.Equal(t, "30.25MB", vm.Rows[0][2])
+	assert.Equal(t, "/etc", vm.rows[1].node.path)
+	assert.Equal(t, "-", vm.Rows[2][2])
+
+	vm.HandleExpand(model)
+	require.Len(t, vm.rows, 5)
+	assert.Equal(t, "/usr/lib", vm.rows[1].node.path)
+	assert.Equal(t, "/usr/bin", vm.rows[2].node.path)
+
+	assert.Equal(t, "Layer: app:latest step 3 [3 added, 0 changed, 1 deleted]", vm.Title())
+}
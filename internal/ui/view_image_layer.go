@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+)
+
+// ImageLayerViewModel shows the files a layer of an image added, changed and deleted as a tree, largest first
+type ImageLayerViewModel struct {
+	changeTreeView
+	title string
+	layer docker.ImageLayer
+}
+
+// Show switches to the files of a layer
+func (m *ImageLayerViewModel) Show(model *Model, title string, layer docker.ImageLayer) {
+	m.title = title
+	m.layer = layer
+	m.showSize = true
+	root := buildChangeTree(layer.Changes)
+	sortChangeTreeBySize(root)
+	m.resetTree()
+	model.SwitchView(ImageLayerView)
+	m.setTree(model, root)
+}
+
+func (m *ImageLayerViewModel) render(model *Model, availableHeight int) string {
+	return m.renderTree(model, availableHeight, "The layer changed no files")
+}
+
+// HandleOpen expands or collapses the selected directory
+func (m *ImageLayerViewModel) HandleOpen(model *Model) tea.Cmd {
+	m.toggleSelected(model)
+	return nil
+}
+
+func (m *ImageLayerViewModel) HandleUp(model *Model) tea.Cmd {
+	return m.TableViewModel.HandleUp(model)
+}
+
+func (m *ImageLayerViewModel) HandleDown(model *Model) tea.Cmd {
+	return m.TableViewModel.HandleDown(model)
+}
+
+func (m *ImageLayerViewModel) HandleBack(model *Model) tea.Cmd {
+	model.SwitchToPreviousView()
+	return nil
+}
+
+func (m *ImageLayerViewModel) Title() string {
+	if m.root == nil {
+		return "Image Layer"
+	}
+	return fmt.Sprintf("%s [%d added, %d changed, %d deleted]", m.title, m.root.added, m.root.changed, m.root.deleted)
+}
@@ -8,6 +8,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 
+	"github.com/tokuhirom/dcv/internal/docker"
 	"github.com/tokuhirom/dcv/internal/models"
 )
 
@@ -16,6 +17,12 @@ type dockerImagesLoadedMsg struct {
 	err    error
 }
 
+// imageFilesystemOpenedMsg is sent when the filesystem of an image is ready to browse
+type imageFilesystemOpenedMsg struct {
+	image *docker.ImageFilesystem
+	err   error
+}
+
 var _ HandleInspectAware = (*ImageListViewModel)(nil)
 var _ UpdateAware = (*ImageListViewModel)(nil)
 
@@ -24,6 +31,9 @@ type ImageListViewModel struct {
 	TableViewModel
 	dockerImages []models.DockerImage
 	showAll      bool
+	prompt       imagePrompt
+	// selectRef is a reference to select once the list is reloaded, like a new tag
+	selectRef string
 }
 
 func (m *ImageListViewModel) Update(model *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -38,6 +48,25 @@ func (m *ImageListViewModel) Update(model *Model, msg tea.Msg) (tea.Model, tea.C
 		}
 		return model, nil
 
+	case imageFilesystemOpenedMsg:
+		model.loading = false
+		if msg.err != nil {
+			model.err = msg.err
+			return model, nil
+		}
+		model.err = nil
+		return model, model.fileBrowserViewModel.LoadImage(model, msg.image)
+
+	case imageTaggedMsg:
+		model.loading = false
+		if msg.err != nil {
+			model.err = msg.err
+			return model, nil
+		}
+		model.err = nil
+		m.selectRef = msg.target
+		return model, m.DoLoad(model)
+
 	default:
 		return model, nil
 	}
@@ -45,6 +74,10 @@ func (m *ImageListViewModel) Update(model *Model, msg tea.Msg) (tea.Model, tea.C
 
 func (m *ImageListViewModel) Loaded(model *Model, images []models.DockerImage) {
 	m.dockerImages = images
+	if m.selectRef != "" {
+		m.selectReference(m.selectRef)
+		m.selectRef = ""
+	}
 	m.SetRows(m.buildRows(), model.ViewHeight())
 }
 
@@ -57,6 +90,15 @@ func (m *ImageListViewModel) Title() string {
 
 // render renders the image list view
 func (m *ImageListViewModel) render(model *Model, availableHeight int) string {
+	if m.prompt.active {
+		prompt := m.renderPrompt()
+		return prompt + m.renderImages(model, availableHeight-strings.Count(prompt, "\n"))
+	}
+	return m.renderImages(model, availableHeight)
+}
+
+// renderImages renders the table of images
+func (m *ImageListViewModel) renderImages(model *Model, availableHeight int) string {
 	// No images
 	if len(m.dockerImages) == 0 {
 		var s strings.Builder
@@ -146,6 +188,27 @@ func (m *ImageListViewModel) HandleDelete(model *Model) tea.Cmd {
 	return model.commandExecutionViewModel.ExecuteCommand(model, true, args...) // rmi is aggressive
 }
 
+// HandleBrowse browses the files of the selected image without running it
+func (m *ImageListViewModel) HandleBrowse(model *Model) tea.Cmd {
+	if len(m.dockerImages) == 0 || m.Cursor >= len(m.dockerImages) {
+		return nil
+	}
+	image := m.dockerImages[m.Cursor].GetRepoTag()
+	model.loading = true
+	return func() tea.Msg {
+		fs, err := docker.OpenImageFilesystem(image)
+		return imageFilesystemOpenedMsg{image: fs, err: err}
+	}
+}
+
+// HandleHistory shows the build steps and layers of the selected image
+func (m *ImageListViewModel) HandleHistory(model *Model) tea.Cmd {
+	if len(m.dockerImages) == 0 || m.Cursor >= len(m.dockerImages) {
+		return nil
+	}
+	return model.imageHistoryViewModel.Show(model, m.dockerImages[m.Cursor].GetRepoTag())
+}
+
 // HandleInspect shows the inspect view for the selected image
 func (m *ImageListViewModel) HandleInspect(model *Model) tea.Cmd {
 	if len(m.dockerImages) == 0 || m.Cursor >= len(m.dockerImages) {
@@ -0,0 +1,398 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"charm.land/bubbles/v2/viewport"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+const (
+	imageTransferPollInterval = 200 * time.Millisecond
+	imageProgressBarWidth     = 30
+)
+
+// imagePromptKind is what the reference typed in the image list is for
+type imagePromptKind int
+
+const (
+	promptPull imagePromptKind = iota
+	promptTag
+	promptPush
+)
+
+// imagePrompt is the reference to pull, tag as or push, edited in the image list
+type imagePrompt struct {
+	active bool
+	kind   imagePromptKind
+	// source is the image to tag
+	source string
+	value  string
+	cursor int
+}
+
+// imageTaggedMsg tells that an image was tagged
+type imageTaggedMsg struct {
+	target string
+	err    error
+}
+
+// imageTransferTickMsg polls the progress of a pull or push
+type imageTransferTickMsg struct {
+	transfer *imageTransfer
+}
+
+// imageTransfer is a pull or push that runs in the background
+type imageTransfer struct {
+	action string // "Pull" or "Push"
+	ref    string
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	progress docker.ImageProgress
+	done     bool
+	err      error
+}
+
+func (t *imageTransfer) update(progress docker.ImageProgress) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.progress = progress
+}
+
+func (t *imageTransfer) finish(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.done = true
+	t.err = err
+}
+
+// state returns the progress so far, whether the transfer ended and how
+func (t *imageTransfer) state() (docker.ImageProgress, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.progress, t.done, t.err
+}
+
+// ImageTransferViewModel shows the progress of pulling or pushing an image, layer by layer
+type ImageTransferViewModel struct {
+	transfer *imageTransfer
+	scrollY  int
+}
+
+// Start runs a pull or push in the background and switches to its progress
+func (m *ImageTransferViewModel) Start(model *Model, action, ref string, run func(ctx context.Context, progress func(docker.ImageProgress)) error) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	transfer := &imageTransfer{action: action, ref: ref, cancel: cancel}
+	m.transfer = transfer
+	m.scrollY = 0
+	model.SwitchView(ImageTransferView)
+
+	go func() {
+		err := run(ctx, transfer.update)
+		transfer.finish(err)
+		cancel()
+	}()
+	return pollImageTransfer(transfer)
+}
+
+func pollImageTransfer(transfer *imageTransfer) tea.Cmd {
+	return tea.Tick(imageTransferPollInterval, func(time.Time) tea.Msg {
+		return imageTransferTickMsg{transfer: transfer}
+	})
+}
+
+// Update keeps polling a running transfer
+func (m *ImageTransferViewModel) Update(model *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case imageTransferTickMsg:
+		if msg.transfer != m.transfer {
+			return model, nil
+		}
+		if _, done, _ := msg.transfer.state(); done {
+			return model, nil
+		}
+		return model, pollImageTransfer(msg.transfer)
+	default:
+		return model, nil
+	}
+}
+
+// running tells whether the shown transfer has not ended yet
+func (m *ImageTransferViewModel) running() bool {
+	if m.transfer == nil {
+		return false
+	}
+	_, done, _ := m.transfer.state()
+	return !done
+}
+
+// renderLayerProgress renders a layer as its short ID, status, a progress bar and the bytes done
+func renderLayerProgress(layer docker.LayerProgress) string {
+	id := layer.ID
+	if len(id) > 12 {
+		id = id[:12]
+	}
+
+	filled := 0
+	switch {
+	case layer.Done():
+		filled = imageProgressBarWidth
+	case layer.Total > 0:
+		filled = int(min(layer.Current, layer.Total) * imageProgressBarWidth / layer.Total)
+	}
+	barStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	if layer.Done() {
+		barStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	}
+	bar := "[" + barStyle.Render(strings.Repeat("█", filled)) + strings.Repeat(" ", imageProgressBarWidth-filled) + "]"
+
+	counts := ""
+	if layer.Total > 0 {
+		counts = fmt.Sprintf("%s/%s", models.FormatHumanSize(layer.Current), models.FormatHumanSize(layer.Total))
+	}
+	return fmt.Sprintf("%-12s  %-22s %s %s", id, layer.Status, bar, counts)
+}
+
+// content renders the progress of the transfer below the header
+func (m *ImageTransferViewModel) content() string {
+	progress, done, err := m.transfer.state()
+
+	var s strings.Builder
+	finished := 0
+	for _, layer := range progress.Layers {
+		s.WriteString(renderLayerProgress(layer))
+		s.WriteString("\n")
+		if layer.Done() {
+			finished++
+		}
+	}
+	if len(progress.Layers) > 0 {
+		s.WriteString("\n")
+	}
+
+	infoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	for _, status := range progress.Status {
+		s.WriteString(infoStyle.Render(status))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+
+	switch {
+	case !done:
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render(
+			fmt.Sprintf("⠋ %sing %s... %d/%d layers done", m.transfer.action, m.transfer.ref, finished, len(progress.Layers))))
+	case errors.Is(err, context.Canceled):
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render(m.transfer.action + " canceled"))
+	case err != nil:
+		s.WriteString(errorStyle.Render(fmt.Sprintf("✗ %s failed: %v", m.transfer.action, err)))
+	default:
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Bold(true).Render(
+			fmt.Sprintf("✓ %sed %s", m.transfer.action, m.transfer.ref)))
+	}
+	s.WriteString("\n")
+	return s.String()
+}
+
+func (m *ImageTransferViewModel) render(model *Model, availableHeight int) string {
+	if m.transfer == nil {
+		return ""
+	}
+	vp := viewport.New(viewport.WithWidth(model.width), viewport.WithHeight(availableHeight))
+	vp.SetContent(m.content())
+	vp.SetYOffset(m.scrollY)
+	return vp.View()
+}
+
+func (m *ImageTransferViewModel) HandleUp() tea.Cmd {
+	if m.scrollY > 0 {
+		m.scrollY--
+	}
+	return nil
+}
+
+func (m *ImageTransferViewModel) HandleDown(model *Model) tea.Cmd {
+	if m.transfer == nil {
+		return nil
+	}
+	maxScroll := strings.Count(m.content(), "\n") - model.ViewHeight()
+	if m.scrollY < maxScroll {
+		m.scrollY++
+	}
+	return nil
+}
+
+// HandleCancel stops the running pull or push
+func (m *ImageTransferViewModel) HandleCancel() tea.Cmd {
+	if m.running() {
+		m.transfer.cancel()
+	}
+	return nil
+}
+
+// HandleBack stops a running transfer and returns to the image list, reloading it
+func (m *ImageTransferViewModel) HandleBack(model *Model) tea.Cmd {
+	if m.running() {
+		m.transfer.cancel()
+	}
+	model.SwitchToPreviousView()
+	return func() tea.Msg {
+		return RefreshMsg{}
+	}
+}
+
+func (m *ImageTransferViewModel) Title() string {
+	if m.transfer == nil {
+		return "Image Transfer"
+	}
+	return fmt.Sprintf("%s: %s", m.transfer.action, m.transfer.ref)
+}
+
+// selectedReference is the repository and tag of the selected image, "" when it has none
+func (m *ImageListViewModel) selectedReference() string {
+	if len(m.dockerImages) == 0 || m.Cursor >= len(m.dockerImages) {
+		return ""
+	}
+	image := m.dockerImages[m.Cursor]
+	if image.Repository == "<none>" {
+		return ""
+	}
+	return image.GetRepoTag()
+}
+
+// selectReference moves the cursor to the image of ref; a reference without a tag means its latest tag
+func (m *ImageListViewModel) selectReference(ref string) {
+	if !strings.Contains(ref[strings.LastIndex(ref, "/")+1:], ":") {
+		ref += ":latest"
+	}
+	for i, image := range m.dockerImages {
+		if image.GetRepoTag() == ref {
+			m.Cursor = i
+			return
+		}
+	}
+}
+
+// openPrompt starts editing a reference for the image list
+func (m *ImageListViewModel) openPrompt(kind imagePromptKind, source, value string) {
+	m.prompt = imagePrompt{active: true, kind: kind, source: source, value: value, cursor: len(value)}
+}
+
+// HandlePull asks for a reference to pull, starting from the selected image
+func (m *ImageListViewModel) HandlePull(model *Model) tea.Cmd {
+	m.openPrompt(promptPull, "", m.selectedReference())
+	return nil
+}
+
+// HandleTag asks for a new name for the selected image
+func (m *ImageListViewModel) HandleTag(model *Model) tea.Cmd {
+	if len(m.dockerImages) == 0 || m.Cursor >= len(m.dockerImages) {
+		return nil
+	}
+	m.openPrompt(promptTag, m.dockerImages[m.Cursor].GetRepoTag(), m.selectedReference())
+	return nil
+}
+
+// HandlePush asks which reference of the selected image to push
+func (m *ImageListViewModel) HandlePush(model *Model) tea.Cmd {
+	ref := m.selectedReference()
+	if ref == "" {
+		model.err = errors.New("the image has no repository to push to; tag it first")
+		return nil
+	}
+	m.openPrompt(promptPush, "", ref)
+	return nil
+}
+
+// HandlePromptInput edits the reference and runs the pull, tag or push on enter
+func (m *ImageListViewModel) HandlePromptInput(model *Model, msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	prompt := &m.prompt
+	switch {
+	case msg.Code == tea.KeyEnter:
+		prompt.active = false
+		ref := strings.TrimSpace(prompt.value)
+		if ref == "" {
+			return model, nil
+		}
+		return model, m.runPrompt(model, prompt.kind, prompt.source, ref)
+	case msg.Code == tea.KeyEsc:
+		prompt.active = false
+	case msg.Code == tea.KeyBackspace || isCtrlKey(msg, 'h'):
+		if prompt.cursor > 0 {
+			prompt.value = prompt.value[:prompt.cursor-1] + prompt.value[prompt.cursor:]
+			prompt.cursor--
+		}
+	case msg.Code == tea.KeyDelete:
+		if prompt.cursor < len(prompt.value) {
+			prompt.value = prompt.value[:prompt.cursor] + prompt.value[prompt.cursor+1:]
+		}
+	case msg.Code == tea.KeyLeft || isCtrlKey(msg, 'b'):
+		if prompt.cursor > 0 {
+			prompt.cursor--
+		}
+	case msg.Code == tea.KeyRight || isCtrlKey(msg, 'f'):
+		if prompt.cursor < len(prompt.value) {
+			prompt.cursor++
+		}
+	case msg.Code == tea.KeyHome || isCtrlKey(msg, 'a'):
+		prompt.cursor = 0
+	case msg.Code == tea.KeyEnd || isCtrlKey(msg, 'e'):
+		prompt.cursor = len(prompt.value)
+	case isCtrlKey(msg, 'u'):
+		prompt.value = prompt.value[prompt.cursor:]
+		prompt.cursor = 0
+	case len(msg.Text) > 0 && msg.Code != tea.KeySpace:
+		// References have no spaces
+		prompt.value = prompt.value[:prompt.cursor] + msg.Text + prompt.value[prompt.cursor:]
+		prompt.cursor += len(msg.Text)
+	}
+	return model, nil
+}
+
+// runPrompt pulls, tags or pushes through the Docker API, which streams the progress as JSON
+func (m *ImageListViewModel) runPrompt(model *Model, kind imagePromptKind, source, ref string) tea.Cmd {
+	cli := model.dockerSDKClient
+	if cli == nil {
+		model.err = errors.New("pulling, tagging and pushing images needs a connection to the Docker API")
+		return nil
+	}
+
+	switch kind {
+	case promptTag:
+		model.loading = true
+		return func() tea.Msg {
+			return imageTaggedMsg{target: ref, err: docker.TagImage(context.Background(), cli, source, ref)}
+		}
+	case promptPush:
+		return model.imageTransferViewModel.Start(model, "Push", ref, func(ctx context.Context, progress func(docker.ImageProgress)) error {
+			return docker.PushImage(ctx, cli, ref, progress)
+		})
+	default:
+		return model.imageTransferViewModel.Start(model, "Pull", ref, func(ctx context.Context, progress func(docker.ImageProgress)) error {
+			return docker.PullImage(ctx, cli, ref, progress)
+		})
+	}
+}
+
+// renderPrompt renders the reference being edited
+func (m *ImageListViewModel) renderPrompt() string {
+	prompt := m.prompt
+	label := "Pull: "
+	switch prompt.kind {
+	case promptTag:
+		label = fmt.Sprintf("Tag %s as: ", prompt.source)
+	case promptPush:
+		label = "Push: "
+	}
+	promptStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
+	return promptStyle.Render(label) + prompt.value[:prompt.cursor] + "█" + prompt.value[prompt.cursor:] + "\n"
+}
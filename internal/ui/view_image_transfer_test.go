@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+func TestImageListViewModel_Prompt(t *testing.T) {
+	model := &Model{
+		currentView: ImageListView,
+		viewHistory: []ViewType{ImageListView},
+		Height:      30,
+		width:       100,
+	}
+	vm := &model.imageListViewModel
+	vm.Loaded(model, []models.DockerImage{
+		{Repository: "alpine", Tag: "3.20", ID: "sha256:aaa"},
+		{Repository: "<none>", Tag: "<none>", ID: "sha256:bbb"},
+	})
+
+	vm.HandleTag(model)
+	require.True(t, vm.prompt.active)
+	assert.Equal(t, "alpine:3.20", vm.prompt.source)
+	assert.Equal(t, "alpine:3.20", vm.prompt.value)
+
+	for range len("alpine:3.20") {
+		vm.HandlePromptInput(model, tea.KeyPressMsg{Code: tea.KeyBackspace})
+	}
+	for _, r := range "localhost:5000/alpine" {
+		vm.HandlePromptInput(model, tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	assert.Contains(t, stripANSI(vm.render(model, 20)), "Tag alpine:3.20 as: localhost:5000/alpine█")
+
+	// Without the Docker API nothing runs
+	vm.HandlePromptInput(model, tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.False(t, vm.prompt.active)
+	assert.Error(t, model.err)
+
+	// Images without a repository cannot be pushed
+	model.err = nil
+	vm.Cursor = 1
+	vm.HandlePush(model)
+	assert.False(t, vm.prompt.active)
+	assert.Error(t, model.err)
+}
+
+func TestImageListViewModel_SelectReference(t *testing.T) {
+	vm := &ImageListViewModel{dockerImages: []models.DockerImage{
+		{Repository: "alpine", Tag: "3.20"},
+		{Repository: "localhost:5000/alpine", Tag: "latest"},
+	}}
+	vm.selectReference("localhost:5000/alpine")
+	assert.Equal(t, 1, vm.Cursor)
+	vm.selectReference("alpine:3.20")
+	assert.Equal(t, 0, vm.Cursor)
+}
+
+func TestImageTransferViewModel(t *testing.T) {
+	model := &Model{
+		currentView: ImageListView,
+		viewHistory: []ViewType{ImageListView},
+		Height:      30,
+		width:       120,
+	}
+	vm := &model.imageTransferViewModel
+
+	reported := make(chan struct{})
+	vm.Start(model, "Pull", "alpine:3.20", func(ctx context.Context, progress func(docker.ImageProgress)) error {
+		progress(docker.ImageProgress{
+			Layers: []docker.LayerProgress{
+				{ID: "a1b2c3d4e5f6a7b8", Status: "Downloading", Current: 1_000_000, Total: 4_000_000},
+				{ID: "d4e5f6", Status: "Already exists"},
+			},
+			Status: []string{"3.20: Pulling from library/alpine"},
+		})
+		close(reported)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.Equal(t, ImageTransferView, model.currentView)
+	assert.Equal(t, "Pull: alpine:3.20", vm.Title())
+
+	<-reported
+	content := stripANSI(vm.content())
+	assert.Contains(t, content, "a1b2c3d4e5f6  Downloading            [███████                       ] 1MB/4MB")
+	assert.Contains(t, content, "d4e5f6        Already exists         [██████████████████████████████]")
+	assert.Contains(t, content, "Pulling alpine:3.20... 1/2 layers done")
+	assert.True(t, vm.running())
+
+	_, cmd := model.CmdCancel(tea.KeyPressMsg{})
+	assert.Nil(t, cmd)
+	require.Eventually(t, func() bool { return !vm.running() }, time.Second, 10*time.Millisecond)
+	assert.Contains(t, stripANSI(vm.content()), "Pull canceled")
+}
@@ -2,7 +2,6 @@ package ui
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
 	"time"
 
@@ -202,42 +201,6 @@ func (m *InspectViewModel) applySearchWithYAMLHighlighting(line string, keyStyle
 	return result.String()
 }
 
-// findSearchMatches finds all search match positions in a line
-func (m *InspectViewModel) findSearchMatches(line string) [][]int {
-	var matches [][]int
-
-	if m.searchRegex {
-		pattern := m.searchText
-		if m.searchIgnoreCase {
-			pattern = "(?i)" + pattern
-		}
-		if re, err := regexp.Compile(pattern); err == nil {
-			matches = re.FindAllStringIndex(line, -1)
-		}
-	} else {
-		searchStr := m.searchText
-		lineToSearch := line
-
-		if m.searchIgnoreCase {
-			searchStr = strings.ToLower(searchStr)
-			lineToSearch = strings.ToLower(lineToSearch)
-		}
-
-		start := 0
-		for {
-			idx := strings.Index(lineToSearch[start:], searchStr)
-			if idx == -1 {
-				break
-			}
-			realIdx := start + idx
-			matches = append(matches, []int{realIdx, realIdx + len(searchStr)})
-			start = realIdx + 1
-		}
-	}
-
-	return matches
-}
-
 // applyHighlightingToPart applies highlighting to a part of the line
 func (m *InspectViewModel) applyHighlightingToPart(part string, baseStyle, highlightStyle lipgloss.Style, allMatches [][]int, partOffset int) string {
 	if len(allMatches) == 0 {
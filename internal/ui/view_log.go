@@ -1,9 +1,13 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
@@ -34,15 +38,50 @@ type LogViewModel struct {
 
 	container *docker.Container
 
+	// followPath is the file followed inside the container, empty for container logs
+	followPath string
+
+	// paused holds back new lines in pausedLines so that the view stays still
+	paused      bool
+	pausedLines []string
+
+	// notice reports the result of the last export
+	notice string
+
 	LogReaderManager
 }
 
+// followFileReadyMsg is sent when the command that follows a file was chosen
+type followFileReadyMsg struct {
+	command *docker.RemoteCommand
+	err     error
+}
+
+// Update handles messages for the log view
+func (m *LogViewModel) Update(model *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case followFileReadyMsg:
+		model.loading = false
+		if msg.err != nil {
+			model.err = msg.err
+			return model, nil
+		}
+		return model, m.streamRemoteCommand(msg.command)
+	default:
+		return model, nil
+	}
+}
+
 func (m *LogViewModel) SwitchToLogView(model *Model, container *docker.Container) {
 	model.SwitchView(LogView)
 
 	m.container = container
 	m.logs = []string{}
 	m.logScrollY = 0
+	m.followPath = ""
+	m.paused = false
+	m.pausedLines = nil
+	m.notice = ""
 }
 
 func (m *LogViewModel) StreamContainerLogs(model *Model, container *docker.Container) tea.Cmd {
@@ -52,6 +91,21 @@ func (m *LogViewModel) StreamContainerLogs(model *Model, container *docker.Conta
 	return m.streamLogsReal(cmd)
 }
 
+// FollowFile streams the lines appended to a file inside the container, like tail -F.
+// Rotated and truncated files are followed by name.
+func (m *LogViewModel) FollowFile(model *Model, container *docker.Container, path string) tea.Cmd {
+	m.stopLogReader()
+	m.SwitchToLogView(model, container)
+	m.followPath = path
+	model.loading = true
+
+	fileOperations := model.sharedFileOperations()
+	return func() tea.Msg {
+		command, err := fileOperations.FollowFileCommand(context.Background(), container, path, 1000)
+		return followFileReadyMsg{command: command, err: err}
+	}
+}
+
 func (m *LogViewModel) HandleBack(model *Model) tea.Cmd {
 	m.stopLogReader()
 	model.SwitchToPreviousView()
@@ -412,6 +466,14 @@ func (m *LogViewModel) performFilter() {
 }
 
 func (m *LogViewModel) LogLines(model *Model, lines []string) {
+	if m.paused {
+		m.pausedLines = append(m.pausedLines, lines...)
+		if len(m.pausedLines) > 10000 {
+			m.pausedLines = m.pausedLines[len(m.pausedLines)-10000:]
+		}
+		return
+	}
+
 	m.logs = append(m.logs, lines...)
 	// Keep only last 10000 lines to prevent unbounded memory growth
 	if len(m.logs) > 10000 {
@@ -437,8 +499,50 @@ func (m *LogViewModel) FilterDeleteLastChar() {
 	}
 }
 
+// HandleTogglePause stops and resumes showing new lines. Lines received while paused are shown on resume.
+func (m *LogViewModel) HandleTogglePause(model *Model) tea.Cmd {
+	m.paused = !m.paused
+	if !m.paused && len(m.pausedLines) > 0 {
+		lines := m.pausedLines
+		m.pausedLines = nil
+		m.LogLines(model, lines)
+	}
+	return nil
+}
+
+// HandleExport saves the shown lines, filtered when a filter is active, to a file in the current directory
+func (m *LogViewModel) HandleExport(model *Model) tea.Cmd {
+	lines := m.logs
+	if m.filterMode && m.filterText != "" {
+		lines = m.filteredLogs
+	}
+
+	name := m.container.GetName()
+	if m.followPath != "" {
+		name += "-" + filepath.Base(m.followPath)
+	}
+	path := fmt.Sprintf("dcv-%s-%s.log", name, time.Now().Format("20060102-150405"))
+
+	var content strings.Builder
+	for _, line := range lines {
+		content.WriteString(line + "\n")
+	}
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		model.err = fmt.Errorf("failed to save logs: %w", err)
+		return nil
+	}
+	m.notice = fmt.Sprintf("saved %d lines to %s", len(lines), path)
+	return nil
+}
+
 func (m *LogViewModel) Title() string {
 	title := fmt.Sprintf("Logs: %s", m.container.Title())
+	if m.followPath != "" {
+		title = fmt.Sprintf("Follow: %s (%s)", m.followPath, m.container.Title())
+	}
+	if m.paused {
+		title += fmt.Sprintf(" [paused, %d new lines]", len(m.pausedLines))
+	}
 
 	// Add search or filter status to title
 	if m.filterMode && m.filterText != "" {
@@ -463,6 +567,9 @@ func (m *LogViewModel) Title() string {
 		title += " - No matches found"
 	}
 
+	if m.notice != "" {
+		title += " - " + m.notice
+	}
 	return title
 }
 
@@ -2,11 +2,16 @@ package ui
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tokuhirom/dcv/internal/docker"
 )
@@ -794,3 +799,84 @@ func TestLogView_Integration(t *testing.T) {
 		assert.Equal(t, ComposeProcessListView, model.currentView)
 	})
 }
+
+func TestLogView_PauseAndExport(t *testing.T) {
+	newModel := func() *Model {
+		return &Model{
+			logViewModel: LogViewModel{
+				logs:      []string{"Line 1", "Line 2"},
+				container: docker.NewContainer("abc123", "web", "web", "running"),
+			},
+			Height:      10,
+			currentView: LogView,
+		}
+	}
+
+	t.Run("pause holds back new lines until resumed", func(t *testing.T) {
+		model := newModel()
+		model.logViewModel.HandleTogglePause(model)
+		model.logViewModel.LogLines(model, []string{"Line 3", "Line 4"})
+		assert.Len(t, model.logViewModel.logs, 2)
+		assert.Contains(t, model.logViewModel.Title(), "[paused, 2 new lines]")
+
+		model.logViewModel.HandleTogglePause(model)
+		assert.Equal(t, []string{"Line 1", "Line 2", "Line 3", "Line 4"}, model.logViewModel.logs)
+		assert.NotContains(t, model.logViewModel.Title(), "paused")
+	})
+
+	t.Run("export saves the filtered lines", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Chdir(dir)
+
+		model := newModel()
+		model.logViewModel.followPath = "/var/log/app.log"
+		model.logViewModel.filterMode = true
+		model.logViewModel.filterText = "2"
+		model.logViewModel.performFilter()
+		model.logViewModel.HandleExport(model)
+		assert.NoError(t, model.err)
+
+		files, err := filepath.Glob(filepath.Join(dir, "dcv-web-app.log-*.log"))
+		assert.NoError(t, err)
+		if assert.Len(t, files, 1) {
+			content, err := os.ReadFile(files[0])
+			assert.NoError(t, err)
+			assert.Equal(t, "Line 2\n", string(content))
+		}
+		assert.Contains(t, model.logViewModel.Title(), "saved 1 lines to dcv-web-app.log-")
+	})
+}
+
+func TestLogView_FollowFile(t *testing.T) {
+	model := NewModel(FileBrowserView)
+	container := docker.NewContainer("abc123", "web", "web", "running")
+	model.logViewModel.logs = []string{"old"}
+
+	cmd := model.logViewModel.FollowFile(model, container, "/var/log/app.log")
+	assert.NotNil(t, cmd)
+	assert.Equal(t, LogView, model.currentView)
+	assert.True(t, model.loading)
+	assert.Empty(t, model.logViewModel.logs)
+	assert.Equal(t, "Follow: /var/log/app.log (web)", model.logViewModel.Title())
+
+	model.logViewModel.Update(model, followFileReadyMsg{err: assert.AnError})
+	assert.False(t, model.loading)
+	assert.Equal(t, assert.AnError, model.err)
+
+	// Container logs do not keep the followed file
+	model.logViewModel.SwitchToLogView(model, container)
+	assert.Equal(t, "Logs: web", model.logViewModel.Title())
+}
+
+func TestLogReader_RemotePID(t *testing.T) {
+	lr, err := newLogReader(exec.Command("sh", "-c", "echo 42; echo line"), &docker.RemoteCommand{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		_, _, done := lr.getNewLines(0)
+		return done
+	}, 5*time.Second, 10*time.Millisecond)
+
+	lines, _, _ := lr.getNewLines(0)
+	assert.Equal(t, []string{"line"}, lines, "the PID is not shown")
+	assert.Equal(t, 42, lr.remotePID)
+}
@@ -0,0 +1,220 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+// processSignalResolvedMsg carries the command that delivers the chosen signal
+type processSignalResolvedMsg struct {
+	args       []string
+	aggressive bool
+	err        error
+}
+
+// ProcessSignal represents a signal that can be sent to a process
+type ProcessSignal struct {
+	Name        string
+	Description string
+	Aggressive  bool
+}
+
+// ProcessSignalViewModel manages the signal selection menu for a process in the top view
+type ProcessSignalViewModel struct {
+	signals         []ProcessSignal
+	selectedSignal  int
+	targetContainer *docker.Container
+	targetProcess   models.Process
+
+	resolving bool
+	err       error
+	// hostLookup is set when the PID could not be located from inside the container,
+	// and the user may confirm looking it up on the Docker host
+	hostLookup bool
+}
+
+// Initialize sets up the signal menu for a process
+func (m *ProcessSignalViewModel) Initialize(container *docker.Container, process models.Process) {
+	m.targetContainer = container
+	m.targetProcess = process
+	m.selectedSignal = 0
+	m.resolving = false
+	m.err = nil
+	m.hostLookup = false
+
+	m.signals = []ProcessSignal{
+		{Name: "TERM", Description: "Terminate gracefully", Aggressive: true},
+		{Name: "KILL", Description: "Kill immediately (cannot be caught)", Aggressive: true},
+		{Name: "HUP", Description: "Hang up (many daemons reload their configuration)", Aggressive: true},
+		{Name: "USR1", Description: "User-defined signal 1 (e.g. reopen log files)", Aggressive: true},
+		{Name: "USR2", Description: "User-defined signal 2", Aggressive: true},
+		{Name: "QUIT", Description: "Quit (Go programs dump goroutine stacks)", Aggressive: true},
+	}
+}
+
+// Update handles messages for the signal menu
+func (m *ProcessSignalViewModel) Update(model *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case processSignalResolvedMsg:
+		m.resolving = false
+		if msg.err != nil {
+			// Keep the menu open so the reason stays visible
+			m.err = msg.err
+			var pidErr *docker.ContainerPIDError
+			m.hostLookup = errors.As(msg.err, &pidErr)
+			return model, nil
+		}
+
+		// Remove the signal menu from history so ESC returns to the top view
+		model.SwitchToPreviousView()
+		return model, model.commandExecutionViewModel.ExecuteCommand(model, msg.aggressive, msg.args...)
+	default:
+		return model, nil
+	}
+}
+
+// render displays the signal selection menu
+func (m *ProcessSignalViewModel) render(model *Model) string {
+	if m.targetContainer == nil {
+		return "No process selected"
+	}
+
+	var s strings.Builder
+
+	// Header
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("7")).
+		Background(lipgloss.Color("4")).
+		Width(model.width).
+		Padding(0, 1)
+
+	header := fmt.Sprintf("Send Signal to PID %s", m.targetProcess.PID)
+	s.WriteString(headerStyle.Render(header))
+	s.WriteString("\n\n")
+
+	// Process info
+	infoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	s.WriteString(infoStyle.Render(fmt.Sprintf("Container: %s\n", m.targetContainer.Title())))
+	s.WriteString(infoStyle.Render(fmt.Sprintf("Process: %s\n", m.targetProcess.CMD)))
+	s.WriteString(infoStyle.Render(fmt.Sprintf("User: %s\n", m.targetProcess.UID)))
+	s.WriteString("\n")
+
+	// Signal list
+	s.WriteString("Available Signals:\n\n")
+
+	for i, signal := range m.signals {
+		prefix := "  "
+		if i == m.selectedSignal {
+			prefix = "> "
+		}
+
+		// Color based on aggressive flag
+		var signalStyle lipgloss.Style
+		if signal.Aggressive {
+			signalStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1")) // Red for aggressive
+		} else {
+			signalStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("2")) // Green for safe
+		}
+
+		if i == m.selectedSignal {
+			signalStyle = signalStyle.Bold(true).Background(lipgloss.Color("237"))
+		}
+
+		line := fmt.Sprintf("%sSIG%-5s - %s", prefix, signal.Name, signal.Description)
+		s.WriteString(signalStyle.Render(line))
+		s.WriteString("\n")
+	}
+
+	if m.resolving {
+		s.WriteString("\n")
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("⠋ Locating process in the container..."))
+		s.WriteString("\n")
+	} else if m.err != nil {
+		s.WriteString("\n")
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		s.WriteString("\n")
+		if m.hostLookup {
+			s.WriteString("\n")
+			s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render(
+				"Press h to look the PID up on the Docker host instead. This runs a short-lived helper container\n" +
+					"in the host's PID namespace (docker run --pid=host), which rootless daemons do not allow."))
+			s.WriteString("\n")
+		}
+	}
+
+	// Footer
+	s.WriteString("\n")
+	footerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	s.WriteString(footerStyle.Render("Use ↑/↓ to select, Enter to send, Esc to cancel"))
+
+	return s.String()
+}
+
+// HandleUp moves selection up
+func (m *ProcessSignalViewModel) HandleUp() tea.Cmd {
+	if m.selectedSignal > 0 {
+		m.selectedSignal--
+	}
+	return nil
+}
+
+// HandleDown moves selection down
+func (m *ProcessSignalViewModel) HandleDown() tea.Cmd {
+	if m.selectedSignal < len(m.signals)-1 {
+		m.selectedSignal++
+	}
+	return nil
+}
+
+// HandleSelect builds the command for the selected signal.
+// Translating the PID and probing for a kill binary run in the background;
+// the result arrives as processSignalResolvedMsg.
+func (m *ProcessSignalViewModel) HandleSelect(model *Model) tea.Cmd {
+	if m.resolving || m.selectedSignal < 0 || m.selectedSignal >= len(m.signals) {
+		return nil
+	}
+
+	return m.resolve(false)
+}
+
+// HandleHostLookup sends the selected signal after looking the PID up on the Docker host.
+// It is only offered once locating the PID from inside the container failed, so that the
+// host PID namespace is entered only when the user asks for it.
+func (m *ProcessSignalViewModel) HandleHostLookup() tea.Cmd {
+	if m.resolving || !m.hostLookup || m.selectedSignal < 0 || m.selectedSignal >= len(m.signals) {
+		return nil
+	}
+	return m.resolve(true)
+}
+
+func (m *ProcessSignalViewModel) resolve(hostLookup bool) tea.Cmd {
+	signal := m.signals[m.selectedSignal]
+	container := m.targetContainer
+	pid := m.targetProcess.PID
+
+	m.resolving = true
+	m.err = nil
+	m.hostLookup = false
+	return func() tea.Msg {
+		args, err := docker.SignalArgs(container, pid, signal.Name, hostLookup)
+		return processSignalResolvedMsg{
+			args:       args,
+			aggressive: signal.Aggressive,
+			err:        err,
+		}
+	}
+}
+
+// HandleBack returns to the previous view
+func (m *ProcessSignalViewModel) HandleBack(model *Model) tea.Cmd {
+	model.SwitchToPreviousView()
+	return nil
+}
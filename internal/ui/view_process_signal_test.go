@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tokuhirom/dcv/internal/docker"
+	"github.com/tokuhirom/dcv/internal/models"
+)
+
+func newTestProcessSignalViewModel() (*ProcessSignalViewModel, *docker.Container) {
+	vm := &ProcessSignalViewModel{}
+	container := docker.NewContainer("abc123", "web-1", "web-1 (myproject)", "running")
+	vm.Initialize(container, models.Process{UID: "app", PID: "4242", PPID: "1", CMD: "gunicorn: worker"})
+	return vm, container
+}
+
+func TestProcessSignalViewModel_Initialize(t *testing.T) {
+	vm, container := newTestProcessSignalViewModel()
+
+	assert.Equal(t, container, vm.targetContainer)
+	assert.Equal(t, "4242", vm.targetProcess.PID)
+	assert.Equal(t, 0, vm.selectedSignal)
+
+	var names []string
+	for _, signal := range vm.signals {
+		names = append(names, signal.Name)
+		assert.True(t, signal.Aggressive, "%s should require confirmation", signal.Name)
+	}
+	assert.Equal(t, []string{"TERM", "KILL", "HUP", "USR1", "USR2", "QUIT"}, names)
+}
+
+func TestProcessSignalViewModel_Navigation(t *testing.T) {
+	vm, _ := newTestProcessSignalViewModel()
+
+	vm.HandleUp()
+	assert.Equal(t, 0, vm.selectedSignal, "should not go above the first signal")
+
+	for i := 0; i < 10; i++ {
+		vm.HandleDown()
+	}
+	assert.Equal(t, len(vm.signals)-1, vm.selectedSignal, "should stop at the last signal")
+}
+
+func TestProcessSignalViewModel_Render(t *testing.T) {
+	vm, _ := newTestProcessSignalViewModel()
+	model := &Model{width: 100, Height: 30}
+
+	result := vm.render(model)
+	assert.Contains(t, result, "Send Signal to PID 4242")
+	assert.Contains(t, result, "gunicorn: worker")
+	assert.Contains(t, result, "SIGTERM")
+	assert.Contains(t, result, "SIGQUIT")
+	assert.Contains(t, result, "goroutine stacks")
+
+	vm.err = errors.New("dcv must run on the Docker host")
+	result = vm.render(model)
+	assert.Contains(t, result, "dcv must run on the Docker host")
+}
+
+func TestProcessSignalViewModel_HandleSelect(t *testing.T) {
+	vm, _ := newTestProcessSignalViewModel()
+	model := &Model{}
+
+	cmd := vm.HandleSelect(model)
+	assert.NotNil(t, cmd)
+	assert.True(t, vm.resolving)
+
+	// A second Enter while resolving is ignored
+	assert.Nil(t, vm.HandleSelect(model))
+}
+
+func TestProcessSignalViewModel_Update(t *testing.T) {
+	t.Run("resolution error keeps the menu open", func(t *testing.T) {
+		vm, _ := newTestProcessSignalViewModel()
+		vm.resolving = true
+		model := &Model{
+			currentView: ProcessSignalView,
+			viewHistory: []ViewType{DockerContainerListView, TopView},
+		}
+
+		vm.Update(model, processSignalResolvedMsg{err: errors.New("no kill binary")})
+		assert.False(t, vm.resolving)
+		assert.EqualError(t, vm.err, "no kill binary")
+		assert.Equal(t, ProcessSignalView, model.currentView)
+		assert.False(t, vm.hostLookup)
+		assert.Nil(t, vm.HandleHostLookup(), "the host lookup is only offered when locating the PID failed")
+	})
+
+	t.Run("failing to locate the PID offers the host lookup", func(t *testing.T) {
+		vm, _ := newTestProcessSignalViewModel()
+		vm.resolving = true
+		model := &Model{
+			width:       100,
+			currentView: ProcessSignalView,
+			viewHistory: []ViewType{DockerContainerListView, TopView},
+		}
+
+		vm.Update(model, processSignalResolvedMsg{err: &docker.ContainerPIDError{PID: "4242", Err: errors.New("no sh")}})
+		assert.True(t, vm.hostLookup)
+		assert.Contains(t, vm.render(model), "--pid=host")
+
+		assert.NotNil(t, vm.HandleHostLookup())
+		assert.True(t, vm.resolving)
+		assert.False(t, vm.hostLookup)
+	})
+
+	t.Run("resolved command asks for confirmation", func(t *testing.T) {
+		vm, _ := newTestProcessSignalViewModel()
+		vm.resolving = true
+		model := &Model{
+			currentView: ProcessSignalView,
+			viewHistory: []ViewType{DockerContainerListView, TopView},
+		}
+
+		args := []string{"exec", "abc123", "kill", "-TERM", "7"}
+		vm.Update(model, processSignalResolvedMsg{args: args, aggressive: true})
+
+		assert.Equal(t, CommandExecutionView, model.currentView)
+		assert.True(t, model.commandExecutionViewModel.pendingConfirmation)
+		assert.Equal(t, args, model.commandExecutionViewModel.pendingArgs)
+
+		// ESC from the command execution view returns to the top view, not the menu
+		model.SwitchToPreviousView()
+		assert.Equal(t, TopView, model.currentView)
+	})
+}
+
+func TestCmdSendSignal(t *testing.T) {
+	model := NewModel(TopView)
+	model.Init()
+	model.topViewModel.container = docker.NewContainer("abc123", "web-1", "web-1", "running")
+	model.topViewModel.processes = []models.Process{
+		{PID: "10", PPID: "1", CMD: "worker"},
+	}
+
+	model.CmdSendSignal(newKeyPress("s"))
+	assert.Equal(t, ProcessSignalView, model.currentView)
+	assert.Equal(t, "10", model.processSignalViewModel.targetProcess.PID)
+}
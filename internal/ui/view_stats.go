@@ -10,6 +10,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 
+	"github.com/tokuhirom/dcv/internal/docker"
 	"github.com/tokuhirom/dcv/internal/models"
 )
 
@@ -52,6 +53,10 @@ type StatsViewModel struct {
 	sortReverse     bool
 	autoRefresh     bool
 	refreshInterval time.Duration
+
+	// hostContainer scopes the stats to the containers inside a dind container.
+	// nil means the containers of the Docker host.
+	hostContainer *docker.Container
 }
 
 // Update handles messages for the stats view
@@ -140,6 +145,17 @@ func (m *StatsViewModel) render(model *Model, availableHeight int) string {
 
 // Show switches to the stats view
 func (m *StatsViewModel) Show(model *Model) tea.Cmd {
+	m.hostContainer = nil
+	return m.show(model)
+}
+
+// ShowDind switches to the stats view for the containers inside a dind container
+func (m *StatsViewModel) ShowDind(model *Model, hostContainer *docker.Container) tea.Cmd {
+	m.hostContainer = hostContainer
+	return m.show(model)
+}
+
+func (m *StatsViewModel) show(model *Model) tea.Cmd {
 	m.autoRefresh = true                // Enable auto-refresh by default
 	m.refreshInterval = 2 * time.Second // Default refresh interval
 	model.SwitchView(StatsView)
@@ -162,9 +178,16 @@ func (m *StatsViewModel) DoLoadSilent(model *Model) tea.Cmd {
 }
 
 func (m *StatsViewModel) doLoadInternal(model *Model) tea.Cmd {
+	hostContainer := m.hostContainer
 	return func() tea.Msg {
 		// TODO: suppport toggle-all stats
-		stats, err := model.dockerClient.GetStats(false)
+		var stats []models.ContainerStats
+		var err error
+		if hostContainer != nil {
+			stats, err = model.dockerClient.GetDindStats(hostContainer.GetContainerID(), false)
+		} else {
+			stats, err = model.dockerClient.GetStats(false)
+		}
 		return statsLoadedMsg{
 			stats: stats,
 			err:   err,
@@ -172,6 +195,14 @@ func (m *StatsViewModel) doLoadInternal(model *Model) tea.Cmd {
 	}
 }
 
+// Title returns the title of the stats view
+func (m *StatsViewModel) Title() string {
+	if m.hostContainer != nil {
+		return fmt.Sprintf("Stats: Docker in Docker: %s", m.hostContainer.GetName())
+	}
+	return "Stats"
+}
+
 // HandleBack returns to the compose process list view
 func (m *StatsViewModel) HandleBack(model *Model) tea.Cmd {
 	model.SwitchToPreviousView()
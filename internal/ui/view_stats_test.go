@@ -709,3 +709,63 @@ func TestStatsViewModel_Integration(t *testing.T) {
 		assert.Equal(t, ComposeProcessListView, model.currentView)
 	})
 }
+
+func TestStatsViewModel_ShowDind(t *testing.T) {
+	host := docker.NewContainer("dind123", "dind-host", "dind-host", "running")
+
+	t.Run("ShowDind scopes the stats to the dind host", func(t *testing.T) {
+		model := &Model{
+			dockerClient: docker.NewClient(),
+			currentView:  DindProcessListView,
+		}
+		vm := &StatsViewModel{}
+
+		cmd := vm.ShowDind(model, host)
+		assert.NotNil(t, cmd)
+		assert.Equal(t, StatsView, model.currentView)
+		assert.True(t, model.loading)
+		assert.True(t, vm.autoRefresh)
+		assert.Equal(t, host, vm.hostContainer)
+		assert.Equal(t, "Stats: Docker in Docker: dind-host", vm.Title())
+	})
+
+	t.Run("Show resets the dind scope", func(t *testing.T) {
+		model := &Model{
+			dockerClient: docker.NewClient(),
+			currentView:  DindProcessListView,
+		}
+		vm := &StatsViewModel{hostContainer: host}
+
+		vm.Show(model)
+		assert.Nil(t, vm.hostContainer)
+		assert.Equal(t, "Stats", vm.Title())
+	})
+}
+
+func TestCmdDindStats(t *testing.T) {
+	host := docker.NewContainer("dind123", "dind-host", "dind-host", "running")
+
+	t.Run("opens stats scoped to the dind host", func(t *testing.T) {
+		model := &Model{
+			dockerClient: docker.NewClient(),
+			currentView:  DindProcessListView,
+		}
+		model.dindProcessListViewModel.hostContainer = host
+
+		_, cmd := model.CmdDindStats(newKeyPress("s"))
+		assert.NotNil(t, cmd)
+		assert.Equal(t, StatsView, model.currentView)
+		assert.Equal(t, host, model.statsViewModel.hostContainer)
+	})
+
+	t.Run("does nothing outside the dind list", func(t *testing.T) {
+		model := &Model{
+			dockerClient: docker.NewClient(),
+			currentView:  DockerContainerListView,
+		}
+
+		_, cmd := model.CmdDindStats(newKeyPress("s"))
+		assert.Nil(t, cmd)
+		assert.Equal(t, DockerContainerListView, model.currentView)
+	})
+}
@@ -50,16 +50,26 @@ func (s SortField) String() string {
 	}
 }
 
+// topPsOptions are passed to `docker top` so that per-process CPU and memory
+// usage is available. Hosts whose ps does not understand them fall back to
+// the default `ps -ef` columns.
+var topPsOptions = []string{"-eo", "user,pid,ppid,pcpu,pmem,stime,tty,time,args"}
+
 // TopViewModel manages the state and rendering of the process info view
 type TopViewModel struct {
 	processes       []models.Process
 	containerStats  *models.ContainerStats
 	sortField       SortField
 	sortReverse     bool
+	cursor          int
 	scrollY         int
 	autoRefresh     bool
 	refreshInterval time.Duration
 
+	// Process tree mode
+	treeMode  bool
+	collapsed map[string]bool
+
 	container *docker.Container
 }
 
@@ -116,11 +126,15 @@ func (m *TopViewModel) render(availableHeight int) string {
 	}
 	s.WriteString("  ")
 
-	s.WriteString(helpStyle.Render("[c]PU [m]EM [p]ID [t]IME [n]ame [r]everse [a]uto-refresh"))
+	if m.treeMode {
+		s.WriteString(searchStyle.Render("Tree: ON (CPU/MEM include children)"))
+		s.WriteString("  ")
+	}
+
+	s.WriteString(helpStyle.Render("[c]PU [m]EM [p]ID [t]IME [n]ame [r]everse [a]uto-refresh [T]ree [s]ignal"))
 	s.WriteString("\n\n")
 
-	// Sort processes
-	m.sortProcesses()
+	rows := m.rows()
 
 	// Display process header
 	header := m.renderProcessHeader()
@@ -136,9 +150,23 @@ func (m *TopViewModel) render(availableHeight int) string {
 	}
 	visibleHeight := availableHeight - headerLines
 
+	// Keep the selected process inside the visible window
+	if m.cursor >= len(rows) {
+		m.cursor = len(rows) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	if m.cursor < m.scrollY {
+		m.scrollY = m.cursor
+	}
+	if visibleHeight > 0 && m.cursor >= m.scrollY+visibleHeight {
+		m.scrollY = m.cursor - visibleHeight + 1
+	}
+
 	// Display processes
-	for i := m.scrollY; i < len(m.processes) && i < m.scrollY+visibleHeight; i++ {
-		s.WriteString(m.renderProcess(&m.processes[i]))
+	for i := m.scrollY; i < len(rows) && i < m.scrollY+visibleHeight; i++ {
+		s.WriteString(m.renderProcess(&rows[i], i == m.cursor))
 		s.WriteString("\n")
 	}
 
@@ -191,21 +219,28 @@ func (m *TopViewModel) renderProcessHeader() string {
 		"UID", pidHeader, "PPID", cpuHeader, memHeader, "STIME", timeHeader, cmdHeader)
 }
 
-func (m *TopViewModel) renderProcess(p *models.Process) string {
-	// Color code based on CPU usage
-	cpuStr := fmt.Sprintf("%.1f%%", p.CPUPerc)
-	memStr := fmt.Sprintf("%.1f%%", p.MemPerc)
+func (m *TopViewModel) renderProcess(row *processRow, selected bool) string {
+	p := &row.process
 
-	if p.CPUPerc > 50 {
-		cpuStr = errorStyle.Render(cpuStr)
-	} else if p.CPUPerc > 20 {
-		cpuStr = searchStyle.Render(cpuStr)
-	}
+	// In tree mode the CPU/MEM columns show the subtree totals
+	cpu, mem := row.totalCPU, row.totalMem
+
+	// Color code based on CPU usage
+	cpuStr := fmt.Sprintf("%.1f%%", cpu)
+	memStr := fmt.Sprintf("%.1f%%", mem)
+
+	if !selected {
+		if cpu > 50 {
+			cpuStr = errorStyle.Render(cpuStr)
+		} else if cpu > 20 {
+			cpuStr = searchStyle.Render(cpuStr)
+		}
 
-	if p.MemPerc > 50 {
-		memStr = errorStyle.Render(memStr)
-	} else if p.MemPerc > 20 {
-		memStr = searchStyle.Render(memStr)
+		if mem > 50 {
+			memStr = errorStyle.Render(memStr)
+		} else if mem > 20 {
+			memStr = searchStyle.Render(memStr)
+		}
 	}
 
 	// Truncate command if too long
@@ -214,39 +249,131 @@ func (m *TopViewModel) renderProcess(p *models.Process) string {
 		cmd = cmd[:47] + "..."
 	}
 
-	return fmt.Sprintf("%-8s %-8s %-8s %-6s %-6s %-10s %-10s %s",
+	if m.treeMode {
+		marker := "  "
+		if row.hasChildren {
+			if row.collapsed {
+				marker = "▸ "
+			} else {
+				marker = "▾ "
+			}
+		}
+		cmd = row.prefix + marker + cmd
+		if row.collapsed {
+			cmd += fmt.Sprintf(" (+%d)", row.descendants)
+		}
+	}
+
+	line := fmt.Sprintf("%-8s %-8s %-8s %-6s %-6s %-10s %-10s %s",
 		p.UID, p.PID, p.PPID, cpuStr, memStr, p.STIME, p.TIME, cmd)
+	if selected {
+		return tableSelectedCellStyle.Render(line)
+	}
+	return line
 }
 
-func (m *TopViewModel) sortProcesses() {
-	sort.Slice(m.processes, func(i, j int) bool {
-		var less bool
-		switch m.sortField {
-		case SortByPID:
-			pid1, _ := strconv.Atoi(m.processes[i].PID)
-			pid2, _ := strconv.Atoi(m.processes[j].PID)
-			less = pid1 < pid2
-		case SortByCPU:
-			less = m.processes[i].CPUPerc < m.processes[j].CPUPerc
-		case SortByMem:
-			less = m.processes[i].MemPerc < m.processes[j].MemPerc
-		case SortByTime:
-			less = m.processes[i].TIME < m.processes[j].TIME
-		case SortByCommand:
-			less = m.processes[i].CMD < m.processes[j].CMD
-		default:
-			pid1, _ := strconv.Atoi(m.processes[i].PID)
-			pid2, _ := strconv.Atoi(m.processes[j].PID)
-			less = pid1 < pid2
-		}
+// lessProcess compares two processes by the current sort field, ignoring the sort direction
+func (m *TopViewModel) lessProcess(a, b *models.Process) bool {
+	switch m.sortField {
+	case SortByCPU:
+		return a.CPUPerc < b.CPUPerc
+	case SortByMem:
+		return a.MemPerc < b.MemPerc
+	case SortByTime:
+		return a.TIME < b.TIME
+	case SortByCommand:
+		return a.CMD < b.CMD
+	default:
+		pid1, _ := strconv.Atoi(a.PID)
+		pid2, _ := strconv.Atoi(b.PID)
+		return pid1 < pid2
+	}
+}
 
+// orderedLess returns the comparator honoring the sort direction
+func (m *TopViewModel) orderedLess() func(a, b *models.Process) bool {
+	return func(a, b *models.Process) bool {
 		if m.sortReverse {
-			return !less
+			return m.lessProcess(b, a)
 		}
-		return less
+		return m.lessProcess(a, b)
+	}
+}
+
+func (m *TopViewModel) sortProcesses() {
+	less := m.orderedLess()
+	sort.SliceStable(m.processes, func(i, j int) bool {
+		return less(&m.processes[i], &m.processes[j])
 	})
 }
 
+// rows returns the processes in display order.
+// In tree mode they are arranged by parent PID with collapsed subtrees hidden.
+func (m *TopViewModel) rows() []processRow {
+	if m.treeMode {
+		return buildProcessTree(m.processes, m.orderedLess(), m.collapsed)
+	}
+
+	m.sortProcesses()
+	rows := make([]processRow, len(m.processes))
+	for i, p := range m.processes {
+		rows[i] = processRow{
+			process:  p,
+			totalCPU: p.CPUPerc,
+			totalMem: p.MemPerc,
+		}
+	}
+	return rows
+}
+
+// selectedRow returns the row under the cursor, or nil if there are no processes
+func (m *TopViewModel) selectedRow() *processRow {
+	rows := m.rows()
+	if len(rows) == 0 {
+		return nil
+	}
+	cursor := m.cursor
+	if cursor >= len(rows) {
+		cursor = len(rows) - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	return &rows[cursor]
+}
+
+// SelectedProcess returns the process under the cursor
+func (m *TopViewModel) SelectedProcess() *models.Process {
+	row := m.selectedRow()
+	if row == nil {
+		return nil
+	}
+	return &row.process
+}
+
+// selectPID moves the cursor to the row of the given PID, if it is visible
+func (m *TopViewModel) selectPID(pid string) {
+	if pid == "" {
+		return
+	}
+	for i, row := range m.rows() {
+		if row.process.PID == pid {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+// keepSelection runs fn and then moves the cursor back to the process that was selected before
+func (m *TopViewModel) keepSelection(fn func()) {
+	var pid string
+	if p := m.SelectedProcess(); p != nil {
+		pid = p.PID
+	}
+	fn()
+	m.selectPID(pid)
+}
+
 // Load switches to the top view and loads process info
 func (m *TopViewModel) Load(model *Model, container *docker.Container) tea.Cmd {
 	m.container = container
@@ -274,14 +401,19 @@ func (m *TopViewModel) DoLoadSilent(model *Model) tea.Cmd {
 func (m *TopViewModel) doLoadInternal(model *Model) tea.Cmd {
 	return func() tea.Msg {
 		// Get process list
-		args := m.container.OperationArgs("top")
+		args := m.container.OperationArgs("top", topPsOptions...)
 		topOutput, err := model.dockerClient.ExecuteCaptured(args...)
 		if err != nil {
-			return topLoadedMsg{err: err}
+			// The host's ps may not support -o (e.g. busybox), retry with the default columns
+			args = m.container.OperationArgs("top")
+			topOutput, err = model.dockerClient.ExecuteCaptured(args...)
+			if err != nil {
+				return topLoadedMsg{err: err}
+			}
 		}
 
 		// Get container stats
-		statsArgs := []string{"stats", "--no-stream", "--format", "json", m.container.GetContainerID()}
+		statsArgs := m.container.OperationArgs("stats", "--no-stream", "--format", "json")
 		statsOutput, statsErr := model.dockerClient.ExecuteCaptured(statsArgs...)
 
 		var stats *models.ContainerStats
@@ -307,37 +439,53 @@ func (m *TopViewModel) parseProcesses(output string) []models.Process {
 		return nil
 	}
 
+	columns := parseTopHeader(lines[0])
+	_, hasCPU := columns["%CPU"]
+	_, hasMem := columns["%MEM"]
+
 	var processes []models.Process
 
 	// Skip the header line
 	for i := 1; i < len(lines); i++ {
 		fields := strings.Fields(lines[i])
-		if len(fields) < 8 {
+		if len(fields) < len(columns) {
 			continue
 		}
 
+		field := func(name string) string {
+			if idx, ok := columns[name]; ok {
+				return fields[idx]
+			}
+			return ""
+		}
+
 		p := models.Process{
-			UID:   fields[0],
-			PID:   fields[1],
-			PPID:  fields[2],
-			C:     fields[3],
-			STIME: fields[4],
-			TTY:   fields[5],
-			TIME:  fields[6],
-			CMD:   strings.Join(fields[7:], " "),
+			UID:   field("UID"),
+			PID:   field("PID"),
+			PPID:  field("PPID"),
+			C:     field("C"),
+			STIME: field("STIME"),
+			TTY:   field("TTY"),
+			TIME:  field("TIME"),
+			CMD:   strings.Join(fields[columns["CMD"]:], " "),
 		}
 
-		// Parse CPU percentage from C field if available
-		if cpu, err := strconv.ParseFloat(fields[3], 64); err == nil {
+		if hasCPU {
+			p.CPUPerc, _ = strconv.ParseFloat(field("%CPU"), 64)
+		} else if cpu, err := strconv.ParseFloat(p.C, 64); err == nil {
+			// Parse CPU percentage from C field if available
 			p.CPUPerc = cpu
 		}
+		if hasMem {
+			p.MemPerc, _ = strconv.ParseFloat(field("%MEM"), 64)
+		}
 
 		processes = append(processes, p)
 	}
 
-	// If we have container stats, distribute CPU/Memory proportionally
+	// Without per-process numbers from ps, distribute the container stats proportionally.
 	// This is a simplified approach - in reality, we'd need per-process stats
-	if m.containerStats != nil {
+	if !hasCPU && m.containerStats != nil {
 		totalCPU := models.ParsePercentage(m.containerStats.CPUPerc)
 		totalMem := models.ParsePercentage(m.containerStats.MemPerc)
 
@@ -356,6 +504,45 @@ func (m *TopViewModel) parseProcesses(output string) []models.Process {
 	return processes
 }
 
+// parseTopHeader maps the column names of the docker top header to field indexes.
+// The command column is always last because it may contain spaces.
+func parseTopHeader(header string) map[string]int {
+	aliases := map[string]string{
+		"USER":    "UID",
+		"UID":     "UID",
+		"PID":     "PID",
+		"PPID":    "PPID",
+		"C":       "C",
+		"%CPU":    "%CPU",
+		"%MEM":    "%MEM",
+		"STIME":   "STIME",
+		"START":   "STIME",
+		"TTY":     "TTY",
+		"TT":      "TTY",
+		"TIME":    "TIME",
+		"CMD":     "CMD",
+		"COMMAND": "CMD",
+	}
+
+	columns := make(map[string]int)
+	for i, name := range strings.Fields(header) {
+		if alias, ok := aliases[name]; ok {
+			columns[alias] = i
+		}
+	}
+
+	_, hasPID := columns["PID"]
+	_, hasCmd := columns["CMD"]
+	if !hasPID || !hasCmd {
+		// Unknown header, assume the default `ps -ef` layout
+		return map[string]int{
+			"UID": 0, "PID": 1, "PPID": 2, "C": 3,
+			"STIME": 4, "TTY": 5, "TIME": 6, "CMD": 7,
+		}
+	}
+	return columns
+}
+
 // HandleBack returns to the compose process list view
 func (m *TopViewModel) HandleBack(model *Model) tea.Cmd {
 	model.SwitchToPreviousView()
@@ -364,8 +551,10 @@ func (m *TopViewModel) HandleBack(model *Model) tea.Cmd {
 
 // Loaded updates the top output after loading
 func (m *TopViewModel) Loaded(processes []models.Process, stats *models.ContainerStats) {
-	m.processes = processes
-	m.containerStats = stats
+	m.keepSelection(func() {
+		m.processes = processes
+		m.containerStats = stats
+	})
 	m.scrollY = 0
 }
 
@@ -373,73 +562,140 @@ func (m *TopViewModel) Title() string {
 	return fmt.Sprintf("Process Info: %s", m.container.Title())
 }
 
-// HandleUp scrolls up in the process list
+// HandleUp moves the selection up in the process list
 func (m *TopViewModel) HandleUp() {
-	if m.scrollY > 0 {
-		m.scrollY--
+	if m.cursor > 0 {
+		m.cursor--
+	}
+	if m.cursor < m.scrollY {
+		m.scrollY = m.cursor
 	}
 }
 
-// HandleDown scrolls down in the process list
+// HandleDown moves the selection down in the process list
 func (m *TopViewModel) HandleDown() {
-	if m.scrollY < len(m.processes)-1 {
-		m.scrollY++
+	if m.cursor < len(m.rows())-1 {
+		m.cursor++
 	}
 }
 
+// HandleToggleTree switches between the flat list and the process tree
+func (m *TopViewModel) HandleToggleTree() {
+	m.keepSelection(func() {
+		m.treeMode = !m.treeMode
+	})
+}
+
+// HandleToggleCollapse collapses or expands the subtree of the selected process
+func (m *TopViewModel) HandleToggleCollapse() {
+	row := m.selectedRow()
+	if row == nil || !m.treeMode || !row.hasChildren {
+		return
+	}
+	m.setCollapsed(row.process.PID, !row.collapsed)
+}
+
+// HandleCollapse collapses the subtree of the selected process.
+// On a leaf or an already collapsed node the selection moves to the parent.
+func (m *TopViewModel) HandleCollapse() {
+	row := m.selectedRow()
+	if row == nil || !m.treeMode {
+		return
+	}
+	if row.hasChildren && !row.collapsed {
+		m.setCollapsed(row.process.PID, true)
+		return
+	}
+	m.selectPID(row.process.PPID)
+}
+
+// HandleExpand expands the subtree of the selected process
+func (m *TopViewModel) HandleExpand() {
+	row := m.selectedRow()
+	if row == nil || !m.treeMode || !row.collapsed {
+		return
+	}
+	m.setCollapsed(row.process.PID, false)
+}
+
+func (m *TopViewModel) setCollapsed(pid string, collapsed bool) {
+	m.keepSelection(func() {
+		if m.collapsed == nil {
+			m.collapsed = make(map[string]bool)
+		}
+		if collapsed {
+			m.collapsed[pid] = true
+		} else {
+			delete(m.collapsed, pid)
+		}
+	})
+}
+
 // HandleSortByCPU sorts processes by CPU usage
 func (m *TopViewModel) HandleSortByCPU() {
-	if m.sortField == SortByCPU {
-		m.sortReverse = !m.sortReverse
-	} else {
-		m.sortField = SortByCPU
-		m.sortReverse = true // Default to descending for CPU
-	}
+	m.keepSelection(func() {
+		if m.sortField == SortByCPU {
+			m.sortReverse = !m.sortReverse
+		} else {
+			m.sortField = SortByCPU
+			m.sortReverse = true // Default to descending for CPU
+		}
+	})
 }
 
 // HandleSortByMem sorts processes by memory usage
 func (m *TopViewModel) HandleSortByMem() {
-	if m.sortField == SortByMem {
-		m.sortReverse = !m.sortReverse
-	} else {
-		m.sortField = SortByMem
-		m.sortReverse = true // Default to descending for memory
-	}
+	m.keepSelection(func() {
+		if m.sortField == SortByMem {
+			m.sortReverse = !m.sortReverse
+		} else {
+			m.sortField = SortByMem
+			m.sortReverse = true // Default to descending for memory
+		}
+	})
 }
 
 // HandleSortByPID sorts processes by PID
 func (m *TopViewModel) HandleSortByPID() {
-	if m.sortField == SortByPID {
-		m.sortReverse = !m.sortReverse
-	} else {
-		m.sortField = SortByPID
-		m.sortReverse = false // Default to ascending for PID
-	}
+	m.keepSelection(func() {
+		if m.sortField == SortByPID {
+			m.sortReverse = !m.sortReverse
+		} else {
+			m.sortField = SortByPID
+			m.sortReverse = false // Default to ascending for PID
+		}
+	})
 }
 
 // HandleSortByTime sorts processes by CPU time
 func (m *TopViewModel) HandleSortByTime() {
-	if m.sortField == SortByTime {
-		m.sortReverse = !m.sortReverse
-	} else {
-		m.sortField = SortByTime
-		m.sortReverse = true // Default to descending for time
-	}
+	m.keepSelection(func() {
+		if m.sortField == SortByTime {
+			m.sortReverse = !m.sortReverse
+		} else {
+			m.sortField = SortByTime
+			m.sortReverse = true // Default to descending for time
+		}
+	})
 }
 
 // HandleSortByCommand sorts processes by command name
 func (m *TopViewModel) HandleSortByCommand() {
-	if m.sortField == SortByCommand {
-		m.sortReverse = !m.sortReverse
-	} else {
-		m.sortField = SortByCommand
-		m.sortReverse = false // Default to ascending for command
-	}
+	m.keepSelection(func() {
+		if m.sortField == SortByCommand {
+			m.sortReverse = !m.sortReverse
+		} else {
+			m.sortField = SortByCommand
+			m.sortReverse = false // Default to ascending for command
+		}
+	})
 }
 
 // HandleReverseSort reverses the current sort order
 func (m *TopViewModel) HandleReverseSort() {
-	m.sortReverse = !m.sortReverse
+	m.keepSelection(func() {
+		m.sortReverse = !m.sortReverse
+	})
 }
 
 // HandleToggleAutoRefresh toggles the auto-refresh feature
@@ -286,32 +286,167 @@ func TestTopViewModel_Navigation(t *testing.T) {
 			{PID: "200"},
 			{PID: "300"},
 		},
+		cursor:  1,
 		scrollY: 1,
 	}
 
-	t.Run("HandleUp scrolls up", func(t *testing.T) {
+	t.Run("HandleUp moves selection up", func(t *testing.T) {
 		vm.HandleUp()
+		assert.Equal(t, 0, vm.cursor)
 		assert.Equal(t, 0, vm.scrollY)
 
 		// Shouldn't go below 0
 		vm.HandleUp()
-		assert.Equal(t, 0, vm.scrollY)
+		assert.Equal(t, 0, vm.cursor)
 	})
 
-	t.Run("HandleDown scrolls down", func(t *testing.T) {
-		vm.scrollY = 0
+	t.Run("HandleDown moves selection down", func(t *testing.T) {
+		vm.cursor = 0
 		vm.HandleDown()
-		assert.Equal(t, 1, vm.scrollY)
+		assert.Equal(t, 1, vm.cursor)
 
 		vm.HandleDown()
-		assert.Equal(t, 2, vm.scrollY)
+		assert.Equal(t, 2, vm.cursor)
 
 		// Shouldn't go beyond last process
 		vm.HandleDown()
+		assert.Equal(t, 2, vm.cursor)
+	})
+
+	t.Run("render scrolls to keep the selection visible", func(t *testing.T) {
+		vm.cursor = 2
+		vm.scrollY = 0
+		vm.render(6) // 5 header lines leave room for a single process
 		assert.Equal(t, 2, vm.scrollY)
 	})
 }
 
+func TestTopViewModel_ParseProcessesWithPsOptions(t *testing.T) {
+	vm := &TopViewModel{
+		containerStats: &models.ContainerStats{CPUPerc: "90%", MemPerc: "90%"},
+	}
+
+	output := `USER                PID                 PPID                %CPU                %MEM                STIME               TT                  TIME                COMMAND
+root                1                   0                   0.1                 0.5                 10:00               ?                   00:00:01            supervisord -n
+www-data            12                  1                   42.5                3.2                 10:00               ?                   00:10:00            gunicorn: worker [app]`
+
+	processes := vm.parseProcesses(output)
+	assert.Len(t, processes, 2)
+	assert.Equal(t, "root", processes[0].UID)
+	assert.Equal(t, "1", processes[0].PID)
+	assert.Equal(t, "0", processes[0].PPID)
+	assert.Equal(t, "?", processes[0].TTY)
+	assert.Equal(t, "supervisord -n", processes[0].CMD)
+	// Per-process numbers from ps are kept instead of distributing the container stats
+	assert.Equal(t, 42.5, processes[1].CPUPerc)
+	assert.Equal(t, 3.2, processes[1].MemPerc)
+	assert.Equal(t, "gunicorn: worker [app]", processes[1].CMD)
+}
+
+func TestTopViewModel_TreeMode(t *testing.T) {
+	newVM := func() *TopViewModel {
+		return &TopViewModel{
+			processes: []models.Process{
+				{UID: "root", PID: "1", PPID: "0", CPUPerc: 0.5, MemPerc: 1.0, CMD: "supervisord"},
+				{UID: "app", PID: "10", PPID: "1", CPUPerc: 1.0, MemPerc: 2.0, CMD: "gunicorn master"},
+				{UID: "app", PID: "11", PPID: "10", CPUPerc: 80.0, MemPerc: 5.0, CMD: "gunicorn worker"},
+				{UID: "app", PID: "12", PPID: "10", CPUPerc: 2.0, MemPerc: 5.0, CMD: "gunicorn worker"},
+				{UID: "root", PID: "20", PPID: "1", CPUPerc: 0.1, MemPerc: 0.5, CMD: "cron"},
+			},
+			sortField:   SortByCPU,
+			sortReverse: true,
+		}
+	}
+
+	t.Run("rows are arranged by parent with subtree totals", func(t *testing.T) {
+		vm := newVM()
+		vm.HandleToggleTree()
+		assert.True(t, vm.treeMode)
+
+		rows := vm.rows()
+		var pids []string
+		for _, row := range rows {
+			pids = append(pids, row.process.PID)
+		}
+		assert.Equal(t, []string{"1", "10", "11", "12", "20"}, pids)
+
+		assert.InDelta(t, 83.6, rows[0].totalCPU, 0.001)
+		assert.InDelta(t, 13.5, rows[0].totalMem, 0.001)
+		assert.InDelta(t, 83.0, rows[1].totalCPU, 0.001)
+		assert.Equal(t, 4, rows[0].descendants)
+		assert.Equal(t, 0, rows[0].depth)
+		assert.Equal(t, 2, rows[2].depth)
+	})
+
+	t.Run("collapse hides the subtree and keeps the selection", func(t *testing.T) {
+		vm := newVM()
+		vm.HandleToggleTree()
+		assert.Equal(t, "11", vm.SelectedProcess().PID, "selection is kept when switching to tree mode")
+		vm.HandleUp() // gunicorn master
+
+		vm.HandleCollapse()
+		rows := vm.rows()
+		assert.Len(t, rows, 3)
+		assert.True(t, rows[1].collapsed)
+		assert.Equal(t, "10", vm.SelectedProcess().PID)
+
+		result := vm.render(30)
+		assert.Contains(t, result, "▸ gunicorn master (+2)")
+		assert.NotContains(t, result, "gunicorn worker")
+
+		vm.HandleExpand()
+		assert.Len(t, vm.rows(), 5)
+
+		vm.HandleToggleCollapse()
+		assert.Len(t, vm.rows(), 3)
+		vm.HandleToggleCollapse()
+		assert.Len(t, vm.rows(), 5)
+	})
+
+	t.Run("collapse on a leaf selects the parent", func(t *testing.T) {
+		vm := newVM()
+		vm.HandleToggleTree()
+		vm.selectPID("11")
+
+		vm.HandleCollapse()
+		assert.Equal(t, "10", vm.SelectedProcess().PID)
+	})
+
+	t.Run("render draws tree prefixes", func(t *testing.T) {
+		vm := newVM()
+		vm.HandleToggleTree()
+
+		result := vm.render(30)
+		assert.Contains(t, result, "Tree: ON")
+		assert.Contains(t, result, "▾ supervisord")
+		assert.Contains(t, result, "├─ ▾ gunicorn master")
+		assert.Contains(t, result, "│  ├─   gunicorn worker")
+		assert.Contains(t, result, "└─   cron")
+	})
+
+	t.Run("orphans and PPID loops become roots", func(t *testing.T) {
+		vm := &TopViewModel{
+			treeMode: true,
+			processes: []models.Process{
+				{PID: "5", PPID: "6", CMD: "a"},
+				{PID: "6", PPID: "5", CMD: "b"},
+				{PID: "7", PPID: "999", CMD: "orphan"},
+			},
+		}
+		assert.Len(t, vm.rows(), 3)
+	})
+
+	t.Run("selection follows the process across reloads", func(t *testing.T) {
+		vm := newVM()
+		vm.selectPID("20")
+		processes := newVM().processes
+		processes[4].CPUPerc = 99.0 // cron becomes the busiest process
+		vm.Loaded(processes, nil)
+		assert.Equal(t, "20", vm.SelectedProcess().PID)
+		assert.Equal(t, 0, vm.cursor)
+	})
+}
+
 func TestTopViewModel_LongStrings(t *testing.T) {
 	longUID := strings.Repeat("u", 200)
 	longPID := strings.Repeat("1", 200)
@@ -7,6 +7,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 
+	"github.com/tokuhirom/dcv/internal/docker"
 	"github.com/tokuhirom/dcv/internal/models"
 )
 
@@ -16,6 +17,14 @@ type dockerVolumesLoadedMsg struct {
 	err     error
 }
 
+// volumeBrowserStartedMsg is sent when the container for browsing a volume is running
+type volumeBrowserStartedMsg struct {
+	volume    string
+	readWrite bool
+	container *docker.Container
+	err       error
+}
+
 // VolumeListViewModel manages the state and rendering of the Docker volume list view
 type VolumeListViewModel struct {
 	TableViewModel
@@ -36,6 +45,14 @@ func (m *VolumeListViewModel) Update(model *Model, msg tea.Msg) (tea.Model, tea.
 
 		m.Loaded(model, msg.volumes)
 		return model, nil
+	case volumeBrowserStartedMsg:
+		model.loading = false
+		if msg.err != nil {
+			model.err = msg.err
+			return model, nil
+		}
+		model.err = nil
+		return model, model.fileBrowserViewModel.LoadVolume(model, msg.container, msg.volume, msg.readWrite)
 	default:
 		return model, nil
 	}
@@ -113,6 +130,22 @@ func (m *VolumeListViewModel) HandleInspect(model *Model) tea.Cmd {
 	})
 }
 
+// HandleBrowse opens the file browser on the selected volume. The volume is mounted in a
+// short-lived container that runs only the helper, read-only unless readWrite is set.
+func (m *VolumeListViewModel) HandleBrowse(model *Model, readWrite bool) tea.Cmd {
+	if len(m.dockerVolumes) == 0 || m.Cursor >= len(m.dockerVolumes) {
+		return nil
+	}
+
+	volume := m.dockerVolumes[m.Cursor].Name
+	model.loading = true
+	model.err = nil
+	return func() tea.Msg {
+		container, err := docker.StartVolumeBrowser(volume, readWrite)
+		return volumeBrowserStartedMsg{volume: volume, readWrite: readWrite, container: container, err: err}
+	}
+}
+
 // HandleDelete removes the selected volume
 func (m *VolumeListViewModel) HandleDelete(model *Model, force bool) tea.Cmd {
 	if len(m.dockerVolumes) == 0 || m.Cursor >= len(m.dockerVolumes) {
@@ -431,3 +431,27 @@ func TestVolumeListViewModel_Loaded(t *testing.T) {
 		assert.Equal(t, 0, vm.Cursor, "Selection reset when list is empty")
 	})
 }
+
+func TestVolumeListViewModel_HandleBrowse(t *testing.T) {
+	model := &Model{
+		dockerClient: docker.NewClient(),
+		currentView:  VolumeListView,
+	}
+	vm := &model.volumeListViewModel
+	assert.Nil(t, vm.HandleBrowse(model, false), "nothing to browse without volumes")
+
+	vm.dockerVolumes = []models.DockerVolume{{Name: "data"}}
+	assert.NotNil(t, vm.HandleBrowse(model, true))
+	assert.True(t, model.loading)
+
+	container := docker.NewContainer("browser123", "volume data", "volume data", "running")
+	vm.Update(model, volumeBrowserStartedMsg{volume: "data", readWrite: true, container: container})
+	assert.Equal(t, FileBrowserView, model.currentView)
+	assert.Equal(t, container, model.fileBrowserViewModel.browsingContainer)
+	assert.True(t, model.fileBrowserViewModel.volumeReadWrite)
+
+	model.currentView = VolumeListView
+	vm.Update(model, volumeBrowserStartedMsg{volume: "data", err: assert.AnError})
+	assert.Equal(t, assert.AnError, model.err)
+	assert.Equal(t, VolumeListView, model.currentView)
+}